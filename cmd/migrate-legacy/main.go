@@ -0,0 +1,74 @@
+// migrate-legacy 将旧版文章、评论、点赞与收藏迁移到V3表
+//
+// 迁移按阶段记录检查点，中断后重新执行即可从上次位置继续：
+//
+//	go run ./cmd/migrate-legacy -config ./config/config.yaml -batch 200
+//	go run ./cmd/migrate-legacy -status
+//
+// 同一时间只能运行一个迁移进程。
+package main
+
+import (
+	"astronomer-gin/config"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/database"
+	"astronomer-gin/repository"
+	"astronomer-gin/service"
+	"flag"
+	"fmt"
+	"log"
+)
+
+func main() {
+	configPath := flag.String("config", "./config/config.yaml", "配置文件路径")
+	batchSize := flag.Int("batch", 200, "每批处理的记录数")
+	statusOnly := flag.Bool("status", false, "仅查看迁移进度")
+	flag.Parse()
+
+	// 加载配置
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置文件失败: %v", err)
+	}
+
+	// 初始化数据库
+	if err := database.InitDB(&cfg.Database); err != nil {
+		log.Fatalf("初始化数据库失败: %v", err)
+	}
+	defer database.Close()
+
+	migrationService := service.NewLegacyMigrationService(
+		repository.NewLegacyMigrationRepository(database.GetDB()),
+	)
+
+	var progress []model.LegacyMigrationProgress
+	if *statusOnly {
+		progress, err = migrationService.GetProgress()
+	} else {
+		progress, err = migrationService.Run(*batchSize)
+	}
+	if err != nil {
+		log.Fatalf("旧版数据迁移失败: %v (重新执行即可从检查点继续)", err)
+	}
+
+	printProgress(progress)
+}
+
+// printProgress 按阶段顺序输出迁移进度
+func printProgress(progress []model.LegacyMigrationProgress) {
+	byStage := make(map[string]model.LegacyMigrationProgress, len(progress))
+	for _, p := range progress {
+		byStage[p.Stage] = p
+	}
+
+	fmt.Printf("%-10s %-6s %12s %12s %10s %10s %10s\n", "阶段", "完成", "LastID", "MaxID", "处理", "跳过", "冲突")
+	for _, stage := range model.LegacyMigrationStages {
+		p, ok := byStage[stage]
+		if !ok {
+			fmt.Printf("%-10s %-6s\n", stage, "未开始")
+			continue
+		}
+		fmt.Printf("%-10s %-6t %12d %12d %10d %10d %10d\n",
+			p.Stage, p.Done, p.LastID, p.MaxID, p.Processed, p.Skipped, p.Conflicts)
+	}
+}
@@ -16,9 +16,17 @@ type Config struct {
 	RabbitMQ      RabbitMQConfig      `yaml:"rabbitmq"`
 	JWT           JWTConfig           `yaml:"jwt"`
 	Admin         AdminConfig         `yaml:"admin"`
+	Security      SecurityConfig      `yaml:"security"`
 	Log           LogConfig           `yaml:"log"`
 	Elasticsearch ElasticsearchConfig `yaml:"elasticsearch"`
 	Email         EmailConfig         `yaml:"email"`
+	SMS           SMSConfig           `yaml:"sms"`
+	OAuth         OAuthConfig         `yaml:"oauth"`
+	GeoIP         GeoIPConfig         `yaml:"geoip"`
+	Trash         TrashConfig         `yaml:"trash"`
+	Account       AccountConfig       `yaml:"account"`
+	Level         LevelConfig         `yaml:"level"`
+	Audit         AuditConfig         `yaml:"audit"`
 }
 
 // ServerConfig 服务器配置
@@ -85,6 +93,22 @@ type AdminConfig struct {
 	Password string `yaml:"password"`
 }
 
+// SecurityConfig 账号安全配置
+type SecurityConfig struct {
+	TwoFactorIssuer        string   `yaml:"two_factor_issuer"`         // 身份验证器App中显示的发行方
+	TwoFactorRequiredRoles []string `yaml:"two_factor_required_roles"` // 必须开启两步验证的角色，未开启时无法访问管理接口
+}
+
+// TwoFactorRequired 角色是否必须开启两步验证
+func (c *SecurityConfig) TwoFactorRequired(role string) bool {
+	for _, required := range c.TwoFactorRequiredRoles {
+		if role == required {
+			return true
+		}
+	}
+	return false
+}
+
 // LogConfig 日志配置
 type LogConfig struct {
 	Level      string `yaml:"level"`
@@ -111,6 +135,7 @@ type EmailConfig struct {
 	Password  string              `yaml:"password"`  // 邮箱授权码
 	FromName  string              `yaml:"from_name"` // 发件人名称
 	UseSSL    bool                `yaml:"use_ssl"`   // 是否使用SSL
+	SiteURL   string              `yaml:"site_url"`  // 前端站点地址（邮件中的链接）
 	Templates EmailTemplateConfig `yaml:"templates"` // 邮件模板配置
 }
 
@@ -120,6 +145,73 @@ type EmailTemplateConfig struct {
 	CommentSubject string `yaml:"comment_subject"` // 评论通知主题
 	LikeSubject    string `yaml:"like_subject"`    // 点赞通知主题
 	FollowSubject  string `yaml:"follow_subject"`  // 关注通知主题
+	VerifySubject  string `yaml:"verify_subject"`  // 邮箱验证主题
+	ResetSubject   string `yaml:"reset_subject"`   // 重置密码主题
+}
+
+// SMSConfig 短信服务配置
+type SMSConfig struct {
+	Provider string `yaml:"provider"`  // 短信通道：console（打印到日志）、file（写入文件），或通过 sms.RegisterProvider 注册的通道
+	FilePath string `yaml:"file_path"` // file 通道的输出文件
+	SignName string `yaml:"sign_name"` // 短信签名
+}
+
+// OAuthConfig 第三方登录配置
+type OAuthConfig struct {
+	RedirectURL string                `yaml:"redirect_url"` // 前端回调页地址，{provider} 会替换为提供方名称
+	Providers   []OAuthProviderConfig `yaml:"providers"`    // 启用的登录提供方（按顺序展示）
+}
+
+// OAuthProviderConfig 第三方登录提供方配置
+type OAuthProviderConfig struct {
+	Name         string   `yaml:"name"`          // 提供方标识（URL和账号绑定使用，启用后不要修改）
+	Type         string   `yaml:"type"`          // 类型：github、wechat、oidc、mock，或通过 oauth.RegisterProvider 注册的类型
+	DisplayName  string   `yaml:"display_name"`  // 登录按钮显示名称
+	ClientID     string   `yaml:"client_id"`     // 客户端ID（微信为AppID）
+	ClientSecret string   `yaml:"client_secret"` // 客户端密钥（微信为AppSecret）
+	Issuer       string   `yaml:"issuer"`        // oidc/mock：发行方地址，通过 /.well-known/openid-configuration 发现端点
+	AuthURL      string   `yaml:"auth_url"`      // 覆盖默认的授权地址
+	TokenURL     string   `yaml:"token_url"`     // 覆盖默认的令牌地址
+	UserInfoURL  string   `yaml:"userinfo_url"`  // 覆盖默认的用户信息地址
+	Scopes       []string `yaml:"scopes"`        // 覆盖默认的授权范围
+}
+
+// GeoIPConfig 离线IP地址库配置
+type GeoIPConfig struct {
+	DBPath string `yaml:"db_path"` // IP库文件路径（CSV：起始IP,结束IP,国家,省份,城市），为空则禁用地域统计
+}
+
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	RetentionDays  int    `yaml:"retention_days"`   // 回收站保留天数，超过后彻底清除（默认30）
+	PurgeBatchSize int    `yaml:"purge_batch_size"` // 每轮清除的条目数上限（默认200）
+	PurgeSpec      string `yaml:"purge_spec"`       // 清除任务的cron表达式（秒级，默认每天3点30分）
+}
+
+// AccountConfig 账号注销与数据导出配置
+type AccountConfig struct {
+	DeletionGraceDays int `yaml:"deletion_grace_days"` // 注销冷静期天数，期间登录或撤销即可取消（默认15，最少1天）
+	ExportExpireDays  int `yaml:"export_expire_days"`  // 数据导出文件保留天数，过期后删除（默认7）
+}
+
+// LevelConfig 用户等级与经验配置（未配置的项使用默认值）
+type LevelConfig struct {
+	Thresholds   []int64           `yaml:"thresholds"`    // 各等级所需累计经验，第一个为1级（必须为0）
+	Rules        map[string]XPRule `yaml:"rules"`         // 经验规则：事件 -> 经验值和每日上限
+	Privileges   map[string]int    `yaml:"privileges"`    // 特权 -> 所需等级
+	UploadQuotas []int             `yaml:"upload_quotas"` // 各等级每日上传文件数，等级超出时取最后一个
+}
+
+// XPRule 单个事件的经验规则
+type XPRule struct {
+	XP         int `yaml:"xp" json:"xp"`                   // 每次获得的经验
+	DailyLimit int `yaml:"daily_limit" json:"daily_limit"` // 每日获得经验的次数上限（0为不限）
+}
+
+// AuditConfig 安全审计日志配置
+type AuditConfig struct {
+	RetentionDays int `yaml:"retention_days"` // 主表保留天数，超过后移入归档表（默认180）
+	ActivityDays  int `yaml:"activity_days"`  // 用户"最近安全活动"展示的天数（默认90）
 }
 
 var GlobalConfig *Config
@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"strconv"
+	"time"
+
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/repository"
+	"astronomer-gin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler 安全审计日志处理器
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// NewAuditHandler 创建审计日志处理器
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// QueryLogs 查询审计日志
+// @Summary 查询审计日志
+// @Description 按操作者、操作、对象、请求ID、IP、结果和时间范围查询审计日志；archived=true 时查询已归档的日志
+// @Tags 管理员
+// @Produce json
+// @Security Bearer
+// @Param actor_id query string false "操作者ID"
+// @Param action query string false "操作，如 user.login、admin.report_handle"
+// @Param target_type query string false "对象类型：user/comment/comment_report/sensitive_word/article/column"
+// @Param target_id query string false "对象ID"
+// @Param request_id query string false "请求ID"
+// @Param ip query string false "IP"
+// @Param result query int false "结果：1-成功 2-失败"
+// @Param start query string false "开始时间（RFC3339）"
+// @Param end query string false "结束时间（RFC3339）"
+// @Param archived query bool false "查询归档表"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{list=[]model.AuditLog,total=int,page=int,pageSize=int,write_status=service.AuditWriteStatus}}
+// @Router /admin/audit-logs [get]
+func (h *AuditHandler) QueryLogs(c *gin.Context) {
+	query := &repository.AuditLogQuery{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		RequestID:  c.Query("request_id"),
+		IP:         c.Query("ip"),
+		Archived:   c.Query("archived") == "true",
+	}
+	if v := c.Query("result"); v != "" {
+		result, err := strconv.ParseInt(v, 10, 8)
+		if err != nil {
+			util.BadRequest(c, "无效的结果")
+			return
+		}
+		query.Result = int8(result)
+	}
+	if v := c.Query("start"); v != "" {
+		start, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			util.BadRequest(c, "无效的开始时间")
+			return
+		}
+		query.Start = &start
+	}
+	if v := c.Query("end"); v != "" {
+		end, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			util.BadRequest(c, "无效的结束时间")
+			return
+		}
+		query.End = &end
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	logs, total, err := h.auditService.Query(query, page, pageSize)
+	if err != nil {
+		util.InternalServerError(c, err.Error())
+		return
+	}
+	util.Success(c, gin.H{
+		"list":         logs,
+		"total":        total,
+		"page":         page,
+		"pageSize":     pageSize,
+		"write_status": h.auditService.WriteStatus(),
+	})
+}
+
+// GetWriteStatus 审计日志写入状态
+// @Summary 审计日志写入状态
+// @Description 本实例启动以来审计日志写入失败的次数和最近一次错误（失败的记录会去掉修改前/后值重试一次）
+// @Tags 管理员
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=service.AuditWriteStatus}
+// @Router /admin/audit-logs/status [get]
+func (h *AuditHandler) GetWriteStatus(c *gin.Context) {
+	util.Success(c, h.auditService.WriteStatus())
+}
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"astronomer-gin/model"
 	"astronomer-gin/pkg/elasticsearch"
 	"astronomer-gin/repository"
 	"log"
@@ -11,13 +12,13 @@ import (
 
 // SyncHandler 数据同步处理器
 type SyncHandler struct {
-	blogRepo repository.BlogRepository
+	articleRepo repository.ArticleV3Repository
 }
 
 // NewSyncHandler 创建数据同步处理器
-func NewSyncHandler(blogRepo repository.BlogRepository) *SyncHandler {
+func NewSyncHandler(articleRepo repository.ArticleV3Repository) *SyncHandler {
 	return &SyncHandler{
-		blogRepo: blogRepo,
+		articleRepo: articleRepo,
 	}
 }
 
@@ -38,8 +39,14 @@ func (h *SyncHandler) SyncArticlesToES(c *gin.Context) {
 	totalSynced := 0
 
 	for {
-		// 获取一批文章（status=1表示已发布）
-		articles, total, err := h.blogRepo.FindList(page, pageSize, "", 1)
+		// 获取一批已发布文章
+		articles, total, err := h.articleRepo.FindList(&repository.ArticleQueryParams{
+			Status:    model.ArticleV3StatusPublished,
+			SortBy:    "id",
+			SortOrder: "ASC",
+			Page:      page,
+			PageSize:  pageSize,
+		})
 		if err != nil {
 			log.Printf("❌ 获取文章失败: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -53,8 +60,16 @@ func (h *SyncHandler) SyncArticlesToES(c *gin.Context) {
 			break
 		}
 
+		// 正文存放在内容表，逐篇读取
+		contents := make(map[uint64]string, len(articles))
+		for _, article := range articles {
+			if content, err := h.articleRepo.FindContentByArticleID(article.ID); err == nil {
+				contents[article.ID] = content.Content
+			}
+		}
+
 		// 批量索引到ES
-		if err := elasticsearch.BulkIndexArticles(articles); err != nil {
+		if err := elasticsearch.BulkIndexArticles(articles, contents); err != nil {
 			log.Printf("❌ 批量索引失败: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"code":    http.StatusInternalServerError,
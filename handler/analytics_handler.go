@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler 创作者数据分析处理器
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+// NewAnalyticsHandler 创建创作者数据分析处理器实例
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *AnalyticsHandler) RegisterRoutes(r *gin.Engine) {
+	v3 := r.Group("/api/v3")
+	{
+		// 需要认证的接口
+		auth := v3.Group("/analytics")
+		auth.Use(middleware.AuthMiddleware())
+		{
+			auth.GET("/summary", h.GetSummary)          // 时间段汇总与环比
+			auth.GET("/trend", h.GetTrend)              // 每日趋势（作者/单篇文章）
+			auth.GET("/top-articles", h.GetTopArticles) // 时间段内文章排行
+			auth.GET("/followers", h.GetFollowerGrowth) // 粉丝增长
+		}
+	}
+}
+
+// GetSummary 获取时间段汇总
+// @Summary 获取创作数据汇总
+// @Description 汇总时间段内的阅读、点赞、评论、收藏、分享与新增粉丝，并与上一等长周期对比（数据T+1）
+// @Tags 创作者数据
+// @Produce json
+// @Param start query string false "开始日期（YYYY-MM-DD），默认最近30天"
+// @Param end query string false "结束日期（YYYY-MM-DD），默认昨天"
+// @Success 200 {object} object{code=int,data=service.AnalyticsSummary}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/analytics/summary [get]
+func (h *AnalyticsHandler) GetSummary(c *gin.Context) {
+	start, end, err := parseAnalyticsRange(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	summary, err := h.analyticsService.GetSummary(userID.(string), start, end)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, summary)
+}
+
+// GetTrend 获取每日趋势
+// @Summary 获取每日趋势
+// @Description 返回时间段内每天的数据（无数据的日期补0）；指定article_id时返回单篇文章趋势（含平均阅读进度与停留时间）
+// @Tags 创作者数据
+// @Produce json
+// @Param start query string false "开始日期（YYYY-MM-DD），默认最近30天"
+// @Param end query string false "结束日期（YYYY-MM-DD），默认昨天"
+// @Param article_id query int false "文章ID"
+// @Success 200 {object} object{code=int,data=[]service.AnalyticsPoint}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/analytics/trend [get]
+func (h *AnalyticsHandler) GetTrend(c *gin.Context) {
+	start, end, err := parseAnalyticsRange(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	var articleID uint64
+	if raw := c.Query("article_id"); raw != "" {
+		articleID, err = strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "无效的文章ID")
+			return
+		}
+	}
+
+	userID, _ := c.Get("user_id")
+	points, err := h.analyticsService.GetTrend(userID.(string), articleID, start, end)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, points)
+}
+
+// GetTopArticles 获取时间段内文章排行
+// @Summary 获取文章排行
+// @Tags 创作者数据
+// @Produce json
+// @Param start query string false "开始日期（YYYY-MM-DD），默认最近30天"
+// @Param end query string false "结束日期（YYYY-MM-DD），默认昨天"
+// @Param metric query string false "排序指标：views/unique_views/likes/comments/favorites/shares" default(views)
+// @Param limit query int false "数量（最多50）" default(10)
+// @Success 200 {object} object{code=int,data=[]repository.TopArticleRow}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/analytics/top-articles [get]
+func (h *AnalyticsHandler) GetTopArticles(c *gin.Context) {
+	start, end, err := parseAnalyticsRange(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	userID, _ := c.Get("user_id")
+	rows, err := h.analyticsService.GetTopArticles(userID.(string), start, end, c.DefaultQuery("metric", "views"), limit)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, rows)
+}
+
+// GetFollowerGrowth 获取粉丝增长
+// @Summary 获取粉丝增长
+// @Tags 创作者数据
+// @Produce json
+// @Param start query string false "开始日期（YYYY-MM-DD），默认最近30天"
+// @Param end query string false "结束日期（YYYY-MM-DD），默认昨天"
+// @Success 200 {object} object{code=int,data=[]service.FollowerPoint}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/analytics/followers [get]
+func (h *AnalyticsHandler) GetFollowerGrowth(c *gin.Context) {
+	start, end, err := parseAnalyticsRange(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	points, err := h.analyticsService.GetFollowerGrowth(userID.(string), start, end)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, points)
+}
+
+// parseAnalyticsRange 解析查询区间（默认截至昨天的最近30天，快照数据T+1）
+func parseAnalyticsRange(c *gin.Context) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	end := today.AddDate(0, 0, -1)
+	if raw := c.Query("end"); raw != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", raw, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("结束日期格式错误，应为YYYY-MM-DD")
+		}
+		end = parsed
+	}
+
+	start := end.AddDate(0, 0, -(service.AnalyticsDefaultRangeDays - 1))
+	if raw := c.Query("start"); raw != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", raw, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("开始日期格式错误，应为YYYY-MM-DD")
+		}
+		start = parsed
+	}
+
+	return start, end, nil
+}
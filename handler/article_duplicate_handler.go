@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArticleDuplicateHandler 文章查重处理器
+type ArticleDuplicateHandler struct {
+	duplicateService service.ArticleDuplicateService
+}
+
+// NewArticleDuplicateHandler 创建文章查重处理器实例
+func NewArticleDuplicateHandler(duplicateService service.ArticleDuplicateService) *ArticleDuplicateHandler {
+	return &ArticleDuplicateHandler{
+		duplicateService: duplicateService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *ArticleDuplicateHandler) RegisterRoutes(r *gin.Engine) {
+	v3 := r.Group("/api/v3")
+	{
+		// 作者接口
+		auth := v3.Group("")
+		auth.Use(middleware.AuthMiddleware())
+		{
+			auth.GET("/articles/:id/duplicates", h.GetArticleDuplicates)      // 我的文章的疑似重复记录
+			auth.POST("/articles/:id/originality-claims", h.ClaimOriginality) // 提交原创申诉
+		}
+
+		// 管理员接口
+		admin := v3.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+		{
+			admin.GET("/duplicates/clusters", h.GetDuplicateClusters)              // 疑似重复聚类报告
+			admin.POST("/duplicates/:id/resolve", h.ResolveDuplicate)              // 确认/排除疑似重复
+			admin.GET("/originality-claims", h.GetOriginalityClaims)               // 原创申诉列表
+			admin.POST("/originality-claims/:id/review", h.ReviewOriginalityClaim) // 审核原创申诉
+		}
+	}
+}
+
+// ==================== 作者 ====================
+
+// GetArticleDuplicates 获取文章的疑似重复记录
+// @Summary 获取文章的疑似重复记录
+// @Description 返回自己文章作为疑似搬运方或原文方的全部查重记录
+// @Tags 文章查重
+// @Produce json
+// @Param id path int true "文章ID"
+// @Success 200 {object} object{code=int,data=[]service.DuplicateItem}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/articles/{id}/duplicates [get]
+func (h *ArticleDuplicateHandler) GetArticleDuplicates(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	items, err := h.duplicateService.GetArticleDuplicates(articleID, userID.(string))
+	if err != nil {
+		response.Forbidden(c, err.Error())
+		return
+	}
+
+	response.Success(c, items)
+}
+
+// ClaimOriginality 提交原创申诉
+// @Summary 提交原创申诉
+// @Description 文章被标记为疑似重复时，作者可提交原创说明与证明材料，由管理员审核
+// @Tags 文章查重
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param request body service.OriginalityClaimRequest true "申诉内容"
+// @Success 200 {object} object{code=int,data=model.ArticleOriginalityClaim}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/articles/{id}/originality-claims [post]
+func (h *ArticleDuplicateHandler) ClaimOriginality(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	var req service.OriginalityClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	claim, err := h.duplicateService.ClaimOriginality(articleID, userID.(string), &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, claim)
+}
+
+// ==================== 管理员 ====================
+
+// GetDuplicateClusters 疑似重复聚类报告
+// @Summary 疑似重复聚类报告
+// @Description 将相互近似的文章合并为簇，按簇内文章数降序返回，簇内按发布时间排序（最早的视为原文）
+// @Tags 文章查重
+// @Produce json
+// @Param status query int false "记录状态：0-待处理 1-确认重复 2-已排除" default(0)
+// @Param limit query int false "参与聚类的记录数上限" default(1000)
+// @Success 200 {object} object{code=int,data=[]service.DuplicateCluster}
+// @Router /api/v3/admin/duplicates/clusters [get]
+func (h *ArticleDuplicateHandler) GetDuplicateClusters(c *gin.Context) {
+	status, _ := strconv.Atoi(c.DefaultQuery("status", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "1000"))
+
+	clusters, err := h.duplicateService.GetDuplicateClusters(int8(status), limit)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, clusters)
+}
+
+// ResolveDuplicate 处理疑似重复
+// @Summary 处理疑似重复
+// @Description 确认重复将下线疑似搬运的文章，排除则清除查重标记
+// @Tags 文章查重
+// @Accept json
+// @Produce json
+// @Param id path int true "记录ID"
+// @Param request body service.ResolveDuplicateRequest true "处理结果"
+// @Success 200 {object} object{code=int}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/admin/duplicates/{id}/resolve [post]
+func (h *ArticleDuplicateHandler) ResolveDuplicate(c *gin.Context) {
+	duplicateID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的记录ID")
+		return
+	}
+
+	var req service.ResolveDuplicateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	adminID, _ := c.Get("user_id")
+	if err := h.duplicateService.ResolveDuplicate(duplicateID, adminID.(string), &req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetOriginalityClaims 原创申诉列表
+// @Summary 原创申诉列表
+// @Tags 文章查重
+// @Produce json
+// @Param status query int false "状态：0-待审核 1-通过 2-驳回" default(0)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{claims=[]model.ArticleOriginalityClaim,total=int,page=int,page_size=int}}
+// @Router /api/v3/admin/originality-claims [get]
+func (h *ArticleDuplicateHandler) GetOriginalityClaims(c *gin.Context) {
+	status, _ := strconv.Atoi(c.DefaultQuery("status", "0"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	claims, total, err := h.duplicateService.GetOriginalityClaims(int8(status), page, pageSize)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"claims":    claims,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// ReviewOriginalityClaim 审核原创申诉
+// @Summary 审核原创申诉
+// @Description 通过后该文章的待处理查重记录全部排除，审核状态恢复为通过
+// @Tags 文章查重
+// @Accept json
+// @Produce json
+// @Param id path int true "申诉ID"
+// @Param request body service.ReviewClaimRequest true "审核结果"
+// @Success 200 {object} object{code=int}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/admin/originality-claims/{id}/review [post]
+func (h *ArticleDuplicateHandler) ReviewOriginalityClaim(c *gin.Context) {
+	claimID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的申诉ID")
+		return
+	}
+
+	var req service.ReviewClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	adminID, _ := c.Get("user_id")
+	if err := h.duplicateService.ReviewOriginalityClaim(claimID, adminID.(string), &req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArticleImportHandler 文章导入导出处理器
+type ArticleImportHandler struct {
+	importService service.ArticleImportService
+}
+
+// NewArticleImportHandler 创建文章导入导出处理器实例
+func NewArticleImportHandler(importService service.ArticleImportService) *ArticleImportHandler {
+	return &ArticleImportHandler{
+		importService: importService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *ArticleImportHandler) RegisterRoutes(r *gin.Engine) {
+	v3 := r.Group("/api/v3")
+	{
+		// 需要认证的接口
+		auth := v3.Group("")
+		auth.Use(middleware.AuthMiddleware())
+		{
+			auth.POST("/imports", h.CreateImportJob)        // 上传导入包（Markdown压缩包/WXR）
+			auth.GET("/imports", h.GetImportJobs)           // 我的导入任务列表
+			auth.GET("/imports/:id", h.GetImportJob)        // 导入任务进度
+			auth.GET("/exports/articles", h.ExportArticles) // 导出全部文章为Markdown压缩包
+		}
+	}
+}
+
+// ==================== 导入 ====================
+
+// CreateImportJob 创建导入任务
+// @Summary 导入文章
+// @Description 上传Markdown压缩包（支持Hexo/Hugo front matter，图片随包上传）或WordPress WXR导出文件（.xml或包含.xml的压缩包），后台异步导入
+// @Tags 文章导入导出
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "导入包（.zip/.xml）"
+// @Param as_draft formData bool false "是否全部导入为草稿"
+// @Success 200 {object} object{code=int,data=model.ArticleImportJob}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/imports [post]
+func (h *ArticleImportHandler) CreateImportJob(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "请选择要导入的文件")
+		return
+	}
+	asDraft, _ := strconv.ParseBool(c.DefaultPostForm("as_draft", "false"))
+
+	userID, _ := c.Get("user_id")
+	job, err := h.importService.CreateImportJob(c.Request.Context(), userID.(string), file, asDraft)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, job)
+}
+
+// GetImportJobs 获取我的导入任务列表
+// @Summary 获取我的导入任务列表
+// @Tags 文章导入导出
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{jobs=[]model.ArticleImportJob,total=int,page=int,page_size=int}}
+// @Router /api/v3/imports [get]
+func (h *ArticleImportHandler) GetImportJobs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 20
+	}
+
+	userID, _ := c.Get("user_id")
+	jobs, total, err := h.importService.GetUserImportJobs(userID.(string), page, pageSize)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"jobs":      jobs,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// GetImportJob 获取导入任务进度
+// @Summary 获取导入任务进度
+// @Description 返回总数、已处理数、成功/失败数以及逐篇导入结果
+// @Tags 文章导入导出
+// @Produce json
+// @Param id path int true "导入任务ID"
+// @Success 200 {object} object{code=int,data=model.ArticleImportJob}
+// @Failure 404 {object} object{code=int,message=string}
+// @Router /api/v3/imports/{id} [get]
+func (h *ArticleImportHandler) GetImportJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	job, err := h.importService.GetImportJob(jobID, userID.(string))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, job)
+}
+
+// ==================== 导出 ====================
+
+// ExportArticles 导出全部文章
+// @Summary 导出全部文章
+// @Description 将当前用户的文章（posts/）与草稿（drafts/）导出为带front matter的Markdown压缩包
+// @Tags 文章导入导出
+// @Produce application/zip
+// @Success 200 {file} file
+// @Router /api/v3/exports/articles [get]
+func (h *ArticleImportHandler) ExportArticles(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	filename := fmt.Sprintf("articles-%s.zip", time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+
+	// 流式写出，响应头发出后无法再返回JSON错误
+	if err := h.importService.ExportUserArticles(userID.(string), c.Writer); err != nil {
+		log.Printf("⚠️ 导出文章失败: user=%s, %v", userID, err)
+	}
+}
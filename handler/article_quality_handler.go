@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArticleQualityHandler 文章质量评分处理器
+type ArticleQualityHandler struct {
+	qualityService service.ArticleQualityService
+}
+
+// NewArticleQualityHandler 创建文章质量评分处理器实例
+func NewArticleQualityHandler(qualityService service.ArticleQualityService) *ArticleQualityHandler {
+	return &ArticleQualityHandler{
+		qualityService: qualityService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *ArticleQualityHandler) RegisterRoutes(r *gin.Engine) {
+	v3 := r.Group("/api/v3")
+	{
+		// 作者接口
+		auth := v3.Group("")
+		auth.Use(middleware.AuthMiddleware())
+		{
+			auth.GET("/articles/:id/quality", h.GetQualityReport) // 质量评分明细
+		}
+
+		// 管理员接口
+		admin := v3.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+		{
+			admin.POST("/articles/:id/quality/rescore", h.RescoreArticle) // 重新评分
+		}
+	}
+}
+
+// GetQualityReport 获取文章质量评分明细
+// @Summary 获取文章质量评分明细
+// @Description 返回总分及篇幅、结构、可读性、外链、敏感内容、作者历史、早期互动各维度的得分与说明（仅作者可见）
+// @Tags 文章质量
+// @Produce json
+// @Param id path int true "文章ID"
+// @Success 200 {object} object{code=int,data=model.ArticleQualityScore}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/articles/{id}/quality [get]
+func (h *ArticleQualityHandler) GetQualityReport(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	report, err := h.qualityService.GetQualityReport(articleID, userID.(string))
+	if err != nil {
+		response.Forbidden(c, err.Error())
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// RescoreArticle 重新计算文章质量分
+// @Summary 重新计算文章质量分
+// @Tags 文章质量
+// @Produce json
+// @Param id path int true "文章ID"
+// @Success 200 {object} object{code=int,data=model.ArticleQualityScore}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/admin/articles/{id}/quality/rescore [post]
+func (h *ArticleQualityHandler) RescoreArticle(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	report, err := h.qualityService.ScoreArticle(articleID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, report)
+}
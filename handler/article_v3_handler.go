@@ -2,11 +2,24 @@ package handler
 
 import (
 	"astronomer-gin/middleware"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
 	"astronomer-gin/pkg/response"
 	"astronomer-gin/service"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// visitorCookieName 匿名访客标识Cookie
+	visitorCookieName = "vid"
+	// visitorCookieMaxAge 匿名访客Cookie有效期（1年）
+	visitorCookieMaxAge = 365 * 24 * 60 * 60
 )
 
 // ArticleV3Handler 企业级文章处理器
@@ -47,9 +60,10 @@ func (h *ArticleV3Handler) RegisterRoutes(r *gin.Engine) {
 		auth.Use(middleware.AuthMiddleware())
 		{
 			// 文章管理
-			auth.POST("/articles", h.CreateArticle)       // 发布文章
-			auth.PUT("/articles/:id", h.UpdateArticle)    // 更新文章
-			auth.DELETE("/articles/:id", h.DeleteArticle) // 删除文章
+			auth.POST("/articles", h.CreateArticle)         // 发布文章
+			auth.PUT("/articles/:id", h.UpdateArticle)      // 更新文章
+			auth.DELETE("/articles/:id", h.DeleteArticle)   // 删除文章
+			auth.POST("/articles/:id/draft", h.EditArticle) // 编辑已发布文章（获取或创建关联草稿）
 
 			// 点赞和收藏
 			auth.POST("/articles/:id/like", h.LikeArticle)             // 点赞文章
@@ -58,6 +72,9 @@ func (h *ArticleV3Handler) RegisterRoutes(r *gin.Engine) {
 			auth.DELETE("/articles/:id/favorite", h.UnfavoriteArticle) // 取消收藏
 			//auth.POST("/articles/:id/share", h.ShareArticle)       // 分享文章
 
+			// 数据统计
+			auth.GET("/articles/:id/stats", h.GetArticleStats) // 文章统计详情（仅作者）
+
 			// 草稿管理
 			auth.POST("/drafts", h.SaveDraft)                // 保存草稿
 			auth.GET("/drafts", h.GetUserDrafts)             // 我的草稿列表
@@ -66,6 +83,11 @@ func (h *ArticleV3Handler) RegisterRoutes(r *gin.Engine) {
 			auth.POST("/drafts/:id/publish", h.PublishDraft) // 发布草稿
 			auth.DELETE("/drafts/:id", h.DeleteDraft)        // 删除草稿
 
+			// 草稿快照（找回被覆盖的内容）
+			auth.GET("/drafts/:id/snapshots", h.GetDraftSnapshots)                         // 快照列表
+			auth.GET("/drafts/:id/snapshots/:snapshotId", h.GetDraftSnapshot)              // 快照详情
+			auth.POST("/drafts/:id/snapshots/:snapshotId/restore", h.RestoreDraftSnapshot) // 用快照覆盖草稿
+
 			// 话题管理
 			auth.POST("/topics", h.CreateTopic)                // 创建话题
 			auth.POST("/topics/:id/follow", h.FollowTopic)     // 关注话题
@@ -151,12 +173,24 @@ func (h *ArticleV3Handler) GetArticleDetail(c *gin.Context) {
 		return
 	}
 
-	var viewerID string
+	visitor := &service.ViewVisitor{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
 	if userID, exists := c.Get("user_id"); exists {
-		viewerID = userID.(string)
+		visitor.UserID = userID.(string)
+	}
+
+	// 匿名访客Cookie（用于浏览量去重）
+	if vid, err := c.Cookie(visitorCookieName); err == nil && vid != "" {
+		visitor.VisitorID = vid
+	} else if visitor.UserID == "" {
+		visitor.VisitorID = uuid.New().String()
+		visitor.NewVisitor = true
+		c.SetCookie(visitorCookieName, visitor.VisitorID, visitorCookieMaxAge, "/", "", false, true)
 	}
 
-	detail, err := h.articleService.GetArticleDetail(articleID, viewerID)
+	detail, err := h.articleService.GetArticleDetail(articleID, visitor)
 	if err != nil {
 		response.NotFound(c, err.Error())
 		return
@@ -229,6 +263,7 @@ func (h *ArticleV3Handler) SaveDraft(c *gin.Context) {
 		return
 	}
 
+	setDraftETag(c, draft)
 	response.Success(c, draft)
 }
 
@@ -246,13 +281,23 @@ func (h *ArticleV3Handler) UpdateDraft(c *gin.Context) {
 		return
 	}
 
+	if req.Version == 0 {
+		req.Version = parseIfMatchVersion(c)
+	}
+	if req.Version == 0 {
+		response.BadRequest(c, "缺少草稿版本号（version字段或If-Match请求头）")
+		return
+	}
+
 	userID, _ := c.Get("user_id")
-	if err := h.articleService.UpdateDraft(draftID, userID.(string), &req); err != nil {
-		response.ServerError(c, err.Error())
+	draft, err := h.articleService.UpdateDraft(draftID, userID.(string), &req)
+	if err != nil {
+		h.handleDraftError(c, err)
 		return
 	}
 
-	response.Success(c, nil)
+	setDraftETag(c, draft)
+	response.Success(c, draft)
 }
 
 // GetDraftDetail 获取草稿详情
@@ -270,6 +315,7 @@ func (h *ArticleV3Handler) GetDraftDetail(c *gin.Context) {
 		return
 	}
 
+	setDraftETag(c, draft)
 	response.Success(c, draft)
 }
 
@@ -328,6 +374,129 @@ func (h *ArticleV3Handler) DeleteDraft(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// EditArticle 编辑已发布文章（返回关联草稿，修改在重新发布草稿后才生效）
+func (h *ArticleV3Handler) EditArticle(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	draft, err := h.articleService.EditArticleDraft(articleID, userID.(string))
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	setDraftETag(c, draft)
+	response.Success(c, draft)
+}
+
+// GetDraftSnapshots 获取草稿快照列表
+func (h *ArticleV3Handler) GetDraftSnapshots(c *gin.Context) {
+	draftID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的草稿ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	snapshots, err := h.articleService.GetDraftSnapshots(draftID, userID.(string))
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, snapshots)
+}
+
+// GetDraftSnapshot 获取草稿快照详情
+func (h *ArticleV3Handler) GetDraftSnapshot(c *gin.Context) {
+	draftID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的草稿ID")
+		return
+	}
+	snapshotID, err := strconv.ParseUint(c.Param("snapshotId"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的快照ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	snapshot, err := h.articleService.GetDraftSnapshot(draftID, snapshotID, userID.(string))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, snapshot)
+}
+
+// RestoreDraftSnapshot 用快照内容覆盖草稿
+func (h *ArticleV3Handler) RestoreDraftSnapshot(c *gin.Context) {
+	draftID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的草稿ID")
+		return
+	}
+	snapshotID, err := strconv.ParseUint(c.Param("snapshotId"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的快照ID")
+		return
+	}
+
+	var req struct {
+		Version  int    `json:"version"`
+		DeviceID string `json:"device_id"`
+	}
+	_ = c.ShouldBindJSON(&req)
+	if req.Version == 0 {
+		req.Version = parseIfMatchVersion(c)
+	}
+	if req.Version == 0 {
+		response.BadRequest(c, "缺少草稿版本号（version字段或If-Match请求头）")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	draft, err := h.articleService.RestoreDraftSnapshot(draftID, snapshotID, userID.(string), req.Version, req.DeviceID)
+	if err != nil {
+		h.handleDraftError(c, err)
+		return
+	}
+
+	setDraftETag(c, draft)
+	response.Success(c, draft)
+}
+
+// handleDraftError 版本冲突时返回服务器当前版本，其余按普通错误处理
+func (h *ArticleV3Handler) handleDraftError(c *gin.Context, err error) {
+	var conflict *service.DraftConflictError
+	if errors.As(err, &conflict) {
+		setDraftETag(c, conflict.Server)
+		response.Conflict(c, conflict.Error(), conflict.Server)
+		return
+	}
+	response.ServerError(c, err.Error())
+}
+
+// setDraftETag 以草稿版本号作为ETag
+func setDraftETag(c *gin.Context, draft *model.ArticleDraft) {
+	c.Header("ETag", fmt.Sprintf("\"%d\"", draft.Version))
+}
+
+// parseIfMatchVersion 从If-Match请求头解析草稿版本号（兼容弱校验W/前缀），无效时返回0
+func parseIfMatchVersion(c *gin.Context) int {
+	value := strings.TrimPrefix(strings.TrimSpace(c.GetHeader("If-Match")), "W/")
+	version, err := strconv.Atoi(strings.Trim(value, `"`))
+	if err != nil || version < 0 {
+		return 0
+	}
+	return version
+}
+
 // ==================== 分类相关接口 ====================
 
 // CreateCategory 创建分类
@@ -575,6 +744,10 @@ func (h *ArticleV3Handler) CreateTopic(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	topic, err := h.articleService.CreateTopic(req.Name, req.Description, userID.(string))
 	if err != nil {
+		if errors.Is(err, constant.ErrLevelTooLowForTopic) {
+			response.Forbidden(c, err.Error())
+			return
+		}
 		response.ServerError(c, err.Error())
 		return
 	}
@@ -757,3 +930,23 @@ func (h *ArticleV3Handler) UnfavoriteArticle(c *gin.Context) {
 //
 //	response.Success(c, nil)
 //}
+
+// ==================== 统计分析接口 ====================
+
+// GetArticleStats 获取文章统计详情（阅读进度、停留时间、来源/设备/地域分布）
+func (h *ArticleV3Handler) GetArticleStats(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	stats, err := h.articleService.GetArticleStats(articleID, userID.(string))
+	if err != nil {
+		response.Forbidden(c, err.Error())
+		return
+	}
+
+	response.Success(c, stats)
+}
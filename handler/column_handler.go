@@ -1,20 +1,26 @@
 package handler
 
 import (
+	"astronomer-gin/pkg/constant"
 	"astronomer-gin/pkg/response"
 	"astronomer-gin/service"
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ColumnHandler struct {
-	columnService service.ColumnService
+	columnService  service.ColumnService
+	exportService  service.ColumnExportService
+	chapterService service.ColumnChapterService
 }
 
-func NewColumnHandler(columnService service.ColumnService) *ColumnHandler {
+func NewColumnHandler(columnService service.ColumnService, exportService service.ColumnExportService, chapterService service.ColumnChapterService) *ColumnHandler {
 	return &ColumnHandler{
-		columnService: columnService,
+		columnService:  columnService,
+		exportService:  exportService,
+		chapterService: chapterService,
 	}
 }
 
@@ -546,3 +552,99 @@ func (h *ColumnHandler) UpdateArticlePosition(c *gin.Context) {
 
 	response.Success(c, nil)
 }
+
+// Export 导出专栏电子书
+// @Summary 导出专栏电子书
+// @Description 按专栏顺序导出为EPUB3（含封面、目录与内嵌图片）或适合打印为PDF的单页HTML。首次请求或专栏变更后异步生成，status=2时file_url为下载地址，其余状态请稍后重试
+// @Tags 专栏模块
+// @Produce json
+// @Param id path int true "专栏ID"
+// @Param format query string false "导出格式：epub/html" default(epub)
+// @Success 200 {object} object{code=int,data=model.ColumnExport}
+// @Failure 400 {object} object{code=int,message=string}
+// @Security Bearer
+// @Router /api/v3/columns/{id}/export [get]
+func (h *ColumnHandler) Export(c *gin.Context) {
+	columnID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "参数错误")
+		return
+	}
+	format := c.DefaultQuery("format", "epub")
+
+	userID, _ := c.Get("user_id")
+	export, err := h.exportService.RequestExport(c.Request.Context(), columnID, format, userID.(string))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, export)
+}
+
+// GetReadingProgress 获取专栏阅读进度
+// @Summary 获取专栏阅读进度
+// @Description 返回当前用户在专栏中的已读章节数、最近阅读的章节和继续阅读的章节；阅读专栏文章详情时自动记录
+// @Tags 专栏模块
+// @Produce json
+// @Param id path int true "专栏ID"
+// @Success 200 {object} object{code=int,data=service.ColumnReadProgress}
+// @Failure 404 {object} object{code=int,message=string}
+// @Security Bearer
+// @Router /api/v3/columns/{id}/progress [get]
+func (h *ColumnHandler) GetReadingProgress(c *gin.Context) {
+	columnID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "参数错误")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	progress, err := h.chapterService.GetProgress(columnID, userID.(string))
+	if err != nil {
+		if errors.Is(err, constant.ErrResourceNotFound) {
+			response.NotFound(c, "专栏不存在")
+			return
+		}
+		response.ServerError(c, "获取阅读进度失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, progress)
+}
+
+// GetReadingList 获取我在读的专栏
+// @Summary 获取我在读的专栏
+// @Description 按最近阅读时间倒序返回读过的专栏及阅读进度
+// @Tags 专栏模块
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} object{code=int,data=object{columns=[]service.ColumnReadProgress,total=int,page=int,page_size=int}}
+// @Security Bearer
+// @Router /api/v3/columns/reading [get]
+func (h *ColumnHandler) GetReadingList(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 10
+	}
+
+	userID, _ := c.Get("user_id")
+	list, total, err := h.chapterService.ListProgress(userID.(string), page, pageSize)
+	if err != nil {
+		response.ServerError(c, "获取阅读进度失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"columns":   list,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"astronomer-gin/middleware"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
 	"astronomer-gin/pkg/response"
 	"astronomer-gin/service"
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -12,12 +15,16 @@ import (
 // CommentV3Handler 企业级评论处理器
 type CommentV3Handler struct {
 	commentService service.CommentV3Service
+	riskService    service.RiskService
+	auditService   service.AuditService
 }
 
 // NewCommentV3Handler 创建评论处理器实例
-func NewCommentV3Handler(commentService service.CommentV3Service) *CommentV3Handler {
+func NewCommentV3Handler(commentService service.CommentV3Service, riskService service.RiskService, auditService service.AuditService) *CommentV3Handler {
 	return &CommentV3Handler{
 		commentService: commentService,
+		riskService:    riskService,
+		auditService:   auditService,
 	}
 }
 
@@ -94,11 +101,21 @@ func (h *CommentV3Handler) CreateRootComment(c *gin.Context) {
 	req.IP = c.ClientIP()
 	req.UserAgent = c.GetHeader("User-Agent")
 
+	subject, ok := h.checkCommentRisk(c, req.UserID, req.IP, req.CaptchaID, req.CaptchaValue)
+	if !ok {
+		return
+	}
+
 	comment, err := h.commentService.CreateRootComment(&req)
 	if err != nil {
+		if errors.Is(err, constant.ErrCommentNotPermitted) || errors.Is(err, constant.ErrLevelTooLowForLink) {
+			response.Forbidden(c, err.Error())
+			return
+		}
 		response.ServerError(c, err.Error())
 		return
 	}
+	h.riskService.RecordSuccess(service.RiskActionComment, subject)
 
 	response.Success(c, comment)
 }
@@ -116,15 +133,45 @@ func (h *CommentV3Handler) CreateReplyComment(c *gin.Context) {
 	req.IP = c.ClientIP()
 	req.UserAgent = c.GetHeader("User-Agent")
 
+	subject, ok := h.checkCommentRisk(c, req.UserID, req.IP, req.CaptchaID, req.CaptchaValue)
+	if !ok {
+		return
+	}
+
 	comment, err := h.commentService.CreateReplyComment(&req)
 	if err != nil {
+		if errors.Is(err, constant.ErrCommentNotPermitted) || errors.Is(err, constant.ErrLevelTooLowForLink) {
+			response.Forbidden(c, err.Error())
+			return
+		}
 		response.ServerError(c, err.Error())
 		return
 	}
+	h.riskService.RecordSuccess(service.RiskActionComment, subject)
 
 	response.Success(c, comment)
 }
 
+// checkCommentRisk 评论频率检查，过于频繁时要求图形验证码（data.captcha_required=true）
+func (h *CommentV3Handler) checkCommentRisk(c *gin.Context, userID, ip, captchaID, captchaValue string) (*service.RiskSubject, bool) {
+	subject := &service.RiskSubject{
+		UserID:       userID,
+		IP:           ip,
+		CaptchaID:    captchaID,
+		CaptchaValue: captchaValue,
+	}
+	err := h.riskService.Check(service.RiskActionComment, subject)
+	switch {
+	case err == nil:
+		return subject, true
+	case errors.Is(err, constant.ErrCaptchaRequired), errors.Is(err, constant.ErrCaptchaInvalid):
+		response.ErrorWithData(c, response.CodeBadRequest, err.Error(), gin.H{"captcha_required": true})
+	default:
+		response.Error(c, response.CodeTooMany, err.Error())
+	}
+	return nil, false
+}
+
 // DeleteComment 删除评论
 func (h *CommentV3Handler) DeleteComment(c *gin.Context) {
 	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
@@ -403,6 +450,12 @@ func (h *CommentV3Handler) HandleReport(c *gin.Context) {
 		response.ServerError(c, err.Error())
 		return
 	}
+	h.auditService.Record(auditContext(c), &model.AuditLog{
+		Action:     model.AuditActionReportHandle,
+		TargetType: model.AuditTargetReport,
+		TargetID:   c.Param("id"),
+		After:      model.JSONMap{"result": req.Result, "approved": req.Approved},
+	})
 
 	response.Success(c, nil)
 }
@@ -538,6 +591,11 @@ func (h *CommentV3Handler) BatchDeleteComments(c *gin.Context) {
 		response.ServerError(c, err.Error())
 		return
 	}
+	h.auditService.Record(auditContext(c), &model.AuditLog{
+		Action:     model.AuditActionCommentBatchDelete,
+		TargetType: model.AuditTargetComment,
+		After:      model.JSONMap{"comment_ids": req.CommentIDs},
+	})
 
 	response.Success(c, nil)
 }
@@ -559,6 +617,11 @@ func (h *CommentV3Handler) BatchFoldComments(c *gin.Context) {
 		response.ServerError(c, err.Error())
 		return
 	}
+	h.auditService.Record(auditContext(c), &model.AuditLog{
+		Action:     model.AuditActionCommentBatchFold,
+		TargetType: model.AuditTargetComment,
+		After:      model.JSONMap{"comment_ids": req.CommentIDs},
+	})
 
 	response.Success(c, nil)
 }
@@ -595,12 +658,28 @@ func (h *CommentV3Handler) AddSensitiveWord(c *gin.Context) {
 		response.ServerError(c, err.Error())
 		return
 	}
+	h.auditService.Record(auditContext(c), &model.AuditLog{
+		Action:     model.AuditActionSensitiveWordCreate,
+		TargetType: model.AuditTargetSensitiveWord,
+		After:      model.JSONMap{"word": req.Word, "level": req.Level, "action": req.Action},
+	})
 
 	response.Success(c, nil)
 }
 
 // ==================== 辅助方法 ====================
 
+// auditContext 从请求中提取审计上下文
+func auditContext(c *gin.Context) *service.AuditContext {
+	return &service.AuditContext{
+		ActorID:   c.GetString("user_id"),
+		ActorRole: c.GetString("admin_role"),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("request_id"),
+	}
+}
+
 func parseUint64(s string) uint64 {
 	val, _ := strconv.ParseUint(s, 10, 64)
 	return val
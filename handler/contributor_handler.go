@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContributorHandler 共同创作处理器
+type ContributorHandler struct {
+	contributorService service.ContributorService
+	auditService       service.AuditService
+}
+
+// NewContributorHandler 创建共同创作处理器实例
+func NewContributorHandler(contributorService service.ContributorService, auditService service.AuditService) *ContributorHandler {
+	return &ContributorHandler{
+		contributorService: contributorService,
+		auditService:       auditService,
+	}
+}
+
+// UpdateContributorRoleRequest 调整协作者角色请求
+type UpdateContributorRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// RegisterRoutes 注册路由
+func (h *ContributorHandler) RegisterRoutes(r *gin.Engine) {
+	auth := r.Group("/api/v3")
+	auth.Use(middleware.AuthMiddleware())
+	{
+		// 文章协作者
+		auth.GET("/articles/:id/contributors", h.ListArticleContributors)
+		auth.POST("/articles/:id/contributors", h.InviteArticleContributor)
+		auth.PUT("/articles/:id/contributors/:userId", h.UpdateArticleContributor)
+		auth.DELETE("/articles/:id/contributors/:userId", h.RevokeArticleContributor)
+
+		// 专栏协作者
+		auth.GET("/columns/:id/contributors", h.ListColumnContributors)
+		auth.POST("/columns/:id/contributors", h.InviteColumnContributor)
+		auth.PUT("/columns/:id/contributors/:userId", h.UpdateColumnContributor)
+		auth.DELETE("/columns/:id/contributors/:userId", h.RevokeColumnContributor)
+
+		// 收到的邀请
+		auth.GET("/contributions/invitations", h.ListInvitations)
+		auth.POST("/contributions/invitations/:id/accept", h.AcceptInvitation)
+		auth.POST("/contributions/invitations/:id/decline", h.DeclineInvitation)
+	}
+}
+
+// ==================== 文章协作者 ====================
+
+// ListArticleContributors 获取文章协作者
+// @Summary 获取文章协作者
+// @Description 返回文章的协作者和待接受的邀请，仅作者和协作者可查看
+// @Tags 共同创作
+// @Produce json
+// @Param id path int true "文章ID"
+// @Success 200 {object} object{code=int,data=[]model.Contributor}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/articles/{id}/contributors [get]
+func (h *ContributorHandler) ListArticleContributors(c *gin.Context) {
+	h.listContributors(c, model.ContributorTargetArticle)
+}
+
+// InviteArticleContributor 邀请文章协作者
+// @Summary 邀请文章协作者
+// @Description 作者或共同所有者邀请用户以owner/editor/reviewer身份参与文章，被邀请人接受后生效；只有主作者可以邀请共同所有者
+// @Tags 共同创作
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param body body service.InviteContributorRequest true "邀请信息"
+// @Success 200 {object} object{code=int,data=model.Contributor}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/articles/{id}/contributors [post]
+func (h *ContributorHandler) InviteArticleContributor(c *gin.Context) {
+	h.invite(c, model.ContributorTargetArticle)
+}
+
+// UpdateArticleContributor 调整文章协作者角色
+// @Summary 调整文章协作者角色
+// @Tags 共同创作
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param userId path string true "协作者用户ID"
+// @Param body body UpdateContributorRoleRequest true "角色"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/articles/{id}/contributors/{userId} [put]
+func (h *ContributorHandler) UpdateArticleContributor(c *gin.Context) {
+	h.updateRole(c, model.ContributorTargetArticle)
+}
+
+// RevokeArticleContributor 移除文章协作者
+// @Summary 移除文章协作者
+// @Description 所有者移除协作者或撤回邀请；协作者传自己的ID表示退出
+// @Tags 共同创作
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param userId path string true "协作者用户ID"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/articles/{id}/contributors/{userId} [delete]
+func (h *ContributorHandler) RevokeArticleContributor(c *gin.Context) {
+	h.revoke(c, model.ContributorTargetArticle)
+}
+
+// ==================== 专栏协作者 ====================
+
+// ListColumnContributors 获取专栏协作者
+// @Summary 获取专栏协作者
+// @Description 返回专栏的协作者和待接受的邀请，仅作者和协作者可查看
+// @Tags 共同创作
+// @Produce json
+// @Param id path int true "专栏ID"
+// @Success 200 {object} object{code=int,data=[]model.Contributor}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/columns/{id}/contributors [get]
+func (h *ContributorHandler) ListColumnContributors(c *gin.Context) {
+	h.listContributors(c, model.ContributorTargetColumn)
+}
+
+// InviteColumnContributor 邀请专栏协作者
+// @Summary 邀请专栏协作者
+// @Description 作者或共同所有者邀请用户参与专栏，编辑可以管理专栏文章
+// @Tags 共同创作
+// @Accept json
+// @Produce json
+// @Param id path int true "专栏ID"
+// @Param body body service.InviteContributorRequest true "邀请信息"
+// @Success 200 {object} object{code=int,data=model.Contributor}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/columns/{id}/contributors [post]
+func (h *ContributorHandler) InviteColumnContributor(c *gin.Context) {
+	h.invite(c, model.ContributorTargetColumn)
+}
+
+// UpdateColumnContributor 调整专栏协作者角色
+// @Summary 调整专栏协作者角色
+// @Tags 共同创作
+// @Accept json
+// @Produce json
+// @Param id path int true "专栏ID"
+// @Param userId path string true "协作者用户ID"
+// @Param body body UpdateContributorRoleRequest true "角色"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/columns/{id}/contributors/{userId} [put]
+func (h *ContributorHandler) UpdateColumnContributor(c *gin.Context) {
+	h.updateRole(c, model.ContributorTargetColumn)
+}
+
+// RevokeColumnContributor 移除专栏协作者
+// @Summary 移除专栏协作者
+// @Tags 共同创作
+// @Produce json
+// @Param id path int true "专栏ID"
+// @Param userId path string true "协作者用户ID"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/columns/{id}/contributors/{userId} [delete]
+func (h *ContributorHandler) RevokeColumnContributor(c *gin.Context) {
+	h.revoke(c, model.ContributorTargetColumn)
+}
+
+// ==================== 收到的邀请 ====================
+
+// ListInvitations 获取收到的协作邀请
+// @Summary 获取收到的协作邀请
+// @Tags 共同创作
+// @Produce json
+// @Success 200 {object} object{code=int,data=[]model.Contributor}
+// @Router /api/v3/contributions/invitations [get]
+func (h *ContributorHandler) ListInvitations(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	invitations, err := h.contributorService.ListInvitations(userID.(string))
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+	response.Success(c, invitations)
+}
+
+// AcceptInvitation 接受协作邀请
+// @Summary 接受协作邀请
+// @Tags 共同创作
+// @Produce json
+// @Param id path int true "邀请ID"
+// @Success 200 {object} object{code=int,message=string}
+// @Router /api/v3/contributions/invitations/{id}/accept [post]
+func (h *ContributorHandler) AcceptInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的邀请ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.contributorService.AcceptInvitation(invitationID, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// DeclineInvitation 拒绝协作邀请
+// @Summary 拒绝协作邀请
+// @Tags 共同创作
+// @Produce json
+// @Param id path int true "邀请ID"
+// @Success 200 {object} object{code=int,message=string}
+// @Router /api/v3/contributions/invitations/{id}/decline [post]
+func (h *ContributorHandler) DeclineInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的邀请ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.contributorService.DeclineInvitation(invitationID, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// ==================== 通用处理 ====================
+
+func (h *ContributorHandler) listContributors(c *gin.Context, targetType string) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	contributors, err := h.contributorService.ListContributors(targetType, targetID, userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Success(c, contributors)
+}
+
+func (h *ContributorHandler) invite(c *gin.Context, targetType string) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req service.InviteContributorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	contributor, err := h.contributorService.Invite(targetType, targetID, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	h.recordContributor(c, model.AuditActionContributorInvite, targetType, targetID, nil,
+		model.JSONMap{"user_id": req.UserID, "role": req.Role})
+	response.Success(c, contributor)
+}
+
+func (h *ContributorHandler) updateRole(c *gin.Context, targetType string) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req UpdateContributorRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	previous, err := h.contributorService.UpdateRole(targetType, targetID, userID.(string), c.Param("userId"), req.Role)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	h.recordContributor(c, model.AuditActionContributorRole, targetType, targetID,
+		model.JSONMap{"user_id": previous.UserID, "role": previous.Role},
+		model.JSONMap{"user_id": previous.UserID, "role": req.Role})
+	response.Success(c, nil)
+}
+
+func (h *ContributorHandler) revoke(c *gin.Context, targetType string) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	removed, err := h.contributorService.Revoke(targetType, targetID, userID.(string), c.Param("userId"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	h.recordContributor(c, model.AuditActionContributorRevoke, targetType, targetID,
+		model.JSONMap{"user_id": removed.UserID, "role": removed.Role, "status": removed.Status}, nil)
+	response.Success(c, nil)
+}
+
+// recordContributor 记录协作者角色变更审计日志（操作者为当前用户）
+func (h *ContributorHandler) recordContributor(c *gin.Context, action, targetType string, targetID uint64, before, after model.JSONMap) {
+	h.auditService.Record(auditContext(c), &model.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   strconv.FormatUint(targetID, 10),
+		Before:     before,
+		After:      after,
+	})
+}
+
+// handleError 按错误类型返回对应状态
+func (h *ContributorHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, constant.ErrResourceNotFound),
+		errors.Is(err, constant.ErrArticleNotFound),
+		errors.Is(err, constant.ErrUserNotExist):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, constant.ErrPermissionDenied):
+		response.Forbidden(c, err.Error())
+	default:
+		response.BadRequest(c, err.Error())
+	}
+}
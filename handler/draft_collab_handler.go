@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/response"
+	wsLib "astronomer-gin/pkg/websocket"
+	"astronomer-gin/service"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DraftCollabHandler 草稿协同编辑处理器
+//
+// 协同消息复用 /api/v3/ws/chat 的WebSocket连接：
+//
+//	draft_join   {draft_id}                               -> draft_joined {draft_id, content, revision, version, participants}
+//	draft_leave  {draft_id}
+//	draft_op     {draft_id, revision, ops}                -> draft_ack {draft_id, revision}，其他成员收到 draft_op
+//	draft_cursor {draft_id, revision, cursor, selection_end} -> 其他成员收到 draft_cursor
+//
+// 服务端推送 draft_presence（成员变化）和 draft_saved（合并文本已写回草稿）；
+// 出错时推送 error，code=409 表示修订落后过多，需要重新 draft_join。
+type DraftCollabHandler struct {
+	collabService service.DraftCollabService
+	hub           *wsLib.Hub
+}
+
+// NewDraftCollabHandler 创建草稿协同编辑处理器实例
+func NewDraftCollabHandler(collabService service.DraftCollabService, hub *wsLib.Hub) *DraftCollabHandler {
+	return &DraftCollabHandler{
+		collabService: collabService,
+		hub:           hub,
+	}
+}
+
+// draftRoomRequest 加入/离开房间
+type draftRoomRequest struct {
+	DraftID uint64 `json:"draft_id"`
+}
+
+// draftOpRequest 编辑操作
+type draftOpRequest struct {
+	DraftID  uint64                 `json:"draft_id"`
+	Revision int                    `json:"revision"`
+	Ops      *service.TextOperation `json:"ops"`
+}
+
+// draftCursorRequest 光标/选区
+type draftCursorRequest struct {
+	DraftID      uint64 `json:"draft_id"`
+	Revision     int    `json:"revision"`
+	Cursor       int    `json:"cursor"`
+	SelectionEnd int    `json:"selection_end"`
+}
+
+// RegisterRoutes 注册路由和WebSocket消息处理
+func (h *DraftCollabHandler) RegisterRoutes(r *gin.Engine) {
+	h.hub.HandleFunc(wsLib.MessageTypeDraftJoin, h.handleJoin)
+	h.hub.HandleFunc(wsLib.MessageTypeDraftLeave, h.handleLeave)
+	h.hub.HandleFunc(wsLib.MessageTypeDraftOp, h.handleOp)
+	h.hub.HandleFunc(wsLib.MessageTypeDraftCursor, h.handleCursor)
+
+	auth := r.Group("/api/v3")
+	auth.Use(middleware.AuthMiddleware())
+	{
+		auth.GET("/drafts/:id/collab", h.GetCollabState) // 协同房间状态
+	}
+}
+
+// GetCollabState 获取草稿协同状态
+// @Summary 获取草稿协同状态
+// @Description 返回草稿当前的协同文本、修订号和在线成员；实时编辑通过WebSocket的draft_*消息进行
+// @Tags 草稿协同
+// @Produce json
+// @Param id path int true "草稿ID"
+// @Success 200 {object} object{code=int,data=service.DraftCollabState}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/drafts/{id}/collab [get]
+func (h *DraftCollabHandler) GetCollabState(c *gin.Context) {
+	draftID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的草稿ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	state, err := h.collabService.GetState(draftID, userID.(string))
+	if err != nil {
+		switch {
+		case errors.Is(err, constant.ErrResourceNotFound):
+			response.NotFound(c, err.Error())
+		case errors.Is(err, constant.ErrPermissionDenied):
+			response.Forbidden(c, err.Error())
+		default:
+			response.BadRequest(c, err.Error())
+		}
+		return
+	}
+	response.Success(c, state)
+}
+
+// ==================== WebSocket消息 ====================
+
+func (h *DraftCollabHandler) handleJoin(client *wsLib.Client, msg *wsLib.WSMessage) {
+	var req draftRoomRequest
+	if err := msg.Bind(&req); err != nil || req.DraftID == 0 {
+		h.sendError(client, msg, response.CodeBadRequest, "无效的草稿ID")
+		return
+	}
+
+	state, err := h.collabService.Join(req.DraftID, client.UserID)
+	if err != nil {
+		h.sendServiceError(client, msg, err)
+		return
+	}
+	h.reply(client, msg, wsLib.MessageTypeDraftJoined, state)
+}
+
+func (h *DraftCollabHandler) handleLeave(client *wsLib.Client, msg *wsLib.WSMessage) {
+	var req draftRoomRequest
+	if err := msg.Bind(&req); err != nil || req.DraftID == 0 {
+		h.sendError(client, msg, response.CodeBadRequest, "无效的草稿ID")
+		return
+	}
+	h.collabService.Leave(req.DraftID, client.UserID)
+}
+
+func (h *DraftCollabHandler) handleOp(client *wsLib.Client, msg *wsLib.WSMessage) {
+	var req draftOpRequest
+	if err := msg.Bind(&req); err != nil || req.DraftID == 0 || req.Ops == nil {
+		h.sendError(client, msg, response.CodeBadRequest, "无效的编辑操作")
+		return
+	}
+
+	// 确认（draft_ack）由服务在广播前发出，保证与其他成员操作的先后顺序
+	if _, err := h.collabService.ApplyOperation(req.DraftID, client.UserID, req.Revision, req.Ops); err != nil {
+		h.sendServiceError(client, msg, err)
+	}
+}
+
+func (h *DraftCollabHandler) handleCursor(client *wsLib.Client, msg *wsLib.WSMessage) {
+	var req draftCursorRequest
+	if err := msg.Bind(&req); err != nil || req.DraftID == 0 {
+		h.sendError(client, msg, response.CodeBadRequest, "无效的光标位置")
+		return
+	}
+
+	if err := h.collabService.UpdateCursor(req.DraftID, client.UserID, req.Revision, req.Cursor, req.SelectionEnd); err != nil {
+		h.sendServiceError(client, msg, err)
+	}
+}
+
+// reply 回复发送者（带上请求的message_id便于客户端对应）
+func (h *DraftCollabHandler) reply(client *wsLib.Client, msg *wsLib.WSMessage, msgType string, data interface{}) {
+	h.hub.SendToUser(client.UserID, &wsLib.WSMessage{
+		Type:      msgType,
+		Data:      data,
+		Timestamp: time.Now().Unix(),
+		MessageID: msg.MessageID,
+	})
+}
+
+func (h *DraftCollabHandler) sendError(client *wsLib.Client, msg *wsLib.WSMessage, code int, message string) {
+	h.reply(client, msg, wsLib.MessageTypeError, wsLib.ErrorData{Code: code, Message: message})
+}
+
+// sendServiceError 按错误类型推送错误码
+func (h *DraftCollabHandler) sendServiceError(client *wsLib.Client, msg *wsLib.WSMessage, err error) {
+	switch {
+	case errors.Is(err, service.ErrDraftCollabOutOfSync):
+		h.sendError(client, msg, response.CodeConflict, err.Error())
+	case errors.Is(err, constant.ErrResourceNotFound):
+		h.sendError(client, msg, response.CodeNotFound, err.Error())
+	case errors.Is(err, constant.ErrPermissionDenied):
+		h.sendError(client, msg, response.CodeForbidden, err.Error())
+	default:
+		h.sendError(client, msg, response.CodeBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DynamicHandler 动态处理器
+type DynamicHandler struct {
+	dynamicService service.DynamicService
+}
+
+// NewDynamicHandler 创建动态处理器实例
+func NewDynamicHandler(dynamicService service.DynamicService) *DynamicHandler {
+	return &DynamicHandler{
+		dynamicService: dynamicService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *DynamicHandler) RegisterRoutes(r *gin.Engine) {
+	v3 := r.Group("/api/v3")
+	{
+		// 公开接口（无需认证）
+		v3.GET("/dynamics/:id", h.GetDynamicDetail)          // 动态详情
+		v3.GET("/dynamics/:id/reposts", h.GetDynamicReposts) // 动态的转发列表
+		v3.GET("/articles/:id/reposts", h.GetArticleReposts) // 文章的转发列表
+		v3.GET("/topics/:id/dynamics", h.GetTopicDynamics)   // 话题下的动态
+		v3.GET("/user/:id/dynamics", h.GetUserDynamics)      // 用户的动态列表
+		v3.GET("/user/:id/timeline", h.GetUserTimeline)      // 用户主页时间线（动态+文章）
+
+		// 需要认证的接口
+		auth := v3.Group("")
+		auth.Use(middleware.AuthMiddleware())
+		{
+			auth.POST("/dynamics", h.CreateDynamic)       // 发布动态（支持转发）
+			auth.DELETE("/dynamics/:id", h.DeleteDynamic) // 删除动态
+		}
+	}
+}
+
+// ==================== 动态发布与删除 ====================
+
+// CreateDynamic 发布动态
+// @Summary 发布动态
+// @Description 发布短动态，最多9张图片（multipart字段images），内容中的#话题#会自动关联；repost_type/repost_id用于转发动态或文章
+// @Tags 动态模块
+// @Accept multipart/form-data
+// @Produce json
+// @Param content formData string false "动态内容"
+// @Param topics formData []string false "话题"
+// @Param repost_type formData int false "转发类型：0-原创 1-动态 2-文章"
+// @Param repost_id formData int false "被转发对象ID"
+// @Param images formData file false "图片（可多张）"
+// @Success 200 {object} object{code=int,data=service.DynamicItem}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/dynamics [post]
+func (h *DynamicHandler) CreateDynamic(c *gin.Context) {
+	var req service.CreateDynamicRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	req.IP = c.ClientIP()
+
+	var images []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		images = form.File["images"]
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "未登录")
+		return
+	}
+
+	item, err := h.dynamicService.CreateDynamic(c.Request.Context(), userID.(string), &req, images)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// DeleteDynamic 删除动态
+// @Summary 删除动态
+// @Tags 动态模块
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/dynamics/{id} [delete]
+func (h *DynamicHandler) DeleteDynamic(c *gin.Context) {
+	dynamicID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的动态ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.dynamicService.DeleteDynamic(dynamicID, userID.(string)); err != nil {
+		response.Forbidden(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ==================== 动态查询 ====================
+
+// GetDynamicDetail 获取动态详情
+// @Summary 获取动态详情
+// @Tags 动态模块
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} object{code=int,data=service.DynamicItem}
+// @Failure 404 {object} object{code=int,message=string}
+// @Router /api/v3/dynamics/{id} [get]
+func (h *DynamicHandler) GetDynamicDetail(c *gin.Context) {
+	dynamicID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的动态ID")
+		return
+	}
+
+	item, err := h.dynamicService.GetDynamicDetail(dynamicID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// GetDynamicReposts 获取动态的转发列表
+// @Summary 获取动态的转发列表
+// @Tags 动态模块
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{dynamics=[]service.DynamicItem,total=int,page=int,page_size=int}}
+// @Router /api/v3/dynamics/{id}/reposts [get]
+func (h *DynamicHandler) GetDynamicReposts(c *gin.Context) {
+	h.getReposts(c, model.DynamicRepostTypeDynamic)
+}
+
+// GetArticleReposts 获取文章的转发列表
+// @Summary 获取文章的转发列表
+// @Tags 动态模块
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{dynamics=[]service.DynamicItem,total=int,page=int,page_size=int}}
+// @Router /api/v3/articles/{id}/reposts [get]
+func (h *DynamicHandler) GetArticleReposts(c *gin.Context) {
+	h.getReposts(c, model.DynamicRepostTypeArticle)
+}
+
+func (h *DynamicHandler) getReposts(c *gin.Context, repostType int8) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	page, pageSize := parseDynamicPage(c)
+	dynamics, total, err := h.dynamicService.GetReposts(repostType, targetID, page, pageSize)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"dynamics":  dynamics,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// GetTopicDynamics 获取话题下的动态
+// @Summary 获取话题下的动态
+// @Tags 动态模块
+// @Produce json
+// @Param id path int true "话题ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{dynamics=[]service.DynamicItem,total=int,page=int,page_size=int}}
+// @Router /api/v3/topics/{id}/dynamics [get]
+func (h *DynamicHandler) GetTopicDynamics(c *gin.Context) {
+	topicID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的话题ID")
+		return
+	}
+
+	page, pageSize := parseDynamicPage(c)
+	dynamics, total, err := h.dynamicService.GetTopicDynamics(topicID, page, pageSize)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"dynamics":  dynamics,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// GetUserDynamics 获取用户的动态列表
+// @Summary 获取用户的动态列表
+// @Tags 动态模块
+// @Produce json
+// @Param id path string true "用户ID(UUID)"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{dynamics=[]service.DynamicItem,total=int,page=int,page_size=int}}
+// @Router /api/v3/user/{id}/dynamics [get]
+func (h *DynamicHandler) GetUserDynamics(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.BadRequest(c, "用户ID不能为空")
+		return
+	}
+
+	page, pageSize := parseDynamicPage(c)
+	dynamics, total, err := h.dynamicService.GetUserDynamics(userID, page, pageSize)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"dynamics":  dynamics,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// GetUserTimeline 获取用户主页时间线
+// @Summary 获取用户主页时间线
+// @Description 动态与已发布的公开文章按时间倒序混排
+// @Tags 动态模块
+// @Produce json
+// @Param id path string true "用户ID(UUID)"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{items=[]service.TimelineItem,total=int,page=int,page_size=int}}
+// @Router /api/v3/user/{id}/timeline [get]
+func (h *DynamicHandler) GetUserTimeline(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.BadRequest(c, "用户ID不能为空")
+		return
+	}
+
+	page, pageSize := parseDynamicPage(c)
+	items, total, err := h.dynamicService.GetUserTimeline(userID, page, pageSize)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"items":     items,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// parseDynamicPage 解析分页参数
+func parseDynamicPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
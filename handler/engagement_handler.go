@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EngagementHandler 阅读参与度处理器
+type EngagementHandler struct {
+	engagementService service.EngagementService
+}
+
+// NewEngagementHandler 创建阅读参与度处理器实例
+func NewEngagementHandler(engagementService service.EngagementService) *EngagementHandler {
+	return &EngagementHandler{
+		engagementService: engagementService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *EngagementHandler) RegisterRoutes(r *gin.Engine) {
+	v3 := r.Group("/api/v3")
+	{
+		// 阅读上报（支持匿名，登录用户按用户去重）
+		v3.POST("/articles/:id/engagement", middleware.OptionalAuthMiddleware(), h.TrackEngagement)
+	}
+}
+
+// TrackEngagement 阅读上报
+// @Summary 阅读上报
+// @Description 客户端在离开页面时上报滚动深度与停留时间（可用 navigator.sendBeacon），服务端解析Referer、User-Agent与IP地域后异步汇总到文章统计
+// @Tags 文章模块V3
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param request body service.EngagementBeaconRequest true "阅读数据"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 400 {object} object{code=int,message=string}
+// @Router /api/v3/articles/{id}/engagement [post]
+func (h *EngagementHandler) TrackEngagement(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	var req service.EngagementBeaconRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.Referrer == "" {
+		req.Referrer = c.Request.Referer()
+	}
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	req.Host = c.Request.Host
+	if userID, exists := c.Get("user_id"); exists {
+		req.UserID, _ = userID.(string)
+	}
+
+	if err := h.engagementService.TrackEngagement(c.Request.Context(), articleID, &req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
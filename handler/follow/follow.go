@@ -4,6 +4,7 @@ import (
 	"astronomer-gin/pkg/constant"
 	"astronomer-gin/pkg/util"
 	"astronomer-gin/service"
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -69,10 +70,12 @@ func (h *FollowHandler) GetFollowers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
 
+	viewerID := c.GetString("user_id")
+
 	// 获取粉丝列表
-	users, total, err := h.followService.GetFollowers(userID, page, pageSize)
+	users, total, err := h.followService.GetFollowers(userID, viewerID, page, pageSize)
 	if err != nil {
-		util.InternalServerError(c, err.Error())
+		respondListError(c, err)
 		return
 	}
 
@@ -89,10 +92,12 @@ func (h *FollowHandler) GetFollowing(c *gin.Context) {
 	userID := c.Param("id")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	viewerID := c.GetString("user_id")
+
 	// 获取关注列表
-	users, total, err := h.followService.GetFollowing(userID, page, pageSize)
+	users, total, err := h.followService.GetFollowing(userID, viewerID, page, pageSize)
 	if err != nil {
-		util.InternalServerError(c, err.Error())
+		respondListError(c, err)
 		return
 	}
 
@@ -261,3 +266,12 @@ func (h *FollowHandler) GetFriendsCount(c *gin.Context) {
 		"count": count,
 	})
 }
+
+// respondListError 粉丝/关注列表查询失败：对方未公开时返回403
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, constant.ErrFollowersHidden) || errors.Is(err, constant.ErrFollowingHidden) {
+		util.Forbidden(c, err.Error())
+		return
+	}
+	util.InternalServerError(c, err.Error())
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LegacyMigrationHandler 旧版数据迁移处理器（旧链接跳转与迁移进度）
+type LegacyMigrationHandler struct {
+	migrationService service.LegacyMigrationService
+}
+
+// NewLegacyMigrationHandler 创建旧版数据迁移处理器实例
+func NewLegacyMigrationHandler(migrationService service.LegacyMigrationService) *LegacyMigrationHandler {
+	return &LegacyMigrationHandler{
+		migrationService: migrationService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *LegacyMigrationHandler) RegisterRoutes(r *gin.Engine) {
+	// 旧版链接（永久跳转到V3接口）
+	v1 := r.Group("/api/v1")
+	{
+		v1.GET("/blog/:id", h.RedirectArticle)                           // 文章详情
+		v1.GET("/comment/article/:articleId", h.RedirectArticleComments) // 文章评论列表
+		v1.GET("/comment/sub/:parentId", h.RedirectReplies)              // 二级评论列表
+	}
+
+	// 管理员接口
+	admin := r.Group("/api/v3/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.GET("/legacy-migration/progress", h.GetProgress) // 迁移进度
+	}
+}
+
+// RedirectArticle 旧版文章链接跳转
+// @Summary 旧版文章链接跳转
+// @Description 根据迁移映射永久跳转到V3文章详情
+// @Tags 旧版兼容
+// @Param id path int true "旧版文章ID"
+// @Success 301 {string} string "跳转到 /api/v3/articles/{newId}"
+// @Failure 404 {object} object{code=int,message=string}
+// @Router /api/v1/blog/{id} [get]
+func (h *LegacyMigrationHandler) RedirectArticle(c *gin.Context) {
+	newID, ok := h.resolve(c, model.LegacyEntityArticle, c.Param("id"))
+	if !ok {
+		return
+	}
+	c.Redirect(http.StatusMovedPermanently, fmt.Sprintf("/api/v3/articles/%d", newID))
+}
+
+// RedirectArticleComments 旧版文章评论列表跳转
+// @Summary 旧版文章评论列表跳转
+// @Tags 旧版兼容
+// @Param articleId path int true "旧版文章ID"
+// @Success 301 {string} string "跳转到 /api/v3/comments/root"
+// @Failure 404 {object} object{code=int,message=string}
+// @Router /api/v1/comment/article/{articleId} [get]
+func (h *LegacyMigrationHandler) RedirectArticleComments(c *gin.Context) {
+	newID, ok := h.resolve(c, model.LegacyEntityArticle, c.Param("articleId"))
+	if !ok {
+		return
+	}
+	c.Redirect(http.StatusMovedPermanently,
+		fmt.Sprintf("/api/v3/comments/root?target_type=%d&target_id=%d", model.CommentTargetTypeArticle, newID))
+}
+
+// RedirectReplies 旧版二级评论列表跳转
+// @Summary 旧版二级评论列表跳转
+// @Tags 旧版兼容
+// @Param parentId path int true "旧版一级评论ID"
+// @Success 301 {string} string "跳转到 /api/v3/comments/{newId}/replies"
+// @Failure 404 {object} object{code=int,message=string}
+// @Router /api/v1/comment/sub/{parentId} [get]
+func (h *LegacyMigrationHandler) RedirectReplies(c *gin.Context) {
+	newID, ok := h.resolve(c, model.LegacyEntityCommentRoot, c.Param("parentId"))
+	if !ok {
+		return
+	}
+	c.Redirect(http.StatusMovedPermanently, fmt.Sprintf("/api/v3/comments/%d/replies", newID))
+}
+
+// GetProgress 获取迁移进度
+// @Summary 获取旧版数据迁移进度
+// @Tags 旧版兼容
+// @Produce json
+// @Success 200 {object} object{code=int,data=[]model.LegacyMigrationProgress}
+// @Router /api/v3/admin/legacy-migration/progress [get]
+func (h *LegacyMigrationHandler) GetProgress(c *gin.Context) {
+	progress, err := h.migrationService.GetProgress()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+	response.Success(c, progress)
+}
+
+// resolve 解析旧ID并查询映射，失败时直接写入响应
+func (h *LegacyMigrationHandler) resolve(c *gin.Context, entity, rawID string) (uint64, bool) {
+	oldID, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return 0, false
+	}
+	newID, err := h.migrationService.ResolveNewID(entity, oldID)
+	if err != nil {
+		response.NotFound(c, "内容不存在或尚未迁移")
+		return 0, false
+	}
+	return newID, true
+}
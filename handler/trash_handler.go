@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"astronomer-gin/middleware"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/response"
+	"astronomer-gin/service"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler 回收站处理器
+type TrashHandler struct {
+	trashService service.TrashService
+}
+
+// NewTrashHandler 创建回收站处理器实例
+func NewTrashHandler(trashService service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *TrashHandler) RegisterRoutes(r *gin.Engine) {
+	trash := r.Group("/api/v3/trash")
+	trash.Use(middleware.AuthMiddleware())
+	{
+		trash.GET("", h.ListTrash)                // 回收站列表
+		trash.POST("/:id/restore", h.RestoreItem) // 恢复
+		trash.DELETE("/:id", h.PurgeItem)         // 彻底删除
+	}
+}
+
+// ListTrash 获取回收站列表
+// @Summary 获取回收站列表
+// @Description 按删除时间倒序返回当前用户删除的文章、草稿和评论，超过保留期的条目会被定时任务彻底清除
+// @Tags 回收站
+// @Produce json
+// @Param type query string false "类型：article/draft/comment，为空表示全部"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{items=[]model.TrashItem,total=int,page=int,page_size=int}}
+// @Router /api/v3/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	userID, _ := c.Get("user_id")
+	items, total, err := h.trashService.ListTrash(userID.(string), c.Query("type"), page, pageSize)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"items":     items,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// RestoreItem 恢复回收站条目
+// @Summary 恢复回收站条目
+// @Description 还原删除前的状态并补回分类、专栏、回复数和评论数等计数；评论所属内容或上级评论仍在删除状态时不能单独恢复
+// @Tags 回收站
+// @Produce json
+// @Param id path int true "回收站条目ID"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 400 {object} object{code=int,message=string}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/trash/{id}/restore [post]
+func (h *TrashHandler) RestoreItem(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.trashService.Restore(itemID, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// PurgeItem 彻底删除回收站条目
+// @Summary 彻底删除回收站条目
+// @Description 立即删除内容及其历史、关联数据和不再被引用的图片，不可恢复
+// @Tags 回收站
+// @Produce json
+// @Param id path int true "回收站条目ID"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 403 {object} object{code=int,message=string}
+// @Router /api/v3/trash/{id} [delete]
+func (h *TrashHandler) PurgeItem(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.trashService.Purge(itemID, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 按错误类型返回对应状态
+func (h *TrashHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, constant.ErrResourceNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, constant.ErrPermissionDenied):
+		response.Forbidden(c, err.Error())
+	default:
+		response.BadRequest(c, err.Error())
+	}
+}
@@ -11,15 +11,29 @@ import (
 // UploadHandler 文件上传处理器
 type UploadHandler struct {
 	uploadService service.UploadServiceV2
+	levelService  service.LevelService
 }
 
 // NewUploadHandler 创建上传Handler实例
-func NewUploadHandler(uploadService service.UploadServiceV2) *UploadHandler {
+func NewUploadHandler(uploadService service.UploadServiceV2, levelService service.LevelService) *UploadHandler {
 	return &UploadHandler{
 		uploadService: uploadService,
+		levelService:  levelService,
 	}
 }
 
+// consumeQuota 占用每日上传额度（额度随用户等级提升），超出时返回429
+func (h *UploadHandler) consumeQuota(c *gin.Context, count int) bool {
+	userID := c.GetString("user_id")
+	for i := 0; i < count; i++ {
+		if err := h.levelService.ConsumeUploadQuota(userID); err != nil {
+			util.TooManyRequests(c, err.Error())
+			return false
+		}
+	}
+	return true
+}
+
 // UploadImage 上传图片
 // @Summary 上传图片
 // @Description 上传图片文件（支持jpg, jpeg, png, gif, webp），最大5MB
@@ -38,6 +52,9 @@ func (h *UploadHandler) UploadImage(c *gin.Context) {
 		util.Error(c, constant.ErrParamInvalid.Code, "请选择要上传的图片")
 		return
 	}
+	if !h.consumeQuota(c, 1) {
+		return
+	}
 
 	// 调用服务层上传图片
 	fileURL, err := h.uploadService.UploadImage(c.Request.Context(), file)
@@ -72,6 +89,9 @@ func (h *UploadHandler) UploadImageWithVersions(c *gin.Context) {
 		util.Error(c, constant.ErrParamInvalid.Code, "请选择要上传的图片")
 		return
 	}
+	if !h.consumeQuota(c, 1) {
+		return
+	}
 
 	// 调用服务层上传图片（多版本）
 	versions, err := h.uploadService.UploadImageWithVersions(c.Request.Context(), file)
@@ -107,6 +127,9 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 		util.Error(c, constant.ErrParamInvalid.Code, "请选择要上传的文件")
 		return
 	}
+	if !h.consumeQuota(c, 1) {
+		return
+	}
 
 	// 获取文件分类（可选）
 	category := c.DefaultPostForm("category", "files")
@@ -159,6 +182,9 @@ func (h *UploadHandler) UploadMultiple(c *gin.Context) {
 		util.Error(c, constant.ErrParamInvalid.Code, "最多只能上传10个文件")
 		return
 	}
+	if !h.consumeQuota(c, len(files)) {
+		return
+	}
 
 	// 获取文件分类
 	category := c.DefaultPostForm("category", "files")
@@ -0,0 +1,169 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler 个人数据导出与账号注销处理器
+type AccountHandler struct {
+	accountService service.AccountService
+	auditService   service.AuditService
+}
+
+// NewAccountHandler 创建个人数据导出与账号注销处理器
+func NewAccountHandler(accountService service.AccountService, auditService service.AuditService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		auditService:   auditService,
+	}
+}
+
+// deletionRequest 注销申请
+type deletionRequest struct {
+	Password string `json:"password"` // 设置过密码的账号必填
+	Reason   string `json:"reason"`
+}
+
+// RequestExport 申请导出个人数据
+// @Summary 申请导出个人数据
+// @Description 异步打包资料、文章（含正文）、草稿、评论、点赞、收藏、关注、拉黑、私信和通知，完成后发送通知；24小时内重复申请返回已有的导出
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=model.UserDataExport}
+// @Router /user/account/exports [post]
+func (h *AccountHandler) RequestExport(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	export, err := h.accountService.RequestExport(c.Request.Context(), userID.(string))
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.SuccessWithMessage(c, "数据导出已开始，完成后会通知你", export)
+}
+
+// ListExports 数据导出记录
+// @Summary 数据导出记录
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=[]model.UserDataExport}
+// @Router /user/account/exports [get]
+func (h *AccountHandler) ListExports(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	exports, err := h.accountService.ListExports(userID.(string))
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, exports)
+}
+
+// DownloadExport 下载导出的个人数据
+// @Summary 下载导出的个人数据
+// @Tags 用户模块
+// @Produce application/zip
+// @Security Bearer
+// @Param id path int true "导出ID"
+// @Success 200 {file} file
+// @Failure 404 {object} object{code=int,message=string} "导出不存在"
+// @Router /user/account/exports/{id}/download [get]
+func (h *AccountHandler) DownloadExport(c *gin.Context) {
+	exportID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	data, err := h.accountService.DownloadExport(c.Request.Context(), exportID, userID.(string))
+	if err != nil {
+		if errors.Is(err, constant.ErrResourceNotFound) {
+			util.NotFound(c, err.Error())
+			return
+		}
+		respondBizError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("astronomer-data-%s.zip", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// GetDeletion 注销申请状态
+// @Summary 注销申请状态
+// @Description 返回冷静期中的注销申请，没有时data为null
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=model.AccountDeletion}
+// @Router /user/account/deletion [get]
+func (h *AccountHandler) GetDeletion(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	deletion, err := h.accountService.GetDeletion(userID.(string))
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, deletion)
+}
+
+// RequestDeletion 申请注销账号
+// @Summary 申请注销账号
+// @Description 冷静期结束后匿名化账号并删除个人数据；冷静期内重新登录或撤销申请即可取消
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{password=string,reason=string} true "密码（设置过密码的账号必填）和注销原因"
+// @Success 200 {object} object{code=int,data=model.AccountDeletion}
+// @Failure 400 {object} object{code=int,message=string} "密码错误或已在冷静期中"
+// @Router /user/account/deletion [post]
+func (h *AccountHandler) RequestDeletion(c *gin.Context) {
+	var req deletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	deletion, err := h.accountService.RequestDeletion(userID.(string), req.Password, req.Reason)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	entry := userAudit(model.AuditActionDeletionApply, userID.(string))
+	entry.After = model.JSONMap{"scheduled_time": deletion.ScheduledTime}
+	h.auditService.Record(auditContext(c), entry)
+	util.SuccessWithMessage(c, "已提交注销申请", deletion)
+}
+
+// CancelDeletion 撤销注销申请
+// @Summary 撤销注销申请
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 400 {object} object{code=int,message=string} "没有可撤销的注销申请"
+// @Router /user/account/deletion [delete]
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := h.accountService.CancelDeletion(userID.(string)); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	h.auditService.Record(auditContext(c), userAudit(model.AuditActionDeletionCancel, userID.(string)))
+	util.SuccessWithMessage(c, "已撤销注销申请", nil)
+}
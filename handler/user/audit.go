@@ -0,0 +1,102 @@
+package user
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSecurityActivity 最近安全活动
+// @Summary 最近安全活动
+// @Description 最近90天（可配置）的登录、登出、密码、手机号、邮箱、两步验证、第三方账号和注销等操作，包含IP和设备信息；result：1-成功 2-失败
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{list=[]model.AuditLog,total=int,page=int,pageSize=int}}
+// @Router /user/security/activity [get]
+func (h *UserHandler) GetSecurityActivity(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	logs, total, err := h.auditService.GetSecurityActivity(userID.(string), page, pageSize)
+	if err != nil {
+		util.InternalServerError(c, err.Error())
+		return
+	}
+	util.Success(c, gin.H{
+		"list":     logs,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// auditContext 从请求中提取审计上下文（操作者为当前登录用户，未登录时为空）
+func auditContext(c *gin.Context) *service.AuditContext {
+	return &service.AuditContext{
+		ActorID:   c.GetString("user_id"),
+		ActorRole: c.GetString("admin_role"),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("request_id"),
+	}
+}
+
+// userAudit 针对用户账号的审计日志
+func userAudit(action, userID string) *model.AuditLog {
+	return &model.AuditLog{
+		Action:     action,
+		TargetType: model.AuditTargetUser,
+		TargetID:   userID,
+	}
+}
+
+// recordLogin 记录登录结果（账号不存在或两步验证凭证无效时userID为空；失败时操作者未知）
+func (h *UserHandler) recordLogin(c *gin.Context, userID, method string, err error) {
+	actx := auditContext(c)
+	actx.ActorID = userID
+	entry := userAudit(model.AuditActionLogin, userID)
+	entry.Detail = method
+	if err != nil {
+		actx.ActorID = ""
+		entry.Result = model.AuditResultFailure
+		entry.Detail = method + ": " + err.Error()
+	}
+	h.auditService.Record(actx, entry)
+}
+
+// auditValues 将设置转换为审计日志中的修改前/后值
+func auditValues(v interface{}) model.JSONMap {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var values model.JSONMap
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil
+	}
+	return values
+}
+
+// recordLoginFailure 记录登录失败（账号存在时记到该账号下，便于用户在安全活动中看到）
+func (h *UserHandler) recordLoginFailure(c *gin.Context, phone, method string, err error) {
+	userID := ""
+	if user, findErr := h.userService.GetUserInfo(phone); findErr == nil {
+		userID = user.ID
+	}
+	h.recordLogin(c, userID, method, err)
+}
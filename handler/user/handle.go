@@ -0,0 +1,121 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUserByHandle 通过个性ID获取用户资料
+// @Summary 通过个性ID获取用户资料
+// @Description 个性ID不区分大小写，可带@前缀；已修改的旧个性ID在跳转期内302跳转到当前个性ID
+// @Tags 用户模块
+// @Produce json
+// @Param handle path string true "个性ID"
+// @Success 200 {object} object{code=int,data=object} "返回用户资料"
+// @Success 302 "旧个性ID，跳转到当前个性ID"
+// @Failure 404 {object} object{code=int,message=string} "用户不存在"
+// @Router /user/by-handle/{handle} [get]
+func (h *UserHandler) GetUserByHandle(c *gin.Context) {
+	requested := c.Param("handle")
+	user, err := h.userService.GetUserByHandle(requested)
+	if err != nil {
+		util.NotFound(c, constant.ErrUserNotExist.Message)
+		return
+	}
+
+	if user.Handle != util.NormalizeHandle(requested) {
+		c.Redirect(http.StatusFound, "/api/v3/user/by-handle/"+url.PathEscape(user.Handle))
+		return
+	}
+	util.Success(c, publicProfile(user, h.profileBirthday(c, user)))
+}
+
+// CheckHandle 检查个性ID是否可用
+// @Summary 检查个性ID是否可用
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Param handle query string true "个性ID"
+// @Success 200 {object} object{code=int,data=object{available=bool,reason=string}}
+// @Router /user/handle/check [get]
+func (h *UserHandler) CheckHandle(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	err := h.userService.CheckHandleAvailable(userID.(string), c.Query("handle"))
+
+	var bizErr *constant.BizError
+	switch {
+	case err == nil:
+		util.Success(c, gin.H{"available": true})
+	case errors.As(err, &bizErr):
+		util.Success(c, gin.H{"available": false, "reason": bizErr.Message})
+	default:
+		util.InternalServerError(c, err.Error())
+	}
+}
+
+// ChangeHandle 修改个性ID
+// @Summary 修改个性ID
+// @Description 3-20位小写字母、数字或下划线，以字母开头；每30天可修改一次，旧个性ID在90天内跳转到新ID且不能被他人使用
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{handle=string} true "新个性ID"
+// @Success 200 {object} object{code=int,data=object{handle=string}}
+// @Failure 400 {object} object{code=int,message=string} "格式错误、已被使用或修改过于频繁"
+// @Router /user/handle [put]
+func (h *UserHandler) ChangeHandle(c *gin.Context) {
+	var req struct {
+		Handle string `json:"handle" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	before := ""
+	if user, err := h.userService.GetUserInfo(c.GetString("phone")); err == nil {
+		before = user.Handle
+	}
+	handle, err := h.userService.ChangeHandle(userID.(string), req.Handle)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	entry := userAudit(model.AuditActionHandleChange, userID.(string))
+	entry.Before = model.JSONMap{"handle": before}
+	entry.After = model.JSONMap{"handle": handle}
+	h.auditService.Record(auditContext(c), entry)
+	util.SuccessWithMessage(c, constant.UpdateSuccess, gin.H{"handle": handle})
+}
+
+// GetNameHistory 用户名和个性ID修改记录（管理员）
+// @Summary 用户改名记录
+// @Description kind：1-用户名 2-个性ID
+// @Tags 管理员
+// @Produce json
+// @Security Bearer
+// @Param id path string true "用户ID"
+// @Success 200 {object} object{code=int,data=[]model.UserNameHistory}
+// @Failure 404 {object} object{code=int,message=string} "用户不存在"
+// @Router /admin/users/{id}/name-history [get]
+func (h *UserHandler) GetNameHistory(c *gin.Context) {
+	histories, err := h.userService.GetNameHistory(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, constant.ErrUserNotExist) {
+			util.NotFound(c, err.Error())
+			return
+		}
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, histories)
+}
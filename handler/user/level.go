@@ -0,0 +1,112 @@
+package user
+
+import (
+	"errors"
+	"strconv"
+
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyLevel 获取当前用户的等级信息
+// @Summary 我的等级
+// @Description 返回等级、经验、下一级所需经验、排名、已解锁的特权、每日上传额度和徽章
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=service.LevelProfile}
+// @Router /user/level [get]
+func (h *UserHandler) GetMyLevel(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	h.respondLevelProfile(c, userID.(string))
+}
+
+// GetUserLevel 获取用户的等级信息（公开）
+// @Summary 用户等级
+// @Tags 用户模块
+// @Produce json
+// @Param id path string true "用户ID"
+// @Success 200 {object} object{code=int,data=service.LevelProfile}
+// @Failure 404 {object} object{code=int,message=string} "用户不存在"
+// @Router /user/{id}/level [get]
+func (h *UserHandler) GetUserLevel(c *gin.Context) {
+	h.respondLevelProfile(c, c.Param("id"))
+}
+
+// GetXPLogs 获取当前用户的经验流水
+// @Summary 经验记录
+// @Description 超出每日上限的事件也会记录，经验为0
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(20)
+// @Success 200 {object} object{code=int,data=object{list=[]model.UserXPLog,total=int,page=int,pageSize=int}}
+// @Router /user/level/logs [get]
+func (h *UserHandler) GetXPLogs(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	logs, total, err := h.levelService.ListXPLogs(userID.(string), page, pageSize)
+	if err != nil {
+		util.InternalServerError(c, err.Error())
+		return
+	}
+	util.Success(c, gin.H{
+		"list":     logs,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// GetLevelLeaderboard 经验排行榜
+// @Summary 经验排行榜
+// @Tags 用户模块
+// @Produce json
+// @Param limit query int false "数量（最多100）" default(50)
+// @Success 200 {object} object{code=int,data=[]service.LeaderboardEntry}
+// @Router /user/level/leaderboard [get]
+func (h *UserHandler) GetLevelLeaderboard(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	entries, err := h.levelService.GetLeaderboard(limit)
+	if err != nil {
+		util.InternalServerError(c, err.Error())
+		return
+	}
+	util.Success(c, entries)
+}
+
+// GetLevelRules 等级规则
+// @Summary 等级规则
+// @Description 各等级所需经验、经验规则和每日上限、特权所需等级、各等级每日上传额度以及全部徽章
+// @Tags 用户模块
+// @Produce json
+// @Success 200 {object} object{code=int,data=service.LevelRules}
+// @Router /user/level/rules [get]
+func (h *UserHandler) GetLevelRules(c *gin.Context) {
+	util.Success(c, h.levelService.GetRules())
+}
+
+// respondLevelProfile 返回用户等级信息
+func (h *UserHandler) respondLevelProfile(c *gin.Context, userID string) {
+	profile, err := h.levelService.GetProfile(userID)
+	if err != nil {
+		if errors.Is(err, constant.ErrUserNotExist) {
+			util.NotFound(c, err.Error())
+			return
+		}
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, profile)
+}
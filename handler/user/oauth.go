@@ -0,0 +1,163 @@
+package user
+
+import (
+	"net/http"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/oauth"
+	"astronomer-gin/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// oauthCookieName 第三方登录发起浏览器标识Cookie（回调时必须带回，防止登录CSRF）
+	oauthCookieName = "oauth_login"
+	// oauthCookiePath 只在第三方登录接口上发送
+	oauthCookiePath = "/api/v3/oauth"
+	// oauthCookieMaxAge 与授权状态有效期一致（10分钟）
+	oauthCookieMaxAge = 10 * 60
+)
+
+// ListOAuthProviders 第三方登录方式列表
+// @Summary 第三方登录方式列表
+// @Tags 用户模块
+// @Produce json
+// @Success 200 {object} object{code=int,data=[]service.OAuthProviderInfo}
+// @Router /oauth/providers [get]
+func (h *UserHandler) ListOAuthProviders(c *gin.Context) {
+	util.Success(c, h.userService.ListOAuthProviders())
+}
+
+// OAuthAuthorize 发起第三方登录
+// @Summary 发起第三方登录
+// @Description 返回第三方授权页地址，前端跳转后由第三方重定向回前端回调页（携带code和state）；同时写入HttpOnly Cookie，回调时需由同一浏览器携带
+// @Tags 用户模块
+// @Produce json
+// @Param provider path string true "提供方名称"
+// @Success 200 {object} object{code=int,data=object{url=string}}
+// @Failure 400 {object} object{code=int,message=string} "不支持的登录方式"
+// @Router /oauth/{provider}/authorize [get]
+func (h *UserHandler) OAuthAuthorize(c *gin.Context) {
+	nonce, err := oauth.RandomString(24)
+	if err != nil {
+		util.InternalServerError(c, constant.ErrSystemError.Message)
+		return
+	}
+	authURL, err := h.userService.OAuthAuthorize(c.Param("provider"), "", nonce)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthCookieName, nonce, oauthCookieMaxAge, oauthCookiePath, "", c.Request.TLS != nil, true)
+	util.Success(c, gin.H{"url": authURL})
+}
+
+// OAuthCallback 第三方授权回调
+// @Summary 第三方授权回调
+// @Description 前端回调页收到code和state后调用。登录流程需携带发起授权时写入的Cookie，返回与密码登录相同的结果（首次登录自动注册，isNew=true）；绑定流程需携带发起绑定用户的token，返回绑定记录
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param provider path string true "提供方名称"
+// @Param request body object{code=string,state=string} true "授权结果"
+// @Success 200 {object} object{code=int,message=string,data=object}
+// @Failure 400 {object} object{code=int,message=string} "授权已过期或第三方授权失败"
+// @Router /oauth/{provider}/callback [post]
+func (h *UserHandler) OAuthCallback(c *gin.Context) {
+	var req struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	currentUserID := c.GetString("user_id")
+	nonce, _ := c.Cookie(oauthCookieName)
+	result, err := h.userService.OAuthCallback(c.Param("provider"), req.Code, req.State, currentUserID, nonce)
+	if err != nil {
+		if currentUserID == "" {
+			h.recordLogin(c, "", "oauth:"+c.Param("provider"), err)
+		}
+		respondBizError(c, err)
+		return
+	}
+	if currentUserID == "" {
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(oauthCookieName, "", -1, oauthCookiePath, "", c.Request.TLS != nil, true)
+	}
+	if result.Linked {
+		entry := userAudit(model.AuditActionIdentityLink, currentUserID)
+		entry.After = model.JSONMap{"provider": c.Param("provider")}
+		h.auditService.Record(auditContext(c), entry)
+		util.SuccessWithMessage(c, "绑定成功", result.Identity)
+		return
+	}
+	if result.Login.User != nil {
+		h.recordLogin(c, result.Login.User.ID, "oauth:"+c.Param("provider"), nil)
+	}
+	respondLogin(c, constant.LoginSuccess, result.Login)
+}
+
+// ListIdentities 已绑定的第三方账号
+// @Summary 已绑定的第三方账号
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=[]model.UserIdentity}
+// @Router /user/identities [get]
+func (h *UserHandler) ListIdentities(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	identities, err := h.userService.ListIdentities(userID.(string))
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, identities)
+}
+
+// LinkIdentity 发起绑定第三方账号
+// @Summary 发起绑定第三方账号
+// @Description 返回第三方授权页地址；授权完成后前端携带当前token调用回调接口完成绑定
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Param provider path string true "提供方名称"
+// @Success 200 {object} object{code=int,data=object{url=string}}
+// @Failure 400 {object} object{code=int,message=string} "不支持的登录方式或已绑定"
+// @Router /user/identities/{provider} [post]
+func (h *UserHandler) LinkIdentity(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	authURL, err := h.userService.OAuthAuthorize(c.Param("provider"), userID.(string), "")
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, gin.H{"url": authURL})
+}
+
+// UnlinkIdentity 解除绑定第三方账号
+// @Summary 解除绑定第三方账号
+// @Description 没有手机号和密码的账号不能解除最后一个第三方账号
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Param provider path string true "提供方名称"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 400 {object} object{code=int,message=string} "未绑定或为唯一登录方式"
+// @Router /user/identities/{provider} [delete]
+func (h *UserHandler) UnlinkIdentity(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := h.userService.UnlinkIdentity(userID.(string), c.Param("provider")); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	entry := userAudit(model.AuditActionIdentityUnlink, userID.(string))
+	entry.Before = model.JSONMap{"provider": c.Param("provider")}
+	h.auditService.Record(auditContext(c), entry)
+	util.SuccessWithMessage(c, "已解除绑定", nil)
+}
@@ -0,0 +1,66 @@
+package user
+
+import (
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPrivacySettings 获取隐私设置
+// @Summary 获取隐私设置
+// @Description 范围取值：everyone-所有人 followers-关注我的人 following-我关注的人 friends-互关好友 nobody-仅自己
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=model.UserPrivacy}
+// @Router /user/privacy [get]
+func (h *UserHandler) GetPrivacySettings(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	privacy, err := h.privacyService.GetSettings(userID.(string))
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, privacy)
+}
+
+// UpdatePrivacySettings 更新隐私设置
+// @Summary 更新隐私设置
+// @Description 控制谁能查看粉丝/关注列表、给我发私信、评论我的文章，以及是否出现在用户搜索和热门榜单中；未传的字段保持不变
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.PrivacySettingsRequest true "隐私设置"
+// @Success 200 {object} object{code=int,data=model.UserPrivacy}
+// @Failure 400 {object} object{code=int,message=string} "设置不正确"
+// @Router /user/privacy [put]
+func (h *UserHandler) UpdatePrivacySettings(c *gin.Context) {
+	var req service.PrivacySettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	before, err := h.privacyService.GetSettings(userID.(string))
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	beforeValues := auditValues(before)
+
+	privacy, err := h.privacyService.UpdateSettings(userID.(string), &req)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	entry := userAudit(model.AuditActionPrivacyUpdate, userID.(string))
+	entry.Before = beforeValues
+	entry.After = auditValues(privacy)
+	h.auditService.Record(auditContext(c), entry)
+	util.SuccessWithMessage(c, constant.UpdateSuccess, privacy)
+}
@@ -0,0 +1,178 @@
+package user
+
+import (
+	"errors"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler 两步验证处理器
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+	auditService     service.AuditService
+}
+
+// NewTwoFactorHandler 创建两步验证处理器
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService, auditService service.AuditService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		auditService:     auditService,
+	}
+}
+
+// twoFactorCodeRequest 验证码或恢复码
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// GetStatus 两步验证状态
+// @Summary 两步验证状态
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=service.TwoFactorStatus}
+// @Router /user/2fa [get]
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	status, err := h.twoFactorService.GetStatus(userID.(string))
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, status)
+}
+
+// Setup 获取两步验证密钥
+// @Summary 获取两步验证密钥
+// @Description 返回密钥和otpauth链接（前端渲染为二维码），用身份验证器App添加后调用开启接口确认
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,data=service.TwoFactorSetup}
+// @Failure 400 {object} object{code=int,message=string} "已开启两步验证"
+// @Router /user/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	setup, err := h.twoFactorService.Setup(userID.(string))
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, setup)
+}
+
+// Enable 开启两步验证
+// @Summary 开启两步验证
+// @Description 输入App中的验证码确认开启，返回10个恢复码（只展示这一次）；此前签发的token全部失效，前端需改用返回的新token
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{code=string} true "App中的验证码"
+// @Success 200 {object} object{code=int,data=object{recoveryCodes=[]string,token=string}}
+// @Failure 400 {object} object{code=int,message=string} "验证码错误"
+// @Router /user/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	codes, token, err := h.twoFactorService.Enable(userID.(string), req.Code)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	h.auditService.Record(auditContext(c), userAudit(model.AuditAction2FAEnable, userID.(string)))
+	util.SuccessWithMessage(c, "两步验证已开启", gin.H{"recoveryCodes": codes, "token": token})
+}
+
+// Disable 关闭两步验证
+// @Summary 关闭两步验证
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{code=string} true "App中的验证码或恢复码"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 400 {object} object{code=int,message=string} "验证码错误"
+// @Router /user/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.twoFactorService.Disable(userID.(string), req.Code); err != nil {
+		entry := userAudit(model.AuditAction2FADisable, userID.(string))
+		entry.Result = model.AuditResultFailure
+		entry.Detail = err.Error()
+		h.auditService.Record(auditContext(c), entry)
+		respondBizError(c, err)
+		return
+	}
+	h.auditService.Record(auditContext(c), userAudit(model.AuditAction2FADisable, userID.(string)))
+	util.SuccessWithMessage(c, "两步验证已关闭", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 旧恢复码全部作废，新恢复码只展示这一次
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{code=string} true "App中的验证码或恢复码"
+// @Success 200 {object} object{code=int,data=object{recoveryCodes=[]string}}
+// @Failure 400 {object} object{code=int,message=string} "验证码错误"
+// @Router /user/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(userID.(string), req.Code)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	h.auditService.Record(auditContext(c), userAudit(model.AuditActionRecoveryCodes, userID.(string)))
+	util.Success(c, gin.H{"recoveryCodes": codes})
+}
+
+// AdminReset 重置用户的两步验证（超级管理员）
+// @Summary 重置用户的两步验证
+// @Description 用户丢失设备和恢复码时由超级管理员重置，用户会收到安全提醒
+// @Tags 管理员
+// @Produce json
+// @Security Bearer
+// @Param id path string true "用户ID"
+// @Success 200 {object} object{code=int,message=string}
+// @Failure 404 {object} object{code=int,message=string} "用户不存在"
+// @Router /admin/users/{id}/2fa [delete]
+func (h *TwoFactorHandler) AdminReset(c *gin.Context) {
+	operatorID, _ := c.Get("user_id")
+	err := h.twoFactorService.AdminReset(operatorID.(string), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, constant.ErrUserNotExist) {
+			util.NotFound(c, err.Error())
+			return
+		}
+		respondBizError(c, err)
+		return
+	}
+	h.auditService.Record(auditContext(c), userAudit(model.AuditActionAdmin2FAReset, c.Param("id")))
+	util.SuccessWithMessage(c, "两步验证已重置", nil)
+}
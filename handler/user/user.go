@@ -1,6 +1,11 @@
 package user
 
 import (
+	"errors"
+	"strconv"
+	"time"
+
+	"astronomer-gin/model"
 	"astronomer-gin/pkg/captcha"
 	"astronomer-gin/pkg/constant"
 	"astronomer-gin/pkg/util"
@@ -11,24 +16,32 @@ import (
 )
 
 type UserHandler struct {
-	userService service.UserServiceV2
+	userService    service.UserServiceV2
+	riskService    service.RiskService
+	privacyService service.PrivacyService
+	levelService   service.LevelService
+	auditService   service.AuditService
 }
 
-func NewUserHandler(userService service.UserServiceV2) *UserHandler {
+func NewUserHandler(userService service.UserServiceV2, riskService service.RiskService, privacyService service.PrivacyService, levelService service.LevelService, auditService service.AuditService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:    userService,
+		riskService:    riskService,
+		privacyService: privacyService,
+		levelService:   levelService,
+		auditService:   auditService,
 	}
 }
 
 // Register 用户注册
 // @Summary 用户注册
-// @Description 新用户注册账号，需要提供手机号、用户名、密码和验证码
+// @Description 新用户注册账号，需要提供手机号、用户名和密码；邮箱可选，验证后生效。同一IP近期注册过或全站注册激增时需要图形验证码
 // @Tags 用户模块
 // @Accept json
 // @Produce json
-// @Param request body object{phone=string,username=string,password=string,captchaId=string,captchaVal=string} true "注册信息"
+// @Param request body object{phone=string,username=string,password=string,email=string,captchaId=string,captchaVal=string} true "注册信息"
 // @Success 200 {object} object{code=int,message=string,data=object} "注册成功"
-// @Failure 400 {object} object{code=int,message=string} "参数错误或验证码错误"
+// @Failure 400 {object} object{code=int,message=string,data=object{captchaRequired=bool}} "参数错误或验证码错误；需要图形验证码时captchaRequired=true"
 // @Failure 500 {object} object{code=int,message=string} "服务器内部错误"
 // @Router /user/register [post]
 func (h *UserHandler) Register(c *gin.Context) {
@@ -36,8 +49,9 @@ func (h *UserHandler) Register(c *gin.Context) {
 		Phone      string `json:"phone" binding:"required"`
 		Username   string `json:"username" binding:"required"`
 		Password   string `json:"password" binding:"required"`
-		CaptchaID  string `json:"captchaId" binding:"required"`
-		CaptchaVal string `json:"captchaVal" binding:"required"`
+		Email      string `json:"email"`
+		CaptchaID  string `json:"captchaId"`
+		CaptchaVal string `json:"captchaVal"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -45,46 +59,46 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// 验证验证码
-	if !captcha.VerifyCaptcha(req.CaptchaID, req.CaptchaVal) {
-		util.BadRequest(c, constant.CaptchaError)
+	// 风险检查（按需要求图形验证码）
+	subject := &service.RiskSubject{
+		Phone:        req.Phone,
+		IP:           c.ClientIP(),
+		CaptchaID:    req.CaptchaID,
+		CaptchaValue: req.CaptchaVal,
+	}
+	if err := h.riskService.Check(service.RiskActionRegister, subject); err != nil {
+		respondRiskError(c, err)
 		return
 	}
 
 	// 调用service层注册用户
-	if err := h.userService.Register(req.Phone, req.Password, req.Username); err != nil {
+	if err := h.userService.Register(req.Phone, req.Password, req.Username, req.Email); err != nil {
 		util.InternalServerError(c, err.Error())
 		return
 	}
+	h.riskService.RecordSuccess(service.RiskActionRegister, subject)
 
 	// 注册成功后自动登录，返回token和用户信息
-	token, user, err := h.userService.Login(req.Phone, req.Password)
+	result, err := h.userService.Login(req.Phone, req.Password)
 	if err != nil {
 		util.InternalServerError(c, "注册成功但自动登录失败")
 		return
 	}
+	h.riskService.RecordSuccess(service.RiskActionLogin, subject)
 
-	util.SuccessWithMessage(c, constant.RegisterSuccess, gin.H{
-		"token": token,
-		"user": gin.H{
-			"id":       user.ID,
-			"phone":    user.Phone,
-			"username": user.Username,
-			"avatar":   user.Icon, // icon -> avatar（前端期望avatar字段）
-			"sex":      user.Sex,
-		},
-	})
+	respondLogin(c, constant.RegisterSuccess, result)
 }
 
 // Login 用户登录
 // @Summary 用户登录
-// @Description 用户登录获取Token，可选验证码验证
+// @Description 用户登录获取Token。密码错误次数过多或在新IP登录时需要图形验证码，连续失败后需要等待递增的时间
 // @Tags 用户模块
 // @Accept json
 // @Produce json
 // @Param request body object{phone=string,password=string,captchaId=string,captchaVal=string} true "登录信息"
-// @Success 200 {object} object{code=int,message=string,data=object{token=string,userInfo=object}} "登录成功，返回token和用户信息"
-// @Failure 400 {object} object{code=int,message=string} "参数错误、验证码错误或登录失败"
+// @Success 200 {object} object{code=int,message=string,data=object{token=string,user=object,twoFactorRequired=bool,challengeToken=string}} "登录成功返回token和用户信息；开启两步验证时返回challengeToken"
+// @Failure 400 {object} object{code=int,message=string,data=object{captchaRequired=bool}} "参数错误、验证码错误或登录失败；需要图形验证码时captchaRequired=true"
+// @Failure 429 {object} object{code=int,message=string,data=object{retryAfter=int}} "失败次数过多，retryAfter秒后再试"
 // @Router /user/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req struct {
@@ -99,23 +113,82 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// 如果提供了验证码，进行验证
-	if req.CaptchaID != "" && req.CaptchaVal != "" {
-		if !captcha.VerifyCaptcha(req.CaptchaID, req.CaptchaVal) {
-			util.BadRequest(c, constant.CaptchaError)
-			return
-		}
+	// 风险检查：等待中直接拒绝，需要时校验图形验证码
+	subject := &service.RiskSubject{
+		Phone:        req.Phone,
+		IP:           c.ClientIP(),
+		CaptchaID:    req.CaptchaID,
+		CaptchaValue: req.CaptchaVal,
+	}
+	if err := h.riskService.Check(service.RiskActionLogin, subject); err != nil {
+		respondRiskError(c, err)
+		return
 	}
 
 	// 调用service层登录
-	token, user, err := h.userService.Login(req.Phone, req.Password)
+	result, err := h.userService.Login(req.Phone, req.Password)
 	if err != nil {
+		if errors.Is(err, constant.ErrUserNotExist) || errors.Is(err, constant.ErrPasswordIncorrect) {
+			h.riskService.RecordFailure(service.RiskActionLogin, subject)
+		}
+		h.recordLoginFailure(c, req.Phone, "password", err)
 		util.BadRequest(c, err.Error())
 		return
 	}
+	h.riskService.RecordSuccess(service.RiskActionLogin, subject)
+	if result.User != nil {
+		h.recordLogin(c, result.User.ID, "password", nil)
+	}
+
+	respondLogin(c, constant.LoginSuccess, result)
+}
+
+// VerifyTwoFactorLogin 登录两步验证
+// @Summary 登录两步验证
+// @Description 密码或短信验证通过后，使用返回的challengeToken和身份验证器App中的验证码（或恢复码）完成登录
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body object{challengeToken=string,code=string} true "验证信息"
+// @Success 200 {object} object{code=int,message=string,data=object{token=string,user=object}} "登录成功"
+// @Failure 400 {object} object{code=int,message=string} "验证码错误或凭证已过期"
+// @Failure 429 {object} object{code=int,message=string} "错误次数过多"
+// @Router /user/login/2fa [post]
+func (h *UserHandler) VerifyTwoFactorLogin(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challengeToken" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
 
-	util.SuccessWithMessage(c, constant.LoginSuccess, gin.H{
-		"token": token,
+	result, err := h.userService.VerifyTwoFactorLogin(req.ChallengeToken, req.Code)
+	if err != nil {
+		h.recordLogin(c, "", "2fa", err)
+		respondBizError(c, err)
+		return
+	}
+	h.recordLogin(c, result.User.ID, "2fa", nil)
+	respondLogin(c, constant.LoginSuccess, result)
+}
+
+// respondLogin 登录响应：需要两步验证时只返回验证凭证
+func respondLogin(c *gin.Context, message string, result *service.LoginResult) {
+	if result.ChallengeToken != "" {
+		util.SuccessWithMessage(c, "请输入两步验证码", gin.H{
+			"twoFactorRequired": true,
+			"challengeToken":    result.ChallengeToken,
+		})
+		return
+	}
+
+	user := result.User
+	util.SuccessWithMessage(c, message, gin.H{
+		"token":                  result.Token,
+		"isNew":                  result.IsNew,
+		"twoFactorSetupRequired": result.TwoFactorSetupRequired,
 		"user": gin.H{
 			"id":       user.ID,
 			"phone":    user.Phone,
@@ -136,11 +209,10 @@ func (h *UserHandler) Login(c *gin.Context) {
 // @Router /user/logout [post]
 func (h *UserHandler) Logout(c *gin.Context) {
 	// 从上下文获取用户信息（由JWT中间件设置）
-	userID, exists := c.Get("userID")
-	if exists {
+	if userID := c.GetString("user_id"); userID != "" {
 		// 这里可以添加清除Redis会话、黑名单Token等逻辑
 		// 例如：将token加入黑名单，或清除用户的会话缓存
-		_ = userID // 暂时未使用，但保留以便后续扩展
+		h.auditService.Record(auditContext(c), userAudit(model.AuditActionLogout, userID))
 	}
 
 	// 前端通常会自己清除localStorage中的token
@@ -166,6 +238,29 @@ func (h *UserHandler) GetCaptcha(c *gin.Context) {
 	util.Success(c, captchaInfo)
 }
 
+// CaptchaRequired 查询是否需要图形验证码
+// @Summary 查询是否需要图形验证码
+// @Description 前端在展示登录/注册表单前调用，需要时先获取并展示图形验证码
+// @Tags 用户模块
+// @Produce json
+// @Param action query string true "操作：login/register"
+// @Param phone query string false "手机号（登录时）"
+// @Success 200 {object} object{code=int,data=object{captchaRequired=bool}}
+// @Router /user/captcha/required [get]
+func (h *UserHandler) CaptchaRequired(c *gin.Context) {
+	action := c.Query("action")
+	if action != service.RiskActionLogin && action != service.RiskActionRegister {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	required := h.riskService.CaptchaRequired(action, &service.RiskSubject{
+		Phone: c.Query("phone"),
+		IP:    c.ClientIP(),
+	})
+	util.Success(c, gin.H{"captchaRequired": required})
+}
+
 // GetUserInfo 获取用户信息
 func (h *UserHandler) GetUserInfo(c *gin.Context) {
 	phone, _ := c.Get("phone")
@@ -176,14 +271,32 @@ func (h *UserHandler) GetUserInfo(c *gin.Context) {
 		return
 	}
 
+	// 每日首次访问计入登录经验
+	h.levelService.RecordDailyLogin(user.ID)
+
+	// 邮箱只对本人展示，不进入用户信息缓存
+	emailStatus, err := h.userService.GetEmailStatus(user.ID)
+	if err != nil {
+		emailStatus = &service.EmailStatus{}
+	}
+
 	util.Success(c, gin.H{
 		"id":             user.ID,
-		"phone":          user.Phone,
+		"phone":          boundPhone(user),
+		"email":          emailStatus.Email,
+		"emailVerified":  emailStatus.Verified,
+		"pendingEmail":   emailStatus.PendingEmail,
+		"handle":         user.Handle,
 		"username":       user.Username,
 		"avatar":         user.Icon,  // icon -> avatar (前端字段)
 		"bio":            user.Intro, // intro -> bio (前端字段)
 		"sex":            user.Sex,
 		"note":           user.Note,
+		"cover":          user.Cover,
+		"website":        user.Website,
+		"socialLinks":    socialLinks(user),
+		"location":       user.Location,
+		"birthday":       formatBirthday(user),
 		"followingCount": user.FollowingCount,
 		"followedCount":  user.FollowedCount,
 		"createTime":     user.CreateTime,
@@ -194,12 +307,18 @@ func (h *UserHandler) GetUserInfo(c *gin.Context) {
 func (h *UserHandler) UpdateUserInfo(c *gin.Context) {
 	phone, _ := c.Get("phone")
 
+	// 封面、网站等资料字段：不传保持不变，传空字符串清空
 	var req struct {
-		Username string `json:"username"`
-		Avatar   string `json:"avatar"` // 前端使用 avatar
-		Bio      string `json:"bio"`    // 前端使用 bio (个人简介)
-		Sex      int    `json:"sex"`
-		Note     string `json:"note"` // 备注（可选）
+		Username    string            `json:"username"`
+		Avatar      string            `json:"avatar"` // 前端使用 avatar
+		Bio         string            `json:"bio"`    // 前端使用 bio (个人简介)
+		Sex         int               `json:"sex"`
+		Note        string            `json:"note"` // 备注（可选）
+		Cover       *string           `json:"cover"`
+		Website     *string           `json:"website"`
+		SocialLinks map[string]string `json:"socialLinks"` // 平台 -> 主页地址，传空对象清空
+		Location    *string           `json:"location"`
+		Birthday    *string           `json:"birthday"` // YYYY-MM-DD
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -225,9 +344,33 @@ func (h *UserHandler) UpdateUserInfo(c *gin.Context) {
 	if req.Note != "" {
 		updates["note"] = req.Note
 	}
+	if req.Cover != nil {
+		updates["cover"] = *req.Cover
+	}
+	if req.Website != nil {
+		updates["website"] = *req.Website
+	}
+	if req.SocialLinks != nil {
+		updates["social_links"] = model.SocialLinks(req.SocialLinks)
+	}
+	if req.Location != nil {
+		updates["location"] = *req.Location
+	}
+	if req.Birthday != nil {
+		var birthday *time.Time
+		if *req.Birthday != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", *req.Birthday, time.Local)
+			if err != nil {
+				util.BadRequest(c, constant.ErrBirthdayInvalid.Message)
+				return
+			}
+			birthday = &parsed
+		}
+		updates["birthday"] = birthday
+	}
 
 	if err := h.userService.UpdateUserInfo(phone.(string), updates); err != nil {
-		util.InternalServerError(c, err.Error())
+		respondBizError(c, err)
 		return
 	}
 
@@ -236,7 +379,7 @@ func (h *UserHandler) UpdateUserInfo(c *gin.Context) {
 
 // GetUserProfile 获取用户资料（公开接口，通过用户ID）
 // @Summary 获取用户资料
-// @Description 获取指定用户的公开资料信息
+// @Description 获取指定用户的公开资料信息；生日按隐私设置返回月日（MM-DD），完整日期只有本人可见
 // @Tags 用户模块
 // @Produce json
 // @Param id path string true "用户ID(UUID)"
@@ -263,18 +406,399 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 		return
 	}
 
-	// 返回与前端期望字段名匹配的数据
-	util.Success(c, gin.H{
+	util.Success(c, publicProfile(user, h.profileBirthday(c, user)))
+}
+
+// profileBirthday 按查看者返回生日：本人看到完整日期，隐私范围内的查看者只看到月日（MM-DD）
+func (h *UserHandler) profileBirthday(c *gin.Context, user *model.User) string {
+	if user.Birthday == nil {
+		return ""
+	}
+	viewerID := c.GetString("user_id")
+	if viewerID == user.ID {
+		return formatBirthday(user)
+	}
+	if err := h.privacyService.CheckBirthdayVisible(user.ID, viewerID); err != nil {
+		return ""
+	}
+	return user.Birthday.Format("01-02")
+}
+
+// publicProfile 用户公开资料（字段名与前端保持一致）
+func publicProfile(user *model.User, birthday string) gin.H {
+	return gin.H{
 		"id":              user.ID,
+		"handle":          user.Handle,
 		"username":        user.Username,
 		"avatar":          user.Icon,  // icon -> avatar
 		"bio":             user.Intro, // intro -> bio
 		"sex":             user.Sex,
+		"cover":           user.Cover,
+		"website":         user.Website,
+		"social_links":    socialLinks(user),
+		"location":        user.Location,
+		"birthday":        birthday,
 		"article_count":   0,                   // TODO: 需要从文章表统计
 		"follower_count":  user.FollowedCount,  // followedCount -> follower_count
 		"following_count": user.FollowingCount, // followingCount -> following_count
 		"like_count":      0,                   // TODO: 需要从点赞表统计
 		"favorite_count":  0,                   // TODO: 需要从收藏表统计
 		"create_time":     user.CreateTime,
-	})
+	}
+}
+
+// BindEmail 绑定或更换邮箱
+// @Summary 绑定邮箱
+// @Description 发送验证邮件到新邮箱，点击链接验证后生效；验证前原邮箱继续有效
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{email=string} true "邮箱"
+// @Success 200 {object} object{code=int,message=string} "验证邮件已发送"
+// @Failure 400 {object} object{code=int,message=string} "邮箱格式错误或已被使用"
+// @Failure 429 {object} object{code=int,message=string} "发送过于频繁"
+// @Router /user/email [put]
+func (h *UserHandler) BindEmail(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.userService.BindEmail(userID.(string), req.Email); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	entry := userAudit(model.AuditActionEmailBind, userID.(string))
+	entry.After = model.JSONMap{"pending_email": util.MaskEmail(req.Email)}
+	h.auditService.Record(auditContext(c), entry)
+	util.SuccessWithMessage(c, "验证邮件已发送，请查收", nil)
+}
+
+// ResendVerifyEmail 重新发送验证邮件
+// @Summary 重新发送验证邮件
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,message=string} "验证邮件已发送"
+// @Failure 400 {object} object{code=int,message=string} "没有待验证的邮箱"
+// @Failure 429 {object} object{code=int,message=string} "发送过于频繁"
+// @Router /user/email/resend [post]
+func (h *UserHandler) ResendVerifyEmail(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := h.userService.ResendVerifyEmail(userID.(string)); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.SuccessWithMessage(c, "验证邮件已发送，请查收", nil)
+}
+
+// VerifyEmail 验证邮箱
+// @Summary 验证邮箱
+// @Description 使用验证邮件中的令牌完成验证，令牌只能使用一次
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body object{token=string} true "验证令牌"
+// @Success 200 {object} object{code=int,message=string} "验证成功"
+// @Failure 400 {object} object{code=int,message=string} "链接无效或已过期"
+// @Router /user/email/verify [post]
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	if err := h.userService.VerifyEmail(req.Token); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.SuccessWithMessage(c, "邮箱验证成功", nil)
+}
+
+// ForgotPassword 忘记密码
+// @Summary 忘记密码
+// @Description 向已验证邮箱发送重置密码链接；无论邮箱是否注册都返回成功
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body object{email=string} true "已验证邮箱"
+// @Success 200 {object} object{code=int,message=string} "如果邮箱已注册，将收到重置邮件"
+// @Failure 400 {object} object{code=int,message=string} "邮箱格式错误"
+// @Router /user/password/forgot [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	if err := h.userService.ForgotPassword(req.Email); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.SuccessWithMessage(c, "如果该邮箱已绑定账号，你将收到重置密码邮件", nil)
+}
+
+// ResetPassword 重置密码
+// @Summary 重置密码
+// @Description 使用重置邮件中的令牌设置新密码，令牌只能使用一次
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body object{token=string,new_password=string} true "重置信息"
+// @Success 200 {object} object{code=int,message=string} "密码已重置"
+// @Failure 400 {object} object{code=int,message=string} "链接无效或密码不符合要求"
+// @Router /user/password/reset [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, err := h.userService.ResetPassword(req.Token, req.NewPassword)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	actx := auditContext(c)
+	actx.ActorID = userID
+	h.auditService.Record(actx, userAudit(model.AuditActionPasswordReset, userID))
+	util.SuccessWithMessage(c, "密码已重置，请重新登录", nil)
+}
+
+// boundPhone 已绑定的手机号，第三方登录注册的账号未绑定时为空
+func boundPhone(user *model.User) string {
+	if !user.HasPhone() {
+		return ""
+	}
+	return user.Phone
+}
+
+// socialLinks 社交账号链接，未设置时返回空对象
+func socialLinks(user *model.User) model.SocialLinks {
+	if user.SocialLinks == nil {
+		return model.SocialLinks{}
+	}
+	return user.SocialLinks
+}
+
+// formatBirthday 生日（YYYY-MM-DD），未设置时为空
+func formatBirthday(user *model.User) string {
+	if user.Birthday == nil {
+		return ""
+	}
+	return user.Birthday.Format("2006-01-02")
+}
+
+// respondBizError 业务错误响应（限流返回429，其余业务错误返回400）
+func respondBizError(c *gin.Context, err error) {
+	var bizErr *constant.BizError
+	switch {
+	case errors.Is(err, constant.ErrTooManyRequests):
+		util.TooManyRequests(c, err.Error())
+	case errors.As(err, &bizErr):
+		util.BadRequest(c, bizErr.Message)
+	default:
+		util.InternalServerError(c, err.Error())
+	}
+}
+
+// respondRiskError 风险检查未通过：需要验证码时带上captchaRequired，需要等待时带上retryAfter
+func respondRiskError(c *gin.Context, err error) {
+	var delayErr *service.RiskDelayError
+	switch {
+	case errors.As(err, &delayErr):
+		retryAfter := int(delayErr.RetryAfter.Seconds() + 0.5)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		util.ErrorWithData(c, constant.CodeTooManyRequests, delayErr.Error(), gin.H{"retryAfter": retryAfter})
+	case errors.Is(err, constant.ErrCaptchaRequired), errors.Is(err, constant.ErrCaptchaInvalid):
+		util.ErrorWithData(c, constant.CodeBadRequest, err.Error(), gin.H{"captchaRequired": true})
+	default:
+		respondBizError(c, err)
+	}
+}
+
+// SendLoginCode 发送登录验证码
+// @Summary 发送登录验证码
+// @Description 向手机号发送6位登录验证码，未注册的手机号验证后自动注册；同一手机号60秒内只能发送一次
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body object{phone=string} true "手机号"
+// @Success 200 {object} object{code=int,message=string} "验证码已发送"
+// @Failure 400 {object} object{code=int,message=string} "手机号格式错误或发送次数已达上限"
+// @Failure 429 {object} object{code=int,message=string} "发送过于频繁"
+// @Router /user/sms/send [post]
+func (h *UserHandler) SendLoginCode(c *gin.Context) {
+	var req struct {
+		Phone string `json:"phone" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	if err := h.userService.SendLoginCode(req.Phone, c.ClientIP()); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.SuccessWithMessage(c, "验证码已发送", nil)
+}
+
+// LoginBySMS 验证码登录
+// @Summary 验证码登录
+// @Description 使用手机号和短信验证码登录，手机号未注册时自动注册
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body object{phone=string,code=string} true "登录信息"
+// @Success 200 {object} object{code=int,message=string,data=object{token=string,user=object,isNew=bool,twoFactorRequired=bool,challengeToken=string}} "登录成功；开启两步验证时返回challengeToken"
+// @Failure 400 {object} object{code=int,message=string} "验证码错误或已失效"
+// @Router /user/login/sms [post]
+func (h *UserHandler) LoginBySMS(c *gin.Context) {
+	var req struct {
+		Phone string `json:"phone" binding:"required"`
+		Code  string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	result, err := h.userService.LoginBySMS(req.Phone, req.Code)
+	if err != nil {
+		h.recordLoginFailure(c, req.Phone, "sms", err)
+		respondBizError(c, err)
+		return
+	}
+	if result.User != nil {
+		h.recordLogin(c, result.User.ID, "sms", nil)
+	}
+	// 验证码登录证明持有手机号，记住本次登录的IP
+	h.riskService.RecordSuccess(service.RiskActionLogin, &service.RiskSubject{Phone: req.Phone, IP: c.ClientIP()})
+	respondLogin(c, constant.LoginSuccess, result)
+}
+
+// SendChangePhoneOldCode 更换手机号：向原手机号发送验证码
+// @Summary 更换手机号-发送原手机号验证码
+// @Tags 用户模块
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{code=int,message=string} "验证码已发送"
+// @Failure 429 {object} object{code=int,message=string} "发送过于频繁"
+// @Router /user/phone/old-code [post]
+func (h *UserHandler) SendChangePhoneOldCode(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := h.userService.SendChangePhoneOldCode(userID.(string), c.ClientIP()); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.SuccessWithMessage(c, "验证码已发送", nil)
+}
+
+// VerifyChangePhoneOld 更换手机号：验证原手机号
+// @Summary 更换手机号-验证原手机号
+// @Description 验证通过后返回更换凭证，10分钟内有效
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{code=string} true "原手机号验证码"
+// @Success 200 {object} object{code=int,data=object{ticket=string}} "更换凭证"
+// @Failure 400 {object} object{code=int,message=string} "验证码错误或已失效"
+// @Router /user/phone/verify-old [post]
+func (h *UserHandler) VerifyChangePhoneOld(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	ticket, err := h.userService.VerifyChangePhoneOld(userID.(string), req.Code)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.Success(c, gin.H{"ticket": ticket})
+}
+
+// SendChangePhoneNewCode 更换手机号：向新手机号发送验证码
+// @Summary 更换手机号-发送新手机号验证码
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{ticket=string,phone=string} true "更换凭证和新手机号"
+// @Success 200 {object} object{code=int,message=string} "验证码已发送"
+// @Failure 400 {object} object{code=int,message=string} "凭证无效或手机号已被使用"
+// @Failure 429 {object} object{code=int,message=string} "发送过于频繁"
+// @Router /user/phone/new-code [post]
+func (h *UserHandler) SendChangePhoneNewCode(c *gin.Context) {
+	var req struct {
+		Ticket string `json:"ticket" binding:"required"`
+		Phone  string `json:"phone" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.userService.SendChangePhoneNewCode(userID.(string), req.Ticket, req.Phone, c.ClientIP()); err != nil {
+		respondBizError(c, err)
+		return
+	}
+	util.SuccessWithMessage(c, "验证码已发送", nil)
+}
+
+// ChangePhone 更换手机号：验证新手机号并完成更换
+// @Summary 更换手机号
+// @Description 完成更换后返回新的token，旧token中的手机号已失效
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body object{ticket=string,phone=string,code=string} true "更换信息"
+// @Success 200 {object} object{code=int,message=string,data=object{token=string}} "更换成功"
+// @Failure 400 {object} object{code=int,message=string} "凭证无效、验证码错误或手机号已被使用"
+// @Router /user/phone [put]
+func (h *UserHandler) ChangePhone(c *gin.Context) {
+	var req struct {
+		Ticket string `json:"ticket" binding:"required"`
+		Phone  string `json:"phone" binding:"required"`
+		Code   string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.BadRequest(c, constant.ParamError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	token, err := h.userService.ChangePhone(userID.(string), req.Ticket, req.Phone, req.Code)
+	if err != nil {
+		respondBizError(c, err)
+		return
+	}
+	entry := userAudit(model.AuditActionPhoneChange, userID.(string))
+	entry.Before = model.JSONMap{"phone": util.MaskPhone(c.GetString("phone"))}
+	entry.After = model.JSONMap{"phone": util.MaskPhone(req.Phone)}
+	h.auditService.Record(auditContext(c), entry)
+	util.SuccessWithMessage(c, "手机号已更换", gin.H{"token": token})
 }
@@ -21,16 +21,26 @@ DROP TABLE IF EXISTS `user`;
 CREATE TABLE `user` (
   `id` VARCHAR(36) PRIMARY KEY COMMENT '主键(UUID)',
   `phone` VARCHAR(20) UNIQUE NOT NULL COMMENT '手机号',
+  `email` VARCHAR(255) UNIQUE DEFAULT NULL COMMENT '已验证邮箱',
+  `pending_email` VARCHAR(255) DEFAULT NULL COMMENT '待验证邮箱',
+  `handle` VARCHAR(30) NOT NULL COMMENT '个性ID（小写，唯一）',
   `username` VARCHAR(255) NOT NULL COMMENT '用户名',
   `password` VARCHAR(255) NOT NULL COMMENT '密码',
   `icon` VARCHAR(500) DEFAULT NULL COMMENT '头像',
   `sex` INT NOT NULL DEFAULT 1 COMMENT '性别(1->男 2->女)',
   `note` VARCHAR(500) DEFAULT NULL COMMENT '备注',
   `intro` VARCHAR(500) DEFAULT NULL COMMENT '个人简介',
+  `cover` VARCHAR(500) DEFAULT NULL COMMENT '主页封面',
+  `website` VARCHAR(255) DEFAULT NULL COMMENT '个人网站',
+  `social_links` JSON DEFAULT NULL COMMENT '社交账号链接',
+  `location` VARCHAR(100) DEFAULT NULL COMMENT '所在地',
+  `birthday` DATE DEFAULT NULL COMMENT '生日',
   `role` VARCHAR(20) NOT NULL DEFAULT 'user' COMMENT '角色:user-普通用户,admin-管理员,super_admin-超级管理员',
   `following_count` BIGINT NOT NULL DEFAULT 0 COMMENT '关注数量',
   `followed_count` BIGINT NOT NULL DEFAULT 0 COMMENT '被关注数量',
   `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `token_valid_after` DATETIME(3) DEFAULT NULL COMMENT '此前签发的token失效（重置密码、开启两步验证、注销账号时更新）',
+  UNIQUE KEY `uk_handle` (`handle`),
   INDEX `idx_phone` (`phone`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户表';
 
@@ -124,8 +134,9 @@ CREATE TABLE `article_v3` (
   `audit_time` DATETIME DEFAULT NULL,
   `audit_user_id` VARCHAR(36) DEFAULT NULL,
   -- 统计数据
-  `view_count` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `view_count` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '原始浏览量',
   `real_view_count` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '真实浏览量（去重）',
+  `human_view_count` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '真人浏览量（去重且排除爬虫）',
   `like_count` BIGINT UNSIGNED NOT NULL DEFAULT 0,
   `comment_count` BIGINT UNSIGNED NOT NULL DEFAULT 0,
   `share_count` BIGINT UNSIGNED NOT NULL DEFAULT 0,
@@ -191,15 +202,38 @@ CREATE TABLE `article_draft` (
   `column_id` BIGINT UNSIGNED DEFAULT NULL,
   `tags` VARCHAR(500) DEFAULT NULL,
   `topics` VARCHAR(500) DEFAULT NULL,
+  `version` INT NOT NULL DEFAULT 1 COMMENT '版本号（乐观锁，每次保存+1）',
   `auto_save_count` INT NOT NULL DEFAULT 0 COMMENT '自动保存次数',
   `last_edit_time` DATETIME DEFAULT NULL,
+  `last_device_id` VARCHAR(64) DEFAULT NULL COMMENT '最后保存的设备标识',
   `is_published` TINYINT(1) NOT NULL DEFAULT 0,
   `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `delete_time` DATETIME DEFAULT NULL COMMENT '软删除（进入回收站）',
   INDEX `idx_user` (`user_id`, `is_published`),
   INDEX `idx_article` (`article_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文章草稿表';
 
+-- 草稿快照表（自动保存时归档被覆盖的版本，每个草稿保留最近20份）
+DROP TABLE IF EXISTS `article_draft_snapshot`;
+CREATE TABLE `article_draft_snapshot` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `draft_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` VARCHAR(36) NOT NULL,
+  `version` INT NOT NULL COMMENT '快照对应的草稿版本',
+  `title` VARCHAR(200) DEFAULT NULL,
+  `summary` VARCHAR(500) DEFAULT NULL,
+  `cover_image` VARCHAR(500) DEFAULT NULL,
+  `content` LONGTEXT DEFAULT NULL,
+  `category_id` BIGINT UNSIGNED DEFAULT NULL,
+  `column_id` BIGINT UNSIGNED DEFAULT NULL,
+  `tags` VARCHAR(500) DEFAULT NULL,
+  `topics` VARCHAR(500) DEFAULT NULL,
+  `device_id` VARCHAR(64) DEFAULT NULL COMMENT '写入该版本的设备标识',
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX `idx_draft` (`draft_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='草稿快照表';
+
 -- 文章历史版本表
 DROP TABLE IF EXISTS `article_history`;
 CREATE TABLE `article_history` (
@@ -353,9 +387,62 @@ CREATE TABLE `article_stats_detail` (
   `region_stats` JSON DEFAULT NULL COMMENT '地域统计',
   `avg_read_progress` DECIMAL(5,2) NOT NULL DEFAULT 0 COMMENT '平均阅读进度（%）',
   `avg_stay_time` INT NOT NULL DEFAULT 0 COMMENT '平均停留时间（秒）',
+  `read_count` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '参与阅读统计的次数（计算平均值用）',
   `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文章统计详情表';
 
+-- 文章每日统计表
+DROP TABLE IF EXISTS `article_stats_daily`;
+CREATE TABLE `article_stats_daily` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `article_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` VARCHAR(36) DEFAULT NULL COMMENT '作者ID',
+  `stat_date` DATE NOT NULL COMMENT '统计日期',
+  `view_count` INT NOT NULL DEFAULT 0 COMMENT '当日浏览量',
+  `unique_view_count` INT NOT NULL DEFAULT 0 COMMENT '当日去重浏览量',
+  `like_count` INT NOT NULL DEFAULT 0 COMMENT '当日点赞',
+  `comment_count` INT NOT NULL DEFAULT 0 COMMENT '当日评论',
+  `favorite_count` INT NOT NULL DEFAULT 0 COMMENT '当日收藏',
+  `share_count` INT NOT NULL DEFAULT 0 COMMENT '当日分享',
+  `total_views` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '日终累计浏览量（计算次日增量）',
+  `total_unique_views` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `total_likes` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `total_comments` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `total_favorites` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `total_shares` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `snapshot_time` DATETIME DEFAULT NULL COMMENT '日终快照时间（为空表示当日仅有阅读上报）',
+  `read_count` INT NOT NULL DEFAULT 0 COMMENT '阅读上报次数',
+  `progress_sum` DECIMAL(12,2) NOT NULL DEFAULT 0 COMMENT '阅读进度合计（%）',
+  `stay_time_sum` BIGINT NOT NULL DEFAULT 0 COMMENT '停留时间合计（秒）',
+  `source_stats` JSON DEFAULT NULL COMMENT '流量来源统计',
+  `device_stats` JSON DEFAULT NULL COMMENT '设备统计',
+  `region_stats` JSON DEFAULT NULL COMMENT '地域统计',
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_article_date` (`article_id`, `stat_date`),
+  INDEX `idx_user_date` (`user_id`, `stat_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文章每日统计表';
+
+-- 作者每日统计表
+DROP TABLE IF EXISTS `author_stats_daily`;
+CREATE TABLE `author_stats_daily` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL COMMENT '作者ID',
+  `stat_date` DATE NOT NULL COMMENT '统计日期',
+  `view_count` INT NOT NULL DEFAULT 0,
+  `unique_view_count` INT NOT NULL DEFAULT 0,
+  `like_count` INT NOT NULL DEFAULT 0,
+  `comment_count` INT NOT NULL DEFAULT 0,
+  `favorite_count` INT NOT NULL DEFAULT 0,
+  `share_count` INT NOT NULL DEFAULT 0,
+  `new_followers` INT NOT NULL DEFAULT 0 COMMENT '当日新增粉丝',
+  `follower_count` BIGINT NOT NULL DEFAULT 0 COMMENT '日终粉丝总数',
+  `article_count` INT NOT NULL DEFAULT 0 COMMENT '日终已发布文章数',
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_user_date` (`user_id`, `stat_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作者每日统计表';
+
 -- ============================================
 -- 4. 评论模块（旧版）
 -- ============================================
@@ -651,6 +738,445 @@ CREATE TABLE `tag` (
   `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间'
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='标签表';
 
+-- ============================================
+-- 8. 动态模块
+-- ============================================
+
+-- 动态表
+DROP TABLE IF EXISTS `dynamic`;
+CREATE TABLE `dynamic` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL COMMENT '发布者ID',
+  `content` VARCHAR(2000) NOT NULL COMMENT '动态内容',
+  `images` VARCHAR(5000) DEFAULT NULL COMMENT '图片URL（JSON数组，最多9张）',
+  `topics` VARCHAR(500) DEFAULT NULL COMMENT '话题（JSON数组）',
+  `repost_type` TINYINT NOT NULL DEFAULT 0 COMMENT '0-原创 1-转发动态 2-转发文章',
+  `repost_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '被转发对象ID',
+  `status` TINYINT NOT NULL DEFAULT 1 COMMENT '1-正常 2-已删除',
+  `allow_comment` TINYINT(1) NOT NULL DEFAULT 1,
+  `allow_repost` TINYINT(1) NOT NULL DEFAULT 1,
+  `like_count` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `comment_count` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `repost_count` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `ip` VARCHAR(50) DEFAULT NULL,
+  `ip_location` VARCHAR(100) DEFAULT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `delete_time` DATETIME DEFAULT NULL,
+  INDEX `idx_user_time` (`user_id`, `create_time`),
+  INDEX `idx_repost` (`repost_type`, `repost_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='动态表';
+
+-- 动态话题关联表
+DROP TABLE IF EXISTS `dynamic_topic_rel`;
+CREATE TABLE `dynamic_topic_rel` (
+  `dynamic_id` BIGINT UNSIGNED NOT NULL,
+  `topic_id` BIGINT UNSIGNED NOT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`dynamic_id`, `topic_id`),
+  INDEX `idx_topic` (`topic_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='动态话题关联表';
+
+-- ============================================
+-- 9. 文章导入模块
+-- ============================================
+
+-- 文章导入任务表
+DROP TABLE IF EXISTS `article_import_job`;
+CREATE TABLE `article_import_job` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL COMMENT '用户ID',
+  `source_type` TINYINT NOT NULL COMMENT '1-Markdown压缩包 2-WordPress WXR',
+  `file_name` VARCHAR(255) DEFAULT NULL COMMENT '原始文件名',
+  `object_name` VARCHAR(500) DEFAULT NULL COMMENT '导入包在对象存储中的路径',
+  `as_draft` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否全部导入为草稿',
+  `status` TINYINT NOT NULL DEFAULT 0 COMMENT '0-等待中 1-执行中 2-已完成 3-失败',
+  `total` INT NOT NULL DEFAULT 0 COMMENT '待导入总数',
+  `processed` INT NOT NULL DEFAULT 0 COMMENT '已处理数',
+  `success_count` INT NOT NULL DEFAULT 0,
+  `fail_count` INT NOT NULL DEFAULT 0,
+  `results` JSON DEFAULT NULL COMMENT '逐篇导入结果',
+  `error_message` VARCHAR(500) DEFAULT NULL,
+  `start_time` DATETIME DEFAULT NULL,
+  `finish_time` DATETIME DEFAULT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX `idx_user` (`user_id`, `create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文章导入任务表';
+
+-- ============================================
+-- 10. 专栏导出模块
+-- ============================================
+
+-- 专栏电子书导出缓存表
+DROP TABLE IF EXISTS `column_export`;
+CREATE TABLE `column_export` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `column_id` BIGINT UNSIGNED NOT NULL COMMENT '专栏ID',
+  `format` VARCHAR(10) NOT NULL COMMENT 'epub/html',
+  `revision` VARCHAR(64) DEFAULT NULL COMMENT '专栏内容版本（专栏、文章顺序及内容的摘要）',
+  `status` TINYINT NOT NULL DEFAULT 0 COMMENT '0-等待中 1-生成中 2-已完成 3-失败',
+  `object_name` VARCHAR(500) DEFAULT NULL COMMENT '对象存储路径',
+  `file_url` VARCHAR(500) DEFAULT NULL COMMENT '下载地址',
+  `file_size` BIGINT NOT NULL DEFAULT 0,
+  `error_message` VARCHAR(500) DEFAULT NULL,
+  `generate_time` DATETIME DEFAULT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_column_format` (`column_id`, `format`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='专栏电子书导出缓存表';
+
+-- ============================================
+-- 11. 文章查重模块
+-- ============================================
+
+-- 文章内容指纹表（64位SimHash按16位拆为4段分别索引）
+DROP TABLE IF EXISTS `article_fingerprint`;
+CREATE TABLE `article_fingerprint` (
+  `article_id` BIGINT UNSIGNED PRIMARY KEY COMMENT '文章ID',
+  `user_id` VARCHAR(36) NOT NULL COMMENT '作者ID',
+  `sim_hash` BIGINT UNSIGNED NOT NULL COMMENT 'SimHash指纹',
+  `band0` SMALLINT UNSIGNED NOT NULL,
+  `band1` SMALLINT UNSIGNED NOT NULL,
+  `band2` SMALLINT UNSIGNED NOT NULL,
+  `band3` SMALLINT UNSIGNED NOT NULL,
+  `token_count` INT NOT NULL DEFAULT 0 COMMENT '特征词数量',
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX `idx_user` (`user_id`),
+  INDEX `idx_band0` (`band0`),
+  INDEX `idx_band1` (`band1`),
+  INDEX `idx_band2` (`band2`),
+  INDEX `idx_band3` (`band3`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文章内容指纹表';
+
+-- 疑似重复记录表（article_id为较晚发布的文章，source_article_id为较早的原文）
+DROP TABLE IF EXISTS `article_duplicate`;
+CREATE TABLE `article_duplicate` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `article_id` BIGINT UNSIGNED NOT NULL COMMENT '疑似重复文章ID',
+  `user_id` VARCHAR(36) NOT NULL,
+  `source_article_id` BIGINT UNSIGNED NOT NULL COMMENT '原文ID',
+  `source_user_id` VARCHAR(36) NOT NULL,
+  `distance` INT NOT NULL COMMENT '汉明距离',
+  `similarity` DECIMAL(5,2) NOT NULL DEFAULT 0 COMMENT '相似度（%）',
+  `status` TINYINT NOT NULL DEFAULT 0 COMMENT '0-待处理 1-确认重复 2-已排除',
+  `handler_id` VARCHAR(36) DEFAULT NULL,
+  `handle_note` VARCHAR(500) DEFAULT NULL,
+  `handle_time` DATETIME DEFAULT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_pair` (`article_id`, `source_article_id`),
+  INDEX `idx_source` (`source_article_id`),
+  INDEX `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='疑似重复记录表';
+
+-- 原创申诉表
+DROP TABLE IF EXISTS `article_originality_claim`;
+CREATE TABLE `article_originality_claim` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `article_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` VARCHAR(36) NOT NULL,
+  `statement` VARCHAR(1000) NOT NULL COMMENT '原创说明',
+  `evidence` JSON DEFAULT NULL COMMENT '证明材料链接',
+  `status` TINYINT NOT NULL DEFAULT 0 COMMENT '0-待审核 1-通过 2-驳回',
+  `reviewer_id` VARCHAR(36) DEFAULT NULL,
+  `review_note` VARCHAR(500) DEFAULT NULL,
+  `review_time` DATETIME DEFAULT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX `idx_article` (`article_id`),
+  INDEX `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='原创申诉表';
+
+-- ============================================
+-- 12. 文章质量评分模块
+-- ============================================
+
+-- 文章质量评分明细表（总分同步写入 article_v3.quality_score）
+DROP TABLE IF EXISTS `article_quality_score`;
+CREATE TABLE `article_quality_score` (
+  `article_id` BIGINT UNSIGNED PRIMARY KEY COMMENT '文章ID',
+  `score` DECIMAL(5,2) NOT NULL DEFAULT 0 COMMENT '总分（0-100）',
+  `scorer` VARCHAR(50) NOT NULL COMMENT '评分器名称及版本',
+  `components` JSON DEFAULT NULL COMMENT '各维度得分明细',
+  `score_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX `idx_score` (`score`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文章质量评分明细表';
+
+-- ============================================
+-- 13. 旧版数据迁移模块
+-- ============================================
+
+-- 旧版ID映射表（旧链接跳转用）
+DROP TABLE IF EXISTS `legacy_id_map`;
+CREATE TABLE `legacy_id_map` (
+  `entity` VARCHAR(20) NOT NULL COMMENT 'article/draft/comment_parent/comment_sub',
+  `old_id` BIGINT UNSIGNED NOT NULL COMMENT '旧表ID',
+  `new_id` BIGINT UNSIGNED NOT NULL COMMENT 'V3表ID',
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`entity`, `old_id`),
+  INDEX `idx_new` (`entity`, `new_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='旧版ID映射表';
+
+-- 迁移进度表（按阶段记录检查点）
+DROP TABLE IF EXISTS `legacy_migration_progress`;
+CREATE TABLE `legacy_migration_progress` (
+  `stage` VARCHAR(30) PRIMARY KEY COMMENT 'articles/comments/replies/stars/favorites/counters',
+  `last_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '已处理到的源表ID',
+  `max_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '首次运行时源表的最大ID（快照）',
+  `processed` BIGINT NOT NULL DEFAULT 0,
+  `skipped` BIGINT NOT NULL DEFAULT 0,
+  `conflicts` BIGINT NOT NULL DEFAULT 0 COMMENT '无法确定归属而跳过的记录数',
+  `done` TINYINT(1) NOT NULL DEFAULT 0,
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='旧版数据迁移进度表';
+
+-- ============================================
+-- 14. 回收站模块
+-- ============================================
+
+-- 回收站条目表（删除的文章/草稿/评论，超过保留期由定时任务彻底清除）
+DROP TABLE IF EXISTS `trash_item`;
+CREATE TABLE `trash_item` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `entity_type` VARCHAR(20) NOT NULL COMMENT 'article/draft/comment',
+  `entity_id` BIGINT UNSIGNED NOT NULL,
+  `title` VARCHAR(200) DEFAULT NULL COMMENT '标题或评论摘要（快照）',
+  `prev_status` TINYINT NOT NULL DEFAULT 0 COMMENT '删除前的状态（恢复时还原）',
+  `delete_time` DATETIME NOT NULL,
+  UNIQUE KEY `uk_entity` (`entity_type`, `entity_id`),
+  INDEX `idx_user_type` (`user_id`, `entity_type`),
+  INDEX `idx_delete_time` (`delete_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='回收站条目表';
+
+-- ============================================
+-- 15. 共同创作模块
+-- ============================================
+
+-- 文章/专栏协作者表（主作者仍记录在 article_v3.user_id / article_column.user_id）
+DROP TABLE IF EXISTS `content_contributor`;
+CREATE TABLE `content_contributor` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `target_type` VARCHAR(20) NOT NULL COMMENT 'article/column',
+  `target_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` VARCHAR(36) NOT NULL,
+  `role` VARCHAR(20) NOT NULL COMMENT 'owner/editor/reviewer',
+  `status` TINYINT NOT NULL DEFAULT 0 COMMENT '0-待接受 1-已接受',
+  `invited_by` VARCHAR(36) NOT NULL,
+  `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+  `accept_time` DATETIME DEFAULT NULL,
+  UNIQUE KEY `uk_target_user` (`target_type`, `target_id`, `user_id`),
+  INDEX `idx_user_status` (`user_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文章/专栏协作者表';
+
+-- ============================================
+-- 16. 专栏阅读进度
+-- ============================================
+
+-- 专栏阅读记录表（每个用户在专栏中读过的章节，一篇一条）
+DROP TABLE IF EXISTS `column_read_record`;
+CREATE TABLE `column_read_record` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `column_id` BIGINT UNSIGNED NOT NULL,
+  `article_id` BIGINT UNSIGNED NOT NULL,
+  `read_time` DATETIME NOT NULL,
+  UNIQUE KEY `uk_user_column_article` (`user_id`, `column_id`, `article_id`),
+  INDEX `idx_user_read_time` (`user_id`, `read_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='专栏阅读记录表';
+
+-- ============================================
+-- 17. 两步验证
+-- ============================================
+
+-- 两步验证表（TOTP，开启前保存待确认的密钥）
+DROP TABLE IF EXISTS `user_two_factor`;
+CREATE TABLE `user_two_factor` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `secret` VARCHAR(64) NOT NULL COMMENT 'Base32密钥',
+  `enabled` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否已开启',
+  `last_used_step` BIGINT NOT NULL DEFAULT 0 COMMENT '最近一次通过验证的时间步，防止验证码重放',
+  `enabled_at` DATETIME DEFAULT NULL,
+  `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='两步验证表';
+
+-- 两步验证恢复码表（只保存哈希，每个只能使用一次）
+DROP TABLE IF EXISTS `user_recovery_code`;
+CREATE TABLE `user_recovery_code` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `code_hash` CHAR(64) NOT NULL COMMENT 'SHA-256',
+  `used_at` DATETIME DEFAULT NULL,
+  `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+  INDEX `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='两步验证恢复码表';
+
+-- ============================================
+-- 18. 第三方登录
+-- ============================================
+
+-- 第三方账号绑定表（同一提供方的同一账号只能绑定一个用户）
+DROP TABLE IF EXISTS `user_identity`;
+CREATE TABLE `user_identity` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `provider` VARCHAR(32) NOT NULL COMMENT '提供方名称',
+  `subject` VARCHAR(128) NOT NULL COMMENT '提供方内的账号ID',
+  `username` VARCHAR(255) DEFAULT NULL COMMENT '第三方用户名（最近一次登录时）',
+  `email` VARCHAR(255) DEFAULT NULL COMMENT '第三方邮箱',
+  `avatar` VARCHAR(500) DEFAULT NULL,
+  `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+  `update_time` DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_provider_subject` (`provider`, `subject`),
+  INDEX `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='第三方账号绑定表';
+
+-- ============================================
+-- 19. 账号注销与数据导出
+-- ============================================
+
+-- 个人数据导出任务表（压缩包存放在对象存储，过期后删除）
+DROP TABLE IF EXISTS `user_data_export`;
+CREATE TABLE `user_data_export` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `status` TINYINT NOT NULL DEFAULT 0 COMMENT '0-等待中 1-生成中 2-已完成 3-失败 4-已过期',
+  `object_name` VARCHAR(500) DEFAULT NULL COMMENT '对象存储路径',
+  `file_size` BIGINT NOT NULL DEFAULT 0,
+  `error_message` VARCHAR(500) DEFAULT NULL,
+  `generate_time` DATETIME DEFAULT NULL,
+  `expire_time` DATETIME DEFAULT NULL COMMENT '下载截止时间',
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX `idx_user_id` (`user_id`),
+  INDEX `idx_expire_time` (`expire_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='个人数据导出任务表';
+
+-- 账号注销申请表（冷静期结束后执行，每个用户一条）
+DROP TABLE IF EXISTS `user_account_deletion`;
+CREATE TABLE `user_account_deletion` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `status` TINYINT NOT NULL DEFAULT 0 COMMENT '0-冷静期 1-已注销 2-已撤销',
+  `reason` VARCHAR(500) DEFAULT NULL COMMENT '注销原因',
+  `scheduled_time` DATETIME NOT NULL COMMENT '计划执行时间',
+  `request_time` DATETIME NOT NULL,
+  `complete_time` DATETIME DEFAULT NULL,
+  UNIQUE KEY `uk_user_id` (`user_id`),
+  INDEX `idx_status_scheduled` (`status`, `scheduled_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='账号注销申请表';
+
+-- ============================================
+-- 20. 隐私设置
+-- ============================================
+
+-- 用户隐私设置表（没有记录时使用默认设置）
+DROP TABLE IF EXISTS `user_privacy`;
+CREATE TABLE `user_privacy` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `followers_visibility` VARCHAR(20) NOT NULL DEFAULT 'everyone' COMMENT '谁可以看我的粉丝列表',
+  `following_visibility` VARCHAR(20) NOT NULL DEFAULT 'everyone' COMMENT '谁可以看我的关注列表',
+  `message_permission` VARCHAR(20) NOT NULL DEFAULT 'friends' COMMENT '谁可以给我发私信',
+  `comment_permission` VARCHAR(20) NOT NULL DEFAULT 'everyone' COMMENT '谁可以评论我的文章',
+  `birthday_visibility` VARCHAR(20) NOT NULL DEFAULT 'everyone' COMMENT '谁可以看我的生日（仅月日，年份只有本人可见）',
+  `searchable` TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否出现在用户搜索中',
+  `show_in_trending` TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否出现在热门用户榜单中',
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_user_id` (`user_id`),
+  INDEX `idx_searchable` (`searchable`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户隐私设置表';
+
+-- ============================================
+-- 21. 个性ID与改名记录
+-- ============================================
+
+-- 用户名和个性ID修改记录表（旧个性ID在跳转期内保留给原用户）
+DROP TABLE IF EXISTS `user_name_history`;
+CREATE TABLE `user_name_history` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `kind` TINYINT NOT NULL COMMENT '1-用户名 2-个性ID',
+  `old_value` VARCHAR(255) NOT NULL,
+  `new_value` VARCHAR(255) NOT NULL,
+  `redirect_until` DATETIME DEFAULT NULL COMMENT '旧个性ID跳转截止时间',
+  `change_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX `idx_user_kind` (`user_id`, `kind`),
+  INDEX `idx_old_value` (`old_value`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户名和个性ID修改记录表';
+
+-- ============================================
+-- 22. 用户等级、经验与徽章
+-- ============================================
+
+-- 用户累计经验表（等级由经验和配置的等级门槛计算）
+DROP TABLE IF EXISTS `user_xp`;
+CREATE TABLE `user_xp` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `xp` BIGINT NOT NULL DEFAULT 0 COMMENT '累计经验',
+  `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_user_id` (`user_id`),
+  INDEX `idx_xp` (`xp`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户累计经验表';
+
+-- 经验流水表（同一事件只计一次，超出每日上限时经验为0）
+DROP TABLE IF EXISTS `user_xp_log`;
+CREATE TABLE `user_xp_log` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `event` VARCHAR(32) NOT NULL COMMENT '经验事件',
+  `xp` INT NOT NULL DEFAULT 0,
+  `biz_key` VARCHAR(100) NOT NULL COMMENT '事件唯一键',
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_user_biz` (`user_id`, `biz_key`),
+  INDEX `idx_user_event_time` (`user_id`, `event`, `create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='经验流水表';
+
+-- 用户徽章表
+DROP TABLE IF EXISTS `user_badge`;
+CREATE TABLE `user_badge` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `user_id` VARCHAR(36) NOT NULL,
+  `badge` VARCHAR(32) NOT NULL COMMENT '徽章编码',
+  `award_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY `uk_user_badge` (`user_id`, `badge`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户徽章表';
+
+-- ============================================
+-- 23. 安全审计日志
+-- ============================================
+
+-- 审计日志表（只追加不修改，超过保留期后移入归档表）
+DROP TABLE IF EXISTS `audit_log`;
+CREATE TABLE `audit_log` (
+  `id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+  `actor_id` VARCHAR(36) DEFAULT NULL COMMENT '操作者ID（未登录时为空）',
+  `actor_role` VARCHAR(20) DEFAULT NULL COMMENT '操作时的管理员角色',
+  `action` VARCHAR(50) NOT NULL COMMENT '操作',
+  `target_type` VARCHAR(30) DEFAULT NULL COMMENT '对象类型：user/comment/comment_report/sensitive_word',
+  `target_id` VARCHAR(64) DEFAULT NULL COMMENT '对象ID',
+  `result` TINYINT NOT NULL DEFAULT 1 COMMENT '1-成功 2-失败',
+  `before` JSON DEFAULT NULL COMMENT '修改前',
+  `after` JSON DEFAULT NULL COMMENT '修改后',
+  `detail` VARCHAR(255) DEFAULT NULL COMMENT '说明（如失败原因、登录方式）',
+  `ip` VARCHAR(45) DEFAULT NULL,
+  `user_agent` VARCHAR(255) DEFAULT NULL,
+  `request_id` VARCHAR(64) DEFAULT NULL COMMENT '请求ID（X-Request-ID）',
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX `idx_actor_time` (`actor_id`, `create_time`),
+  INDEX `idx_action_time` (`action`, `create_time`),
+  INDEX `idx_target_time` (`target_type`, `target_id`, `create_time`),
+  INDEX `idx_request_id` (`request_id`),
+  INDEX `idx_create_time` (`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='安全审计日志表';
+
+-- 审计日志归档表（结构与主表相同）
+DROP TABLE IF EXISTS `audit_log_archive`;
+CREATE TABLE `audit_log_archive` LIKE `audit_log`;
+ALTER TABLE `audit_log_archive` COMMENT='安全审计日志归档表';
+
 -- ============================================
 -- 初始化完成
 -- ============================================
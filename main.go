@@ -6,11 +6,15 @@ import (
 	"astronomer-gin/pkg/database"
 	"astronomer-gin/pkg/elasticsearch"
 	"astronomer-gin/pkg/email"
+	"astronomer-gin/pkg/geoip"
 	"astronomer-gin/pkg/minio"
+	"astronomer-gin/pkg/oauth"
 	"astronomer-gin/pkg/queue"
 	"astronomer-gin/pkg/redis"
+	"astronomer-gin/pkg/sms"
 	"astronomer-gin/repository"
 	"astronomer-gin/router"
+	"astronomer-gin/service"
 	"astronomer-gin/worker"
 	"fmt"
 	"log"
@@ -104,6 +108,21 @@ func main() {
 		log.Printf("⚠️  初始化邮件服务失败: %v (将禁用邮件功能)", err)
 	}
 
+	// 初始化短信通道
+	if err := sms.InitSMS(&cfg.SMS); err != nil {
+		log.Printf("⚠️  初始化短信服务失败: %v (短信将打印到日志)", err)
+	}
+
+	// 初始化第三方登录（必须在注册路由之前，模拟提供方的路由依赖该配置）
+	if err := oauth.InitOAuth(&cfg.OAuth); err != nil {
+		log.Printf("⚠️  初始化第三方登录失败: %v", err)
+	}
+
+	// 初始化离线IP地址库（可选）
+	if err := geoip.InitGeoIP(&cfg.GeoIP); err != nil {
+		log.Printf("⚠️  初始化IP地址库失败: %v (地域统计将记为未知)", err)
+	}
+
 	// 初始化RabbitMQ
 	if err := queue.InitRabbitMQ(&cfg.RabbitMQ); err != nil {
 		log.Fatalf("初始化RabbitMQ失败: %v", err)
@@ -115,12 +134,51 @@ func main() {
 	db := database.GetDB()
 	notifyRepo := repository.NewNotificationRepository(db)
 	userRepo := repository.NewUserRepository(db)
-	blogRepo := repository.NewBlogRepository(db)
+	articleV3Repo := repository.NewArticleV3Repository(db)
+	columnRepo := repository.NewColumnRepository(db)
+	columnChapterService := service.NewColumnChapterService(columnRepo, articleV3Repo, notifyRepo)
+	importRepo := repository.NewArticleImportRepository(db)
+	qualityService := service.NewArticleQualityService(repository.NewArticleQualityRepository(db), articleV3Repo, nil)
+	articleV3Service := service.NewArticleV3Service(
+		articleV3Repo,
+		userRepo,
+		repository.NewFollowRepository(db),
+		repository.NewLikeRepository(db),
+		repository.NewFavoriteRepository(db),
+		service.NewArticleDuplicateService(repository.NewArticleDuplicateRepository(db), articleV3Repo),
+		qualityService,
+		repository.NewContributorRepository(db),
+		columnChapterService,
+		service.NewLevelService(repository.NewLevelRepository(db), userRepo, notifyRepo, cfg.Level),
+		db,
+	)
+	importService := service.NewArticleImportService(importRepo, articleV3Repo, articleV3Service)
+	columnExportService := service.NewColumnExportService(
+		repository.NewColumnExportRepository(db),
+		columnRepo,
+		articleV3Repo,
+		userRepo,
+	)
+	articleStatsRepo := repository.NewArticleStatsRepository(db)
+	engagementService := service.NewEngagementService(articleStatsRepo, articleV3Repo)
+	accountService := service.NewAccountService(
+		repository.NewAccountRepository(db),
+		userRepo,
+		repository.NewUserIdentityRepository(db),
+		notifyRepo,
+		cfg.Account,
+	)
 
 	// 创建各种处理器
 	notificationHandler := worker.NewNotificationHandler(notifyRepo, userRepo)
-	statsHandler := worker.NewStatsHandler(blogRepo)
-	combinedHandler := worker.NewCombinedHandler(notificationHandler, statsHandler)
+	statsHandler := worker.NewStatsHandler(articleV3Repo)
+	importHandler := worker.NewImportHandler(importService)
+	columnExportHandler := worker.NewColumnExportHandler(columnExportService)
+	engagementHandler := worker.NewEngagementHandler(engagementService)
+	columnChapterHandler := worker.NewColumnChapterHandler(columnChapterService)
+	smsHandler := worker.NewSMSHandler()
+	accountExportHandler := worker.NewAccountExportHandler(accountService)
+	combinedHandler := worker.NewCombinedHandler(notificationHandler, statsHandler, importHandler, columnExportHandler, engagementHandler, columnChapterHandler, smsHandler, accountExportHandler)
 
 	// 启动Worker（5个并发）
 	taskWorker := worker.NewTaskWorker(queue.Client, combinedHandler, 5)
@@ -128,10 +186,18 @@ func main() {
 		log.Fatalf("启动Task Worker失败: %v", err)
 	}
 	defer taskWorker.Stop()
-	log.Println("Task Worker启动成功 (5个并发worker,支持通知和统计任务)")
+	log.Println("Task Worker启动成功 (5个并发worker,支持通知、统计、导入和导出任务)")
 
 	// 初始化并启动定时任务
-	cronManager := cron.NewCronManager(db, blogRepo)
+	trashService := service.NewTrashService(
+		repository.NewTrashRepository(db),
+		articleV3Repo,
+		repository.NewCommentV3Repository(db),
+		repository.NewDynamicRepository(db),
+		service.NewUploadServiceV2(),
+	)
+	cronManager := cron.NewCronManager(db, articleStatsRepo, repository.NewCounterRepository(db), qualityService, trashService, cfg.Trash, accountService,
+		service.NewAuditService(repository.NewAuditRepository(db), cfg.Audit))
 	if err := cronManager.Start(); err != nil {
 		log.Fatalf("启动定时任务失败: %v", err)
 	}
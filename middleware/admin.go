@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"astronomer-gin/config"
 	"astronomer-gin/model"
 	"astronomer-gin/pkg/constant"
 	"astronomer-gin/pkg/database"
@@ -24,7 +25,7 @@ func AdminMiddleware() gin.HandlerFunc {
 		// 查询用户角色
 		var user model.User
 		db := database.GetDB()
-		if err := db.Where("phone = ?", phone).Select("role").First(&user).Error; err != nil {
+		if err := db.Where("phone = ?", phone).Select("id, role").First(&user).Error; err != nil {
 			util.Forbidden(c, constant.ErrPermissionDenied.Message)
 			c.Abort()
 			return
@@ -37,6 +38,13 @@ func AdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 角色要求两步验证时，未开启不能使用管理功能
+		if twoFactorMissing(&user) {
+			util.Forbidden(c, constant.ErrTwoFactorRequired.Message)
+			c.Abort()
+			return
+		}
+
 		// 设置管理员标识
 		c.Set("is_admin", true)
 		c.Set("admin_role", user.Role)
@@ -60,7 +68,7 @@ func SuperAdminMiddleware() gin.HandlerFunc {
 		// 查询用户角色
 		var user model.User
 		db := database.GetDB()
-		if err := db.Where("phone = ?", phone).Select("role").First(&user).Error; err != nil {
+		if err := db.Where("phone = ?", phone).Select("id, role").First(&user).Error; err != nil {
 			util.Forbidden(c, constant.PermissionDenied)
 			c.Abort()
 			return
@@ -73,9 +81,26 @@ func SuperAdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if twoFactorMissing(&user) {
+			util.Forbidden(c, constant.ErrTwoFactorRequired.Message)
+			c.Abort()
+			return
+		}
+
 		// 设置超级管理员标识
 		c.Set("is_super_admin", true)
+		c.Set("admin_role", user.Role)
 
 		c.Next()
 	}
 }
+
+// twoFactorMissing 角色要求两步验证但用户尚未开启
+func twoFactorMissing(user *model.User) bool {
+	if config.GlobalConfig == nil || !config.GlobalConfig.Security.TwoFactorRequired(user.Role) {
+		return false
+	}
+	var count int64
+	database.GetDB().Model(&model.UserTwoFactor{}).Where("user_id = ? AND enabled = ?", user.ID, true).Count(&count)
+	return count == 0
+}
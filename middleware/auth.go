@@ -1,9 +1,13 @@
 package middleware
 
 import (
+	"astronomer-gin/model"
 	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/database"
 	"astronomer-gin/pkg/jwt"
+	"astronomer-gin/pkg/redis"
 	"astronomer-gin/pkg/util"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -36,8 +40,8 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 验证UserID是否存在
-		if claims.UserID == "" {
+		// 验证UserID是否存在，且token未被吊销
+		if claims.UserID == "" || tokenRevoked(c, claims) {
 			util.Unauthorized(c, constant.TokenInvalid)
 			c.Abort()
 			return
@@ -80,8 +84,8 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 		}
 
 		// 验证UserID是否存在
-		if claims.UserID == "" {
-			// UserID为空，不报错，继续执行
+		if claims.UserID == "" || tokenRevoked(c, claims) {
+			// UserID为空或token已失效，不报错，按未登录继续执行
 			c.Next()
 			return
 		}
@@ -94,3 +98,30 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// tokenRevoked token已失效：更换手机号后携带旧手机号，或在重置密码、开启两步验证、注销账号之前签发
+// 优先读取Redis中的记录（保留到旧token全部过期），Redis不可用时回退查库
+func tokenRevoked(c *gin.Context, claims *jwt.LoginClaims) bool {
+	if rdb := redis.GetClient(); rdb != nil {
+		values, err := rdb.MGet(c, constant.CacheKeyTokenPhone+claims.UserID, constant.CacheKeyTokenValid+claims.UserID).Result()
+		if err == nil {
+			if phone, ok := values[0].(string); ok && phone != claims.Phone {
+				return true
+			}
+			if raw, ok := values[1].(string); ok {
+				validAfter, err := strconv.ParseInt(raw, 10, 64)
+				return err != nil || claims.IssuedBefore(validAfter)
+			}
+			return false
+		}
+	}
+
+	var user model.User
+	if err := database.GetDB().Where("id = ?", claims.UserID).Select("id, phone, token_valid_after").First(&user).Error; err != nil {
+		return true
+	}
+	if user.Phone != claims.Phone {
+		return true
+	}
+	return user.TokenValidAfter != nil && claims.IssuedBefore(user.TokenValidAfter.UnixMilli())
+}
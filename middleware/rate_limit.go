@@ -110,6 +110,15 @@ func FollowRateLimit() gin.HandlerFunc {
 	})
 }
 
+// PasswordRateLimit 找回/重置密码限流（5次/小时）
+func PasswordRateLimit() gin.HandlerFunc {
+	return RateLimit(RateLimitConfig{
+		MaxRequests: constant.RateLimitPassword,
+		Window:      time.Hour,
+		KeyPrefix:   "password",
+	})
+}
+
 // getUserKey 获取用户唯一标识
 func getUserKey(c *gin.Context) string {
 	// 优先使用用户phone（已登录用户）
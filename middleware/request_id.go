@@ -1,17 +1,22 @@
 package middleware
 
 import (
+	"regexp"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// requestIDPattern 允许客户端传入的Request ID（与日志、审计日志的存储长度一致）
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
 // RequestID 请求追踪中间件
 // 为每个请求生成唯一的Request ID，用于日志追踪和问题排查
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 从请求头获取或生成新的Request ID
+		// 从请求头获取，为空或格式不合法时生成新的Request ID
 		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
+		if !requestIDPattern.MatchString(requestID) {
 			requestID = uuid.New().String()
 		}
 
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// ==================== 账号注销与数据导出 ====================
+
+// DeletedUsername 已注销账号对外展示的用户名
+const DeletedUsername = "已注销用户"
+
+// DeletedPhonePrefix 已注销账号的占位手机号前缀（phone 列唯一且非空，注销后释放原手机号）
+const DeletedPhonePrefix = "deleted_"
+
+// UserDataExport 个人数据导出任务（压缩包存放在对象存储，过期后删除）
+type UserDataExport struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       string     `gorm:"type:varchar(36);not null;index:idx_user_id" json:"user_id"`
+	Status       int8       `gorm:"type:tinyint;default:0;comment:'0-等待中 1-生成中 2-已完成 3-失败 4-已过期'" json:"status"`
+	ObjectName   string     `gorm:"type:varchar(500)" json:"-"`
+	FileSize     int64      `gorm:"default:0" json:"file_size"`
+	ErrorMessage string     `gorm:"type:varchar(500)" json:"error_message,omitempty"`
+	GenerateTime *time.Time `json:"generate_time"`
+	ExpireTime   *time.Time `gorm:"index:idx_expire_time;comment:'下载截止时间'" json:"expire_time"`
+	CreateTime   time.Time  `gorm:"autoCreateTime" json:"create_time"`
+	UpdateTime   time.Time  `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (UserDataExport) TableName() string {
+	return "user_data_export"
+}
+
+// 数据导出状态
+const (
+	DataExportStatusPending = 0 // 等待中
+	DataExportStatusRunning = 1 // 生成中
+	DataExportStatusReady   = 2 // 已完成
+	DataExportStatusFailed  = 3 // 失败
+	DataExportStatusExpired = 4 // 已过期（文件已删除）
+)
+
+// AccountDeletion 账号注销申请（冷静期结束后执行，每个用户一条，撤销后可再次申请）
+type AccountDeletion struct {
+	ID            uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        string     `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_id" json:"user_id"`
+	Status        int8       `gorm:"type:tinyint;default:0;index:idx_status_scheduled;comment:'0-冷静期 1-已注销 2-已撤销'" json:"status"`
+	Reason        string     `gorm:"type:varchar(500);comment:'注销原因'" json:"reason"`
+	ScheduledTime time.Time  `gorm:"not null;index:idx_status_scheduled;comment:'计划执行时间'" json:"scheduled_time"`
+	RequestTime   time.Time  `gorm:"not null" json:"request_time"`
+	CompleteTime  *time.Time `json:"complete_time"`
+}
+
+func (AccountDeletion) TableName() string {
+	return "user_account_deletion"
+}
+
+// 账号注销状态
+const (
+	AccountDeletionStatusPending   = 0 // 冷静期
+	AccountDeletionStatusCompleted = 1 // 已注销
+	AccountDeletionStatusCancelled = 2 // 已撤销
+)
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// ArticleFingerprint 文章内容SimHash指纹
+// 64位指纹按16位拆成4段分别建索引：汉明距离不超过3的两个指纹至少有一段完全相同，
+// 先按段精确匹配筛出候选，再在内存中计算汉明距离
+type ArticleFingerprint struct {
+	ArticleID  uint64    `gorm:"primaryKey" json:"article_id"`
+	UserID     string    `gorm:"type:varchar(36);not null;index:idx_user" json:"user_id"`
+	SimHash    uint64    `gorm:"not null" json:"simhash"`
+	Band0      uint16    `gorm:"not null;index:idx_band0" json:"-"`
+	Band1      uint16    `gorm:"not null;index:idx_band1" json:"-"`
+	Band2      uint16    `gorm:"not null;index:idx_band2" json:"-"`
+	Band3      uint16    `gorm:"not null;index:idx_band3" json:"-"`
+	TokenCount int       `gorm:"default:0;comment:'特征词数量'" json:"token_count"`
+	UpdateTime time.Time `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (ArticleFingerprint) TableName() string {
+	return "article_fingerprint"
+}
+
+// ArticleDuplicate 疑似重复记录（ArticleID为较晚发布的疑似搬运文章，SourceArticleID为较早的原文）
+type ArticleDuplicate struct {
+	ID              uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ArticleID       uint64     `gorm:"not null;uniqueIndex:uk_pair" json:"article_id"`
+	UserID          string     `gorm:"type:varchar(36);not null" json:"user_id"`
+	SourceArticleID uint64     `gorm:"not null;uniqueIndex:uk_pair;index:idx_source" json:"source_article_id"`
+	SourceUserID    string     `gorm:"type:varchar(36);not null" json:"source_user_id"`
+	Distance        int        `gorm:"not null;comment:'汉明距离'" json:"distance"`
+	Similarity      float64    `gorm:"type:decimal(5,2);comment:'相似度（%）'" json:"similarity"`
+	Status          int8       `gorm:"type:tinyint;default:0;index:idx_status;comment:'0-待处理 1-确认重复 2-已排除'" json:"status"`
+	HandlerID       string     `gorm:"type:varchar(36)" json:"handler_id,omitempty"`
+	HandleNote      string     `gorm:"type:varchar(500)" json:"handle_note,omitempty"`
+	HandleTime      *time.Time `json:"handle_time,omitempty"`
+	CreateTime      time.Time  `gorm:"autoCreateTime" json:"create_time"`
+}
+
+func (ArticleDuplicate) TableName() string {
+	return "article_duplicate"
+}
+
+// 疑似重复处理状态
+const (
+	DuplicateStatusPending   = 0 // 待处理
+	DuplicateStatusConfirmed = 1 // 确认重复
+	DuplicateStatusDismissed = 2 // 已排除
+)
+
+// ArticleOriginalityClaim 原创申诉（被标记为疑似重复的作者提交原创证明）
+type ArticleOriginalityClaim struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ArticleID  uint64         `gorm:"not null;index:idx_article" json:"article_id"`
+	UserID     string         `gorm:"type:varchar(36);not null" json:"user_id"`
+	Statement  string         `gorm:"type:varchar(1000);not null;comment:'原创说明'" json:"statement"`
+	Evidence   JSONStringList `gorm:"type:json;comment:'证明材料链接'" json:"evidence"`
+	Status     int8           `gorm:"type:tinyint;default:0;index:idx_status;comment:'0-待审核 1-通过 2-驳回'" json:"status"`
+	ReviewerID string         `gorm:"type:varchar(36)" json:"reviewer_id,omitempty"`
+	ReviewNote string         `gorm:"type:varchar(500)" json:"review_note,omitempty"`
+	ReviewTime *time.Time     `json:"review_time,omitempty"`
+	CreateTime time.Time      `gorm:"autoCreateTime" json:"create_time"`
+}
+
+func (ArticleOriginalityClaim) TableName() string {
+	return "article_originality_claim"
+}
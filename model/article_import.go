@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// ==================== 文章导入任务表 ====================
+
+// ArticleImportJob 文章导入任务（后台执行，记录进度与逐篇结果）
+type ArticleImportJob struct {
+	ID         uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string `gorm:"type:varchar(36);not null;index:idx_user" json:"user_id"`
+	SourceType int8   `gorm:"type:tinyint;not null;comment:'1-Markdown压缩包 2-WordPress WXR'" json:"source_type"`
+	FileName   string `gorm:"type:varchar(255)" json:"file_name"`
+	ObjectName string `gorm:"type:varchar(500);comment:'导入包在对象存储中的路径'" json:"-"`
+	AsDraft    bool   `gorm:"default:false;comment:'是否全部导入为草稿'" json:"as_draft"`
+
+	// 进度
+	Status       int8      `gorm:"type:tinyint;default:0;comment:'0-等待中 1-执行中 2-已完成 3-失败'" json:"status"`
+	Total        int       `gorm:"default:0" json:"total"`
+	Processed    int       `gorm:"default:0" json:"processed"`
+	SuccessCount int       `gorm:"default:0" json:"success_count"`
+	FailCount    int       `gorm:"default:0" json:"fail_count"`
+	Results      JSONArray `gorm:"type:json;comment:'逐篇导入结果'" json:"results"`
+	ErrorMessage string    `gorm:"type:varchar(500)" json:"error_message,omitempty"`
+
+	// 时间戳
+	StartTime  *time.Time `json:"start_time"`
+	FinishTime *time.Time `json:"finish_time"`
+	CreateTime time.Time  `gorm:"autoCreateTime" json:"create_time"`
+	UpdateTime time.Time  `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (ArticleImportJob) TableName() string {
+	return "article_import_job"
+}
+
+// 导入来源类型
+const (
+	ImportSourceMarkdown = 1 // Markdown压缩包（Hexo/Hugo front matter）
+	ImportSourceWXR      = 2 // WordPress WXR导出文件
+)
+
+// 导入任务状态
+const (
+	ImportStatusPending  = 0 // 等待中
+	ImportStatusRunning  = 1 // 执行中
+	ImportStatusFinished = 2 // 已完成（可能存在部分失败）
+	ImportStatusFailed   = 3 // 失败
+)
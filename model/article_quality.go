@@ -0,0 +1,51 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// ArticleQualityScore 文章质量评分明细（总分同步写入 article_v3.quality_score）
+type ArticleQualityScore struct {
+	ArticleID  uint64               `gorm:"primaryKey" json:"article_id"`
+	Score      float64              `gorm:"type:decimal(5,2);not null;default:0" json:"score"`
+	Scorer     string               `gorm:"type:varchar(50);not null;comment:'评分器名称及版本'" json:"scorer"`
+	Components QualityComponentList `gorm:"type:json;comment:'各维度得分明细'" json:"components"`
+	ScoreTime  time.Time            `gorm:"autoUpdateTime" json:"score_time"`
+}
+
+func (ArticleQualityScore) TableName() string {
+	return "article_quality_score"
+}
+
+// QualityComponent 单个评分维度
+type QualityComponent struct {
+	Key    string  `json:"key"`    // 维度标识：length/structure/readability/links/sensitive/author/engagement
+	Name   string  `json:"name"`   // 维度名称
+	Score  float64 `json:"score"`  // 维度得分（0-100）
+	Weight float64 `json:"weight"` // 维度权重
+	Detail string  `json:"detail"` // 得分说明
+}
+
+// QualityComponentList 评分维度列表（JSON存储）
+type QualityComponentList []QualityComponent
+
+func (j QualityComponentList) Value() (driver.Value, error) {
+	if j == nil {
+		return "[]", nil
+	}
+	return json.Marshal(j)
+}
+
+func (j *QualityComponentList) Scan(value interface{}) error {
+	if value == nil {
+		*j = []QualityComponent{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, j)
+}
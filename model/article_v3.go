@@ -38,12 +38,13 @@ type ArticleV3 struct {
 	AuditUserID string     `gorm:"type:varchar(36)" json:"audit_user_id"`
 
 	// 统计数据
-	ViewCount     uint64 `gorm:"default:0" json:"view_count"`
-	RealViewCount uint64 `gorm:"default:0;comment:'真实浏览量（去重）'" json:"real_view_count"`
-	LikeCount     uint64 `gorm:"default:0" json:"like_count"`
-	CommentCount  uint64 `gorm:"default:0" json:"comment_count"`
-	ShareCount    uint64 `gorm:"default:0" json:"share_count"`
-	FavoriteCount uint64 `gorm:"default:0" json:"favorite_count"`
+	ViewCount      uint64 `gorm:"default:0;comment:'原始浏览量'" json:"view_count"`
+	RealViewCount  uint64 `gorm:"default:0;comment:'真实浏览量（去重）'" json:"real_view_count"`
+	HumanViewCount uint64 `gorm:"default:0;comment:'真人浏览量（去重且排除爬虫）'" json:"human_view_count"`
+	LikeCount      uint64 `gorm:"default:0" json:"like_count"`
+	CommentCount   uint64 `gorm:"default:0" json:"comment_count"`
+	ShareCount     uint64 `gorm:"default:0" json:"share_count"`
+	FavoriteCount  uint64 `gorm:"default:0" json:"favorite_count"`
 
 	// 推荐与排序
 	Weight       int     `gorm:"default:0;comment:'权重（影响排序）'" json:"weight"`
@@ -117,12 +118,15 @@ type ArticleDraft struct {
 	Topics     JSONStringList `gorm:"type:varchar(500)" json:"topics"`
 
 	// 草稿管理
+	Version       int        `gorm:"default:1;comment:'版本号（乐观锁，每次保存+1）'" json:"version"`
 	AutoSaveCount int        `gorm:"default:0;comment:'自动保存次数'" json:"auto_save_count"`
 	LastEditTime  *time.Time `json:"last_edit_time"`
+	LastDeviceID  string     `gorm:"type:varchar(64);comment:'最后保存的设备标识'" json:"last_device_id,omitempty"`
 	IsPublished   bool       `gorm:"default:false;index:idx_user" json:"is_published"`
 
-	CreateTime time.Time `gorm:"autoCreateTime" json:"create_time"`
-	UpdateTime time.Time `gorm:"autoUpdateTime" json:"update_time"`
+	CreateTime time.Time  `gorm:"autoCreateTime" json:"create_time"`
+	UpdateTime time.Time  `gorm:"autoUpdateTime" json:"update_time"`
+	DeleteTime *time.Time `json:"delete_time,omitempty"` // 软删除（进入回收站）
 }
 
 func (ArticleDraft) TableName() string {
@@ -293,6 +297,7 @@ type ArticleStatsDetail struct {
 	RegionStats       JSONMap   `gorm:"type:json;comment:'地域统计'" json:"region_stats,omitempty"`
 	AvgReadProgress   float64   `gorm:"type:decimal(5,2);default:0;comment:'平均阅读进度（%）'" json:"avg_read_progress"`
 	AvgStayTime       int       `gorm:"default:0;comment:'平均停留时间（秒）'" json:"avg_stay_time"`
+	ReadCount         uint64    `gorm:"default:0;comment:'参与阅读统计的次数（计算平均值用）'" json:"read_count"`
 	UpdateTime        time.Time `gorm:"autoUpdateTime" json:"update_time"`
 }
 
@@ -300,6 +305,87 @@ func (ArticleStatsDetail) TableName() string {
 	return "article_stats_detail"
 }
 
+// ==================== 文章每日统计表 ====================
+
+// ArticleStatsDaily 文章每日统计（日终快照计算当日增量，阅读上报实时累加）
+type ArticleStatsDaily struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ArticleID uint64    `gorm:"not null;uniqueIndex:uk_article_date" json:"article_id"`
+	UserID    string    `gorm:"type:varchar(36);index:idx_user_date" json:"user_id"`
+	StatDate  time.Time `gorm:"type:date;not null;uniqueIndex:uk_article_date;index:idx_user_date" json:"stat_date"`
+
+	// 当日增量
+	ViewCount       int `gorm:"default:0" json:"view_count"`
+	UniqueViewCount int `gorm:"default:0" json:"unique_view_count"`
+	LikeCount       int `gorm:"default:0" json:"like_count"`
+	CommentCount    int `gorm:"default:0" json:"comment_count"`
+	FavoriteCount   int `gorm:"default:0" json:"favorite_count"`
+	ShareCount      int `gorm:"default:0" json:"share_count"`
+
+	// 日终累计（用于计算次日增量）
+	TotalViews       uint64     `gorm:"default:0" json:"-"`
+	TotalUniqueViews uint64     `gorm:"default:0" json:"-"`
+	TotalLikes       uint64     `gorm:"default:0" json:"-"`
+	TotalComments    uint64     `gorm:"default:0" json:"-"`
+	TotalFavorites   uint64     `gorm:"default:0" json:"-"`
+	TotalShares      uint64     `gorm:"default:0" json:"-"`
+	SnapshotTime     *time.Time `gorm:"comment:'日终快照时间（为空表示当日仅有阅读上报）'" json:"-"`
+
+	// 阅读参与度
+	ReadCount   int     `gorm:"default:0;comment:'阅读上报次数'" json:"read_count"`
+	ProgressSum float64 `gorm:"type:decimal(12,2);default:0;comment:'阅读进度合计（%）'" json:"progress_sum"`
+	StayTimeSum int64   `gorm:"default:0;comment:'停留时间合计（秒）'" json:"stay_time_sum"`
+	SourceStats JSONMap `gorm:"type:json;comment:'流量来源统计'" json:"source_stats,omitempty"`
+	DeviceStats JSONMap `gorm:"type:json;comment:'设备统计'" json:"device_stats,omitempty"`
+	RegionStats JSONMap `gorm:"type:json;comment:'地域统计'" json:"region_stats,omitempty"`
+
+	CreateTime time.Time `gorm:"autoCreateTime" json:"create_time"`
+	UpdateTime time.Time `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (ArticleStatsDaily) TableName() string {
+	return "article_stats_daily"
+}
+
+// AuthorStatsDaily 作者每日统计（由文章日统计汇总，附带粉丝增长）
+type AuthorStatsDaily struct {
+	ID              uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID          string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_date" json:"user_id"`
+	StatDate        time.Time `gorm:"type:date;not null;uniqueIndex:uk_user_date" json:"stat_date"`
+	ViewCount       int       `gorm:"default:0" json:"view_count"`
+	UniqueViewCount int       `gorm:"default:0" json:"unique_view_count"`
+	LikeCount       int       `gorm:"default:0" json:"like_count"`
+	CommentCount    int       `gorm:"default:0" json:"comment_count"`
+	FavoriteCount   int       `gorm:"default:0" json:"favorite_count"`
+	ShareCount      int       `gorm:"default:0" json:"share_count"`
+	NewFollowers    int       `gorm:"default:0;comment:'当日新增粉丝'" json:"new_followers"`
+	FollowerCount   int64     `gorm:"default:0;comment:'日终粉丝总数'" json:"follower_count"`
+	ArticleCount    int       `gorm:"default:0;comment:'日终已发布文章数'" json:"article_count"`
+	CreateTime      time.Time `gorm:"autoCreateTime" json:"create_time"`
+	UpdateTime      time.Time `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (AuthorStatsDaily) TableName() string {
+	return "author_stats_daily"
+}
+
+// 流量来源分类
+const (
+	TrafficSourceDirect   = "direct"   // 直接访问
+	TrafficSourceInternal = "internal" // 站内跳转
+	TrafficSourceSearch   = "search"   // 搜索引擎
+	TrafficSourceSocial   = "social"   // 社交媒体
+	TrafficSourceOther    = "other"    // 其他外链
+)
+
+// 设备分类
+const (
+	DeviceTypeDesktop = "desktop"
+	DeviceTypeMobile  = "mobile"
+	DeviceTypeTablet  = "tablet"
+	DeviceTypeOther   = "other"
+)
+
 // ==================== 自定义JSON类型（便于处理） ====================
 
 // JSONStringList JSON字符串数组类型
@@ -0,0 +1,81 @@
+package model
+
+import "time"
+
+// ==================== 安全审计日志 ====================
+
+// 审计操作
+const (
+	AuditActionLogin          = "user.login"           // 登录（密码、短信、两步验证、第三方）
+	AuditActionLogout         = "user.logout"          // 登出
+	AuditActionPasswordReset  = "user.password_reset"  // 通过邮件重置密码
+	AuditActionPhoneChange    = "user.phone_change"    // 更换手机号
+	AuditActionEmailBind      = "user.email_bind"      // 绑定/更换邮箱
+	AuditActionHandleChange   = "user.handle_change"   // 修改个性ID
+	AuditActionPrivacyUpdate  = "user.privacy_update"  // 修改隐私设置
+	AuditAction2FAEnable      = "user.2fa_enable"      // 开启两步验证
+	AuditAction2FADisable     = "user.2fa_disable"     // 关闭两步验证
+	AuditActionRecoveryCodes  = "user.2fa_recovery"    // 重新生成恢复码
+	AuditActionIdentityLink   = "user.identity_link"   // 绑定第三方账号
+	AuditActionIdentityUnlink = "user.identity_unlink" // 解绑第三方账号
+	AuditActionDeletionApply  = "account.deletion_apply"
+	AuditActionDeletionCancel = "account.deletion_cancel"
+
+	AuditActionAdmin2FAReset       = "admin.2fa_reset"            // 管理员重置用户两步验证
+	AuditActionReportHandle        = "admin.report_handle"        // 审核评论举报
+	AuditActionCommentBatchDelete  = "admin.comment_batch_delete" // 批量删除评论
+	AuditActionCommentBatchFold    = "admin.comment_batch_fold"   // 批量折叠评论
+	AuditActionSensitiveWordCreate = "admin.sensitive_word_create"
+
+	AuditActionContributorInvite = "contributor.invite" // 邀请协作者（授予角色，接受后生效）
+	AuditActionContributorRole   = "contributor.role"   // 调整协作者角色
+	AuditActionContributorRevoke = "contributor.revoke" // 移除协作者、撤回邀请或主动退出
+)
+
+// SecurityAuditActions 用户"最近安全活动"中展示的操作
+var SecurityAuditActions = []string{
+	AuditActionLogin, AuditActionLogout, AuditActionPasswordReset, AuditActionPhoneChange,
+	AuditActionEmailBind, AuditActionHandleChange, AuditActionPrivacyUpdate,
+	AuditAction2FAEnable, AuditAction2FADisable, AuditActionRecoveryCodes,
+	AuditActionIdentityLink, AuditActionIdentityUnlink,
+	AuditActionDeletionApply, AuditActionDeletionCancel, AuditActionAdmin2FAReset,
+}
+
+// 审计对象类型（协作者操作的对象为文章或专栏，使用 ContributorTarget*）
+const (
+	AuditTargetUser          = "user"
+	AuditTargetComment       = "comment"
+	AuditTargetReport        = "comment_report"
+	AuditTargetSensitiveWord = "sensitive_word"
+)
+
+// 审计结果
+const (
+	AuditResultSuccess = 1
+	AuditResultFailure = 2
+)
+
+// AuditLog 安全审计日志（只追加，不修改；超过保留期后移入 audit_log_archive）
+type AuditLog struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorID    string    `gorm:"type:varchar(36);index:idx_actor_time;comment:'操作者ID（未登录时为空）'" json:"actor_id"`
+	ActorRole  string    `gorm:"type:varchar(20);comment:'操作时的管理员角色'" json:"actor_role,omitempty"`
+	Action     string    `gorm:"type:varchar(50);not null;index:idx_action_time" json:"action"`
+	TargetType string    `gorm:"type:varchar(30);index:idx_target_time" json:"target_type"`
+	TargetID   string    `gorm:"type:varchar(64);index:idx_target_time" json:"target_id"`
+	Result     int8      `gorm:"type:tinyint;not null;default:1;comment:'1-成功 2-失败'" json:"result"`
+	Before     JSONMap   `gorm:"type:json;comment:'修改前'" json:"before,omitempty"`
+	After      JSONMap   `gorm:"type:json;comment:'修改后'" json:"after,omitempty"`
+	Detail     string    `gorm:"type:varchar(255);comment:'说明（如失败原因、登录方式）'" json:"detail,omitempty"`
+	IP         string    `gorm:"type:varchar(45)" json:"ip"`
+	UserAgent  string    `gorm:"type:varchar(255)" json:"user_agent"`
+	RequestID  string    `gorm:"type:varchar(64);index:idx_request_id" json:"request_id"`
+	CreateTime time.Time `gorm:"autoCreateTime;index:idx_actor_time;index:idx_action_time;index:idx_target_time;index:idx_create_time" json:"create_time"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// AuditLogArchiveTable 归档表（结构与 audit_log 相同）
+const AuditLogArchiveTable = "audit_log_archive"
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// ==================== 专栏导出表 ====================
+
+// ColumnExport 专栏电子书导出缓存（每个专栏每种格式一条，按内容版本失效）
+type ColumnExport struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ColumnID     uint64     `gorm:"not null;uniqueIndex:uk_column_format" json:"column_id"`
+	Format       string     `gorm:"type:varchar(10);not null;uniqueIndex:uk_column_format;comment:'epub/html'" json:"format"`
+	Revision     string     `gorm:"type:varchar(64);comment:'专栏内容版本（专栏、文章顺序及内容的摘要）'" json:"revision"`
+	Status       int8       `gorm:"type:tinyint;default:0;comment:'0-等待中 1-生成中 2-已完成 3-失败'" json:"status"`
+	ObjectName   string     `gorm:"type:varchar(500)" json:"-"`
+	FileURL      string     `gorm:"type:varchar(500)" json:"file_url"`
+	FileSize     int64      `gorm:"default:0" json:"file_size"`
+	ErrorMessage string     `gorm:"type:varchar(500)" json:"error_message,omitempty"`
+	GenerateTime *time.Time `json:"generate_time"`
+	CreateTime   time.Time  `gorm:"autoCreateTime" json:"create_time"`
+	UpdateTime   time.Time  `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (ColumnExport) TableName() string {
+	return "column_export"
+}
+
+// 专栏导出格式
+const (
+	ColumnExportFormatEPUB = "epub" // EPUB3电子书
+	ColumnExportFormatHTML = "html" // 适合打印为PDF的单页HTML
+)
+
+// 专栏导出状态
+const (
+	ColumnExportStatusPending = 0 // 等待中
+	ColumnExportStatusRunning = 1 // 生成中
+	ColumnExportStatusReady   = 2 // 已完成
+	ColumnExportStatusFailed  = 3 // 失败
+)
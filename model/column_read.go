@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// ColumnReadRecord 专栏阅读记录（用户在专栏中读过的章节，每篇一条，重复阅读只刷新时间）
+type ColumnReadRecord struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_column_article,priority:1;index:idx_user_read_time,priority:1" json:"user_id"`
+	ColumnID  uint64    `gorm:"not null;uniqueIndex:uk_user_column_article,priority:2" json:"column_id"`
+	ArticleID uint64    `gorm:"not null;uniqueIndex:uk_user_column_article,priority:3" json:"article_id"`
+	ReadTime  time.Time `gorm:"not null;index:idx_user_read_time,priority:2" json:"read_time"`
+}
+
+func (ColumnReadRecord) TableName() string {
+	return "column_read_record"
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// ==================== 共同创作 ====================
+
+// Contributor 文章/专栏协作者（主作者仍记录在 ArticleV3.UserID / ArticleColumn.UserID）
+type Contributor struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TargetType string     `gorm:"type:varchar(20);not null;uniqueIndex:uk_target_user,priority:1;comment:'article/column'" json:"target_type"`
+	TargetID   uint64     `gorm:"not null;uniqueIndex:uk_target_user,priority:2" json:"target_id"`
+	UserID     string     `gorm:"type:varchar(36);not null;uniqueIndex:uk_target_user,priority:3;index:idx_user_status,priority:1" json:"user_id"`
+	Role       string     `gorm:"type:varchar(20);not null;comment:'owner/editor/reviewer'" json:"role"`
+	Status     int8       `gorm:"type:tinyint;default:0;index:idx_user_status,priority:2;comment:'0-待接受 1-已接受'" json:"status"`
+	InvitedBy  string     `gorm:"type:varchar(36);not null" json:"invited_by"`
+	CreateTime time.Time  `gorm:"autoCreateTime" json:"create_time"`
+	AcceptTime *time.Time `json:"accept_time"`
+
+	// 关联（非数据库字段）
+	Username string `gorm:"-" json:"username,omitempty"`
+	Avatar   string `gorm:"-" json:"avatar,omitempty"`
+}
+
+func (Contributor) TableName() string {
+	return "content_contributor"
+}
+
+// 协作对象类型
+const (
+	ContributorTargetArticle = "article" // article_v3
+	ContributorTargetColumn  = "column"  // article_column
+)
+
+// 协作角色
+const (
+	ContributorRoleOwner    = "owner"    // 共同所有者：可编辑、发布、管理协作者
+	ContributorRoleEditor   = "editor"   // 编辑：可编辑和发布
+	ContributorRoleReviewer = "reviewer" // 审阅：只能查看草稿
+)
+
+// 邀请状态
+const (
+	ContributorStatusPending  int8 = 0
+	ContributorStatusAccepted int8 = 1
+)
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// ArticleDraftSnapshot 草稿自动保存快照（每个草稿保留最近若干份，用于找回被覆盖的内容）
+type ArticleDraftSnapshot struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	DraftID    uint64         `gorm:"not null;index:idx_draft" json:"draft_id"`
+	UserID     string         `gorm:"type:varchar(36);not null" json:"user_id"`
+	Version    int            `gorm:"not null;comment:'快照对应的草稿版本'" json:"version"`
+	Title      string         `gorm:"type:varchar(200)" json:"title"`
+	Summary    string         `gorm:"type:varchar(500)" json:"summary"`
+	CoverImage string         `gorm:"type:varchar(500)" json:"cover_image"`
+	Content    string         `gorm:"type:longtext" json:"content,omitempty"`
+	CategoryID uint64         `json:"category_id"`
+	ColumnID   uint64         `json:"column_id"`
+	Tags       JSONStringList `gorm:"type:varchar(500)" json:"tags"`
+	Topics     JSONStringList `gorm:"type:varchar(500)" json:"topics"`
+	DeviceID   string         `gorm:"type:varchar(64);comment:'写入该版本的设备标识'" json:"device_id,omitempty"`
+	CreateTime time.Time      `gorm:"autoCreateTime" json:"create_time"`
+}
+
+func (ArticleDraftSnapshot) TableName() string {
+	return "article_draft_snapshot"
+}
@@ -0,0 +1,84 @@
+package model
+
+import "time"
+
+// ==================== 动态主表 ====================
+
+// Dynamic 动态（短内容，支持九宫格图片、话题与转发）
+type Dynamic struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID string `gorm:"type:varchar(36);not null;index:idx_user_time" json:"user_id"`
+
+	// 动态内容
+	Content string         `gorm:"type:varchar(2000);not null" json:"content"`
+	Images  JSONStringList `gorm:"type:varchar(5000);comment:'图片URL（JSON数组，最多9张）'" json:"images"`
+	Topics  JSONStringList `gorm:"type:varchar(500);comment:'话题（JSON数组）'" json:"topics"`
+
+	// 转发信息（0表示原创）
+	RepostType int8   `gorm:"type:tinyint;default:0;index:idx_repost;comment:'0-原创 1-转发动态 2-转发文章'" json:"repost_type"`
+	RepostID   uint64 `gorm:"default:0;index:idx_repost;comment:'被转发对象ID'" json:"repost_id"`
+
+	// 状态管理
+	Status       int8 `gorm:"type:tinyint;default:1;comment:'1-正常 2-已删除'" json:"status"`
+	AllowComment bool `gorm:"default:true" json:"allow_comment"`
+	AllowRepost  bool `gorm:"default:true" json:"allow_repost"`
+
+	// 统计数据
+	LikeCount    uint64 `gorm:"default:0" json:"like_count"`
+	CommentCount uint64 `gorm:"default:0" json:"comment_count"`
+	RepostCount  uint64 `gorm:"default:0" json:"repost_count"`
+
+	// IP与设备
+	IP         string `gorm:"type:varchar(50)" json:"-"`
+	IPLocation string `gorm:"type:varchar(100)" json:"ip_location,omitempty"`
+
+	// 时间戳
+	CreateTime time.Time  `gorm:"autoCreateTime;index:idx_user_time" json:"create_time"`
+	UpdateTime time.Time  `gorm:"autoUpdateTime" json:"update_time"`
+	DeleteTime *time.Time `json:"delete_time,omitempty"` // 软删除
+}
+
+func (Dynamic) TableName() string {
+	return "dynamic"
+}
+
+// ==================== 动态话题关联表 ====================
+
+// DynamicTopicRel 动态话题关联（话题与文章共用topic表）
+type DynamicTopicRel struct {
+	DynamicID  uint64    `gorm:"not null;primaryKey" json:"dynamic_id"`
+	TopicID    uint64    `gorm:"not null;primaryKey;index:idx_topic" json:"topic_id"`
+	CreateTime time.Time `gorm:"autoCreateTime" json:"create_time"`
+}
+
+func (DynamicTopicRel) TableName() string {
+	return "dynamic_topic_rel"
+}
+
+// ==================== 常量定义 ====================
+
+// 动态状态
+const (
+	DynamicStatusNormal  = 1 // 正常
+	DynamicStatusDeleted = 2 // 已删除
+)
+
+// 转发类型
+const (
+	DynamicRepostTypeNone    = 0 // 原创
+	DynamicRepostTypeDynamic = 1 // 转发动态
+	DynamicRepostTypeArticle = 2 // 转发文章
+)
+
+// 动态内容限制
+const (
+	DynamicMaxImages        = 9    // 最多9张图片
+	DynamicMaxContentLength = 2000 // 内容最大长度
+	DynamicMaxTopics        = 5    // 最多关联5个话题
+)
+
+// 时间线条目类型
+const (
+	TimelineItemDynamic = "dynamic" // 动态
+	TimelineItemArticle = "article" // 文章
+)
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// ==================== 旧版数据迁移 ====================
+
+// LegacyIDMap 旧版ID与V3 ID映射（迁移后用于旧链接跳转）
+type LegacyIDMap struct {
+	Entity     string    `gorm:"type:varchar(20);primaryKey;index:idx_new,priority:1" json:"entity"`
+	OldID      uint64    `gorm:"primaryKey;autoIncrement:false" json:"old_id"`
+	NewID      uint64    `gorm:"not null;index:idx_new,priority:2" json:"new_id"`
+	CreateTime time.Time `gorm:"autoCreateTime" json:"create_time"`
+}
+
+func (LegacyIDMap) TableName() string {
+	return "legacy_id_map"
+}
+
+// 映射实体类型
+const (
+	LegacyEntityArticle      = "article"        // article -> article_v3
+	LegacyEntityDraft        = "draft"          // article（草稿）-> article_draft
+	LegacyEntityCommentRoot  = "comment_parent" // comment_parent -> comment_v3（根评论）
+	LegacyEntityCommentReply = "comment_sub"    // comment_sub_two -> comment_v3（回复）
+)
+
+// LegacyMigrationProgress 迁移进度（按阶段记录检查点，中断后从检查点继续）
+type LegacyMigrationProgress struct {
+	Stage      string    `gorm:"type:varchar(30);primaryKey" json:"stage"`
+	LastID     uint64    `gorm:"default:0;comment:'已处理到的源表ID'" json:"last_id"`
+	MaxID      uint64    `gorm:"default:0;comment:'首次运行时源表的最大ID（快照）'" json:"max_id"`
+	Processed  int64     `gorm:"default:0" json:"processed"`
+	Skipped    int64     `gorm:"default:0" json:"skipped"`
+	Conflicts  int64     `gorm:"default:0;comment:'无法确定归属而跳过的记录数'" json:"conflicts"`
+	Done       bool      `gorm:"default:false" json:"done"`
+	UpdateTime time.Time `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (LegacyMigrationProgress) TableName() string {
+	return "legacy_migration_progress"
+}
+
+// 迁移阶段（按顺序执行）
+const (
+	LegacyStageArticles  = "articles"  // 文章与草稿
+	LegacyStageComments  = "comments"  // 一级评论
+	LegacyStageReplies   = "replies"   // 二级评论
+	LegacyStageStars     = "stars"     // 点赞
+	LegacyStageFavorites = "favorites" // 收藏
+	LegacyStageCounters  = "counters"  // 重算迁移文章的冗余计数
+)
+
+// LegacyMigrationStages 迁移阶段执行顺序
+var LegacyMigrationStages = []string{
+	LegacyStageArticles,
+	LegacyStageComments,
+	LegacyStageReplies,
+	LegacyStageStars,
+	LegacyStageFavorites,
+	LegacyStageCounters,
+}
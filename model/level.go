@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+// ==================== 用户等级、经验与徽章 ====================
+
+// 经验事件
+const (
+	XPEventPublishArticle  = "publish_article"  // 发布文章
+	XPEventPublishDynamic  = "publish_dynamic"  // 发布动态
+	XPEventReceiveLike     = "receive_like"     // 文章或评论被点赞
+	XPEventFeaturedComment = "featured_comment" // 评论被作者精选
+	XPEventDailyLogin      = "daily_login"      // 每日登录
+)
+
+// 等级特权
+const (
+	PrivilegePostLink          = "post_link"           // 评论和动态中发布链接
+	PrivilegeCreateTopic       = "create_topic"        // 创建新话题
+	PrivilegeSkipPreModeration = "skip_pre_moderation" // 评论免先审
+)
+
+// UserXP 用户累计经验（等级由经验和配置的等级门槛计算）
+type UserXP struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"-"`
+	UserID     string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_id" json:"user_id"`
+	XP         int64     `gorm:"not null;default:0;index:idx_xp" json:"xp"`
+	UpdateTime time.Time `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (UserXP) TableName() string {
+	return "user_xp"
+}
+
+// UserXPLog 经验流水（每个事件一条，BizKey 保证同一事件只计一次；超出每日上限时经验为0）
+type UserXPLog struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_biz;index:idx_user_event_time" json:"user_id"`
+	Event      string    `gorm:"type:varchar(32);not null;index:idx_user_event_time" json:"event"`
+	XP         int       `gorm:"not null;default:0" json:"xp"`
+	BizKey     string    `gorm:"type:varchar(100);not null;uniqueIndex:uk_user_biz;comment:'事件唯一键'" json:"-"`
+	CreateTime time.Time `gorm:"autoCreateTime;index:idx_user_event_time" json:"create_time"`
+}
+
+func (UserXPLog) TableName() string {
+	return "user_xp_log"
+}
+
+// UserBadge 用户获得的徽章
+type UserBadge struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"-"`
+	UserID    string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_badge" json:"user_id"`
+	Badge     string    `gorm:"type:varchar(32);not null;uniqueIndex:uk_user_badge" json:"badge"`
+	AwardTime time.Time `gorm:"autoCreateTime" json:"award_time"`
+}
+
+func (UserBadge) TableName() string {
+	return "user_badge"
+}
+
+// BadgeDefinition 里程碑徽章：Event 累计次数达到 Count 时获得；Event 为空时按等级达到 Level 获得
+type BadgeDefinition struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Event       string `json:"-"`
+	Count       int64  `json:"-"`
+	Level       int    `json:"-"`
+}
+
+// Badges 全部徽章
+var Badges = []BadgeDefinition{
+	{Code: "first_article", Name: "初出茅庐", Description: "发布第一篇文章", Event: XPEventPublishArticle, Count: 1},
+	{Code: "prolific_writer", Name: "笔耕不辍", Description: "累计发布50篇文章", Event: XPEventPublishArticle, Count: 50},
+	{Code: "liked_100", Name: "小有名气", Description: "累计获得100个赞", Event: XPEventReceiveLike, Count: 100},
+	{Code: "liked_1000", Name: "广受欢迎", Description: "累计获得1000个赞", Event: XPEventReceiveLike, Count: 1000},
+	{Code: "featured_10", Name: "金牌评论员", Description: "10条评论被作者精选", Event: XPEventFeaturedComment, Count: 10},
+	{Code: "login_30", Name: "常驻居民", Description: "累计登录30天", Event: XPEventDailyLogin, Count: 30},
+	{Code: "level_5", Name: "资深观星者", Description: "达到5级", Level: 5},
+}
+
+// FindBadge 根据编码查找徽章定义
+func FindBadge(code string) *BadgeDefinition {
+	for i := range Badges {
+		if Badges[i].Code == code {
+			return &Badges[i]
+		}
+	}
+	return nil
+}
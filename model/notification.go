@@ -27,4 +27,11 @@ const (
 	NotificationTypeReply       = 3 // 回复评论
 	NotificationTypeFollow      = 4 // 关注
 	NotificationTypeLikeComment = 5 // 点赞评论
+
+	NotificationTypeColumnSubscribe   = 6  // 订阅专栏
+	NotificationTypeColumnUpdate      = 7  // 订阅的专栏更新了新章节
+	NotificationTypeContributorInvite = 8  // 邀请共同创作
+	NotificationTypeSecurity          = 9  // 账号安全提醒
+	NotificationTypeDataExport        = 10 // 个人数据导出完成
+	NotificationTypeAchievement       = 11 // 等级提升或获得徽章
 )
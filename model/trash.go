@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// ==================== 回收站 ====================
+
+// TrashItem 回收站条目（用户删除文章/草稿/评论时记录，恢复或彻底清除时移除）
+type TrashItem struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string    `gorm:"type:varchar(36);not null;index:idx_user_type,priority:1" json:"user_id"`
+	EntityType string    `gorm:"type:varchar(20);not null;uniqueIndex:uk_entity,priority:1;index:idx_user_type,priority:2" json:"entity_type"`
+	EntityID   uint64    `gorm:"not null;uniqueIndex:uk_entity,priority:2" json:"entity_id"`
+	Title      string    `gorm:"type:varchar(200);comment:'标题或评论摘要（快照）'" json:"title"`
+	PrevStatus int8      `gorm:"type:tinyint;default:0;comment:'删除前的状态（恢复时还原）'" json:"prev_status"`
+	DeleteTime time.Time `gorm:"not null;index:idx_delete_time" json:"delete_time"`
+}
+
+func (TrashItem) TableName() string {
+	return "trash_item"
+}
+
+// 回收站实体类型
+const (
+	TrashEntityArticle = "article" // article_v3
+	TrashEntityDraft   = "draft"   // article_draft
+	TrashEntityComment = "comment" // comment_v3
+)
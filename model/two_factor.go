@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// ==================== 两步验证 ====================
+
+// UserTwoFactor 用户两步验证（TOTP），开启前先保存待确认的密钥
+type UserTwoFactor struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       string     `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_id" json:"user_id"`
+	Secret       string     `gorm:"type:varchar(64);not null;comment:'Base32密钥'" json:"-"`
+	Enabled      bool       `gorm:"default:0;comment:'是否已开启'" json:"enabled"`
+	LastUsedStep int64      `gorm:"default:0;comment:'最近一次通过验证的时间步，防止验证码重放'" json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	CreateTime   time.Time  `gorm:"autoCreateTime" json:"create_time"`
+}
+
+func (UserTwoFactor) TableName() string {
+	return "user_two_factor"
+}
+
+// UserRecoveryCode 两步验证恢复码（只保存哈希，每个只能使用一次）
+type UserRecoveryCode struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string     `gorm:"type:varchar(36);not null;index:idx_user_id" json:"user_id"`
+	CodeHash   string     `gorm:"type:char(64);not null;comment:'SHA-256'" json:"-"`
+	UsedAt     *time.Time `json:"used_at"`
+	CreateTime time.Time  `gorm:"autoCreateTime" json:"create_time"`
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_code"
+}
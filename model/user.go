@@ -1,26 +1,45 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"astronomer-gin/pkg/uuid"
 	"gorm.io/gorm"
 )
 
+// UnboundPhonePrefix 未绑定手机号的账号（第三方登录注册）使用的占位手机号前缀
+// phone 列唯一且非空，占位值不是合法手机号，不会与真实号码冲突
+const UnboundPhonePrefix = "oauth_"
+
+// DefaultHandlePrefix 新用户自动分配的个性ID前缀（用户不能自行设置此前缀）
+const DefaultHandlePrefix = "user_"
+
 // User 用户表
 type User struct {
-	ID             string     `json:"id" gorm:"type:varchar(36);primary_key"`
-	Phone          string     `json:"phone" gorm:"column:phone;type:varchar(20);unique;not null"`
-	Username       string     `json:"username" gorm:"column:username;type:varchar(255);not null"`
-	Password       string     `json:"password" gorm:"column:password;type:varchar(255);not null"`
-	Icon           string     `json:"icon" gorm:"column:icon;type:varchar(500)"`
-	Sex            int        `json:"sex" gorm:"column:sex;comment:'性别(1->男 2->女)';default:1;not null"`
-	Note           string     `json:"note" gorm:"column:note;type:varchar(500);comment:'备注'"`
-	Intro          string     `json:"intro" gorm:"column:intro;type:varchar(500);comment:'个人简介'"`
-	Role           string     `json:"role" gorm:"column:role;type:varchar(20);default:'user';comment:'角色:user-普通用户,admin-管理员,super_admin-超级管理员'"`
-	FollowingCount int64      `json:"followingCount" gorm:"column:following_count;comment:'关注数量';default:0;not null"`
-	FollowedCount  int64      `json:"followedCount" gorm:"column:followed_count;comment:'被关注数量';default:0;not null"`
-	CreateTime     *time.Time `json:"createTime" gorm:"column:create_time;comment:'创建时间';not null"`
+	ID              string      `json:"id" gorm:"type:varchar(36);primary_key"`
+	Phone           string      `json:"phone" gorm:"column:phone;type:varchar(20);unique;not null"`
+	Email           *string     `json:"-" gorm:"column:email;type:varchar(255);unique;comment:'已验证邮箱'"`
+	PendingEmail    string      `json:"-" gorm:"column:pending_email;type:varchar(255);comment:'待验证邮箱'"`
+	Handle          string      `json:"handle" gorm:"column:handle;type:varchar(30);uniqueIndex:uk_handle;not null;comment:'个性ID（小写，唯一）'"`
+	Username        string      `json:"username" gorm:"column:username;type:varchar(255);not null"`
+	Password        string      `json:"password" gorm:"column:password;type:varchar(255);not null"`
+	Icon            string      `json:"icon" gorm:"column:icon;type:varchar(500)"`
+	Sex             int         `json:"sex" gorm:"column:sex;comment:'性别(1->男 2->女)';default:1;not null"`
+	Note            string      `json:"note" gorm:"column:note;type:varchar(500);comment:'备注'"`
+	Intro           string      `json:"intro" gorm:"column:intro;type:varchar(500);comment:'个人简介'"`
+	Cover           string      `json:"cover" gorm:"column:cover;type:varchar(500);comment:'主页封面'"`
+	Website         string      `json:"website" gorm:"column:website;type:varchar(255);comment:'个人网站'"`
+	SocialLinks     SocialLinks `json:"socialLinks" gorm:"column:social_links;type:json;comment:'社交账号链接'"`
+	Location        string      `json:"location" gorm:"column:location;type:varchar(100);comment:'所在地'"`
+	Birthday        *time.Time  `json:"birthday" gorm:"column:birthday;type:date;comment:'生日'"`
+	Role            string      `json:"role" gorm:"column:role;type:varchar(20);default:'user';comment:'角色:user-普通用户,admin-管理员,super_admin-超级管理员'"`
+	FollowingCount  int64       `json:"followingCount" gorm:"column:following_count;comment:'关注数量';default:0;not null"`
+	FollowedCount   int64       `json:"followedCount" gorm:"column:followed_count;comment:'被关注数量';default:0;not null"`
+	CreateTime      *time.Time  `json:"createTime" gorm:"column:create_time;comment:'创建时间';not null"`
+	TokenValidAfter *time.Time  `json:"-" gorm:"column:token_valid_after;type:datetime(3);comment:'此前签发的token失效'"`
 
 	// 非数据库字段
 	IsFollowed bool `json:"isFollowed" gorm:"-"` // 当前用户是否已关注此用户
@@ -31,9 +50,42 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == "" {
 		u.ID = uuid.New()
 	}
+	if u.Handle == "" {
+		u.Handle = DefaultHandlePrefix + strings.ReplaceAll(u.ID, "-", "")[:12]
+	}
 	return nil
 }
 
+// HasPhone 是否已绑定真实手机号
+func (u *User) HasPhone() bool {
+	return !strings.HasPrefix(u.Phone, UnboundPhonePrefix)
+}
+
 func (u *User) TableName() string {
 	return "user"
 }
+
+// 支持展示的社交平台
+var SocialPlatforms = []string{"github", "weibo", "zhihu", "bilibili", "juejin", "twitter", "linkedin"}
+
+// SocialLinks 社交账号链接（平台 -> 主页地址）
+type SocialLinks map[string]string
+
+func (s SocialLinks) Value() (driver.Value, error) {
+	if s == nil {
+		return "{}", nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *SocialLinks) Scan(value interface{}) error {
+	if value == nil {
+		*s = SocialLinks{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
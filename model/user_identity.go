@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// ==================== 第三方登录 ====================
+
+// UserIdentity 用户绑定的第三方账号（同一提供方的同一账号只能绑定一个用户）
+type UserIdentity struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string    `gorm:"type:varchar(36);not null;index:idx_user_id" json:"user_id"`
+	Provider   string    `gorm:"type:varchar(32);not null;uniqueIndex:uk_provider_subject;comment:'提供方名称'" json:"provider"`
+	Subject    string    `gorm:"type:varchar(128);not null;uniqueIndex:uk_provider_subject;comment:'提供方内的账号ID'" json:"-"`
+	Username   string    `gorm:"type:varchar(255);comment:'第三方用户名（最近一次登录时）'" json:"username"`
+	Email      string    `gorm:"type:varchar(255);comment:'第三方邮箱'" json:"-"`
+	Avatar     string    `gorm:"type:varchar(500)" json:"avatar"`
+	CreateTime time.Time `gorm:"autoCreateTime" json:"create_time"`
+	UpdateTime time.Time `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identity"
+}
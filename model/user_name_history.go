@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// UserNameHistory 用户名和个性ID修改记录（管理员可查看）
+// 个性ID修改后，旧ID在 RedirectUntil 之前继续跳转到该用户，且不能被他人占用
+type UserNameHistory struct {
+	ID            uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        string     `gorm:"type:varchar(36);not null;index:idx_user_kind" json:"user_id"`
+	Kind          int8       `gorm:"type:tinyint;not null;index:idx_user_kind;comment:'1-用户名 2-个性ID'" json:"kind"`
+	OldValue      string     `gorm:"type:varchar(255);not null;index:idx_old_value" json:"old_value"`
+	NewValue      string     `gorm:"type:varchar(255);not null" json:"new_value"`
+	RedirectUntil *time.Time `gorm:"comment:'旧个性ID跳转截止时间'" json:"redirect_until,omitempty"`
+	ChangeTime    time.Time  `gorm:"autoCreateTime" json:"change_time"`
+}
+
+func (UserNameHistory) TableName() string {
+	return "user_name_history"
+}
+
+// 修改记录类型
+const (
+	NameHistoryKindUsername = 1 // 用户名（展示名）
+	NameHistoryKindHandle   = 2 // 个性ID
+)
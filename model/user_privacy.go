@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// ==================== 隐私设置 ====================
+
+// 隐私范围（相对设置者本人，本人始终可见）
+const (
+	PrivacyEveryone  = "everyone"  // 所有人（包括未登录用户）
+	PrivacyFollowers = "followers" // 关注我的人
+	PrivacyFollowing = "following" // 我关注的人
+	PrivacyFriends   = "friends"   // 互相关注的好友
+	PrivacyNobody    = "nobody"    // 仅自己
+)
+
+// UserPrivacy 用户隐私设置（没有记录时使用 DefaultUserPrivacy）
+type UserPrivacy struct {
+	ID                  uint64    `gorm:"primaryKey;autoIncrement" json:"-"`
+	UserID              string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_id" json:"user_id"`
+	FollowersVisibility string    `gorm:"type:varchar(20);not null;default:'everyone';comment:'谁可以看我的粉丝列表'" json:"followers_visibility"`
+	FollowingVisibility string    `gorm:"type:varchar(20);not null;default:'everyone';comment:'谁可以看我的关注列表'" json:"following_visibility"`
+	MessagePermission   string    `gorm:"type:varchar(20);not null;default:'friends';comment:'谁可以给我发私信'" json:"message_permission"`
+	CommentPermission   string    `gorm:"type:varchar(20);not null;default:'everyone';comment:'谁可以评论我的文章'" json:"comment_permission"`
+	BirthdayVisibility  string    `gorm:"type:varchar(20);not null;default:'everyone';comment:'谁可以看我的生日（仅月日，年份只有本人可见）'" json:"birthday_visibility"`
+	Searchable          bool      `gorm:"not null;default:true;index:idx_searchable;comment:'是否出现在用户搜索中'" json:"searchable"`
+	ShowInTrending      bool      `gorm:"not null;default:true;comment:'是否出现在热门用户榜单中'" json:"show_in_trending"`
+	UpdateTime          time.Time `gorm:"autoUpdateTime" json:"update_time"`
+}
+
+func (UserPrivacy) TableName() string {
+	return "user_privacy"
+}
+
+// DefaultUserPrivacy 默认隐私设置（私信默认仅好友，与原有规则一致）
+func DefaultUserPrivacy(userID string) *UserPrivacy {
+	return &UserPrivacy{
+		UserID:              userID,
+		FollowersVisibility: PrivacyEveryone,
+		FollowingVisibility: PrivacyEveryone,
+		MessagePermission:   PrivacyFriends,
+		CommentPermission:   PrivacyEveryone,
+		BirthdayVisibility:  PrivacyEveryone,
+		Searchable:          true,
+		ShowInTrending:      true,
+	}
+}
+
+// IsValidPrivacyScope 是否为合法的隐私范围
+func IsValidPrivacyScope(scope string) bool {
+	switch scope {
+	case PrivacyEveryone, PrivacyFollowers, PrivacyFollowing, PrivacyFriends, PrivacyNobody:
+		return true
+	}
+	return false
+}
@@ -33,30 +33,53 @@ const (
 	MinCommentLength  = 1     // 评论最小长度
 	MaxUsernameLength = 50    // 用户名最大长度
 	MinUsernameLength = 2     // 用户名最小长度
+	MaxHandleLength   = 20    // 个性ID最大长度
+	MinHandleLength   = 3     // 个性ID最小长度
 	MaxPasswordLength = 50    // 密码最大长度
 	MinPasswordLength = 6     // 密码最小长度
 )
 
 // 文件上传限制
 const (
-	MaxImageSize        = 5 * 1024 * 1024  // 图片最大5MB
-	MaxFileSize         = 20 * 1024 * 1024 // 文件最大20MB
-	MaxBatchUploadCount = 10               // 批量上传最多10个文件
+	MaxImageSize        = 5 * 1024 * 1024   // 图片最大5MB
+	MaxFileSize         = 20 * 1024 * 1024  // 文件最大20MB
+	MaxBatchUploadCount = 10                // 批量上传最多10个文件
+	MaxImportFileSize   = 100 * 1024 * 1024 // 导入包最大100MB
+	MaxImportEntrySize  = 100 * 1024 * 1024 // 导入包内单个Markdown/WXR文件解压后最大100MB
+	MaxImportTotalSize  = 200 * 1024 * 1024 // 导入包内Markdown/WXR文件解压后合计最大200MB（防止压缩炸弹）
 )
 
 // 缓存键前缀
 const (
-	CacheKeyUserInfo      = "user:info:"      // 用户信息缓存
-	CacheKeyArticle       = "article:"        // 文章缓存
-	CacheKeyArticleList   = "article:list:"   // 文章列表缓存
-	CacheKeyUserArticles  = "user:articles:"  // 用户文章列表缓存
-	CacheKeyHotArticles   = "hot:articles"    // 热门文章缓存
-	CacheKeyCommentList   = "comment:list:"   // 评论列表缓存
-	CacheKeyFollow        = "follow:"         // 关注缓存
-	CacheKeyFavorite      = "favorite:"       // 收藏缓存
-	CacheKeyNotification  = "notification:"   // 通知缓存
-	CacheKeyFollowCount   = "follow:count:"   // 关注数缓存
-	CacheKeyFavoriteCount = "favorite:count:" // 收藏数缓存
+	CacheKeyUserInfo      = "user:info:"        // 用户信息缓存
+	CacheKeyArticle       = "article:"          // 文章缓存
+	CacheKeyArticleList   = "article:list:"     // 文章列表缓存
+	CacheKeyUserArticles  = "user:articles:"    // 用户文章列表缓存
+	CacheKeyHotArticles   = "hot:articles"      // 热门文章缓存
+	CacheKeyCommentList   = "comment:list:"     // 评论列表缓存
+	CacheKeyFollow        = "follow:"           // 关注缓存
+	CacheKeyFavorite      = "favorite:"         // 收藏缓存
+	CacheKeyNotification  = "notification:"     // 通知缓存
+	CacheKeyFollowCount   = "follow:count:"     // 关注数缓存
+	CacheKeyFavoriteCount = "favorite:count:"   // 收藏数缓存
+	CacheKeyEmailVerify   = "email:verify:"     // 邮箱验证令牌
+	CacheKeyPasswordReset = "password:reset:"   // 重置密码令牌
+	CacheKeyEmailCooldown = "email:cooldown:"   // 邮件发送冷却
+	CacheKeySMSCode       = "sms:code:"         // 短信验证码
+	CacheKeySMSAttempt    = "sms:attempt:"      // 短信验证码错误次数
+	CacheKeySMSCooldown   = "sms:cooldown:"     // 短信发送间隔（按手机号）
+	CacheKeySMSDaily      = "sms:daily:"        // 短信每日发送次数（按手机号）
+	CacheKeySMSIP         = "sms:ip:"           // 短信每小时发送次数（按IP）
+	CacheKeyPhoneChange   = "phone:change:"     // 更换手机号凭证
+	CacheKeyTokenPhone    = "token:phone:"      // 更换手机号后token须携带的手机号（旧token失效）
+	CacheKeyTokenValid    = "token:valid:"      // 早于该时间（毫秒）签发的token失效
+	CacheKey2FAChallenge  = "2fa:challenge:"    // 两步验证登录凭证
+	CacheKey2FAFail       = "2fa:fail:"         // 两步验证失败次数
+	CacheKeyOAuthState    = "oauth:state:"      // 第三方登录授权状态
+	CacheKeyUserPrivacy   = "user:privacy:"     // 用户隐私设置
+	CacheKeyDailyLogin    = "level:login:"      // 每日登录经验已发放标记
+	CacheKeyUploadDaily   = "upload:daily:"     // 每日上传文件数
+	CacheKeyXPLeaderboard = "level:leaderboard" // 经验排行榜缓存
 )
 
 // 缓存过期时间（秒）
@@ -74,13 +97,41 @@ const (
 	RateLimitComment  = 10 // 评论限流：10次/分钟
 	RateLimitArticle  = 5  // 发文限流：5次/小时
 	RateLimitFollow   = 20 // 关注限流：20次/分钟
+	RateLimitPassword = 5  // 找回/重置密码限流：5次/小时
+)
+
+// 短信验证码
+const (
+	SMSCodeLength      = 6   // 验证码位数
+	SMSCodeExpire      = 300 // 验证码有效期5分钟
+	SMSSendInterval    = 60  // 同一手机号发送间隔60秒
+	SMSDailyLimitPhone = 10  // 同一手机号每天最多10条
+	SMSHourlyLimitIP   = 20  // 同一IP每小时最多20条
+	SMSMaxAttempts     = 5   // 同一验证码最多输错5次
+)
+
+// 风险控制：触发风险信号后要求图形验证码（登录失败改为递增等待，不再锁定账号）
+const (
+	RiskLoginFailWindow = 1800    // 登录失败次数统计窗口30分钟
+	RiskLoginFailPhone  = 2       // 同一手机号失败2次后需要验证码
+	RiskLoginFailIP     = 5       // 同一IP失败5次后需要验证码
+	RiskLoginDelayFrom  = 3       // 同一手机号失败3次起，每次失败后需要等待
+	RiskLoginDelayBase  = 5       // 首次等待5秒，之后每次翻倍
+	RiskLoginDelayMax   = 300     // 最长等待5分钟
+	RiskKnownIPExpire   = 7776000 // 登录过的IP记录保留90天，之外的IP视为新IP
+
+	RiskRegisterPerIP       = 1   // 同一IP 24小时内注册过账号后需要验证码
+	RiskRegisterBurst       = 20  // 全站窗口内注册数达到该值后所有注册需要验证码
+	RiskRegisterBurstWindow = 600 // 全站注册统计窗口10分钟
+
+	RiskCommentPerUser = 3  // 同一用户1分钟内评论3条后需要验证码
+	RiskCommentPerIP   = 10 // 同一IP 1分钟内评论10条后需要验证码
 )
 
 // 敏感操作锁定时间（秒）
 const (
-	LockTimeLogin   = 300 // 登录失败锁定5分钟
-	LockTimeComment = 60  // 评论过快锁定1分钟
-	LockTimeFollow  = 60  // 关注过快锁定1分钟
+	LockTimeComment = 60 // 评论过快锁定1分钟
+	LockTimeFollow  = 60 // 关注过快锁定1分钟
 )
 
 // ==================== 兼容旧代码的常量（逐步废弃）====================
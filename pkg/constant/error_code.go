@@ -27,6 +27,14 @@ func NewBizError(code int, message, enMessage string) *BizError {
 	}
 }
 
+// ==================== 响应码 ====================
+
+// 与 HTTP 状态对应的通用响应码（util.BadRequest 等响应函数使用）
+const (
+	CodeBadRequest      = 40000 // 参数错误或需要图形验证码
+	CodeTooManyRequests = 42900 // 请求过于频繁
+)
+
 // ==================== 通用错误码 (00xxx) ====================
 
 var (
@@ -74,6 +82,7 @@ var (
 	ErrRegisterFailed     = NewBizError(10005, "注册失败", "Registration failed")
 	ErrCaptchaInvalid     = NewBizError(10006, "验证码错误", "Invalid captcha")
 	ErrCaptchaExpired     = NewBizError(10007, "验证码已过期", "Captcha expired")
+	ErrCaptchaRequired    = NewBizError(10008, "请完成图形验证码", "Captcha required")
 
 	// 登录相关 (101xx)
 	ErrUserNotExist      = NewBizError(10101, "用户不存在", "User not found")
@@ -88,6 +97,57 @@ var (
 	ErrUpdateUserFailed      = NewBizError(10203, "更新用户信息失败", "Update user failed")
 	ErrUsernameInvalid       = NewBizError(10204, "用户名格式不正确", "Invalid username format")
 	ErrPhoneInvalid          = NewBizError(10205, "手机号格式不正确", "Invalid phone format")
+	ErrEmailInvalid          = NewBizError(10206, "邮箱格式不正确", "Invalid email format")
+	ErrEmailNotPending       = NewBizError(10207, "没有待验证的邮箱", "No email awaiting verification")
+	ErrEmailLinkInvalid      = NewBizError(10208, "链接无效或已过期", "Link is invalid or expired")
+	ErrSMSCodeInvalid        = NewBizError(10209, "短信验证码错误", "Invalid SMS code")
+	ErrSMSCodeExpired        = NewBizError(10210, "短信验证码已失效，请重新获取", "SMS code expired")
+	ErrSMSSendLimit          = NewBizError(10211, "短信发送次数已达上限，请稍后再试", "SMS send limit reached")
+	ErrPhoneChangeExpired    = NewBizError(10212, "请先验证原手机号", "Verify the current phone number first")
+	ErrPhoneUnchanged        = NewBizError(10213, "新手机号不能与原手机号相同", "New phone number is the same as the current one")
+
+	// 两步验证 (103xx)
+	ErrTwoFactorCodeInvalid      = NewBizError(10301, "两步验证码错误", "Invalid two-factor code")
+	ErrTwoFactorAlreadyEnabled   = NewBizError(10302, "两步验证已开启", "Two-factor authentication already enabled")
+	ErrTwoFactorNotEnabled       = NewBizError(10303, "未开启两步验证", "Two-factor authentication not enabled")
+	ErrTwoFactorSetupMissing     = NewBizError(10304, "请先获取两步验证密钥", "Start two-factor setup first")
+	ErrTwoFactorChallengeInvalid = NewBizError(10305, "验证已过期，请重新登录", "Two-factor challenge expired")
+	ErrTwoFactorRequired         = NewBizError(10306, "当前账号需先开启两步验证", "Two-factor authentication required")
+
+	// 第三方登录 (104xx)
+	ErrOAuthProviderNotFound = NewBizError(10401, "不支持的登录方式", "Unsupported login provider")
+	ErrOAuthStateInvalid     = NewBizError(10402, "授权已过期，请重新发起", "Authorization expired")
+	ErrOAuthFailed           = NewBizError(10403, "第三方授权失败，请重试", "Third-party authorization failed")
+	ErrIdentityAlreadyLinked = NewBizError(10404, "已绑定该平台的账号", "An account from this provider is already linked")
+	ErrIdentityLinkedToOther = NewBizError(10405, "该第三方账号已绑定其他用户", "This account is linked to another user")
+	ErrIdentityNotLinked     = NewBizError(10406, "未绑定该平台的账号", "No account from this provider is linked")
+	ErrIdentityLastLogin     = NewBizError(10407, "这是唯一的登录方式，请先绑定手机号或设置密码", "Cannot unlink the only login method")
+
+	// 账号注销与数据导出 (105xx)
+	ErrDeletionPending    = NewBizError(10501, "账号已在注销冷静期中", "Account deletion already requested")
+	ErrDeletionNotPending = NewBizError(10502, "没有可撤销的注销申请", "No pending account deletion")
+	ErrDataExportNotReady = NewBizError(10503, "数据导出尚未完成", "Data export is not ready")
+	ErrDataExportExpired  = NewBizError(10504, "导出文件已过期，请重新申请", "Data export has expired")
+
+	// 个人资料与隐私设置 (106xx)
+	ErrWebsiteInvalid        = NewBizError(10601, "网站地址格式不正确", "Invalid website URL")
+	ErrSocialLinkInvalid     = NewBizError(10602, "社交账号链接不正确", "Invalid social link")
+	ErrLocationTooLong       = NewBizError(10603, "所在地过长", "Location too long")
+	ErrBirthdayInvalid       = NewBizError(10604, "生日格式不正确", "Invalid birthday")
+	ErrCoverInvalid          = NewBizError(10605, "封面地址格式不正确", "Invalid cover URL")
+	ErrPrivacySettingInvalid = NewBizError(10606, "隐私设置不正确", "Invalid privacy setting")
+
+	// 个性ID (107xx)
+	ErrHandleInvalid           = NewBizError(10701, "个性ID需为3-20位小写字母、数字或下划线，且以字母开头", "Handle must be 3-20 lowercase letters, digits or underscores and start with a letter")
+	ErrHandleReserved          = NewBizError(10702, "该个性ID为系统保留", "Handle is reserved")
+	ErrHandleTaken             = NewBizError(10703, "该个性ID已被使用", "Handle is already taken")
+	ErrHandleChangeTooFrequent = NewBizError(10704, "个性ID每30天只能修改一次", "Handle can only be changed once every 30 days")
+	ErrHandleUnchanged         = NewBizError(10705, "新个性ID与当前相同", "New handle is the same as the current one")
+
+	// 等级特权 (108xx)
+	ErrLevelTooLowForLink  = NewBizError(10801, "当前等级暂不能发布链接", "Your level is too low to post links")
+	ErrLevelTooLowForTopic = NewBizError(10802, "当前等级暂不能创建话题", "Your level is too low to create topics")
+	ErrUploadQuotaExceeded = NewBizError(10803, "今日上传数量已达上限，提升等级可获得更多额度", "Daily upload quota reached")
 )
 
 // ==================== 博��模块错误码 (20xxx) ====================
@@ -140,6 +200,7 @@ var (
 	// 评论权限 (302xx)
 	ErrNotCommentOwner        = NewBizError(30201, "无权操作此评论", "Not comment owner")
 	ErrArticleNotAllowComment = NewBizError(30202, "文章不允许评论", "Article does not allow comments")
+	ErrCommentNotPermitted    = NewBizError(30203, "作者设置了评论权限，你暂时无法评论", "The author has restricted who can comment")
 
 	// 评论点赞 (303xx)
 	ErrLikeCommentFailed = NewBizError(30301, "点赞评论失败", "Like comment failed")
@@ -163,6 +224,12 @@ var (
 	ErrNotBlocked      = NewBizError(40103, "未拉黑该用户", "Not blocked")
 	ErrBlockFailed     = NewBizError(40104, "拉黑失败", "Block failed")
 	ErrUnblockFailed   = NewBizError(40105, "取消拉黑失败", "Unblock failed")
+
+	// 隐私限制 (402xx)
+	ErrFollowersHidden     = NewBizError(40201, "对方未公开粉丝列表", "Followers list is private")
+	ErrFollowingHidden     = NewBizError(40202, "对方未公开关注列表", "Following list is private")
+	ErrMessageNotPermitted = NewBizError(40203, "对方设置了私信权限，你暂时无法发送私信", "This user has restricted who can message them")
+	ErrBirthdayHidden      = NewBizError(40204, "对方未公开生日", "Birthday is private")
 )
 
 // ==================== 收藏模块错误码 (50xxx) ====================
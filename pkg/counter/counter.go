@@ -0,0 +1,236 @@
+package counter
+
+import (
+	"astronomer-gin/pkg/redis"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	redisv8 "github.com/go-redis/redis/v8"
+)
+
+// 计数缓冲：高频计数（浏览、点赞、收藏等）先以增量写入Redis Hash，由定时任务批量落库，
+// 避免热门文章的 UPDATE ... +1 在同一行上产生锁竞争。读取时合并尚未落库的增量。
+//
+// Key设计：
+//   counter:{entity}:{id}           待落库增量（Hash，field为数据库列名）
+//   counter:flushing:{entity}:{id}  正在落库的增量（存在即表示已被某次落库占用；成功后删除，失败则并回待落库增量）
+//   counter:pending:{entity}        有待落库增量的ID集合
+
+// 计数实体
+const (
+	EntityArticle = "article"
+)
+
+// ErrUnavailable Redis不可用（调用方应回退为直接写库）
+var ErrUnavailable = errors.New("计数缓冲不可用")
+
+// ErrInFlight 该ID的增量正在被另一次落库处理（本轮跳过）
+var ErrInFlight = errors.New("计数正在落库")
+
+var ctx = context.Background()
+
+func liveKey(entity string, id uint64) string {
+	return fmt.Sprintf("counter:%s:%d", entity, id)
+}
+
+func flushingKey(entity string, id uint64) string {
+	return fmt.Sprintf("counter:flushing:%s:%d", entity, id)
+}
+
+func pendingKey(entity string) string {
+	return fmt.Sprintf("counter:pending:%s", entity)
+}
+
+// takeScript 原子地把待落库增量转入flushing并返回；flushing已存在说明另一次落库尚未结束，
+// 此时不做任何修改（ID保留在待落库集合中）并返回false
+var takeScript = redisv8.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	return false
+end
+redis.call('SREM', KEYS[3], ARGV[1])
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return {}
+end
+redis.call('RENAME', KEYS[1], KEYS[2])
+return redis.call('HGETALL', KEYS[2])
+`)
+
+// restoreScript 把flushing中的增量并回待落库增量，并重新加入待落库集合
+var restoreScript = redisv8.NewScript(`
+local flushing = redis.call('HGETALL', KEYS[2])
+for i = 1, #flushing, 2 do
+	redis.call('HINCRBY', KEYS[1], flushing[i], flushing[i + 1])
+end
+redis.call('DEL', KEYS[2])
+redis.call('SADD', KEYS[3], ARGV[1])
+return #flushing / 2
+`)
+
+// Incr 累加计数增量
+func Incr(entity string, id uint64, field string, delta int64) error {
+	if redis.Client == nil {
+		return ErrUnavailable
+	}
+
+	pipe := redis.Client.TxPipeline()
+	pipe.HIncrBy(ctx, liveKey(entity, id), field, delta)
+	pipe.SAdd(ctx, pendingKey(entity), id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Pending 批量查询尚未落库的增量（含正在落库的部分），Redis异常时返回空结果
+func Pending(entity string, ids []uint64) map[uint64]map[string]int64 {
+	result := make(map[uint64]map[string]int64)
+	if redis.Client == nil || len(ids) == 0 {
+		return result
+	}
+
+	pipe := redis.Client.Pipeline()
+	cmds := make([][2]*redisv8.StringStringMapCmd, len(ids))
+	for i, id := range ids {
+		cmds[i][0] = pipe.HGetAll(ctx, liveKey(entity, id))
+		cmds[i][1] = pipe.HGetAll(ctx, flushingKey(entity, id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redisv8.Nil {
+		return result
+	}
+
+	for i, id := range ids {
+		for _, cmd := range cmds[i] {
+			for field, raw := range cmd.Val() {
+				delta, err := strconv.ParseInt(raw, 10, 64)
+				if err != nil || delta == 0 {
+					continue
+				}
+				if result[id] == nil {
+					result[id] = make(map[string]int64)
+				}
+				result[id][field] += delta
+			}
+		}
+	}
+	return result
+}
+
+// PendingIDs 获取一批有待落库增量的ID
+func PendingIDs(entity string, limit int64) ([]uint64, error) {
+	if redis.Client == nil {
+		return nil, ErrUnavailable
+	}
+
+	members, err := redis.Client.SRandMemberN(ctx, pendingKey(entity), limit).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(members))
+	for _, member := range members {
+		if id, err := strconv.ParseUint(member, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// AllPendingIDs 获取全部有未落库增量的ID（含正在落库的），用于核对计数时跳过
+func AllPendingIDs(entity string) ([]uint64, error) {
+	if redis.Client == nil {
+		return nil, ErrUnavailable
+	}
+
+	seen := make(map[uint64]bool)
+	ids := make([]uint64, 0)
+	add := func(raw string) {
+		if id, err := strconv.ParseUint(raw, 10, 64); err == nil && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	members := redis.Client.SScan(ctx, pendingKey(entity), 0, "", 500).Iterator()
+	for members.Next(ctx) {
+		add(members.Val())
+	}
+	if err := members.Err(); err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("counter:flushing:%s:", entity)
+	keys := redis.Client.Scan(ctx, 0, prefix+"*", 200).Iterator()
+	for keys.Next(ctx) {
+		add(strings.TrimPrefix(keys.Val(), prefix))
+	}
+	if err := keys.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Take 取出待落库增量，落库后必须调用Ack或Retry；另一次落库尚未结束时返回ErrInFlight
+func Take(entity string, id uint64) (map[string]int64, error) {
+	if redis.Client == nil {
+		return nil, ErrUnavailable
+	}
+
+	keys := []string{liveKey(entity, id), flushingKey(entity, id), pendingKey(entity)}
+	raw, err := takeScript.Run(ctx, redis.Client, keys, id).StringSlice()
+	if err == redisv8.Nil {
+		return nil, ErrInFlight
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make(map[string]int64, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		delta, err := strconv.ParseInt(raw[i+1], 10, 64)
+		if err != nil || delta == 0 {
+			continue
+		}
+		deltas[raw[i]] = delta
+	}
+	return deltas, nil
+}
+
+// Ack 确认增量已落库
+func Ack(entity string, id uint64) error {
+	if redis.Client == nil {
+		return ErrUnavailable
+	}
+	return redis.Client.Del(ctx, flushingKey(entity, id)).Err()
+}
+
+// Retry 落库失败，把flushing中的增量并回待落库增量，下一轮重新落库
+func Retry(entity string, id uint64) error {
+	if redis.Client == nil {
+		return ErrUnavailable
+	}
+	keys := []string{liveKey(entity, id), flushingKey(entity, id), pendingKey(entity)}
+	return restoreScript.Run(ctx, redis.Client, keys, id).Err()
+}
+
+// Recover 找回进程异常退出时遗留的flushing增量（启动、尚未开始落库时调用一次）
+func Recover(entity string) (int, error) {
+	if redis.Client == nil {
+		return 0, ErrUnavailable
+	}
+
+	prefix := fmt.Sprintf("counter:flushing:%s:", entity)
+	recovered := 0
+	iter := redis.Client.Scan(ctx, 0, prefix+"*", 200).Iterator()
+	for iter.Next(ctx) {
+		id, err := strconv.ParseUint(strings.TrimPrefix(iter.Val(), prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		if err := Retry(entity, id); err != nil {
+			return recovered, err
+		}
+		recovered++
+	}
+	return recovered, iter.Err()
+}
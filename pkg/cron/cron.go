@@ -1,31 +1,99 @@
 package cron
 
 import (
+	"astronomer-gin/config"
+	"astronomer-gin/pkg/counter"
 	"astronomer-gin/repository"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"gorm.io/gorm"
 )
 
+// QualityRescorer 近期文章质量重评分（由service层实现，cron包不直接依赖service）
+type QualityRescorer interface {
+	RescoreRecentArticles(since time.Time, limit int) (int, error)
+}
+
+// TrashPurger 回收站过期条目清除（由service层实现）
+type TrashPurger interface {
+	PurgeExpired(before time.Time, limit int) (int, error)
+}
+
+// AccountMaintainer 到期的账号注销和过期的数据导出文件清理（由service层实现）
+type AccountMaintainer interface {
+	ExecuteDueDeletions(now time.Time, limit int) (int, error)
+	PurgeExpiredExports(now time.Time, limit int) (int, error)
+}
+
+// AuditArchiver 超过保留期的审计日志归档（由service层实现）
+type AuditArchiver interface {
+	ArchiveExpired(now time.Time, limit int) (int, error)
+}
+
+const (
+	// qualityRescoreWindow 早期互动仍在变化的时间窗口
+	qualityRescoreWindow = 72 * time.Hour
+	// qualityRescoreBatch 每轮重评分的文章数上限
+	qualityRescoreBatch = 2000
+
+	// 回收站清除默认配置
+	defaultTrashRetentionDays = 30
+	defaultTrashPurgeBatch    = 200
+	defaultTrashPurgeSpec     = "0 30 3 * * *"
+
+	// accountMaintainBatch 每轮处理的注销申请/导出文件数上限
+	accountMaintainBatch = 100
+
+	// auditArchiveBatch 每轮归档的审计日志条数上限
+	auditArchiveBatch = 1000
+)
+
 // CronManager 定时任务管理器
 type CronManager struct {
-	cron     *cron.Cron
-	db       *gorm.DB
-	blogRepo repository.BlogRepository
+	cron            *cron.Cron
+	db              *gorm.DB
+	statsRepo       repository.ArticleStatsRepository
+	counterRepo     repository.CounterRepository
+	qualityRescorer QualityRescorer
+	trashPurger     TrashPurger
+	trashCfg        config.TrashConfig
+	accounts        AccountMaintainer
+	audits          AuditArchiver
+
+	// flushMu 串行化计数落库（定时落库与每日统计、核对任务会在同一秒触发）
+	flushMu sync.Mutex
 }
 
 // NewCronManager 创建定时任务管理器
-func NewCronManager(db *gorm.DB, blogRepo repository.BlogRepository) *CronManager {
+func NewCronManager(db *gorm.DB, statsRepo repository.ArticleStatsRepository, counterRepo repository.CounterRepository, qualityRescorer QualityRescorer, trashPurger TrashPurger, trashCfg config.TrashConfig, accounts AccountMaintainer, audits AuditArchiver) *CronManager {
 	// 创建带秒级精度的cron实例
 	c := cron.New(cron.WithSeconds())
 
+	if trashCfg.RetentionDays <= 0 {
+		trashCfg.RetentionDays = defaultTrashRetentionDays
+	}
+	if trashCfg.PurgeBatchSize <= 0 {
+		trashCfg.PurgeBatchSize = defaultTrashPurgeBatch
+	}
+	if trashCfg.PurgeSpec == "" {
+		trashCfg.PurgeSpec = defaultTrashPurgeSpec
+	}
+
 	return &CronManager{
-		cron:     c,
-		db:       db,
-		blogRepo: blogRepo,
+		cron:            c,
+		db:              db,
+		statsRepo:       statsRepo,
+		counterRepo:     counterRepo,
+		qualityRescorer: qualityRescorer,
+		trashPurger:     trashPurger,
+		trashCfg:        trashCfg,
+		accounts:        accounts,
+		audits:          audits,
 	}
 }
 
@@ -39,11 +107,11 @@ func (m *CronManager) Start() error {
 	}
 	log.Println("✅ 热度更新任务: 每小时执行")
 
-	// 2. 每天凌晨0点重置每日统计
+	// 2. 每天凌晨0点生成昨日统计快照并重置每日统计
 	if _, err := m.cron.AddFunc("0 0 0 * * *", m.ResetDailyStats); err != nil {
 		return fmt.Errorf("添加每日统计重置任务失败: %w", err)
 	}
-	log.Println("✅ 每日统计重置: 每天0点执行")
+	log.Println("✅ 每日统计快照与重置: 每天0点执行")
 
 	// 3. 每周一凌晨1点重置每周统计
 	if _, err := m.cron.AddFunc("0 0 1 * * 1", m.ResetWeeklyStats); err != nil {
@@ -69,6 +137,45 @@ func (m *CronManager) Start() error {
 	}
 	log.Println("✅ 数据备份任务: 每天3点执行")
 
+	// 7. 每10秒将Redis缓冲的计数增量批量落库（先找回上次异常退出时遗留的增量）
+	if recovered, err := counter.Recover(counter.EntityArticle); err == nil && recovered > 0 {
+		log.Printf("♻️  找回未落库的计数增量: %d篇文章", recovered)
+	}
+	if _, err := m.cron.AddFunc("*/10 * * * * *", m.FlushCounters); err != nil {
+		return fmt.Errorf("添加计数落库任务失败: %w", err)
+	}
+	log.Println("✅ 计数落库任务: 每10秒执行")
+
+	// 8. 每天凌晨4点核对冗余计数
+	if _, err := m.cron.AddFunc("0 0 4 * * *", m.ReconcileCounters); err != nil {
+		return fmt.Errorf("添加计数核对任务失败: %w", err)
+	}
+	log.Println("✅ 计数核对任务: 每天4点执行")
+
+	// 9. 每小时30分重新评估近72小时发布文章的质量分（早期互动数据持续变化）
+	if _, err := m.cron.AddFunc("0 30 * * * *", m.RescoreRecentQuality); err != nil {
+		return fmt.Errorf("添加质量重评分任务失败: %w", err)
+	}
+	log.Println("✅ 质量重评分任务: 每小时30分执行")
+
+	// 10. 彻底清除超过保留期的回收站条目
+	if _, err := m.cron.AddFunc(m.trashCfg.PurgeSpec, m.PurgeTrash); err != nil {
+		return fmt.Errorf("添加回收站清除任务失败: %w", err)
+	}
+	log.Printf("✅ 回收站清除任务: %s 执行（保留%d天）", m.trashCfg.PurgeSpec, m.trashCfg.RetentionDays)
+
+	// 11. 每小时15分执行冷静期已结束的账号注销，并删除过期的数据导出文件
+	if _, err := m.cron.AddFunc("0 15 * * * *", m.MaintainAccounts); err != nil {
+		return fmt.Errorf("添加账号注销任务失败: %w", err)
+	}
+	log.Println("✅ 账号注销与导出清理任务: 每小时15分执行")
+
+	// 12. 每天凌晨4点30分将超过保留期的审计日志移入归档表
+	if _, err := m.cron.AddFunc("0 30 4 * * *", m.ArchiveAuditLogs); err != nil {
+		return fmt.Errorf("添加审计日志归档任务失败: %w", err)
+	}
+	log.Println("✅ 审计日志归档任务: 每天4点30分执行")
+
 	// 启动定时任务
 	m.cron.Start()
 	log.Println("🚀 定时任务已启动")
@@ -81,6 +188,9 @@ func (m *CronManager) Stop() {
 	log.Println("⏸️  停止定时任务...")
 	ctx := m.cron.Stop()
 	<-ctx.Done()
+
+	// 退出前把缓冲的计数全部落库
+	m.FlushCounters()
 	log.Println("✅ 定时任务已停止")
 }
 
@@ -117,6 +227,10 @@ func (m *CronManager) ResetDailyStats() {
 	startTime := time.Now()
 	log.Println("\n[定时任务] 开始重置每日统计...")
 
+	// 先生成昨日快照（依赖累计计数，必须在任何计数重置前完成，快照前先落库缓冲的计数）
+	m.FlushCounters()
+	m.SnapshotDailyStats(startTime.AddDate(0, 0, -1))
+
 	// 重置article表的today_view_count
 	result := m.db.Exec("UPDATE article SET today_view_count = 0 WHERE delete_time IS NULL")
 	if result.Error != nil {
@@ -128,6 +242,16 @@ func (m *CronManager) ResetDailyStats() {
 	log.Printf("✅ 每日统计重置完成！影响行数: %d, 耗时: %v\n", result.RowsAffected, duration)
 }
 
+// SnapshotDailyStats 生成指定日期的文章/作者日统计快照（可重复执行，重复执行会覆盖当日结果）
+func (m *CronManager) SnapshotDailyStats(statDate time.Time) {
+	startTime := time.Now()
+	if err := m.statsRepo.SnapshotDaily(statDate); err != nil {
+		log.Printf("❌ 生成每日统计快照失败: date=%s, %v\n", statDate.Format("2006-01-02"), err)
+		return
+	}
+	log.Printf("✅ 每日统计快照完成！日期: %s, 耗时: %v\n", statDate.Format("2006-01-02"), time.Since(startTime))
+}
+
 // ResetWeeklyStats 重置每周统计
 func (m *CronManager) ResetWeeklyStats() {
 	startTime := time.Now()
@@ -171,30 +295,7 @@ func (m *CronManager) CleanupTempData() {
 	// 注意: 这里假设使用Redis，实际需要调用Redis服务
 	log.Println("  - 清理过期验证码...")
 
-	// 2. 清理30天前的软删除数据
-	thirtyDaysAgo := time.Now().AddDate(0, 0, -30)
-	result := m.db.Exec("DELETE FROM article WHERE delete_time IS NOT NULL AND delete_time < ?", thirtyDaysAgo)
-	if result.Error != nil {
-		log.Printf("❌ 清理软删除文章失败: %v\n", result.Error)
-	} else {
-		count += int(result.RowsAffected)
-		log.Printf("  - 清理软删除文章: %d条\n", result.RowsAffected)
-	}
-
-	// 3. 清理过期的草稿（90天未编辑）
-	ninetyDaysAgo := time.Now().AddDate(0, 0, -90)
-	result = m.db.Exec(`
-		DELETE FROM article
-		WHERE status = 0
-		AND update_time < ?
-		AND is_published = false
-	`, ninetyDaysAgo)
-	if result.Error != nil {
-		log.Printf("❌ 清理过期草稿失败: %v\n", result.Error)
-	} else {
-		count += int(result.RowsAffected)
-		log.Printf("  - 清理过期草稿: %d条\n", result.RowsAffected)
-	}
+	// 注意: 删除的文章、草稿和评论进入回收站，由 PurgeTrash 按保留期统一清除
 
 	duration := time.Since(startTime)
 	log.Printf("✅ 临时数据清理完成！总清理: %d条, 耗时: %v\n", count, duration)
@@ -234,6 +335,186 @@ func (m *CronManager) BackupCriticalData() {
 	// 例如: mysqldump、上传到云存储等
 }
 
+// counterFlushBatch 单次落库的最大文章数（剩余的留给下一轮）
+const counterFlushBatch = 1000
+
+// FlushCounters 将Redis缓冲的计数增量落库
+func (m *CronManager) FlushCounters() {
+	m.flushMu.Lock()
+	defer m.flushMu.Unlock()
+	m.flushCounters()
+}
+
+// flushCounters 落库一批计数增量（调用方需持有flushMu）
+func (m *CronManager) flushCounters() {
+	ids, err := counter.PendingIDs(counter.EntityArticle, counterFlushBatch)
+	if err != nil || len(ids) == 0 {
+		return
+	}
+
+	startTime := time.Now()
+	flushed, failed := 0, 0
+	for _, id := range ids {
+		deltas, err := counter.Take(counter.EntityArticle, id)
+		if errors.Is(err, counter.ErrInFlight) {
+			continue
+		}
+		if err != nil {
+			failed++
+			continue
+		}
+
+		if err := m.counterRepo.ApplyArticleDeltas(id, deltas); err != nil {
+			log.Printf("❌ 计数落库失败: article=%d, %v\n", id, err)
+			counter.Retry(counter.EntityArticle, id)
+			failed++
+			continue
+		}
+		counter.Ack(counter.EntityArticle, id)
+		flushed++
+	}
+
+	if flushed > 0 || failed > 0 {
+		log.Printf("✅ 计数落库完成！成功: %d, 失败: %d, 耗时: %v\n", flushed, failed, time.Since(startTime))
+	}
+}
+
+// ReconcileCounters 按源表核对并修正冗余计数
+func (m *CronManager) ReconcileCounters() {
+	startTime := time.Now()
+	log.Println("\n[定时任务] 开始核对冗余计数...")
+
+	// 先落库缓冲，仍有未落库增量的文章本轮跳过；核对期间暂停落库，避免增量在核对前后被重复计入
+	m.flushMu.Lock()
+	defer m.flushMu.Unlock()
+	m.flushCounters()
+	skipIDs, err := counter.AllPendingIDs(counter.EntityArticle)
+	if err != nil && err != counter.ErrUnavailable {
+		log.Printf("❌ 获取未落库计数失败，跳过本轮核对: %v\n", err)
+		return
+	}
+
+	reports, err := m.counterRepo.ReconcileCounters(skipIDs)
+	for _, report := range reports {
+		if report.Drifted > 0 {
+			log.Printf("⚠️  计数偏差 %s\n", report)
+		} else {
+			log.Printf("  - %s\n", report)
+		}
+	}
+	if err != nil {
+		log.Printf("❌ 核对冗余计数失败: %v\n", err)
+		return
+	}
+
+	log.Printf("✅ 冗余计数核对完成！耗时: %v\n", time.Since(startTime))
+}
+
+// RescoreRecentQuality 重新评估近期文章质量分
+func (m *CronManager) RescoreRecentQuality() {
+	if m.qualityRescorer == nil {
+		return
+	}
+	startTime := time.Now()
+	log.Println("\n[定时任务] 开始重新评估近期文章质量分...")
+
+	scored, err := m.qualityRescorer.RescoreRecentArticles(startTime.Add(-qualityRescoreWindow), qualityRescoreBatch)
+	if err != nil {
+		log.Printf("❌ 质量重评分失败: %v\n", err)
+		return
+	}
+
+	log.Printf("✅ 质量重评分完成！文章数: %d, 耗时: %v\n", scored, time.Since(startTime))
+}
+
+// PurgeTrash 彻底清除超过保留期的回收站条目（级联内容、历史、关联数据和MinIO媒体）
+func (m *CronManager) PurgeTrash() {
+	if m.trashPurger == nil {
+		return
+	}
+	startTime := time.Now()
+	log.Println("\n[定时任务] 开始清除过期回收站条目...")
+
+	before := startTime.AddDate(0, 0, -m.trashCfg.RetentionDays)
+	total := 0
+	for {
+		purged, err := m.trashPurger.PurgeExpired(before, m.trashCfg.PurgeBatchSize)
+		if err != nil {
+			log.Printf("❌ 清除回收站失败: %v\n", err)
+			break
+		}
+		total += purged
+		// 本轮不足一批（或全部失败）时结束，失败条目留待下次重试
+		if purged < m.trashCfg.PurgeBatchSize {
+			break
+		}
+	}
+
+	log.Printf("✅ 回收站清除完成！条目数: %d, 耗时: %v\n", total, time.Since(startTime))
+}
+
+// MaintainAccounts 执行到期的账号注销，删除过期的数据导出文件
+func (m *CronManager) MaintainAccounts() {
+	if m.accounts == nil {
+		return
+	}
+	startTime := time.Now()
+	log.Println("\n[定时任务] 开始执行到期的账号注销...")
+
+	deleted := 0
+	for {
+		executed, err := m.accounts.ExecuteDueDeletions(startTime, accountMaintainBatch)
+		if err != nil {
+			log.Printf("❌ 执行账号注销失败: %v\n", err)
+			break
+		}
+		deleted += executed
+		// 本轮不足一批（或部分失败）时结束，失败的申请留待下次重试
+		if executed < accountMaintainBatch {
+			break
+		}
+	}
+
+	purged := 0
+	for {
+		count, err := m.accounts.PurgeExpiredExports(startTime, accountMaintainBatch)
+		if err != nil {
+			log.Printf("❌ 清理过期导出文件失败: %v\n", err)
+			break
+		}
+		purged += count
+		if count < accountMaintainBatch {
+			break
+		}
+	}
+
+	log.Printf("✅ 账号注销完成！注销: %d, 清理导出文件: %d, 耗时: %v\n", deleted, purged, time.Since(startTime))
+}
+
+// ArchiveAuditLogs 将超过保留期的审计日志移入归档表
+func (m *CronManager) ArchiveAuditLogs() {
+	if m.audits == nil {
+		return
+	}
+	startTime := time.Now()
+	log.Println("\n[定时任务] 开始归档审计日志...")
+
+	total := 0
+	for {
+		archived, err := m.audits.ArchiveExpired(startTime, auditArchiveBatch)
+		if err != nil {
+			log.Printf("❌ 归档审计日志失败: %v\n", err)
+			break
+		}
+		total += archived
+		if archived < auditArchiveBatch {
+			break
+		}
+	}
+
+	log.Printf("✅ 审计日志归档完成！条数: %d, 耗时: %v\n", total, time.Since(startTime))
+}
+
 // ==================== 手动触发任务 ====================
 
 // ManualUpdateHotScores 手动触发热度更新
@@ -248,6 +529,16 @@ func (m *CronManager) ManualResetStats(statsType string) error {
 	log.Printf("🔧 手动触发统计重置: %s\n", statsType)
 
 	switch statsType {
+	case "counters":
+		m.ReconcileCounters()
+	case "quality":
+		m.RescoreRecentQuality()
+	case "trash":
+		m.PurgeTrash()
+	case "accounts":
+		m.MaintainAccounts()
+	case "snapshot":
+		m.SnapshotDailyStats(time.Now().AddDate(0, 0, -1))
 	case "daily":
 		m.ResetDailyStats()
 	case "weekly":
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/olivere/elastic/v7"
@@ -145,29 +146,34 @@ func CreateArticleIndex() error {
 	return nil
 }
 
-// IndexArticle 索引单篇文章
-func IndexArticle(article *model.Article) error {
-	if Client == nil {
-		return nil // ES未启用，静默失败
-	}
-
-	ctx := context.Background()
-
-	doc := ArticleDocument{
+// newArticleDocument 由V3文章与正文构建ES文档（沿用原有字段名，索引mapping无需变更）
+func newArticleDocument(article *model.ArticleV3, content string) ArticleDocument {
+	return ArticleDocument{
 		ID:            article.ID,
 		UserID:        article.UserID,
 		Title:         article.Title,
-		Preface:       article.Preface,
-		Content:       article.Content,
-		Photo:         article.Photo,
-		Tag:           article.Tag,
-		Visit:         int64(article.Visit),
-		GoodCount:     int64(article.GoodCount),
+		Preface:       article.Summary,
+		Content:       content,
+		Photo:         article.CoverImage,
+		Tag:           strings.Join(article.Tags, ","),
+		Visit:         int64(article.ViewCount),
+		GoodCount:     int64(article.LikeCount),
 		CommentCount:  int64(article.CommentCount),
 		FavoriteCount: int64(article.FavoriteCount),
 		CreateTime:    article.CreateTime,
 		UpdateTime:    article.UpdateTime,
 	}
+}
+
+// IndexArticle 索引单篇文章
+func IndexArticle(article *model.ArticleV3, content string) error {
+	if Client == nil {
+		return nil // ES未启用，静默失败
+	}
+
+	ctx := context.Background()
+
+	doc := newArticleDocument(article, content)
 
 	_, err := Client.Index().
 		Index(ArticleIndex).
@@ -265,8 +271,8 @@ func SearchArticles(keyword string, page, pageSize int) ([]ArticleDocument, int6
 	return articles, total, nil
 }
 
-// BulkIndexArticles 批量索引文章
-func BulkIndexArticles(articles []model.Article) error {
+// BulkIndexArticles 批量索引文章（contents为文章ID到正文的映射）
+func BulkIndexArticles(articles []model.ArticleV3, contents map[uint64]string) error {
 	if Client == nil {
 		return nil // ES未启用，静默失败
 	}
@@ -278,22 +284,9 @@ func BulkIndexArticles(articles []model.Article) error {
 	ctx := context.Background()
 	bulkRequest := Client.Bulk()
 
-	for _, article := range articles {
-		doc := ArticleDocument{
-			ID:            article.ID,
-			UserID:        article.UserID,
-			Title:         article.Title,
-			Preface:       article.Preface,
-			Content:       article.Content,
-			Photo:         article.Photo,
-			Tag:           article.Tag,
-			Visit:         int64(article.Visit),
-			GoodCount:     int64(article.GoodCount),
-			CommentCount:  int64(article.CommentCount),
-			FavoriteCount: int64(article.FavoriteCount),
-			CreateTime:    article.CreateTime,
-			UpdateTime:    article.UpdateTime,
-		}
+	for i := range articles {
+		article := &articles[i]
+		doc := newArticleDocument(article, contents[article.ID])
 
 		req := elastic.NewBulkIndexRequest().
 			Index(ArticleIndex).
@@ -2,6 +2,8 @@ package email
 
 import (
 	"fmt"
+	"html"
+	"strings"
 	"time"
 )
 
@@ -256,6 +258,120 @@ func (d *FollowNotificationData) GetBody() string {
 `, d.Username, d.FollowerName, d.FollowerName, d.FollowerBio, d.FollowTime.Format("2006-01-02 15:04:05"), d.FollowerCount)
 }
 
+// ============================= 邮箱验证邮件模板 =============================
+
+// VerifyEmailData 邮箱验证邮件数据
+type VerifyEmailData struct {
+	Username    string // 收件人用户名
+	Link        string // 验证链接
+	ExpireHours int    // 链接有效期（小时）
+}
+
+// GetSubject 获取邮箱验证邮件主题
+func (d *VerifyEmailData) GetSubject() string {
+	if Client != nil && Client.config.Templates.VerifySubject != "" {
+		return Client.config.Templates.VerifySubject
+	}
+	return "请验证你的邮箱"
+}
+
+// GetBody 获取邮箱验证邮件正文（HTML格式）
+func (d *VerifyEmailData) GetBody() string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); color: white; padding: 30px; text-align: center; border-radius: 10px 10px 0 0; }
+        .content { background: #f9f9f9; padding: 30px; border-radius: 0 0 10px 10px; }
+        .button { display: inline-block; padding: 12px 30px; background: #667eea; color: white; text-decoration: none; border-radius: 5px; margin-top: 20px; }
+        .link { word-break: break-all; color: #667eea; font-size: 12px; }
+        .footer { text-align: center; margin-top: 30px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📮 验证你的邮箱</h1>
+        </div>
+        <div class="content">
+            <h2>你好，%s！</h2>
+            <p>请点击下方按钮完成邮箱验证，验证后评论、点赞、关注等通知将发送到此邮箱，也可以通过此邮箱找回密码。</p>
+            <a href="%s" class="button">验证邮箱</a>
+            <p>如果按钮无法点击，请复制以下链接到浏览器打开：</p>
+            <p class="link">%s</p>
+            <p>链接 %d 小时内有效且只能使用一次。如果这不是你的操作，请忽略此邮件。</p>
+        </div>
+        <div class="footer">
+            <p>此邮件由系统自动发送，请勿直接回复。</p>
+            <p>© 2025 Astronomer博客平台. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(d.Username), d.Link, d.Link, d.ExpireHours)
+}
+
+// ============================= 重置密码邮件模板 =============================
+
+// ResetPasswordData 重置密码邮件数据
+type ResetPasswordData struct {
+	Username      string // 收件人用户名
+	Link          string // 重置链接
+	ExpireMinutes int    // 链接有效期（分钟）
+}
+
+// GetSubject 获取重置密码邮件主题
+func (d *ResetPasswordData) GetSubject() string {
+	if Client != nil && Client.config.Templates.ResetSubject != "" {
+		return Client.config.Templates.ResetSubject
+	}
+	return "重置你的Astronomer密码"
+}
+
+// GetBody 获取重置密码邮件正文（HTML格式）
+func (d *ResetPasswordData) GetBody() string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: linear-gradient(135deg, #f093fb 0%%, #f5576c 100%%); color: white; padding: 30px; text-align: center; border-radius: 10px 10px 0 0; }
+        .content { background: #f9f9f9; padding: 30px; border-radius: 0 0 10px 10px; }
+        .button { display: inline-block; padding: 12px 30px; background: #f5576c; color: white; text-decoration: none; border-radius: 5px; margin-top: 20px; }
+        .link { word-break: break-all; color: #f5576c; font-size: 12px; }
+        .footer { text-align: center; margin-top: 30px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔑 重置密码</h1>
+        </div>
+        <div class="content">
+            <h2>你好，%s！</h2>
+            <p>我们收到了重置你账号密码的请求，请点击下方按钮设置新密码。</p>
+            <a href="%s" class="button">重置密码</a>
+            <p>如果按钮无法点击，请复制以下链接到浏览器打开：</p>
+            <p class="link">%s</p>
+            <p>链接 %d 分钟内有效且只能使用一次。如果这不是你的操作，请忽略此邮件，你的密码不会改变。</p>
+        </div>
+        <div class="footer">
+            <p>此邮件由系统自动发送，请勿直接回复。</p>
+            <p>© 2025 Astronomer博客平台. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(d.Username), d.Link, d.Link, d.ExpireMinutes)
+}
+
 // ============================= 辅助函数 =============================
 
 // SendWelcomeEmail 发送欢迎邮件
@@ -298,3 +414,29 @@ func SendFollowNotification(email string, data *FollowNotificationData) error {
 
 	return Client.SendEmail(email, data.GetSubject(), data.GetBody(), true)
 }
+
+// SendVerifyEmail 发送邮箱验证邮件
+func SendVerifyEmail(email string, data *VerifyEmailData) error {
+	if Client == nil {
+		return fmt.Errorf("邮件客户端未初始化")
+	}
+
+	return Client.SendEmail(email, data.GetSubject(), data.GetBody(), true)
+}
+
+// SendResetPasswordEmail 发送重置密码邮件
+func SendResetPasswordEmail(email string, data *ResetPasswordData) error {
+	if Client == nil {
+		return fmt.Errorf("邮件客户端未初始化")
+	}
+
+	return Client.SendEmail(email, data.GetSubject(), data.GetBody(), true)
+}
+
+// SiteURL 邮件链接使用的前端站点地址
+func SiteURL() string {
+	if Client != nil && Client.config.SiteURL != "" {
+		return strings.TrimRight(Client.config.SiteURL, "/")
+	}
+	return "http://localhost:8080"
+}
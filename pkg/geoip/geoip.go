@@ -0,0 +1,119 @@
+package geoip
+
+import (
+	"astronomer-gin/config"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// 地域名称
+const (
+	RegionUnknown = "未知"
+	RegionLAN     = "内网"
+)
+
+// ipRange 一条IP段记录
+type ipRange struct {
+	start  uint32
+	end    uint32
+	region string
+}
+
+// ranges 按起始IP升序排列的IP段（只读，加载后不再修改）
+var ranges []ipRange
+
+// InitGeoIP 加载离线IP地址库
+// 文件格式为CSV，每行：起始IP,结束IP,国家,省份,城市；国内IP取省份，境外IP取国家
+func InitGeoIP(cfg *config.GeoIPConfig) error {
+	if cfg.DBPath == "" {
+		return fmt.Errorf("未配置IP地址库路径")
+	}
+
+	file, err := os.Open(cfg.DBPath)
+	if err != nil {
+		return fmt.Errorf("打开IP地址库失败: %w", err)
+	}
+	defer file.Close()
+
+	var loaded []ipRange
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		start, ok1 := ipv4ToUint(fields[0])
+		end, ok2 := ipv4ToUint(fields[1])
+		if !ok1 || !ok2 || end < start {
+			continue
+		}
+
+		region := strings.TrimSpace(fields[2])
+		if len(fields) > 3 && (region == "中国" || region == "CN") {
+			if province := strings.TrimSpace(fields[3]); province != "" {
+				region = province
+			}
+		}
+		if region == "" {
+			region = RegionUnknown
+		}
+
+		loaded = append(loaded, ipRange{start: start, end: end, region: region})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取IP地址库失败: %w", err)
+	}
+
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].start < loaded[j].start })
+	ranges = loaded
+
+	log.Printf("IP地址库加载成功 (%d条记录)", len(ranges))
+	return nil
+}
+
+// Lookup 查询IP所属地域（未加载地址库或未命中时返回"未知"）
+func Lookup(ip string) string {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return RegionUnknown
+	}
+	if parsed.IsLoopback() || parsed.IsPrivate() {
+		return RegionLAN
+	}
+
+	value, ok := ipv4ToUint(parsed.String())
+	if !ok || len(ranges) == 0 {
+		return RegionUnknown
+	}
+
+	// 二分查找最后一个起始IP不大于value的段
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].start > value }) - 1
+	if i >= 0 && value <= ranges[i].end {
+		return ranges[i].region
+	}
+	return RegionUnknown
+}
+
+// ipv4ToUint 将IPv4地址转换为整数
+func ipv4ToUint(ip string) (uint32, bool) {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return 0, false
+	}
+	v4 := parsed.To4()
+	if v4 == nil {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(v4), true
+}
@@ -10,11 +10,25 @@ import (
 )
 
 type LoginClaims struct {
-	Phone                string `json:"phone"`   // 保留用于兼容
-	UserID               string `json:"user_id"` // 新增：直接存储UUID
+	Phone                string `json:"phone"`            // 保留用于兼容
+	UserID               string `json:"user_id"`          // 新增：直接存储UUID
+	IssuedAtMs           int64  `json:"iat_ms,omitempty"` // 毫秒级签发时间（iat只到秒，判断token是否在吊销之前签发）
 	jwt.RegisteredClaims        //内嵌标准的声明
 }
 
+// IssuedBefore token是否在指定时间（毫秒时间戳）之前签发
+// 旧token没有iat_ms时按iat所在秒的起点计算
+func (c *LoginClaims) IssuedBefore(unixMilli int64) bool {
+	issuedAt := c.IssuedAtMs
+	if issuedAt == 0 {
+		if c.IssuedAt == nil {
+			return true
+		}
+		issuedAt = c.IssuedAt.Unix() * 1000
+	}
+	return issuedAt < unixMilli
+}
+
 var (
 	letters = []rune("0123456789qwertyuiopasdfghjklzxcvbnmQWERTYUIOPASDFGHJKLZXCVBNM")
 )
@@ -31,8 +45,9 @@ func RandStr(strLen int) string {
 func Sign(phone, userID string) (string, error) {
 	cfg := config.GlobalConfig.JWT
 	claim := LoginClaims{
-		Phone:  phone,
-		UserID: userID,
+		Phone:      phone,
+		UserID:     userID,
+		IssuedAtMs: time.Now().UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "Auth_Server",                                                                  //jwt签发者
 			Subject:   "Auth",                                                                         //jwt面向的用户
@@ -0,0 +1,40 @@
+package jwt
+
+import (
+	"astronomer-gin/config"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// NewSignedToken 生成一次性链接令牌（随机ID + HMAC签名）
+// 令牌本身不含用户信息，服务端用随机ID到Redis查询；签名按用途区分，防止伪造或挪作他用
+func NewSignedToken(purpose string) (token, id string, err error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	id = hex.EncodeToString(buf)
+	return id + "." + signTokenID(purpose, id), id, nil
+}
+
+// ParseSignedToken 校验令牌签名，返回随机ID
+func ParseSignedToken(purpose, token string) (string, bool) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok || id == "" {
+		return "", false
+	}
+	if !hmac.Equal([]byte(sig), []byte(signTokenID(purpose, id))) {
+		return "", false
+	}
+	return id, true
+}
+
+func signTokenID(purpose, id string) string {
+	mac := hmac.New(sha256.New, []byte(config.GlobalConfig.JWT.SecretKey))
+	mac.Write([]byte(purpose + ":" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
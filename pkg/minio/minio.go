@@ -98,6 +98,21 @@ func (m *MinIOClient) DeleteFile(ctx context.Context, objectName string) error {
 	return nil
 }
 
+// DownloadFile 下载文件内容
+func (m *MinIOClient) DownloadFile(ctx context.Context, objectName string) ([]byte, error) {
+	object, err := m.client.GetObject(ctx, m.bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
 // GetFileURL 获取文件的临时访问URL（带过期时间）
 func (m *MinIOClient) GetFileURL(ctx context.Context, objectName string, expires time.Duration) (string, error) {
 	presignedURL, err := m.client.PresignedGetObject(ctx, m.bucketName, objectName, expires, nil)
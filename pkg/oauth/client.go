@@ -0,0 +1,155 @@
+package oauth
+
+import (
+	"astronomer-gin/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// httpClient 请求提供方接口使用的客户端
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// tokenResponse 令牌接口响应
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	ErrorDesc   string `json:"error_description"`
+}
+
+// oauth2Client 标准授权码流程
+type oauth2Client struct {
+	name         string
+	displayName  string
+	clientID     string
+	clientSecret string
+	authURL      string
+	tokenURL     string
+	scopes       []string
+}
+
+func (c *oauth2Client) Name() string        { return c.name }
+func (c *oauth2Client) DisplayName() string { return c.displayName }
+
+// buildAuthURL 拼接授权页地址
+func (c *oauth2Client) buildAuthURL(authURL, state, codeChallenge, redirectURL string) string {
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.clientID},
+		"redirect_uri":          {redirectURL},
+		"state":                 {state},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	if len(c.scopes) > 0 {
+		params.Set("scope", strings.Join(c.scopes, " "))
+	}
+	separator := "?"
+	if strings.Contains(authURL, "?") {
+		separator = "&"
+	}
+	return authURL + separator + params.Encode()
+}
+
+// exchangeCode 用授权码换取令牌
+func (c *oauth2Client) exchangeCode(ctx context.Context, tokenURL, code, codeVerifier, redirectURL string) (*tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {c.clientID},
+		"code_verifier": {codeVerifier},
+	}
+	if c.clientSecret != "" {
+		form.Set("client_secret", c.clientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	if err := doJSON(req, &token); err != nil {
+		return nil, err
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("%s: %s", token.Error, token.ErrorDesc)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("令牌响应缺少access_token")
+	}
+	return &token, nil
+}
+
+// getJSON 携带访问令牌请求接口
+func getJSON(ctx context.Context, endpoint, accessToken string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	req.Header.Set("Accept", "application/json")
+	return doJSON(req, dest)
+}
+
+// doJSON 发送请求并解析JSON响应
+func doJSON(req *http.Request, dest interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s 返回 %d: %s", req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, dest); err != nil {
+		return fmt.Errorf("解析 %s 响应失败: %w", req.URL.Path, err)
+	}
+	return nil
+}
+
+// newOAuth2Client 按配置创建客户端，未配置的端点和授权范围使用默认值
+func newOAuth2Client(cfg *config.OAuthProviderConfig, defaultAuthURL, defaultTokenURL string, defaultScopes []string) *oauth2Client {
+	client := &oauth2Client{
+		name:         cfg.Name,
+		displayName:  cfg.DisplayName,
+		clientID:     cfg.ClientID,
+		clientSecret: cfg.ClientSecret,
+		authURL:      firstNonEmpty(cfg.AuthURL, defaultAuthURL),
+		tokenURL:     firstNonEmpty(cfg.TokenURL, defaultTokenURL),
+		scopes:       cfg.Scopes,
+	}
+	if client.displayName == "" {
+		client.displayName = cfg.Name
+	}
+	if len(client.scopes) == 0 {
+		client.scopes = defaultScopes
+	}
+	return client
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
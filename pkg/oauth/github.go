@@ -0,0 +1,78 @@
+package oauth
+
+import (
+	"astronomer-gin/config"
+	"context"
+	"fmt"
+	"strconv"
+)
+
+const (
+	githubAuthURL     = "https://github.com/login/oauth/authorize"
+	githubTokenURL    = "https://github.com/login/oauth/access_token"
+	githubUserInfoURL = "https://api.github.com/user"
+	githubEmailsURL   = "https://api.github.com/user/emails"
+)
+
+// githubProvider GitHub登录
+type githubProvider struct {
+	*oauth2Client
+	userInfoURL string
+}
+
+func newGitHubProvider(cfg *config.OAuthProviderConfig) (Provider, error) {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("缺少client_id或client_secret")
+	}
+	return &githubProvider{
+		oauth2Client: newOAuth2Client(cfg, githubAuthURL, githubTokenURL, []string{"read:user", "user:email"}),
+		userInfoURL:  firstNonEmpty(cfg.UserInfoURL, githubUserInfoURL),
+	}, nil
+}
+
+func (p *githubProvider) AuthCodeURL(ctx context.Context, state, codeChallenge, redirectURL string) (string, error) {
+	return p.buildAuthURL(p.authURL, state, codeChallenge, redirectURL), nil
+}
+
+func (p *githubProvider) Exchange(ctx context.Context, code, codeVerifier, redirectURL string) (*Identity, error) {
+	token, err := p.exchangeCode(ctx, p.tokenURL, code, codeVerifier, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := getJSON(ctx, p.userInfoURL, token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("GitHub用户信息缺少id")
+	}
+
+	identity := &Identity{
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Username: firstNonEmpty(user.Name, user.Login),
+		Avatar:   user.AvatarURL,
+	}
+
+	// 公开资料中的邮箱未必验证过，以邮箱接口中已验证的主邮箱为准（失败不影响登录）
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, githubEmailsURL, token.AccessToken, &emails); err == nil {
+		for _, email := range emails {
+			if email.Primary && email.Verified {
+				identity.Email = email.Email
+				identity.EmailVerified = true
+				break
+			}
+		}
+	}
+	return identity, nil
+}
@@ -0,0 +1,245 @@
+package oauth
+
+import (
+	"astronomer-gin/config"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 模拟提供方的授权码和令牌有效期
+const (
+	mockCodeExpire  = 5 * time.Minute
+	mockTokenExpire = time.Hour
+)
+
+// mockServer 本地联调用的模拟OIDC提供方（由本服务在 issuer 路径下提供，数据只保存在内存中）
+var mockServer *MockServer
+
+// GetMockServer 配置了 mock 类型提供方时返回模拟服务，否则为nil
+func GetMockServer() *MockServer {
+	return mockServer
+}
+
+func newMockProvider(cfg *config.OAuthProviderConfig) (Provider, error) {
+	// 任意用户名即可登录，只允许在调试模式下启用
+	if config.GlobalConfig == nil || config.GlobalConfig.Server.Mode != gin.DebugMode {
+		return nil, fmt.Errorf("模拟提供方仅限 server.mode 为 debug 时使用")
+	}
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("缺少issuer（本服务下的模拟提供方地址）")
+	}
+	issuerURL, err := url.Parse(strings.TrimSuffix(cfg.Issuer, "/"))
+	if err != nil || issuerURL.Path == "" {
+		return nil, fmt.Errorf("无效的issuer: %s", cfg.Issuer)
+	}
+
+	mockCfg := *cfg
+	if mockCfg.ClientID == "" {
+		mockCfg.ClientID = "mock-client"
+	}
+	mockServer = &MockServer{
+		issuer:      issuerURL.String(),
+		basePath:    issuerURL.Path,
+		clientID:    mockCfg.ClientID,
+		redirectURI: RedirectURL(mockCfg.Name),
+		codes:       make(map[string]*mockGrant),
+		tokens:      make(map[string]*mockGrant),
+	}
+	return newOIDCProvider(&mockCfg)
+}
+
+// mockGrant 授权码或访问令牌对应的授权
+type mockGrant struct {
+	login         string
+	redirectURI   string
+	codeChallenge string
+	expireAt      time.Time
+}
+
+// MockServer 模拟OIDC提供方：授权页输入任意用户名即可登录，令牌接口校验 PKCE
+type MockServer struct {
+	issuer      string
+	basePath    string
+	clientID    string
+	redirectURI string // 只允许跳转到本服务配置的回调地址
+
+	mu     sync.Mutex
+	codes  map[string]*mockGrant
+	tokens map[string]*mockGrant
+}
+
+// RegisterRoutes 注册模拟提供方的路由
+func (m *MockServer) RegisterRoutes(r *gin.Engine) {
+	group := r.Group(m.basePath)
+	{
+		group.GET("/.well-known/openid-configuration", m.discovery)
+		group.GET("/authorize", m.authorize)
+		group.POST("/token", m.token)
+		group.GET("/userinfo", m.userinfo)
+	}
+	log.Printf("⚠️  已启用模拟第三方登录: %s（仅限本地联调）", m.issuer)
+}
+
+func (m *MockServer) discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                           m.issuer,
+		"authorization_endpoint":           m.issuer + "/authorize",
+		"token_endpoint":                   m.issuer + "/token",
+		"userinfo_endpoint":                m.issuer + "/userinfo",
+		"response_types_supported":         []string{"code"},
+		"code_challenge_methods_supported": []string{"S256"},
+		"scopes_supported":                 []string{"openid", "profile", "email"},
+	})
+}
+
+// mockLoginPage 模拟授权页：原样带上授权参数，补充用户名后再次提交
+var mockLoginPage = template.Must(template.New("mock").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>模拟登录</title></head>
+<body style="font-family:sans-serif;max-width:360px;margin:80px auto">
+<h3>模拟第三方登录</h3>
+<p>输入任意用户名，相同用户名对应同一个第三方账号。</p>
+<form method="get">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{index $v 0}}">
+{{end}}<input name="login" placeholder="用户名" value="mockuser" required>
+<button type="submit">授权</button>
+</form>
+</body></html>`))
+
+func (m *MockServer) authorize(c *gin.Context) {
+	query := c.Request.URL.Query()
+	redirectURI := query.Get("redirect_uri")
+	switch {
+	case query.Get("client_id") != m.clientID:
+		c.String(http.StatusBadRequest, "invalid client_id")
+		return
+	case redirectURI != m.redirectURI:
+		c.String(http.StatusBadRequest, "invalid redirect_uri")
+		return
+	case query.Get("response_type") != "code":
+		c.String(http.StatusBadRequest, "invalid request")
+		return
+	case query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256":
+		c.String(http.StatusBadRequest, "PKCE (S256) required")
+		return
+	}
+
+	login := strings.TrimSpace(query.Get("login"))
+	if login == "" {
+		query.Del("login")
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		_ = mockLoginPage.Execute(c.Writer, gin.H{"Params": query})
+		return
+	}
+
+	code, err := m.issue(m.codes, &mockGrant{
+		login:         login,
+		redirectURI:   redirectURI,
+		codeChallenge: query.Get("code_challenge"),
+		expireAt:      time.Now().Add(mockCodeExpire),
+	})
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		c.String(http.StatusBadRequest, "invalid redirect_uri")
+		return
+	}
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	target.RawQuery = params.Encode()
+	c.Redirect(http.StatusFound, target.String())
+}
+
+func (m *MockServer) token(c *gin.Context) {
+	if c.PostForm("grant_type") != "authorization_code" || c.PostForm("client_id") != m.clientID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_client"})
+		return
+	}
+
+	grant := m.take(m.codes, c.PostForm("code"))
+	if grant == nil || grant.redirectURI != c.PostForm("redirect_uri") ||
+		S256Challenge(c.PostForm("code_verifier")) != grant.codeChallenge {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant"})
+		return
+	}
+
+	accessToken, err := m.issue(m.tokens, &mockGrant{
+		login:    grant.login,
+		expireAt: time.Now().Add(mockTokenExpire),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(mockTokenExpire.Seconds()),
+	})
+}
+
+func (m *MockServer) userinfo(c *gin.Context) {
+	accessToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+	m.mu.Lock()
+	grant, ok := m.tokens[accessToken]
+	m.mu.Unlock()
+	if !ok || time.Now().After(grant.expireAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sub":                "mock-" + grant.login,
+		"preferred_username": grant.login,
+		"name":               grant.login,
+		"email":              grant.login + "@mock.local",
+		"email_verified":     false,
+	})
+}
+
+// issue 保存授权并返回随机生成的授权码/令牌（顺带清理过期数据）
+func (m *MockServer) issue(store map[string]*mockGrant, grant *mockGrant) (string, error) {
+	key, err := RandomString(24)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, v := range store {
+		if now.After(v.expireAt) {
+			delete(store, k)
+		}
+	}
+	store[key] = grant
+	return key, nil
+}
+
+// take 取出并作废授权码
+func (m *MockServer) take(store map[string]*mockGrant, key string) *mockGrant {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	grant, ok := store[key]
+	if !ok {
+		return nil
+	}
+	delete(store, key)
+	if time.Now().After(grant.expireAt) {
+		return nil
+	}
+	return grant
+}
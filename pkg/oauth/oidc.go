@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"astronomer-gin/config"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// oidcProvider 通用 OpenID Connect 登录
+// 端点在首次使用时通过发现文档获取（发行方可能晚于本服务启动），账号信息以 userinfo 接口为准
+type oidcProvider struct {
+	*oauth2Client
+	issuer      string
+	userInfoURL string
+
+	mu         sync.Mutex
+	discovered bool
+}
+
+func newOIDCProvider(cfg *config.OAuthProviderConfig) (Provider, error) {
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("缺少client_id")
+	}
+	if cfg.Issuer == "" && (cfg.AuthURL == "" || cfg.TokenURL == "" || cfg.UserInfoURL == "") {
+		return nil, fmt.Errorf("缺少issuer，或未完整配置auth_url/token_url/userinfo_url")
+	}
+	return &oidcProvider{
+		oauth2Client: newOAuth2Client(cfg, "", "", []string{"openid", "profile", "email"}),
+		issuer:       strings.TrimSuffix(cfg.Issuer, "/"),
+		userInfoURL:  cfg.UserInfoURL,
+	}, nil
+}
+
+// endpoints 返回授权、令牌和用户信息端点，未配置的通过发现文档补全
+func (p *oidcProvider) endpoints(ctx context.Context) (string, string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.discovered && (p.authURL == "" || p.tokenURL == "" || p.userInfoURL == "") {
+		var doc struct {
+			AuthorizationEndpoint string `json:"authorization_endpoint"`
+			TokenEndpoint         string `json:"token_endpoint"`
+			UserinfoEndpoint      string `json:"userinfo_endpoint"`
+		}
+		if err := getJSON(ctx, p.issuer+"/.well-known/openid-configuration", "", &doc); err != nil {
+			return "", "", "", fmt.Errorf("获取OIDC发现文档失败: %w", err)
+		}
+		p.authURL = firstNonEmpty(p.authURL, doc.AuthorizationEndpoint)
+		p.tokenURL = firstNonEmpty(p.tokenURL, doc.TokenEndpoint)
+		p.userInfoURL = firstNonEmpty(p.userInfoURL, doc.UserinfoEndpoint)
+		p.discovered = true
+	}
+	if p.authURL == "" || p.tokenURL == "" || p.userInfoURL == "" {
+		return "", "", "", fmt.Errorf("OIDC发现文档缺少端点")
+	}
+	return p.authURL, p.tokenURL, p.userInfoURL, nil
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, codeChallenge, redirectURL string) (string, error) {
+	authURL, _, _, err := p.endpoints(ctx)
+	if err != nil {
+		return "", err
+	}
+	return p.buildAuthURL(authURL, state, codeChallenge, redirectURL), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, redirectURL string) (*Identity, error) {
+	_, tokenURL, userInfoURL, err := p.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.exchangeCode(ctx, tokenURL, code, codeVerifier, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims struct {
+		Subject           string `json:"sub"`
+		PreferredUsername string `json:"preferred_username"`
+		Name              string `json:"name"`
+		Nickname          string `json:"nickname"`
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		Picture           string `json:"picture"`
+	}
+	if err := getJSON(ctx, userInfoURL, token.AccessToken, &claims); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("userinfo缺少sub")
+	}
+	return &Identity{
+		Subject:       claims.Subject,
+		Username:      firstNonEmpty(claims.PreferredUsername, claims.Nickname, claims.Name),
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Avatar:        claims.Picture,
+	}, nil
+}
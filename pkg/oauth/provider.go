@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"astronomer-gin/config"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Identity 第三方账号信息
+type Identity struct {
+	Subject       string // 提供方内的唯一ID（绑定关系以此为准）
+	Username      string // 登录名或昵称
+	Email         string
+	EmailVerified bool
+	Avatar        string
+}
+
+// Provider 第三方登录提供方
+// 接入新的提供方类型时实现该接口，并在 InitOAuth 之前通过 RegisterProvider 注册
+type Provider interface {
+	Name() string
+	DisplayName() string
+	// AuthCodeURL 授权页地址，codeChallenge 为 PKCE 的 S256 挑战值
+	AuthCodeURL(ctx context.Context, state, codeChallenge, redirectURL string) (string, error)
+	// Exchange 用授权码换取令牌并查询账号信息
+	Exchange(ctx context.Context, code, codeVerifier, redirectURL string) (*Identity, error)
+}
+
+// ProviderFactory 根据配置创建提供方
+type ProviderFactory func(cfg *config.OAuthProviderConfig) (Provider, error)
+
+var (
+	providers   []Provider
+	redirectURL string
+	factories   = map[string]ProviderFactory{
+		"github": newGitHubProvider,
+		"wechat": newWeChatProvider,
+		"oidc":   newOIDCProvider,
+		"mock":   newMockProvider,
+	}
+)
+
+// RegisterProvider 注册提供方类型（同名覆盖）
+func RegisterProvider(providerType string, factory ProviderFactory) {
+	factories[providerType] = factory
+}
+
+// InitOAuth 初始化配置中的提供方，单个提供方配置错误时跳过
+func InitOAuth(cfg *config.OAuthConfig) error {
+	redirectURL = cfg.RedirectURL
+	providers = nil
+
+	seen := make(map[string]bool)
+	for i := range cfg.Providers {
+		providerCfg := &cfg.Providers[i]
+		if providerCfg.Name == "" || seen[providerCfg.Name] {
+			log.Printf("⚠️  第三方登录提供方名称为空或重复: %q", providerCfg.Name)
+			continue
+		}
+		factory, ok := factories[providerCfg.Type]
+		if !ok {
+			log.Printf("⚠️  未知的第三方登录类型: %s (%s)", providerCfg.Type, providerCfg.Name)
+			continue
+		}
+		provider, err := factory(providerCfg)
+		if err != nil {
+			log.Printf("⚠️  初始化第三方登录%s失败: %v", providerCfg.Name, err)
+			continue
+		}
+		seen[providerCfg.Name] = true
+		providers = append(providers, provider)
+	}
+
+	if len(cfg.Providers) > 0 && redirectURL == "" {
+		return fmt.Errorf("未配置第三方登录回调地址 oauth.redirect_url")
+	}
+	log.Printf("✅ 第三方登录初始化成功 (提供方: %d)", len(providers))
+	return nil
+}
+
+// Providers 已启用的提供方
+func Providers() []Provider {
+	return providers
+}
+
+// GetProvider 按名称查找提供方
+func GetProvider(name string) (Provider, bool) {
+	for _, provider := range providers {
+		if provider.Name() == name {
+			return provider, true
+		}
+	}
+	return nil, false
+}
+
+// RedirectURL 提供方的回调地址
+func RedirectURL(name string) string {
+	return strings.ReplaceAll(redirectURL, "{provider}", name)
+}
@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	"astronomer-gin/config"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	wechatAuthURL     = "https://open.weixin.qq.com/connect/qrconnect"
+	wechatTokenURL    = "https://api.weixin.qq.com/sns/oauth2/access_token"
+	wechatUserInfoURL = "https://api.weixin.qq.com/sns/userinfo"
+)
+
+// wechatProvider 微信网站应用扫码登录
+// 微信使用 appid/secret 参数名且不支持 PKCE，授权页不传挑战值，换取令牌时也不校验
+type wechatProvider struct {
+	*oauth2Client
+	userInfoURL string
+}
+
+// wechatError 微信接口的错误字段
+type wechatError struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (e wechatError) err() error {
+	if e.ErrCode != 0 {
+		return fmt.Errorf("微信接口错误 %d: %s", e.ErrCode, e.ErrMsg)
+	}
+	return nil
+}
+
+func newWeChatProvider(cfg *config.OAuthProviderConfig) (Provider, error) {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("缺少AppID或AppSecret")
+	}
+	return &wechatProvider{
+		oauth2Client: newOAuth2Client(cfg, wechatAuthURL, wechatTokenURL, []string{"snsapi_login"}),
+		userInfoURL:  firstNonEmpty(cfg.UserInfoURL, wechatUserInfoURL),
+	}, nil
+}
+
+func (p *wechatProvider) AuthCodeURL(ctx context.Context, state, codeChallenge, redirectURL string) (string, error) {
+	params := url.Values{
+		"appid":         {p.clientID},
+		"redirect_uri":  {redirectURL},
+		"response_type": {"code"},
+		"scope":         {strings.Join(p.scopes, ",")},
+		"state":         {state},
+	}
+	return p.authURL + "?" + params.Encode() + "#wechat_redirect", nil
+}
+
+func (p *wechatProvider) Exchange(ctx context.Context, code, codeVerifier, redirectURL string) (*Identity, error) {
+	params := url.Values{
+		"appid":      {p.clientID},
+		"secret":     {p.clientSecret},
+		"code":       {code},
+		"grant_type": {"authorization_code"},
+	}
+	var token struct {
+		wechatError
+		AccessToken string `json:"access_token"`
+		OpenID      string `json:"openid"`
+		UnionID     string `json:"unionid"`
+	}
+	if err := getJSON(ctx, p.tokenURL+"?"+params.Encode(), "", &token); err != nil {
+		return nil, err
+	}
+	if err := token.err(); err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		wechatError
+		Nickname   string `json:"nickname"`
+		HeadImgURL string `json:"headimgurl"`
+		UnionID    string `json:"unionid"`
+	}
+	userParams := url.Values{"access_token": {token.AccessToken}, "openid": {token.OpenID}}
+	if err := getJSON(ctx, p.userInfoURL+"?"+userParams.Encode(), "", &user); err != nil {
+		return nil, err
+	}
+	if err := user.err(); err != nil {
+		return nil, err
+	}
+
+	// 同一开放平台下的多个应用以 unionid 识别同一用户
+	subject := firstNonEmpty(user.UnionID, token.UnionID, token.OpenID)
+	if subject == "" {
+		return nil, fmt.Errorf("微信用户信息缺少openid")
+	}
+	return &Identity{
+		Subject:  subject,
+		Username: user.Nickname,
+		Avatar:   user.HeadImgURL,
+	}, nil
+}
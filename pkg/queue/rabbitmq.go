@@ -27,11 +27,16 @@ type RabbitMQClient struct {
 type TaskType string
 
 const (
-	TaskTypeNotification TaskType = "notification" // 通知任务
-	TaskTypeEmail        TaskType = "email"        // 邮件任务
-	TaskTypeSMS          TaskType = "sms"          // 短信任务
-	TaskTypeImageProcess TaskType = "image"        // 图片处理任务
-	TaskTypeStats        TaskType = "stats"        // 统计任务
+	TaskTypeNotification  TaskType = "notification"   // 通知任务
+	TaskTypeEmail         TaskType = "email"          // 邮件任务
+	TaskTypeSMS           TaskType = "sms"            // 短信任务
+	TaskTypeImageProcess  TaskType = "image"          // 图片处理任务
+	TaskTypeStats         TaskType = "stats"          // 统计任务
+	TaskTypeImport        TaskType = "import"         // 文章导入任务
+	TaskTypeColumnExport  TaskType = "column_export"  // 专栏电子书导出任务
+	TaskTypeEngagement    TaskType = "engagement"     // 阅读参与度统计任务
+	TaskTypeColumnChapter TaskType = "column_chapter" // 专栏新章节通知扇出任务
+	TaskTypeAccountExport TaskType = "account_export" // 个人数据导出任务
 )
 
 // Task 任务消息结构
@@ -20,6 +20,8 @@ const (
 	CodeUnauthorized = 401 // 未授权
 	CodeForbidden    = 403 // 无权限
 	CodeNotFound     = 404 // 资源不存在
+	CodeConflict     = 409 // 资源冲突（如版本已变化）
+	CodeTooMany      = 429 // 请求过于频繁
 	CodeServerError  = 500 // 服务器内部错误
 )
 
@@ -77,6 +79,15 @@ func NotFound(c *gin.Context, message string) {
 	})
 }
 
+// Conflict 资源冲突（data中返回服务器当前版本）
+func Conflict(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusOK, Response{
+		Code:    CodeConflict,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // ServerError 服务器内部错误
 func ServerError(c *gin.Context, message string) {
 	c.JSON(http.StatusOK, Response{
@@ -94,3 +105,12 @@ func Error(c *gin.Context, code int, message string) {
 		Data:    nil,
 	})
 }
+
+// ErrorWithData 带数据的错误响应
+func ErrorWithData(c *gin.Context, code int, message string, data interface{}) {
+	c.JSON(http.StatusOK, Response{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	})
+}
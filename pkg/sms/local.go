@@ -0,0 +1,61 @@
+package sms
+
+import (
+	"astronomer-gin/config"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// consoleProvider 打印到日志（本地开发用，不真正发送）
+type consoleProvider struct{}
+
+func newConsoleProvider(cfg *config.SMSConfig) (SMSProvider, error) {
+	return &consoleProvider{}, nil
+}
+
+func (p *consoleProvider) Name() string {
+	return "console"
+}
+
+func (p *consoleProvider) Send(phone, content string) error {
+	log.Printf("📱 [SMS] %s: %s", phone, content)
+	return nil
+}
+
+// fileProvider 追加写入文件（联调或自动化测试时读取验证码）
+type fileProvider struct {
+	path string
+	mu   sync.Mutex
+}
+
+func newFileProvider(cfg *config.SMSConfig) (SMSProvider, error) {
+	if cfg.FilePath == "" {
+		return nil, fmt.Errorf("未配置短信输出文件")
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.FilePath), 0755); err != nil {
+		return nil, err
+	}
+	return &fileProvider{path: cfg.FilePath}, nil
+}
+
+func (p *fileProvider) Name() string {
+	return "file"
+}
+
+func (p *fileProvider) Send(phone, content string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	file, err := os.OpenFile(p.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\n", time.Now().Format("2006-01-02 15:04:05"), phone, content)
+	return err
+}
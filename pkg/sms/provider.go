@@ -0,0 +1,69 @@
+package sms
+
+import (
+	"astronomer-gin/config"
+	"fmt"
+	"log"
+)
+
+// SMSProvider 短信发送通道
+// 接入真实短信服务商时实现该接口，并在 InitSMS 之前通过 RegisterProvider 注册
+type SMSProvider interface {
+	Name() string
+	Send(phone, content string) error
+}
+
+// ProviderFactory 根据配置创建短信通道
+type ProviderFactory func(cfg *config.SMSConfig) (SMSProvider, error)
+
+var (
+	// Provider 当前使用的短信通道
+	Provider SMSProvider
+
+	signName  string
+	factories = map[string]ProviderFactory{
+		"console": newConsoleProvider,
+		"file":    newFileProvider,
+	}
+)
+
+// RegisterProvider 注册短信通道（同名覆盖）
+func RegisterProvider(name string, factory ProviderFactory) {
+	factories[name] = factory
+}
+
+// InitSMS 初始化短信通道，未配置时使用 console
+func InitSMS(cfg *config.SMSConfig) error {
+	name := cfg.Provider
+	if name == "" {
+		name = "console"
+	}
+	factory, ok := factories[name]
+	if !ok {
+		return fmt.Errorf("未知的短信通道: %s", name)
+	}
+
+	provider, err := factory(cfg)
+	if err != nil {
+		return fmt.Errorf("初始化短信通道%s失败: %w", name, err)
+	}
+	Provider = provider
+	signName = cfg.SignName
+
+	log.Printf("✅ 短信服务初始化成功 (通道: %s)", provider.Name())
+	return nil
+}
+
+// Send 通过当前通道发送短信，未初始化时打印到日志
+func Send(phone, content string) error {
+	if Provider == nil {
+		Provider = &consoleProvider{}
+	}
+	if signName != "" {
+		content = "【" + signName + "】" + content
+	}
+	if err := Provider.Send(phone, content); err != nil {
+		return fmt.Errorf("发送短信失败(%s): %w", Provider.Name(), err)
+	}
+	return nil
+}
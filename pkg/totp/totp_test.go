@@ -0,0 +1,107 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录B的SHA1测试密钥 "12345678901234567890"（Base32）
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// RFC 6238 附录B的SHA1测试向量（取8位验证码的后6位）
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCodeRFC6238(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		got, err := Code(rfc6238Secret, Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("T=%d: %v", v.unix, err)
+		}
+		if got != v.code {
+			t.Errorf("T=%d: got %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestCodeLowercaseSecret(t *testing.T) {
+	got, err := Code(strings.ToLower(rfc6238Secret), Step(time.Unix(59, 0)))
+	if err != nil || got != "287082" {
+		t.Errorf("小写密钥: got %s, %v", got, err)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Fatal("无效密钥应返回错误")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	prev, _ := Code(rfc6238Secret, current-1)
+	stale, _ := Code(rfc6238Secret, current-2)
+
+	tests := []struct {
+		name     string
+		code     string
+		skew     int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"当前时间步", "050471", 0, current, true},
+		{"前一时间步在误差内", prev, 1, current - 1, true},
+		{"前一时间步不允许误差", prev, 0, 0, false},
+		{"超出误差", stale, 1, 0, false},
+		{"位数不对", "50471", 1, 0, false},
+		{"错误验证码", "000000", 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfc6238Secret, tt.code, now, tt.skew)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("got (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("密钥不是合法的Base32: %v", err)
+	}
+	if len(key) != 20 {
+		t.Errorf("密钥长度 %d 字节, want 20", len(key))
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Astronomer", "user@example.com", rfc6238Secret)
+	for _, want := range []string{
+		"otpauth://totp/Astronomer:user@example.com?",
+		"secret=" + rfc6238Secret,
+		"issuer=Astronomer",
+		"digits=6",
+		"period=30",
+	} {
+		if !strings.Contains(uri, want) {
+			t.Errorf("%s 缺少 %s", uri, want)
+		}
+	}
+}
@@ -36,6 +36,11 @@ func (c *CacheHelper) GetString(key string) (string, error) {
 	return c.client.Get(c.ctx, key).Result()
 }
 
+// GetDel 获取并删除字符串缓存（一次性令牌）
+func (c *CacheHelper) GetDel(key string) (string, error) {
+	return c.client.GetDel(c.ctx, key).Result()
+}
+
 // Set 设置缓存（自动序列化）
 func (c *CacheHelper) Set(key string, value interface{}, expiration time.Duration) error {
 	data, err := json.Marshal(value)
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"time"
 
+	"astronomer-gin/pkg/constant"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -84,7 +86,7 @@ func ErrorWithData(c *gin.Context, code int, message string, data interface{}) {
 
 // BadRequest 400 参数错误
 func BadRequest(c *gin.Context, message string) {
-	c.JSON(http.StatusOK, buildResponse(c, 40000, message, nil))
+	c.JSON(http.StatusOK, buildResponse(c, constant.CodeBadRequest, message, nil))
 }
 
 // Unauthorized 401 未授权
@@ -109,7 +111,7 @@ func Conflict(c *gin.Context, message string) {
 
 // TooManyRequests 429 请求过于频繁
 func TooManyRequests(c *gin.Context, message string) {
-	c.JSON(http.StatusOK, buildResponse(c, 42900, message, nil))
+	c.JSON(http.StatusOK, buildResponse(c, constant.CodeTooManyRequests, message, nil))
 }
 
 // InternalServerError 500 服务器内部错误
@@ -133,6 +133,45 @@ func ValidateUsername(username string) *constant.BizError {
 	return nil
 }
 
+// reservedHandles 系统保留的个性ID（路由、角色和品牌相关）
+var reservedHandles = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "system": true, "support": true,
+	"help": true, "api": true, "www": true, "mail": true, "login": true, "logout": true,
+	"register": true, "signup": true, "settings": true, "account": true, "user": true,
+	"users": true, "me": true, "null": true, "undefined": true, "official": true,
+	"moderator": true, "security": true, "astronomer": true, "search": true, "trending": true,
+}
+
+// reservedHandlePrefixes 系统自动分配使用的前缀（默认个性ID、已注销账号）
+var reservedHandlePrefixes = []string{"user_", "deleted_"}
+
+var handlePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// NormalizeHandle 规范化个性ID：去掉前导@并转为小写（个性ID不区分大小写）
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// ValidateHandle 验证个性ID（需先规范化）
+func ValidateHandle(handle string) *constant.BizError {
+	if len(handle) < constant.MinHandleLength || len(handle) > constant.MaxHandleLength {
+		return constant.ErrHandleInvalid
+	}
+	if !handlePattern.MatchString(handle) {
+		return constant.ErrHandleInvalid
+	}
+
+	if reservedHandles[handle] {
+		return constant.ErrHandleReserved
+	}
+	for _, prefix := range reservedHandlePrefixes {
+		if strings.HasPrefix(handle, prefix) {
+			return constant.ErrHandleReserved
+		}
+	}
+	return nil
+}
+
 // ValidatePassword 验证密码
 func ValidatePassword(password string) *constant.BizError {
 	length := len(password)
@@ -162,6 +201,18 @@ func ValidatePhone(phone string) *constant.BizError {
 	return nil
 }
 
+// ValidateEmail 验证邮箱
+func ValidateEmail(email string) *constant.BizError {
+	if len(email) > 255 {
+		return constant.ErrEmailInvalid
+	}
+	matched, _ := regexp.MatchString(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`, email)
+	if !matched {
+		return constant.ErrEmailInvalid
+	}
+	return nil
+}
+
 // ==================== 敏感词过滤工具 ====================
 
 // 敏感词列表（实际项目应该从配置文件或数据库加载）
@@ -19,13 +19,21 @@ type Client struct {
 	LastActive time.Time       // 最后活跃时间
 }
 
+// MessageHandler 业务消息处理函数（由业务模块通过 HandleFunc 注册）
+type MessageHandler func(client *Client, msg *WSMessage)
+
 // Hub WebSocket连接管理器（单例）
 type Hub struct {
-	clients    map[string]*Client // 用户ID -> 客户端映射
-	broadcast  chan *WSMessage    // 广播消息
-	register   chan *Client       // 注册客户端
-	unregister chan *Client       // 注销客户端
-	mu         sync.RWMutex       // 保护clients map
+	clients    map[string]*Client             // 用户ID -> 客户端映射
+	rooms      map[string]map[string]struct{} // 房间 -> 成员用户ID
+	broadcast  chan *WSMessage                // 广播消息
+	register   chan *Client                   // 注册客户端
+	unregister chan *Client                   // 注销客户端
+	mu         sync.RWMutex                   // 保护clients和rooms
+
+	handlers     map[string]MessageHandler // 消息类型 -> 业务处理函数
+	onDisconnect []func(userID string)     // 用户断开连接回调
+	handlerMu    sync.RWMutex              // 保护handlers和onDisconnect
 }
 
 var (
@@ -38,6 +46,8 @@ func GetHub() *Hub {
 	once.Do(func() {
 		instance = &Hub{
 			clients:    make(map[string]*Client),
+			rooms:      make(map[string]map[string]struct{}),
+			handlers:   make(map[string]MessageHandler),
 			broadcast:  make(chan *WSMessage, 256),
 			register:   make(chan *Client),
 			unregister: make(chan *Client),
@@ -71,13 +81,20 @@ func (h *Hub) Run() {
 
 		case client := <-h.unregister:
 			h.mu.Lock()
-			if _, exists := h.clients[client.UserID]; exists {
+			// 只注销当前连接（同一用户重连后旧连接的注销不影响新连接）
+			current, exists := h.clients[client.UserID]
+			if exists && current == client {
 				delete(h.clients, client.UserID)
+				h.leaveAllRooms(client.UserID)
 				close(client.Send)
 				log.Printf("👋 用户 %d 下线，当前在线人数: %d", client.UserID, len(h.clients))
 			}
 			h.mu.Unlock()
 
+			if exists && current == client {
+				h.notifyDisconnect(client.UserID)
+			}
+
 			// 通知好友该用户下线
 			h.NotifyOnlineStatus(client.UserID, false)
 
@@ -147,6 +164,100 @@ func (h *Hub) BroadcastToAll(message *WSMessage) {
 	}
 }
 
+// HandleFunc 注册业务消息处理函数（同一类型重复注册时覆盖）
+func (h *Hub) HandleFunc(msgType string, handler MessageHandler) {
+	h.handlerMu.Lock()
+	defer h.handlerMu.Unlock()
+	h.handlers[msgType] = handler
+}
+
+// OnDisconnect 注册用户断开连接回调（用于清理房间内的在线状态）
+func (h *Hub) OnDisconnect(fn func(userID string)) {
+	h.handlerMu.Lock()
+	defer h.handlerMu.Unlock()
+	h.onDisconnect = append(h.onDisconnect, fn)
+}
+
+// getHandler 查询业务消息处理函数
+func (h *Hub) getHandler(msgType string) (MessageHandler, bool) {
+	h.handlerMu.RLock()
+	defer h.handlerMu.RUnlock()
+	handler, ok := h.handlers[msgType]
+	return handler, ok
+}
+
+// notifyDisconnect 异步执行断开连接回调（不阻塞Hub主循环）
+func (h *Hub) notifyDisconnect(userID string) {
+	h.handlerMu.RLock()
+	hooks := append([]func(string){}, h.onDisconnect...)
+	h.handlerMu.RUnlock()
+
+	for _, fn := range hooks {
+		go fn(userID)
+	}
+}
+
+// JoinRoom 加入房间
+func (h *Hub) JoinRoom(room, userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	members, exists := h.rooms[room]
+	if !exists {
+		members = make(map[string]struct{})
+		h.rooms[room] = members
+	}
+	members[userID] = struct{}{}
+}
+
+// LeaveRoom 离开房间（房间为空时移除）
+func (h *Hub) LeaveRoom(room, userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if members, exists := h.rooms[room]; exists {
+		delete(members, userID)
+		if len(members) == 0 {
+			delete(h.rooms, room)
+		}
+	}
+}
+
+// leaveAllRooms 用户离开所有房间（调用方需持有写锁）
+func (h *Hub) leaveAllRooms(userID string) {
+	for room, members := range h.rooms {
+		delete(members, userID)
+		if len(members) == 0 {
+			delete(h.rooms, room)
+		}
+	}
+}
+
+// BroadcastToRoom 向房间内在线成员发送消息（excludeUserID 为空表示不排除）
+func (h *Hub) BroadcastToRoom(room string, message *WSMessage, excludeUserID string) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("❌ 序列化房间消息失败: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for userID := range h.rooms[room] {
+		if userID == excludeUserID {
+			continue
+		}
+		client, exists := h.clients[userID]
+		if !exists {
+			continue
+		}
+		select {
+		case client.Send <- data:
+		default:
+			log.Printf("⚠️  房间 %s 消息发送到用户 %s 失败，发送队列已满", room, userID)
+		}
+	}
+}
+
 // IsOnline 检查用户是否在线
 func (h *Hub) IsOnline(userID string) bool {
 	h.mu.RLock()
@@ -206,7 +317,9 @@ func (h *Hub) CleanupInactiveClients(timeout time.Duration) {
 			log.Printf("🧹 清理不活跃连接: 用户 %d", userID)
 			client.Conn.Close()
 			delete(h.clients, userID)
+			h.leaveAllRooms(userID)
 			close(client.Send)
+			h.notifyDisconnect(userID)
 		}
 	}
 }
@@ -331,6 +444,11 @@ func (c *Client) handleMessage(msg *WSMessage) {
 		}
 
 	default:
+		// 业务模块注册的消息（如草稿协同编辑）
+		if handler, ok := c.Hub.getHandler(msg.Type); ok {
+			handler(c, msg)
+			return
+		}
 		log.Printf("⚠️  未知消息类型: %s", msg.Type)
 	}
 }
@@ -1,6 +1,9 @@
 package websocket
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // MessageType WebSocket消息类型
 const (
@@ -18,6 +21,16 @@ const (
 	MessageTypeReadReceipt = "read_receipt" // 已读回执
 	MessageTypePong        = "pong"         // 心跳pong
 	MessageTypeError       = "error"        // 错误消息
+
+	// 草稿协同编辑
+	MessageTypeDraftJoin     = "draft_join"     // 加入草稿房间（客户端 -> 服务端）
+	MessageTypeDraftLeave    = "draft_leave"    // 离开草稿房间（客户端 -> 服务端）
+	MessageTypeDraftOp       = "draft_op"       // 编辑操作（双向）
+	MessageTypeDraftCursor   = "draft_cursor"   // 光标/选区（双向）
+	MessageTypeDraftJoined   = "draft_joined"   // 加入成功，下发当前文本和修订号
+	MessageTypeDraftAck      = "draft_ack"      // 编辑操作已被服务端接受
+	MessageTypeDraftPresence = "draft_presence" // 房间成员变化
+	MessageTypeDraftSaved    = "draft_saved"    // 合并后的文本已保存到草稿
 )
 
 // WSMessage WebSocket消息结构
@@ -28,6 +41,15 @@ type WSMessage struct {
 	MessageID string      `json:"message_id,omitempty"` // 消息ID（用于追踪）
 }
 
+// Bind 将Data解析到结构体（客户端消息反序列化后Data为map）
+func (m *WSMessage) Bind(v interface{}) error {
+	raw, err := json.Marshal(m.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
 // ChatMessageData 聊天消息数据
 type ChatMessageData struct {
 	ID          uint64    `json:"id"`           // 消息ID
@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// AccountRepository 账号注销与个人数据导出仓储接口
+type AccountRepository interface {
+	// 数据导出任务
+	CreateExport(export *model.UserDataExport) error
+	FindExportByID(id uint64) (*model.UserDataExport, error)
+	FindLatestExport(userID string) (*model.UserDataExport, error)
+	FindExportsByUser(userID string, limit int) ([]model.UserDataExport, error)
+	UpdateExportFields(id uint64, fields map[string]interface{}) error
+	// FindExpiredExports 下载截止时间已过、文件仍在对象存储中的导出
+	FindExpiredExports(before time.Time, limit int) ([]model.UserDataExport, error)
+
+	// 注销申请
+	FindDeletion(userID string) (*model.AccountDeletion, error)
+	// SaveDeletion 创建注销申请，已有记录（已撤销）时重新进入冷静期
+	SaveDeletion(deletion *model.AccountDeletion) error
+	// CancelDeletion 撤销冷静期中的注销申请，返回是否有申请被撤销
+	CancelDeletion(userID string) (bool, error)
+	// FindDueDeletions 冷静期已结束的注销申请
+	FindDueDeletions(before time.Time, limit int) ([]model.AccountDeletion, error)
+	// AnonymizeUser 在同一事务中匿名化账号、清理个人数据并标记注销完成
+	AnonymizeUser(deletion *model.AccountDeletion, placeholderPhone string, now time.Time) error
+
+	// 导出数据查询
+	FindArticles(userID string) ([]model.ArticleV3, error)
+	FindArticleContents(articleIDs []uint64) ([]model.ArticleContent, error)
+	FindDrafts(userID string) ([]model.ArticleDraft, error)
+	FindComments(userID string) ([]model.CommentV3, error)
+	FindLikes(userID string) ([]model.ArticleStar, error)
+	FindFavorites(userID string) ([]model.UserFavorite, error)
+	FindFollowing(userID string) ([]model.UserFollow, error)
+	FindFollowers(userID string) ([]model.UserFollow, error)
+	FindBlocks(userID string) ([]model.UserBlock, error)
+	FindChats(userID string) ([]model.UserChat, error)
+	FindNotifications(userID string) ([]model.Notification, error)
+}
+
+type accountRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountRepository 创建AccountRepository实例
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepository{db: db}
+}
+
+// ==================== 数据导出任务 ====================
+
+func (r *accountRepository) CreateExport(export *model.UserDataExport) error {
+	return r.db.Create(export).Error
+}
+
+func (r *accountRepository) FindExportByID(id uint64) (*model.UserDataExport, error) {
+	var export model.UserDataExport
+	if err := r.db.First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *accountRepository) FindLatestExport(userID string) (*model.UserDataExport, error) {
+	var export model.UserDataExport
+	if err := r.db.Where("user_id = ?", userID).Order("id DESC").First(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *accountRepository) FindExportsByUser(userID string, limit int) ([]model.UserDataExport, error) {
+	var exports []model.UserDataExport
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&exports).Error
+	return exports, err
+}
+
+func (r *accountRepository) UpdateExportFields(id uint64, fields map[string]interface{}) error {
+	return r.db.Model(&model.UserDataExport{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *accountRepository) FindExpiredExports(before time.Time, limit int) ([]model.UserDataExport, error) {
+	var exports []model.UserDataExport
+	err := r.db.Where("status = ? AND expire_time <= ?", model.DataExportStatusReady, before).
+		Order("expire_time ASC").Limit(limit).Find(&exports).Error
+	return exports, err
+}
+
+// ==================== 注销申请 ====================
+
+func (r *accountRepository) FindDeletion(userID string) (*model.AccountDeletion, error) {
+	var deletion model.AccountDeletion
+	if err := r.db.Where("user_id = ?", userID).First(&deletion).Error; err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+func (r *accountRepository) SaveDeletion(deletion *model.AccountDeletion) error {
+	if deletion.ID == 0 {
+		return r.db.Create(deletion).Error
+	}
+	return r.db.Model(&model.AccountDeletion{}).Where("id = ?", deletion.ID).Updates(map[string]interface{}{
+		"status":         deletion.Status,
+		"reason":         deletion.Reason,
+		"scheduled_time": deletion.ScheduledTime,
+		"request_time":   deletion.RequestTime,
+		"complete_time":  nil,
+	}).Error
+}
+
+func (r *accountRepository) CancelDeletion(userID string) (bool, error) {
+	result := r.db.Model(&model.AccountDeletion{}).
+		Where("user_id = ? AND status = ?", userID, model.AccountDeletionStatusPending).
+		Update("status", model.AccountDeletionStatusCancelled)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *accountRepository) FindDueDeletions(before time.Time, limit int) ([]model.AccountDeletion, error) {
+	var deletions []model.AccountDeletion
+	err := r.db.Where("status = ? AND scheduled_time <= ?", model.AccountDeletionStatusPending, before).
+		Order("scheduled_time ASC").Limit(limit).Find(&deletions).Error
+	return deletions, err
+}
+
+// AnonymizeUser 匿名化账号：
+// 用户资料清空并释放手机号和邮箱；文章下线、草稿删除；评论保留内容但抹去冗余的用户名、头像和设备信息；
+// 私信、关注（同时修正对方的关注数/粉丝数）、拉黑、点赞、收藏、第三方绑定、两步验证、通知等个人数据直接删除
+func (r *accountRepository) AnonymizeUser(deletion *model.AccountDeletion, placeholderPhone string, now time.Time) error {
+	userID := deletion.UserID
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 1. 申请已被撤销（冷静期内重新登录）时放弃执行
+		result := tx.Model(&model.AccountDeletion{}).
+			Where("id = ? AND status = ?", deletion.ID, model.AccountDeletionStatusPending).
+			Updates(map[string]interface{}{
+				"status":        model.AccountDeletionStatusCompleted,
+				"complete_time": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 2. 用户资料
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"phone":           placeholderPhone,
+			"handle":          placeholderPhone, // 释放个性ID（占位值使用保留前缀，不会与用户设置的冲突）
+			"email":           nil,
+			"pending_email":   "",
+			"username":        model.DeletedUsername,
+			"password":        "",
+			"icon":            "",
+			"note":            "",
+			"intro":           "",
+			"cover":           "",
+			"website":         "",
+			"social_links":    model.SocialLinks{},
+			"location":        "",
+			"birthday":        nil,
+			"following_count": 0,
+			"followed_count":  0,
+		}).Error; err != nil {
+			return err
+		}
+
+		// 3. 文章下线并删除正文和历史版本
+		articleIDs := tx.Model(&model.ArticleV3{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("article_id IN (?)", articleIDs).Delete(&model.ArticleContent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN (?)", articleIDs).Delete(&model.ArticleHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.ArticleV3{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"status":      model.ArticleV3StatusDeleted,
+			"delete_time": gorm.Expr("COALESCE(delete_time, ?)", now),
+		}).Error; err != nil {
+			return err
+		}
+
+		// 4. 草稿及其快照
+		draftIDs := tx.Model(&model.ArticleDraft{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("draft_id IN (?)", draftIDs).Delete(&model.ArticleDraftSnapshot{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&model.ArticleDraft{}).Error; err != nil {
+			return err
+		}
+
+		// 5. 评论保留楼层结构，抹去冗余的用户信息
+		if err := tx.Model(&model.CommentV3{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"username":    model.DeletedUsername,
+			"user_avatar": "",
+			"ip":          "",
+			"ip_location": "",
+			"device_type": "",
+			"user_agent":  "",
+		}).Error; err != nil {
+			return err
+		}
+
+		// 6. 动态
+		if err := tx.Model(&model.Dynamic{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"status":      model.DynamicStatusDeleted,
+			"ip":          "",
+			"ip_location": "",
+		}).Error; err != nil {
+			return err
+		}
+
+		// 7. 私信（双方的会话记录一并删除）
+		if err := tx.Where("from_user_id = ? OR to_user_id = ?", userID, userID).Delete(&model.UserChat{}).Error; err != nil {
+			return err
+		}
+
+		// 8. 关注关系：先修正对方的计数再删除
+		if err := tx.Model(&model.User{}).
+			Where("id IN (?) AND followed_count > 0", tx.Model(&model.UserFollow{}).Select("follow_user_id").Where("user_id = ?", userID)).
+			UpdateColumn("followed_count", gorm.Expr("followed_count - ?", 1)).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.User{}).
+			Where("id IN (?) AND following_count > 0", tx.Model(&model.UserFollow{}).Select("user_id").Where("follow_user_id = ?", userID)).
+			UpdateColumn("following_count", gorm.Expr("following_count - ?", 1)).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? OR follow_user_id = ?", userID, userID).Delete(&model.UserFollow{}).Error; err != nil {
+			return err
+		}
+
+		// 9. 点赞和收藏：先修正文章的计数，删除在下面的个人数据中完成
+		if err := tx.Model(&model.ArticleV3{}).
+			Where("id IN (?) AND like_count > 0", tx.Model(&model.ArticleStar{}).Select("article_id").Where("user_id = ?", userID)).
+			UpdateColumn("like_count", gorm.Expr("like_count - ?", 1)).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.ArticleV3{}).
+			Where("id IN (?) AND favorite_count > 0", tx.Model(&model.UserFavorite{}).Select("article_id").Where("user_id = ?", userID)).
+			UpdateColumn("favorite_count", gorm.Expr("favorite_count - ?", 1)).Error; err != nil {
+			return err
+		}
+
+		// 10. 其他个人数据
+		personal := []interface{}{
+			&model.UserBlock{},
+			&model.UserFavorite{},
+			&model.ArticleStar{},
+			&model.ColumnReadRecord{},
+			&model.Contributor{},
+			&model.UserIdentity{},
+			&model.UserTwoFactor{},
+			&model.UserRecoveryCode{},
+			&model.UserPrivacy{},
+			&model.UserNameHistory{}, // 旧个性ID不再跳转
+			&model.UserXP{},
+			&model.UserXPLog{},
+			&model.UserBadge{},
+			&model.Notification{},
+		}
+		for _, table := range personal {
+			if err := tx.Where("user_id = ?", userID).Delete(table).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("block_user_id = ?", userID).Delete(&model.UserBlock{}).Error; err != nil {
+			return err
+		}
+		// 发给他人的通知保留，抹去用户名
+		return tx.Model(&model.Notification{}).Where("from_user_id = ?", userID).
+			Update("from_username", model.DeletedUsername).Error
+	})
+}
+
+// ==================== 导出数据查询 ====================
+
+func (r *accountRepository) FindArticles(userID string) ([]model.ArticleV3, error) {
+	var articles []model.ArticleV3
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&articles).Error
+	return articles, err
+}
+
+func (r *accountRepository) FindArticleContents(articleIDs []uint64) ([]model.ArticleContent, error) {
+	var contents []model.ArticleContent
+	if len(articleIDs) == 0 {
+		return contents, nil
+	}
+	err := r.db.Where("article_id IN ?", articleIDs).Find(&contents).Error
+	return contents, err
+}
+
+func (r *accountRepository) FindDrafts(userID string) ([]model.ArticleDraft, error) {
+	var drafts []model.ArticleDraft
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&drafts).Error
+	return drafts, err
+}
+
+func (r *accountRepository) FindComments(userID string) ([]model.CommentV3, error) {
+	var comments []model.CommentV3
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&comments).Error
+	return comments, err
+}
+
+func (r *accountRepository) FindLikes(userID string) ([]model.ArticleStar, error) {
+	var likes []model.ArticleStar
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&likes).Error
+	return likes, err
+}
+
+func (r *accountRepository) FindFavorites(userID string) ([]model.UserFavorite, error) {
+	var favorites []model.UserFavorite
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&favorites).Error
+	return favorites, err
+}
+
+func (r *accountRepository) FindFollowing(userID string) ([]model.UserFollow, error) {
+	var follows []model.UserFollow
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&follows).Error
+	return follows, err
+}
+
+func (r *accountRepository) FindFollowers(userID string) ([]model.UserFollow, error) {
+	var follows []model.UserFollow
+	err := r.db.Where("follow_user_id = ?", userID).Order("id ASC").Find(&follows).Error
+	return follows, err
+}
+
+func (r *accountRepository) FindBlocks(userID string) ([]model.UserBlock, error) {
+	var blocks []model.UserBlock
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *accountRepository) FindChats(userID string) ([]model.UserChat, error) {
+	var chats []model.UserChat
+	err := r.db.Where("from_user_id = ? OR to_user_id = ?", userID, userID).Order("id ASC").Find(&chats).Error
+	return chats, err
+}
+
+func (r *accountRepository) FindNotifications(userID string) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&notifications).Error
+	return notifications, err
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FingerprintCandidate 指纹候选（含文章发布信息，用于判断原文）
+type FingerprintCandidate struct {
+	ArticleID  uint64
+	UserID     string
+	SimHash    uint64
+	CreateTime time.Time
+}
+
+// ArticleDuplicateRepository 文章查重仓储接口
+type ArticleDuplicateRepository interface {
+	// ==================== 指纹索引 ====================
+	SaveFingerprint(fp *model.ArticleFingerprint) error
+	DeleteFingerprint(articleID uint64) error
+	// FindCandidates 查找任一段相同、且属于其他作者的未删除文章指纹
+	FindCandidates(fp *model.ArticleFingerprint, limit int) ([]FingerprintCandidate, error)
+
+	// ==================== 疑似重复 ====================
+	// CreateDuplicateIfAbsent 记录疑似重复（同一对文章只记录一次）
+	CreateDuplicateIfAbsent(dup *model.ArticleDuplicate) error
+	FindDuplicateByID(id uint64) (*model.ArticleDuplicate, error)
+	FindDuplicatesByArticle(articleID uint64) ([]model.ArticleDuplicate, error)
+	FindDuplicatesByStatus(status int8, limit int) ([]model.ArticleDuplicate, error)
+	CountPendingByArticle(articleID uint64) (int64, error)
+	UpdateDuplicate(id uint64, fields map[string]interface{}) error
+	// DismissPendingByArticle 排除文章的待处理记录（excludeSourceIDs中的原文除外）
+	DismissPendingByArticle(articleID uint64, excludeSourceIDs []uint64, note string) error
+	// FindArticleBriefs 批量查询文章简要信息（不限状态，用于报告展示）
+	FindArticleBriefs(ids []uint64) ([]model.ArticleV3, error)
+
+	// ==================== 原创申诉 ====================
+	CreateClaim(claim *model.ArticleOriginalityClaim) error
+	FindClaimByID(id uint64) (*model.ArticleOriginalityClaim, error)
+	FindPendingClaimByArticle(articleID uint64) (*model.ArticleOriginalityClaim, error)
+	FindClaims(status int8, page, pageSize int) ([]model.ArticleOriginalityClaim, int64, error)
+	UpdateClaim(id uint64, fields map[string]interface{}) error
+}
+
+type articleDuplicateRepository struct {
+	db *gorm.DB
+}
+
+// NewArticleDuplicateRepository 创建ArticleDuplicateRepository实例
+func NewArticleDuplicateRepository(db *gorm.DB) ArticleDuplicateRepository {
+	return &articleDuplicateRepository{db: db}
+}
+
+// ==================== 指纹索引实现 ====================
+
+func (r *articleDuplicateRepository) SaveFingerprint(fp *model.ArticleFingerprint) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(fp).Error
+}
+
+func (r *articleDuplicateRepository) DeleteFingerprint(articleID uint64) error {
+	return r.db.Where("article_id = ?", articleID).Delete(&model.ArticleFingerprint{}).Error
+}
+
+func (r *articleDuplicateRepository) FindCandidates(fp *model.ArticleFingerprint, limit int) ([]FingerprintCandidate, error) {
+	var candidates []FingerprintCandidate
+	err := r.db.Table("article_fingerprint f").
+		Select("f.article_id, f.user_id, f.sim_hash, a.create_time").
+		Joins("INNER JOIN article_v3 a ON a.id = f.article_id AND a.delete_time IS NULL").
+		Where("(f.band0 = ? OR f.band1 = ? OR f.band2 = ? OR f.band3 = ?) AND f.user_id <> ? AND f.article_id <> ?",
+			fp.Band0, fp.Band1, fp.Band2, fp.Band3, fp.UserID, fp.ArticleID).
+		Limit(limit).
+		Scan(&candidates).Error
+	return candidates, err
+}
+
+// ==================== 疑似重复实现 ====================
+
+func (r *articleDuplicateRepository) CreateDuplicateIfAbsent(dup *model.ArticleDuplicate) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(dup).Error
+}
+
+func (r *articleDuplicateRepository) FindDuplicateByID(id uint64) (*model.ArticleDuplicate, error) {
+	var dup model.ArticleDuplicate
+	if err := r.db.First(&dup, id).Error; err != nil {
+		return nil, err
+	}
+	return &dup, nil
+}
+
+func (r *articleDuplicateRepository) FindDuplicatesByArticle(articleID uint64) ([]model.ArticleDuplicate, error) {
+	var dups []model.ArticleDuplicate
+	err := r.db.Where("article_id = ? OR source_article_id = ?", articleID, articleID).
+		Order("create_time DESC").
+		Find(&dups).Error
+	return dups, err
+}
+
+func (r *articleDuplicateRepository) FindDuplicatesByStatus(status int8, limit int) ([]model.ArticleDuplicate, error) {
+	var dups []model.ArticleDuplicate
+	err := r.db.Where("status = ?", status).
+		Order("create_time DESC").
+		Limit(limit).
+		Find(&dups).Error
+	return dups, err
+}
+
+func (r *articleDuplicateRepository) CountPendingByArticle(articleID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ArticleDuplicate{}).
+		Where("article_id = ? AND status = ?", articleID, model.DuplicateStatusPending).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *articleDuplicateRepository) UpdateDuplicate(id uint64, fields map[string]interface{}) error {
+	return r.db.Model(&model.ArticleDuplicate{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *articleDuplicateRepository) DismissPendingByArticle(articleID uint64, excludeSourceIDs []uint64, note string) error {
+	query := r.db.Model(&model.ArticleDuplicate{}).
+		Where("article_id = ? AND status = ?", articleID, model.DuplicateStatusPending)
+	if len(excludeSourceIDs) > 0 {
+		query = query.Where("source_article_id NOT IN ?", excludeSourceIDs)
+	}
+
+	now := time.Now()
+	return query.Updates(map[string]interface{}{
+		"status":      model.DuplicateStatusDismissed,
+		"handle_note": note,
+		"handle_time": &now,
+	}).Error
+}
+
+func (r *articleDuplicateRepository) FindArticleBriefs(ids []uint64) ([]model.ArticleV3, error) {
+	var articles []model.ArticleV3
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	err := r.db.Select("id, user_id, title, status, audit_status, audit_reason, create_time").
+		Where("id IN ?", ids).
+		Find(&articles).Error
+	return articles, err
+}
+
+// ==================== 原创申诉实现 ====================
+
+func (r *articleDuplicateRepository) CreateClaim(claim *model.ArticleOriginalityClaim) error {
+	return r.db.Create(claim).Error
+}
+
+func (r *articleDuplicateRepository) FindClaimByID(id uint64) (*model.ArticleOriginalityClaim, error) {
+	var claim model.ArticleOriginalityClaim
+	if err := r.db.First(&claim, id).Error; err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *articleDuplicateRepository) FindPendingClaimByArticle(articleID uint64) (*model.ArticleOriginalityClaim, error) {
+	var claim model.ArticleOriginalityClaim
+	err := r.db.Where("article_id = ? AND status = ?", articleID, model.AuditStatusPending).
+		First(&claim).Error
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *articleDuplicateRepository) FindClaims(status int8, page, pageSize int) ([]model.ArticleOriginalityClaim, int64, error) {
+	var claims []model.ArticleOriginalityClaim
+	var total int64
+
+	query := r.db.Model(&model.ArticleOriginalityClaim{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("create_time ASC").Limit(pageSize).Offset(offset).Find(&claims).Error; err != nil {
+		return nil, 0, err
+	}
+	return claims, total, nil
+}
+
+func (r *articleDuplicateRepository) UpdateClaim(id uint64, fields map[string]interface{}) error {
+	return r.db.Model(&model.ArticleOriginalityClaim{}).Where("id = ?", id).Updates(fields).Error
+}
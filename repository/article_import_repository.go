@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// ArticleImportRepository 文章导入任务仓储接口
+type ArticleImportRepository interface {
+	Create(job *model.ArticleImportJob) error
+	FindByID(id uint64) (*model.ArticleImportJob, error)
+	FindByUserID(userID string, page, pageSize int) ([]model.ArticleImportJob, int64, error)
+	UpdateFields(id uint64, fields map[string]interface{}) error
+}
+
+type articleImportRepository struct {
+	db *gorm.DB
+}
+
+// NewArticleImportRepository 创建ArticleImportRepository实例
+func NewArticleImportRepository(db *gorm.DB) ArticleImportRepository {
+	return &articleImportRepository{db: db}
+}
+
+func (r *articleImportRepository) Create(job *model.ArticleImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *articleImportRepository) FindByID(id uint64) (*model.ArticleImportJob, error) {
+	var job model.ArticleImportJob
+	if err := r.db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *articleImportRepository) FindByUserID(userID string, page, pageSize int) ([]model.ArticleImportJob, int64, error) {
+	var jobs []model.ArticleImportJob
+	var total int64
+
+	query := r.db.Model(&model.ArticleImportJob{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("create_time DESC").
+		Limit(pageSize).Offset(offset).
+		Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return jobs, total, nil
+}
+
+func (r *articleImportRepository) UpdateFields(id uint64, fields map[string]interface{}) error {
+	return r.db.Model(&model.ArticleImportJob{}).Where("id = ?", id).Updates(fields).Error
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthorQualityHistory 作者历史表现（质量评分的作者维度）
+type AuthorQualityHistory struct {
+	PublishedCount    int64   // 已发布文章数（不含当前文章）
+	ScoredCount       int64   // 已评分文章数
+	AvgQualityScore   float64 // 历史文章平均质量分
+	RejectedCount     int64   // 审核失败或被下线的文章数
+	ConfirmedDupCount int64   // 被确认为重复搬运的次数
+}
+
+// ArticleQualityRepository 文章质量评分仓储接口
+type ArticleQualityRepository interface {
+	// SaveScore 保存评分明细并同步文章表的质量分
+	SaveScore(score *model.ArticleQualityScore) error
+	FindScore(articleID uint64) (*model.ArticleQualityScore, error)
+	// FindAuthorHistory 统计作者除当前文章外的历史表现
+	FindAuthorHistory(userID string, excludeArticleID uint64) (*AuthorQualityHistory, error)
+	FindEnabledSensitiveWords() ([]model.CommentSensitiveWord, error)
+	// FindPublishedSince 查询某时间之后发布的文章ID（用于早期互动数据变化后重新评分）
+	FindPublishedSince(since time.Time, limit int) ([]uint64, error)
+}
+
+type articleQualityRepository struct {
+	db *gorm.DB
+}
+
+// NewArticleQualityRepository 创建ArticleQualityRepository实例
+func NewArticleQualityRepository(db *gorm.DB) ArticleQualityRepository {
+	return &articleQualityRepository{db: db}
+}
+
+func (r *articleQualityRepository) SaveScore(score *model.ArticleQualityScore) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(score).Error; err != nil {
+			return err
+		}
+		// UpdateColumn 不触发 update_time，评分不算作文章编辑
+		return tx.Model(&model.ArticleV3{}).
+			Where("id = ?", score.ArticleID).
+			UpdateColumn("quality_score", score.Score).Error
+	})
+}
+
+func (r *articleQualityRepository) FindScore(articleID uint64) (*model.ArticleQualityScore, error) {
+	var score model.ArticleQualityScore
+	if err := r.db.Where("article_id = ?", articleID).First(&score).Error; err != nil {
+		return nil, err
+	}
+	return &score, nil
+}
+
+func (r *articleQualityRepository) FindAuthorHistory(userID string, excludeArticleID uint64) (*AuthorQualityHistory, error) {
+	var history AuthorQualityHistory
+
+	// 1. 发布数与历史平均分
+	var row struct {
+		PublishedCount  int64
+		ScoredCount     int64
+		AvgQualityScore float64
+		RejectedCount   int64
+	}
+	err := r.db.Model(&model.ArticleV3{}).
+		Select("SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS published_count, "+
+			"SUM(CASE WHEN status = ? AND quality_score > 0 THEN 1 ELSE 0 END) AS scored_count, "+
+			"COALESCE(AVG(CASE WHEN status = ? AND quality_score > 0 THEN quality_score END), 0) AS avg_quality_score, "+
+			"SUM(CASE WHEN status IN ? THEN 1 ELSE 0 END) AS rejected_count",
+			model.ArticleV3StatusPublished, model.ArticleV3StatusPublished, model.ArticleV3StatusPublished,
+			[]int8{model.ArticleV3StatusAuditFailed, model.ArticleV3StatusOffline}).
+		Where("user_id = ? AND id <> ? AND delete_time IS NULL", userID, excludeArticleID).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	history.PublishedCount = row.PublishedCount
+	history.ScoredCount = row.ScoredCount
+	history.AvgQualityScore = row.AvgQualityScore
+	history.RejectedCount = row.RejectedCount
+
+	// 2. 被确认的重复搬运记录
+	if err := r.db.Model(&model.ArticleDuplicate{}).
+		Where("user_id = ? AND status = ?", userID, model.DuplicateStatusConfirmed).
+		Count(&history.ConfirmedDupCount).Error; err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+func (r *articleQualityRepository) FindEnabledSensitiveWords() ([]model.CommentSensitiveWord, error) {
+	var words []model.CommentSensitiveWord
+	err := r.db.Where("is_enabled = ?", true).Find(&words).Error
+	return words, err
+}
+
+func (r *articleQualityRepository) FindPublishedSince(since time.Time, limit int) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&model.ArticleV3{}).
+		Where("status = ? AND delete_time IS NULL AND publish_time >= ?", model.ArticleV3StatusPublished, since).
+		Order("publish_time DESC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
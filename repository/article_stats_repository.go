@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// TopArticleRow 时间段内文章表现汇总
+type TopArticleRow struct {
+	ArticleID   uint64 `json:"article_id"`
+	Title       string `json:"title"`
+	Value       int64  `json:"value"` // 排序指标值
+	Views       int64  `json:"views"`
+	UniqueViews int64  `json:"unique_views"`
+	Likes       int64  `json:"likes"`
+	Comments    int64  `json:"comments"`
+	Favorites   int64  `json:"favorites"`
+	Shares      int64  `json:"shares"`
+}
+
+// topArticleMetrics 可排序指标（白名单，防止注入）
+var topArticleMetrics = map[string]string{
+	"views":        "view_count",
+	"unique_views": "unique_view_count",
+	"likes":        "like_count",
+	"comments":     "comment_count",
+	"favorites":    "favorite_count",
+	"shares":       "share_count",
+}
+
+// ArticleStatsRepository 文章统计仓储接口（阅读参与度、每日汇总）
+type ArticleStatsRepository interface {
+	// ApplyEngagement 累加一次阅读上报到统计详情与当日汇总
+	ApplyEngagement(articleID uint64, statDate time.Time, progress float64, stayTime int, source, device, region string) error
+
+	// SnapshotDaily 日终快照：按累计值计算当日增量，并汇总作者维度与粉丝增长（可重复执行）
+	SnapshotDaily(statDate time.Time) error
+	// FindArticleDaily 查询文章日统计
+	FindArticleDaily(articleID uint64, start, end time.Time) ([]model.ArticleStatsDaily, error)
+	// FindAuthorDaily 查询作者日统计
+	FindAuthorDaily(userID string, start, end time.Time) ([]model.AuthorStatsDaily, error)
+	// FindLatestFollowerCount 查询指定日期前最近一次记录的粉丝总数
+	FindLatestFollowerCount(userID string, before time.Time) (int64, error)
+	// FindTopArticles 查询时间段内表现最好的文章
+	FindTopArticles(userID string, start, end time.Time, metric string, limit int) ([]TopArticleRow, error)
+}
+
+type articleStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewArticleStatsRepository 创建ArticleStatsRepository实例
+func NewArticleStatsRepository(db *gorm.DB) ArticleStatsRepository {
+	return &articleStatsRepository{db: db}
+}
+
+// ApplyEngagement 累加阅读参与度
+// 平均值按 read_count 增量计算；MySQL按SET顺序求值，read_count 必须最后更新
+func (r *articleStatsRepository) ApplyEngagement(articleID uint64, statDate time.Time, progress float64, stayTime int, source, device, region string) error {
+	source, device, region = jsonKey(source), jsonKey(device), jsonKey(region)
+	sourcePath, devicePath, regionPath := jsonPath(source), jsonPath(device), jsonPath(region)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 1. 统计详情（历史文章可能尚未初始化）
+		if err := tx.Exec("INSERT IGNORE INTO article_stats_detail (article_id) VALUES (?)", articleID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE article_stats_detail SET
+				avg_read_progress = (avg_read_progress * read_count + ?) / (read_count + 1),
+				avg_stay_time = ROUND((avg_stay_time * read_count + ?) / (read_count + 1)),
+				source_stats = JSON_SET(COALESCE(source_stats, JSON_OBJECT()), ?, COALESCE(JSON_EXTRACT(source_stats, ?), 0) + 1),
+				device_stats = JSON_SET(COALESCE(device_stats, JSON_OBJECT()), ?, COALESCE(JSON_EXTRACT(device_stats, ?), 0) + 1),
+				region_stats = JSON_SET(COALESCE(region_stats, JSON_OBJECT()), ?, COALESCE(JSON_EXTRACT(region_stats, ?), 0) + 1),
+				read_count = read_count + 1
+			WHERE article_id = ?`,
+			progress, stayTime,
+			sourcePath, sourcePath,
+			devicePath, devicePath,
+			regionPath, regionPath,
+			articleID,
+		).Error; err != nil {
+			return err
+		}
+
+		// 2. 当日汇总
+		return tx.Exec(`INSERT INTO article_stats_daily
+				(article_id, stat_date, read_count, progress_sum, stay_time_sum, source_stats, device_stats, region_stats)
+			VALUES (?, ?, 1, ?, ?, JSON_OBJECT(?, 1), JSON_OBJECT(?, 1), JSON_OBJECT(?, 1))
+			ON DUPLICATE KEY UPDATE
+				read_count = read_count + 1,
+				progress_sum = progress_sum + VALUES(progress_sum),
+				stay_time_sum = stay_time_sum + VALUES(stay_time_sum),
+				source_stats = JSON_SET(COALESCE(source_stats, JSON_OBJECT()), ?, COALESCE(JSON_EXTRACT(source_stats, ?), 0) + 1),
+				device_stats = JSON_SET(COALESCE(device_stats, JSON_OBJECT()), ?, COALESCE(JSON_EXTRACT(device_stats, ?), 0) + 1),
+				region_stats = JSON_SET(COALESCE(region_stats, JSON_OBJECT()), ?, COALESCE(JSON_EXTRACT(region_stats, ?), 0) + 1)`,
+			articleID, statDate.Format("2006-01-02"), progress, stayTime,
+			source, device, region,
+			sourcePath, sourcePath,
+			devicePath, devicePath,
+			regionPath, regionPath,
+		).Error
+	})
+}
+
+// SnapshotDaily 日终快照
+func (r *articleStatsRepository) SnapshotDaily(statDate time.Time) error {
+	dayStart := time.Date(statDate.Year(), statDate.Month(), statDate.Day(), 0, 0, 0, 0, statDate.Location())
+	args := map[string]interface{}{
+		"stat_date": dayStart.Format("2006-01-02"),
+		"day_start": dayStart,
+		"day_end":   dayStart.AddDate(0, 0, 1),
+	}
+
+	// 当日增量 = 今日累计 - 上一次快照累计；首次快照的存量文章以当前值为基线（增量记0），当天新发布的文章基线为0
+	delta := func(column, total string) string {
+		return fmt.Sprintf("GREATEST(CAST(a.%s AS SIGNED) - CAST(COALESCE(p.%s, IF(a.create_time >= @day_start, 0, a.%s)) AS SIGNED), 0)",
+			column, total, column)
+	}
+
+	articleSQL := `INSERT INTO article_stats_daily
+			(article_id, user_id, stat_date,
+			 view_count, unique_view_count, like_count, comment_count, favorite_count, share_count,
+			 total_views, total_unique_views, total_likes, total_comments, total_favorites, total_shares, snapshot_time)
+		SELECT a.id, a.user_id, @stat_date, ` +
+		delta("view_count", "total_views") + ", " +
+		delta("real_view_count", "total_unique_views") + ", " +
+		delta("like_count", "total_likes") + ", " +
+		delta("comment_count", "total_comments") + ", " +
+		delta("favorite_count", "total_favorites") + ", " +
+		delta("share_count", "total_shares") + `,
+			a.view_count, a.real_view_count, a.like_count, a.comment_count, a.favorite_count, a.share_count, NOW()
+		FROM article_v3 a
+		LEFT JOIN article_stats_daily p ON p.id = (
+			SELECT s.id FROM article_stats_daily s
+			WHERE s.article_id = a.id AND s.stat_date < @stat_date AND s.snapshot_time IS NOT NULL
+			ORDER BY s.stat_date DESC LIMIT 1
+		)
+		WHERE a.status = 1 AND a.delete_time IS NULL
+		ON DUPLICATE KEY UPDATE
+			user_id = VALUES(user_id),
+			view_count = VALUES(view_count),
+			unique_view_count = VALUES(unique_view_count),
+			like_count = VALUES(like_count),
+			comment_count = VALUES(comment_count),
+			favorite_count = VALUES(favorite_count),
+			share_count = VALUES(share_count),
+			total_views = VALUES(total_views),
+			total_unique_views = VALUES(total_unique_views),
+			total_likes = VALUES(total_likes),
+			total_comments = VALUES(total_comments),
+			total_favorites = VALUES(total_favorites),
+			total_shares = VALUES(total_shares),
+			snapshot_time = VALUES(snapshot_time)`
+
+	authorSQL := `INSERT INTO author_stats_daily
+			(user_id, stat_date, view_count, unique_view_count, like_count, comment_count, favorite_count, share_count, article_count)
+		SELECT user_id, stat_date, SUM(view_count), SUM(unique_view_count), SUM(like_count),
+			SUM(comment_count), SUM(favorite_count), SUM(share_count), COUNT(*)
+		FROM article_stats_daily
+		WHERE stat_date = @stat_date AND snapshot_time IS NOT NULL AND user_id IS NOT NULL
+		GROUP BY user_id, stat_date
+		ON DUPLICATE KEY UPDATE
+			view_count = VALUES(view_count),
+			unique_view_count = VALUES(unique_view_count),
+			like_count = VALUES(like_count),
+			comment_count = VALUES(comment_count),
+			favorite_count = VALUES(favorite_count),
+			share_count = VALUES(share_count),
+			article_count = VALUES(article_count)`
+
+	followerSQL := `INSERT INTO author_stats_daily (user_id, stat_date, new_followers)
+		SELECT follow_user_id, @stat_date, COUNT(*)
+		FROM user_follow
+		WHERE create_time >= @day_start AND create_time < @day_end
+		GROUP BY follow_user_id
+		ON DUPLICATE KEY UPDATE new_followers = VALUES(new_followers)`
+
+	followerTotalSQL := `UPDATE author_stats_daily d
+		INNER JOIN user u ON u.id = d.user_id
+		SET d.follower_count = u.followed_count
+		WHERE d.stat_date = @stat_date`
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, query := range []string{articleSQL, authorSQL, followerSQL, followerTotalSQL} {
+			if err := tx.Exec(query, args).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *articleStatsRepository) FindArticleDaily(articleID uint64, start, end time.Time) ([]model.ArticleStatsDaily, error) {
+	var rows []model.ArticleStatsDaily
+	err := r.db.Where("article_id = ? AND stat_date BETWEEN ? AND ?",
+		articleID, start.Format("2006-01-02"), end.Format("2006-01-02")).
+		Order("stat_date ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *articleStatsRepository) FindAuthorDaily(userID string, start, end time.Time) ([]model.AuthorStatsDaily, error) {
+	var rows []model.AuthorStatsDaily
+	err := r.db.Where("user_id = ? AND stat_date BETWEEN ? AND ?",
+		userID, start.Format("2006-01-02"), end.Format("2006-01-02")).
+		Order("stat_date ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *articleStatsRepository) FindLatestFollowerCount(userID string, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.AuthorStatsDaily{}).
+		Select("follower_count").
+		Where("user_id = ? AND stat_date < ?", userID, before.Format("2006-01-02")).
+		Order("stat_date DESC").
+		Limit(1).
+		Scan(&count).Error
+	return count, err
+}
+
+func (r *articleStatsRepository) FindTopArticles(userID string, start, end time.Time, metric string, limit int) ([]TopArticleRow, error) {
+	column, ok := topArticleMetrics[metric]
+	if !ok {
+		return nil, fmt.Errorf("不支持的排序指标: %s", metric)
+	}
+
+	var rows []TopArticleRow
+	err := r.db.Table("article_stats_daily d").
+		Select("d.article_id, a.title, SUM(d."+column+") AS value, "+
+			"SUM(d.view_count) AS views, SUM(d.unique_view_count) AS unique_views, SUM(d.like_count) AS likes, "+
+			"SUM(d.comment_count) AS comments, SUM(d.favorite_count) AS favorites, SUM(d.share_count) AS shares").
+		Joins("INNER JOIN article_v3 a ON a.id = d.article_id").
+		Where("d.user_id = ? AND d.stat_date BETWEEN ? AND ? AND a.delete_time IS NULL",
+			userID, start.Format("2006-01-02"), end.Format("2006-01-02")).
+		Group("d.article_id, a.title").
+		Order("value DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// jsonKey 清理JSON对象键（去除会破坏JSON路径的字符）
+func jsonKey(key string) string {
+	key = strings.NewReplacer(`"`, "", `\`, "").Replace(strings.TrimSpace(key))
+	if key == "" {
+		return "unknown"
+	}
+	return key
+}
+
+// jsonPath 生成JSON路径，如 $."search"
+func jsonPath(key string) string {
+	return `$."` + key + `"`
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"astronomer-gin/model"
+	"astronomer-gin/pkg/counter"
 	"gorm.io/gorm"
 	"time"
 )
@@ -17,12 +18,14 @@ type ArticleV3Repository interface {
 	UpdateFields(id uint64, fields map[string]interface{}) error
 	Delete(id uint64) error     // 硬删除
 	SoftDelete(id uint64) error // 软删除
+	// MoveToTrash 软删除并记录到作者的回收站（同一事务）
+	MoveToTrash(article *model.ArticleV3) error // 已被删除时返回gorm.ErrRecordNotFound
 	CheckOwnership(id uint64, userID string) bool
 
 	// ==================== 列表查询 ====================
 	// 查询文章列表（支持分类、专栏、状态、可见性、排序等复杂查询）
 	FindList(params *ArticleQueryParams) ([]model.ArticleV3, int64, error)
-	FindByUserID(userID uint64, page, pageSize int, status int8) ([]model.ArticleV3, int64, error)
+	FindByUserID(userID string, page, pageSize int, status int8) ([]model.ArticleV3, int64, error)
 	FindByCategoryID(categoryID uint64, page, pageSize int) ([]model.ArticleV3, int64, error)
 	FindByColumnID(columnID uint64, page, pageSize int) ([]model.ArticleV3, int64, error)
 	FindByTopicID(topicID uint64, page, pageSize int) ([]model.ArticleV3, int64, error)
@@ -37,6 +40,7 @@ type ArticleV3Repository interface {
 	// ==================== 统计字段更新 ====================
 	IncrementViewCount(id uint64) error
 	IncrementRealViewCount(id uint64) error
+	IncrementHumanViewCount(id uint64) error
 	IncrementLikeCount(id uint64) error
 	DecrementLikeCount(id uint64) error
 	IncrementCommentCount(id uint64) error
@@ -60,8 +64,20 @@ type ArticleV3Repository interface {
 	UpdateDraft(draft *model.ArticleDraft) error
 	FindDraftByID(id uint64) (*model.ArticleDraft, error)
 	FindUserDrafts(userID string, page, pageSize int) ([]model.ArticleDraft, int64, error)
-	DeleteDraft(id uint64) error
+	DeleteDraft(id uint64) error                                 // 硬删除
+	MoveDraftToTrash(draft *model.ArticleDraft) error            // 软删除并记录到回收站（已被删除时返回gorm.ErrRecordNotFound）
 	PublishDraft(draftID uint64, article *model.ArticleV3) error // 发布草稿
+	// SaveDraftVersion 仅当草稿仍为expectedVersion时写入（版本号+1），并可附带写入快照（每个草稿保留最近snapshotLimit份）
+	// 返回false表示版本已变化（其他设备先保存）
+	SaveDraftVersion(draft *model.ArticleDraft, expectedVersion int, snapshot *model.ArticleDraftSnapshot, snapshotLimit int) (bool, error)
+	FindLinkedDraft(articleID uint64) (*model.ArticleDraft, error) // 已发布文章正在编辑中的草稿
+	// RepublishDraft 将关联草稿的修改应用到已发布文章并标记草稿为已发布（同一事务）
+	RepublishDraft(draftID uint64, articleID uint64, updates map[string]interface{}, content *model.ArticleContent) error
+
+	// ==================== 草稿快照 ====================
+	FindLatestDraftSnapshot(draftID uint64) (*model.ArticleDraftSnapshot, error)
+	FindDraftSnapshots(draftID uint64) ([]model.ArticleDraftSnapshot, error) // 不含正文
+	FindDraftSnapshot(draftID, snapshotID uint64) (*model.ArticleDraftSnapshot, error)
 
 	// ==================== 版本历史 ====================
 	CreateHistory(history *model.ArticleHistory) error
@@ -198,6 +214,7 @@ func (r *articleV3Repository) FindByID(id uint64) (*model.ArticleV3, error) {
 	if err != nil {
 		return nil, err
 	}
+	mergePendingCounters(&article)
 	return &article, nil
 }
 
@@ -209,6 +226,7 @@ func (r *articleV3Repository) FindByIDWithContent(id uint64) (*model.ArticleV3,
 	if err := r.db.Where("id = ? AND delete_time IS NULL", id).First(&article).Error; err != nil {
 		return nil, nil, err
 	}
+	mergePendingCounters(&article)
 
 	// 查询内容
 	if err := r.db.Where("article_id = ?", id).First(&content).Error; err != nil {
@@ -235,11 +253,38 @@ func (r *articleV3Repository) SoftDelete(id uint64) error {
 	return r.db.Model(&model.ArticleV3{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
-			"status":      model.ArticleStatusDeleted,
+			"status":      model.ArticleV3StatusDeleted,
 			"delete_time": &now,
 		}).Error
 }
 
+func (r *articleV3Repository) MoveToTrash(article *model.ArticleV3) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 并发删除时只有一次能更新成功，其余返回ErrRecordNotFound，避免重复记录回收站和扣减计数
+		result := tx.Model(&model.ArticleV3{}).
+			Where("id = ? AND delete_time IS NULL", article.ID).
+			Updates(map[string]interface{}{
+				"status":      model.ArticleV3StatusDeleted,
+				"delete_time": &now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&model.TrashItem{
+			UserID:     article.UserID,
+			EntityType: model.TrashEntityArticle,
+			EntityID:   article.ID,
+			Title:      article.Title,
+			PrevStatus: article.Status,
+			DeleteTime: now,
+		}).Error
+	})
+}
+
 func (r *articleV3Repository) CheckOwnership(id uint64, userID string) bool {
 	var count int64
 	r.db.Model(&model.ArticleV3{}).Where("id = ? AND user_id = ?", id, userID).Count(&count)
@@ -325,10 +370,11 @@ func (r *articleV3Repository) FindList(params *ArticleQueryParams) ([]model.Arti
 		return nil, 0, err
 	}
 
+	mergePendingCounterList(articles)
 	return articles, total, nil
 }
 
-func (r *articleV3Repository) FindByUserID(userID uint64, page, pageSize int, status int8) ([]model.ArticleV3, int64, error) {
+func (r *articleV3Repository) FindByUserID(userID string, page, pageSize int, status int8) ([]model.ArticleV3, int64, error) {
 	var articles []model.ArticleV3
 	var total int64
 
@@ -346,6 +392,7 @@ func (r *articleV3Repository) FindByUserID(userID uint64, page, pageSize int, st
 		return nil, 0, err
 	}
 
+	mergePendingCounterList(articles)
 	return articles, total, nil
 }
 
@@ -365,6 +412,7 @@ func (r *articleV3Repository) FindByCategoryID(categoryID uint64, page, pageSize
 		return nil, 0, err
 	}
 
+	mergePendingCounterList(articles)
 	return articles, total, nil
 }
 
@@ -382,13 +430,22 @@ func (r *articleV3Repository) FindByColumnID(columnID uint64, page, pageSize int
 		return nil, 0, err
 	}
 
+	// 按专栏的排序方式排序
+	var sortTypes []int8
+	r.db.Model(&model.ArticleColumn{}).Where("id = ?", columnID).Limit(1).Pluck("sort_type", &sortTypes)
+	sortType := int8(model.ColumnSortCustom)
+	if len(sortTypes) > 0 {
+		sortType = sortTypes[0]
+	}
+
 	offset := (page - 1) * pageSize
-	if err := query.Order("article_column_rel.sort_order ASC").
+	if err := query.Order(columnArticleOrder(sortType)).
 		Limit(pageSize).Offset(offset).
 		Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
 
+	mergePendingCounterList(articles)
 	return articles, total, nil
 }
 
@@ -413,6 +470,7 @@ func (r *articleV3Repository) FindByTopicID(topicID uint64, page, pageSize int)
 		return nil, 0, err
 	}
 
+	mergePendingCounterList(articles)
 	return articles, total, nil
 }
 
@@ -425,6 +483,7 @@ func (r *articleV3Repository) FindByIDs(ids []uint64) ([]model.ArticleV3, error)
 	err := r.db.Where("id IN ? AND status = ? AND delete_time IS NULL", ids, model.ArticleStatusPublished).
 		Order("create_time DESC").
 		Find(&articles).Error
+	mergePendingCounterList(articles)
 	return articles, err
 }
 
@@ -437,6 +496,7 @@ func (r *articleV3Repository) FindFeaturedArticles(limit int) ([]model.ArticleV3
 		Order("publish_time DESC").
 		Limit(limit).
 		Find(&articles).Error
+	mergePendingCounterList(articles)
 	return articles, err
 }
 
@@ -447,6 +507,7 @@ func (r *articleV3Repository) FindHotArticles(limit int) ([]model.ArticleV3, err
 		Order("hot_score DESC").
 		Limit(limit).
 		Find(&articles).Error
+	mergePendingCounterList(articles)
 	return articles, err
 }
 
@@ -457,6 +518,7 @@ func (r *articleV3Repository) FindRecommendedArticles(limit int) ([]model.Articl
 		Order("quality_score DESC, hot_score DESC").
 		Limit(limit).
 		Find(&articles).Error
+	mergePendingCounterList(articles)
 	return articles, err
 }
 
@@ -478,54 +540,113 @@ func (r *articleV3Repository) FindByHotScore(page, pageSize int) ([]model.Articl
 		return nil, 0, err
 	}
 
+	mergePendingCounterList(articles)
 	return articles, total, nil
 }
 
 // ==================== 统计字段更新实现 ====================
 
 func (r *articleV3Repository) IncrementViewCount(id uint64) error {
-	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+	return r.addCounter(id, "view_count", 1)
 }
 
 func (r *articleV3Repository) IncrementRealViewCount(id uint64) error {
-	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("real_view_count", gorm.Expr("real_view_count + 1")).Error
+	return r.addCounter(id, "real_view_count", 1)
+}
+
+func (r *articleV3Repository) IncrementHumanViewCount(id uint64) error {
+	return r.addCounter(id, "human_view_count", 1)
 }
 
 func (r *articleV3Repository) IncrementLikeCount(id uint64) error {
-	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error
+	return r.addCounter(id, "like_count", 1)
 }
 
 func (r *articleV3Repository) DecrementLikeCount(id uint64) error {
-	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("like_count", gorm.Expr("GREATEST(like_count - 1, 0)")).Error
+	return r.addCounter(id, "like_count", -1)
 }
 
 func (r *articleV3Repository) IncrementCommentCount(id uint64) error {
-	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("comment_count", gorm.Expr("comment_count + 1")).Error
+	return r.addCounter(id, "comment_count", 1)
 }
 
 func (r *articleV3Repository) DecrementCommentCount(id uint64) error {
-	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("comment_count", gorm.Expr("GREATEST(comment_count - 1, 0)")).Error
+	return r.addCounter(id, "comment_count", -1)
 }
 
 func (r *articleV3Repository) IncrementShareCount(id uint64) error {
-	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("share_count", gorm.Expr("share_count + 1")).Error
+	return r.addCounter(id, "share_count", 1)
 }
 
 func (r *articleV3Repository) IncrementFavoriteCount(id uint64) error {
-	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("favorite_count", gorm.Expr("favorite_count + 1")).Error
+	return r.addCounter(id, "favorite_count", 1)
 }
 
 func (r *articleV3Repository) DecrementFavoriteCount(id uint64) error {
+	return r.addCounter(id, "favorite_count", -1)
+}
+
+// addCounter 计数增量优先写入Redis缓冲（由定时任务批量落库），Redis不可用时直接更新数据库
+func (r *articleV3Repository) addCounter(id uint64, column string, delta int64) error {
+	if err := counter.Incr(counter.EntityArticle, id, column, delta); err == nil {
+		return nil
+	}
 	return r.db.Model(&model.ArticleV3{}).Where("id = ?", id).
-		UpdateColumn("favorite_count", gorm.Expr("GREATEST(favorite_count - 1, 0)")).Error
+		UpdateColumn(column, gorm.Expr("GREATEST(CAST("+column+" AS SIGNED) + ?, 0)", delta)).Error
+}
+
+// mergePendingCounters 合并Redis中尚未落库的计数增量
+func mergePendingCounters(articles ...*model.ArticleV3) {
+	if len(articles) == 0 {
+		return
+	}
+
+	ids := make([]uint64, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	pending := counter.Pending(counter.EntityArticle, ids)
+	if len(pending) == 0 {
+		return
+	}
+
+	for _, article := range articles {
+		for column, delta := range pending[article.ID] {
+			switch column {
+			case "view_count":
+				article.ViewCount = addDelta(article.ViewCount, delta)
+			case "real_view_count":
+				article.RealViewCount = addDelta(article.RealViewCount, delta)
+			case "human_view_count":
+				article.HumanViewCount = addDelta(article.HumanViewCount, delta)
+			case "like_count":
+				article.LikeCount = addDelta(article.LikeCount, delta)
+			case "comment_count":
+				article.CommentCount = addDelta(article.CommentCount, delta)
+			case "share_count":
+				article.ShareCount = addDelta(article.ShareCount, delta)
+			case "favorite_count":
+				article.FavoriteCount = addDelta(article.FavoriteCount, delta)
+			}
+		}
+	}
+}
+
+// mergePendingCounterList 合并列表中文章的未落库计数
+func mergePendingCounterList(articles []model.ArticleV3) {
+	ptrs := make([]*model.ArticleV3, len(articles))
+	for i := range articles {
+		ptrs[i] = &articles[i]
+	}
+	mergePendingCounters(ptrs...)
+}
+
+// addDelta 无符号计数加增量（不小于0）
+func addDelta(value uint64, delta int64) uint64 {
+	if delta < 0 && uint64(-delta) > value {
+		return 0
+	}
+	return uint64(int64(value) + delta)
 }
 
 // ==================== 热度计算实现 ====================
@@ -583,7 +704,7 @@ func (r *articleV3Repository) UpdateDraft(draft *model.ArticleDraft) error {
 
 func (r *articleV3Repository) FindDraftByID(id uint64) (*model.ArticleDraft, error) {
 	var draft model.ArticleDraft
-	err := r.db.Where("id = ?", id).First(&draft).Error
+	err := r.db.Where("id = ? AND delete_time IS NULL", id).First(&draft).Error
 	if err != nil {
 		return nil, err
 	}
@@ -594,7 +715,7 @@ func (r *articleV3Repository) FindUserDrafts(userID string, page, pageSize int)
 	var drafts []model.ArticleDraft
 	var total int64
 
-	query := r.db.Model(&model.ArticleDraft{}).Where("user_id = ? AND is_published = ?", userID, false)
+	query := r.db.Model(&model.ArticleDraft{}).Where("user_id = ? AND is_published = ? AND delete_time IS NULL", userID, false)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -612,6 +733,28 @@ func (r *articleV3Repository) DeleteDraft(id uint64) error {
 	return r.db.Delete(&model.ArticleDraft{}, id).Error
 }
 
+func (r *articleV3Repository) MoveDraftToTrash(draft *model.ArticleDraft) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.ArticleDraft{}).
+			Where("id = ? AND delete_time IS NULL", draft.ID).
+			Update("delete_time", &now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&model.TrashItem{
+			UserID:     draft.UserID,
+			EntityType: model.TrashEntityDraft,
+			EntityID:   draft.ID,
+			Title:      draft.Title,
+			DeleteTime: now,
+		}).Error
+	})
+}
+
 func (r *articleV3Repository) PublishDraft(draftID uint64, article *model.ArticleV3) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// 1. 创建文章
@@ -632,6 +775,120 @@ func (r *articleV3Repository) PublishDraft(draftID uint64, article *model.Articl
 	})
 }
 
+func (r *articleV3Repository) SaveDraftVersion(draft *model.ArticleDraft, expectedVersion int, snapshot *model.ArticleDraftSnapshot, snapshotLimit int) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.ArticleDraft{}).
+			Where("id = ? AND version = ? AND delete_time IS NULL", draft.ID, expectedVersion).
+			Updates(map[string]interface{}{
+				"title":           draft.Title,
+				"summary":         draft.Summary,
+				"cover_image":     draft.CoverImage,
+				"content":         draft.Content,
+				"category_id":     draft.CategoryID,
+				"column_id":       draft.ColumnID,
+				"tags":            draft.Tags,
+				"topics":          draft.Topics,
+				"last_edit_time":  draft.LastEditTime,
+				"last_device_id":  draft.LastDeviceID,
+				"version":         gorm.Expr("version + 1"),
+				"auto_save_count": gorm.Expr("auto_save_count + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		saved = true
+
+		if snapshot == nil {
+			return nil
+		}
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+
+		// 只保留最近snapshotLimit份快照
+		var boundary []uint64
+		if err := tx.Model(&model.ArticleDraftSnapshot{}).
+			Where("draft_id = ?", draft.ID).
+			Order("id DESC").
+			Offset(snapshotLimit-1).
+			Limit(1).
+			Pluck("id", &boundary).Error; err != nil {
+			return err
+		}
+		if len(boundary) == 0 {
+			return nil
+		}
+		return tx.Where("draft_id = ? AND id < ?", draft.ID, boundary[0]).
+			Delete(&model.ArticleDraftSnapshot{}).Error
+	})
+	if err != nil || !saved {
+		return false, err
+	}
+
+	draft.Version = expectedVersion + 1
+	draft.AutoSaveCount++
+	return true, nil
+}
+
+func (r *articleV3Repository) FindLinkedDraft(articleID uint64) (*model.ArticleDraft, error) {
+	var draft model.ArticleDraft
+	err := r.db.Where("article_id = ? AND is_published = ? AND delete_time IS NULL", articleID, false).
+		Order("update_time DESC").
+		First(&draft).Error
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (r *articleV3Repository) RepublishDraft(draftID uint64, articleID uint64, updates map[string]interface{}, content *model.ArticleContent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 1. 更新文章（状态不变，线上版本全程可见）
+		if err := tx.Model(&model.ArticleV3{}).Where("id = ?", articleID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// 2. 更新内容
+		if err := tx.Save(content).Error; err != nil {
+			return err
+		}
+
+		// 3. 标记草稿为已发布
+		return tx.Model(&model.ArticleDraft{}).Where("id = ?", draftID).
+			Update("is_published", true).Error
+	})
+}
+
+// ==================== 草稿快照实现 ====================
+
+func (r *articleV3Repository) FindLatestDraftSnapshot(draftID uint64) (*model.ArticleDraftSnapshot, error) {
+	var snapshot model.ArticleDraftSnapshot
+	err := r.db.Omit("content").Where("draft_id = ?", draftID).Order("id DESC").First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *articleV3Repository) FindDraftSnapshots(draftID uint64) ([]model.ArticleDraftSnapshot, error) {
+	var snapshots []model.ArticleDraftSnapshot
+	err := r.db.Omit("content").Where("draft_id = ?", draftID).Order("id DESC").Find(&snapshots).Error
+	return snapshots, err
+}
+
+func (r *articleV3Repository) FindDraftSnapshot(draftID, snapshotID uint64) (*model.ArticleDraftSnapshot, error) {
+	var snapshot model.ArticleDraftSnapshot
+	err := r.db.Where("id = ? AND draft_id = ?", snapshotID, draftID).First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
 // ==================== 版本历史实现 ====================
 
 func (r *articleV3Repository) CreateHistory(history *model.ArticleHistory) error {
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogQuery 审计日志查询条件（空值表示不限）
+type AuditLogQuery struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	IP         string
+	Result     int8
+	Start      *time.Time
+	End        *time.Time
+	Archived   bool // 查询归档表
+}
+
+// AuditRepository 审计日志数据访问接口（只追加，不提供修改和删除）
+type AuditRepository interface {
+	Create(log *model.AuditLog) error
+	Find(query *AuditLogQuery, page, pageSize int) ([]model.AuditLog, int64, error)
+	// FindByTargetUser 与用户账号相关的指定操作（最近在前）
+	FindByTargetUser(userID string, actions []string, since time.Time, page, pageSize int) ([]model.AuditLog, int64, error)
+	// ArchiveBefore 将早于before的日志移入归档表，返回移动的条数
+	ArchiveBefore(before time.Time, limit int) (int, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository 创建AuditRepository实例
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create 写入审计日志
+func (r *auditRepository) Create(log *model.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+// Find 按条件查询审计日志
+func (r *auditRepository) Find(query *AuditLogQuery, page, pageSize int) ([]model.AuditLog, int64, error) {
+	db := r.db.Model(&model.AuditLog{})
+	if query.Archived {
+		db = r.db.Table(model.AuditLogArchiveTable)
+	}
+	if query.ActorID != "" {
+		db = db.Where("actor_id = ?", query.ActorID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.TargetType != "" {
+		db = db.Where("target_type = ?", query.TargetType)
+	}
+	if query.TargetID != "" {
+		db = db.Where("target_id = ?", query.TargetID)
+	}
+	if query.RequestID != "" {
+		db = db.Where("request_id = ?", query.RequestID)
+	}
+	if query.IP != "" {
+		db = db.Where("ip = ?", query.IP)
+	}
+	if query.Result > 0 {
+		db = db.Where("result = ?", query.Result)
+	}
+	if query.Start != nil {
+		db = db.Where("create_time >= ?", *query.Start)
+	}
+	if query.End != nil {
+		db = db.Where("create_time < ?", *query.End)
+	}
+
+	var logs []model.AuditLog
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := db.Order("id DESC").Limit(pageSize).Offset(offset).Find(&logs).Error
+	return logs, total, err
+}
+
+// FindByTargetUser 查询用户账号相关的安全活动
+func (r *auditRepository) FindByTargetUser(userID string, actions []string, since time.Time, page, pageSize int) ([]model.AuditLog, int64, error) {
+	var logs []model.AuditLog
+	var total int64
+	query := r.db.Model(&model.AuditLog{}).
+		Where("target_type = ? AND target_id = ? AND action IN ? AND create_time >= ?", model.AuditTargetUser, userID, actions, since)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&logs).Error
+	return logs, total, err
+}
+
+// ArchiveBefore 在同一事务中复制到归档表并从主表删除
+func (r *auditRepository) ArchiveBefore(before time.Time, limit int) (int, error) {
+	var ids []uint64
+	if err := r.db.Model(&model.AuditLog{}).
+		Where("create_time < ?", before).
+		Order("id ASC").Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("INSERT IGNORE INTO "+model.AuditLogArchiveTable+" SELECT * FROM audit_log WHERE id IN ?", ids).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&model.AuditLog{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
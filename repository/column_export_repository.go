@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// ColumnRevisionRow 计算专栏内容版本所需的最小数据
+type ColumnRevisionRow struct {
+	ArticleID         uint64
+	SortOrder         int
+	ArticleUpdateTime time.Time
+	ContentUpdateTime *time.Time
+}
+
+// ColumnExportRepository 专栏导出仓储接口
+type ColumnExportRepository interface {
+	Create(export *model.ColumnExport) error
+	FindByID(id uint64) (*model.ColumnExport, error)
+	FindByColumnAndFormat(columnID uint64, format string) (*model.ColumnExport, error)
+	UpdateFields(id uint64, fields map[string]interface{}) error
+	FindRevisionRows(columnID uint64) ([]ColumnRevisionRow, error)
+}
+
+type columnExportRepository struct {
+	db *gorm.DB
+}
+
+// NewColumnExportRepository 创建ColumnExportRepository实例
+func NewColumnExportRepository(db *gorm.DB) ColumnExportRepository {
+	return &columnExportRepository{db: db}
+}
+
+func (r *columnExportRepository) Create(export *model.ColumnExport) error {
+	return r.db.Create(export).Error
+}
+
+func (r *columnExportRepository) FindByID(id uint64) (*model.ColumnExport, error) {
+	var export model.ColumnExport
+	if err := r.db.First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *columnExportRepository) FindByColumnAndFormat(columnID uint64, format string) (*model.ColumnExport, error) {
+	var export model.ColumnExport
+	if err := r.db.Where("column_id = ? AND format = ?", columnID, format).First(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *columnExportRepository) UpdateFields(id uint64, fields map[string]interface{}) error {
+	return r.db.Model(&model.ColumnExport{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// FindRevisionRows 查询专栏中已发布文章的顺序与更新时间（内容表单独更新时也能感知）
+func (r *columnExportRepository) FindRevisionRows(columnID uint64) ([]ColumnRevisionRow, error) {
+	var rows []ColumnRevisionRow
+	err := r.db.Table("article_column_rel").
+		Select("article_column_rel.article_id, article_column_rel.sort_order, "+
+			"article_v3.update_time AS article_update_time, article_content.update_time AS content_update_time").
+		Joins("INNER JOIN article_v3 ON article_v3.id = article_column_rel.article_id").
+		Joins("LEFT JOIN article_content ON article_content.article_id = article_column_rel.article_id").
+		Where("article_column_rel.column_id = ? AND article_v3.status = ?", columnID, 1).
+		Order("article_column_rel.sort_order ASC, article_column_rel.add_time ASC").
+		Scan(&rows).Error
+	return rows, err
+}
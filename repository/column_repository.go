@@ -3,8 +3,10 @@ package repository
 import (
 	"astronomer-gin/model"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ColumnRepository 专栏仓储接口
@@ -65,6 +67,21 @@ type ColumnRepository interface {
 
 	// UpdateArticlePosition 更新文章在专栏中的位置
 	UpdateArticlePosition(columnID, articleID uint64, sortOrder int) error
+
+	// GetArticleIDs 按专栏排序方式获取已发布文章ID（章节导航、阅读进度）
+	GetArticleIDs(columnID uint64) ([]uint64, error)
+
+	// GetSubscriberBatch 按订阅ID游标分批获取订阅记录（通知扇出）
+	GetSubscriberBatch(columnID, afterID uint64, limit int) ([]model.ColumnSubscription, error)
+
+	// RecordRead 记录用户读过专栏中的文章
+	RecordRead(userID string, columnID, articleID uint64) error
+
+	// GetReadRecords 获取用户在专栏中的阅读记录（最近阅读在前）
+	GetReadRecords(userID string, columnID uint64) ([]model.ColumnReadRecord, error)
+
+	// GetReadColumnIDs 获取用户读过的专栏ID（最近阅读在前，分页）
+	GetReadColumnIDs(userID string, page, pageSize int) ([]uint64, int64, error)
 }
 
 type columnRepository struct {
@@ -415,11 +432,11 @@ func (r *columnRepository) GetColumnArticles(columnID uint64, page, pageSize int
 		return nil, 0, fmt.Errorf("统计专栏文章总数失败: %w", err)
 	}
 
-	// 关联查询文章，按专栏中的顺序排序
+	// 关联查询文章，按专栏的排序方式排序
 	if err := r.db.Table("article_v3").
 		Joins("INNER JOIN article_column_rel ON article_v3.id = article_column_rel.article_id").
 		Where("article_column_rel.column_id = ? AND article_v3.status = ?", columnID, 1).
-		Order("article_column_rel.sort_order ASC, article_column_rel.add_time ASC").
+		Order(columnArticleOrder(r.getSortType(columnID))).
 		Offset(offset).
 		Limit(pageSize).
 		Find(&articles).Error; err != nil {
@@ -458,3 +475,101 @@ func (r *columnRepository) UpdateArticlePosition(columnID, articleID uint64, sor
 
 	return nil
 }
+
+// GetArticleIDs 按专栏排序方式获取已发布文章ID
+func (r *columnRepository) GetArticleIDs(columnID uint64) ([]uint64, error) {
+	var ids []uint64
+	if err := r.db.Table("article_v3").
+		Joins("INNER JOIN article_column_rel ON article_v3.id = article_column_rel.article_id").
+		Where("article_column_rel.column_id = ? AND article_v3.status = ? AND article_v3.delete_time IS NULL",
+			columnID, model.ArticleV3StatusPublished).
+		Order(columnArticleOrder(r.getSortType(columnID))).
+		Pluck("article_v3.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("查询专栏文章失败: %w", err)
+	}
+	return ids, nil
+}
+
+// GetSubscriberBatch 按订阅ID游标分批获取订阅记录
+func (r *columnRepository) GetSubscriberBatch(columnID, afterID uint64, limit int) ([]model.ColumnSubscription, error) {
+	var subscriptions []model.ColumnSubscription
+	if err := r.db.Where("column_id = ? AND id > ?", columnID, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&subscriptions).Error; err != nil {
+		return nil, fmt.Errorf("查询订阅者失败: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// RecordRead 记录用户读过专栏中的文章（已读过则刷新阅读时间）
+func (r *columnRepository) RecordRead(userID string, columnID, articleID uint64) error {
+	record := &model.ColumnReadRecord{
+		UserID:    userID,
+		ColumnID:  columnID,
+		ArticleID: articleID,
+		ReadTime:  time.Now(),
+	}
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"read_time"}),
+	}).Create(record).Error
+}
+
+// GetReadRecords 获取用户在专栏中的阅读记录
+func (r *columnRepository) GetReadRecords(userID string, columnID uint64) ([]model.ColumnReadRecord, error) {
+	var records []model.ColumnReadRecord
+	if err := r.db.Where("user_id = ? AND column_id = ?", userID, columnID).
+		Order("read_time DESC").
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("查询阅读记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// GetReadColumnIDs 获取用户读过的专栏ID（按最近阅读时间倒序）
+func (r *columnRepository) GetReadColumnIDs(userID string, page, pageSize int) ([]uint64, int64, error) {
+	var total int64
+	if err := r.db.Model(&model.ColumnReadRecord{}).
+		Where("user_id = ?", userID).
+		Distinct("column_id").
+		Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计阅读专栏数失败: %w", err)
+	}
+
+	var ids []uint64
+	offset := (page - 1) * pageSize
+	if err := r.db.Model(&model.ColumnReadRecord{}).
+		Where("user_id = ?", userID).
+		Group("column_id").
+		Order("MAX(read_time) DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Pluck("column_id", &ids).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询阅读专栏失败: %w", err)
+	}
+	return ids, total, nil
+}
+
+// getSortType 查询专栏排序方式（专栏不存在时按自定义顺序）
+func (r *columnRepository) getSortType(columnID uint64) int8 {
+	var sortTypes []int8
+	if err := r.db.Model(&model.ArticleColumn{}).
+		Where("id = ?", columnID).
+		Limit(1).
+		Pluck("sort_type", &sortTypes).Error; err != nil || len(sortTypes) == 0 {
+		return model.ColumnSortCustom
+	}
+	return sortTypes[0]
+}
+
+// columnArticleOrder 专栏文章排序子句（需关联 article_column_rel）
+func columnArticleOrder(sortType int8) string {
+	switch sortType {
+	case model.ColumnSortTimeAsc:
+		return "article_v3.publish_time ASC, article_v3.id ASC"
+	case model.ColumnSortTimeDesc:
+		return "article_v3.publish_time DESC, article_v3.id DESC"
+	default:
+		return "article_column_rel.sort_order ASC, article_column_rel.add_time ASC, article_v3.id ASC"
+	}
+}
@@ -15,6 +15,8 @@ type CommentV3Repository interface {
 	UpdateFields(id uint64, fields map[string]interface{}) error
 	Delete(id uint64) error     // 硬删除
 	SoftDelete(id uint64) error // 软删除
+	// MoveToTrash 软删除并记录到评论者的回收站（同一事务）
+	MoveToTrash(comment *model.CommentV3) error // 已被删除时返回gorm.ErrRecordNotFound
 	CheckOwnership(id uint64, userID string) bool
 
 	// ==================== 评论查询 ====================
@@ -185,6 +187,42 @@ func (r *commentV3Repository) SoftDelete(id uint64) error {
 		}).Error
 }
 
+func (r *commentV3Repository) MoveToTrash(comment *model.CommentV3) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 并发删除时只有一次能更新成功，其余返回ErrRecordNotFound
+		result := tx.Model(&model.CommentV3{}).
+			Where("id = ? AND delete_time IS NULL", comment.ID).
+			Updates(map[string]interface{}{
+				"status":      model.CommentStatusDeleted,
+				"delete_time": &now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&model.TrashItem{
+			UserID:     comment.UserID,
+			EntityType: model.TrashEntityComment,
+			EntityID:   comment.ID,
+			Title:      trashCommentSnippet(comment.Content),
+			PrevStatus: comment.Status,
+			DeleteTime: now,
+		}).Error
+	})
+}
+
+// trashCommentSnippet 截取评论摘要作为回收站标题
+func trashCommentSnippet(content string) string {
+	runes := []rune(content)
+	if len(runes) > 50 {
+		return string(runes[:50]) + "..."
+	}
+	return content
+}
+
 func (r *commentV3Repository) CheckOwnership(id uint64, userID string) bool {
 	var count int64
 	r.db.Model(&model.CommentV3{}).Where("id = ? AND user_id = ?", id, userID).Count(&count)
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// ContributorRepository 协作者仓储接口
+type ContributorRepository interface {
+	Create(contributor *model.Contributor) error
+	FindByID(id uint64) (*model.Contributor, error)
+	// Find 查询某用户在对象上的协作记录（含待接受的邀请）
+	Find(targetType string, targetID uint64, userID string) (*model.Contributor, error)
+	// FindByTarget 对象的全部协作者（含待接受的邀请）
+	FindByTarget(targetType string, targetID uint64) ([]model.Contributor, error)
+	// FindAccepted 对象已接受邀请的协作者
+	FindAccepted(targetType string, targetID uint64) ([]model.Contributor, error)
+	// FindPendingByUser 用户收到的待处理邀请
+	FindPendingByUser(userID string) ([]model.Contributor, error)
+	UpdateRole(id uint64, role string) error
+	Accept(id uint64) error
+	Delete(id uint64) error
+	// DeleteByTarget 删除对象的全部协作记录（对象彻底删除时调用）
+	DeleteByTarget(targetType string, targetID uint64) error
+	// HasRole 用户是否以指定角色之一参与对象（仅已接受的邀请）
+	HasRole(targetType string, targetID uint64, userID string, roles ...string) bool
+}
+
+type contributorRepository struct {
+	db *gorm.DB
+}
+
+// NewContributorRepository 创建ContributorRepository实例
+func NewContributorRepository(db *gorm.DB) ContributorRepository {
+	return &contributorRepository{db: db}
+}
+
+func (r *contributorRepository) Create(contributor *model.Contributor) error {
+	return r.db.Create(contributor).Error
+}
+
+func (r *contributorRepository) FindByID(id uint64) (*model.Contributor, error) {
+	var contributor model.Contributor
+	if err := r.db.Where("id = ?", id).First(&contributor).Error; err != nil {
+		return nil, err
+	}
+	return &contributor, nil
+}
+
+func (r *contributorRepository) Find(targetType string, targetID uint64, userID string) (*model.Contributor, error) {
+	var contributor model.Contributor
+	err := r.db.Where("target_type = ? AND target_id = ? AND user_id = ?", targetType, targetID, userID).
+		First(&contributor).Error
+	if err != nil {
+		return nil, err
+	}
+	return &contributor, nil
+}
+
+func (r *contributorRepository) FindByTarget(targetType string, targetID uint64) ([]model.Contributor, error) {
+	var contributors []model.Contributor
+	err := r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("create_time ASC").
+		Find(&contributors).Error
+	return contributors, err
+}
+
+func (r *contributorRepository) FindAccepted(targetType string, targetID uint64) ([]model.Contributor, error) {
+	var contributors []model.Contributor
+	err := r.db.Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, model.ContributorStatusAccepted).
+		Order("accept_time ASC").
+		Find(&contributors).Error
+	return contributors, err
+}
+
+func (r *contributorRepository) FindPendingByUser(userID string) ([]model.Contributor, error) {
+	var contributors []model.Contributor
+	err := r.db.Where("user_id = ? AND status = ?", userID, model.ContributorStatusPending).
+		Order("create_time DESC").
+		Find(&contributors).Error
+	return contributors, err
+}
+
+func (r *contributorRepository) UpdateRole(id uint64, role string) error {
+	return r.db.Model(&model.Contributor{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (r *contributorRepository) Accept(id uint64) error {
+	now := time.Now()
+	return r.db.Model(&model.Contributor{}).
+		Where("id = ? AND status = ?", id, model.ContributorStatusPending).
+		Updates(map[string]interface{}{
+			"status":      model.ContributorStatusAccepted,
+			"accept_time": &now,
+		}).Error
+}
+
+func (r *contributorRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.Contributor{}, id).Error
+}
+
+func (r *contributorRepository) DeleteByTarget(targetType string, targetID uint64) error {
+	return r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Delete(&model.Contributor{}).Error
+}
+
+func (r *contributorRepository) HasRole(targetType string, targetID uint64, userID string, roles ...string) bool {
+	if userID == "" {
+		return false
+	}
+	var count int64
+	r.db.Model(&model.Contributor{}).
+		Where("target_type = ? AND target_id = ? AND user_id = ? AND status = ? AND role IN ?",
+			targetType, targetID, userID, model.ContributorStatusAccepted, roles).
+		Count(&count)
+	return count > 0
+}
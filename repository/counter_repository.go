@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// CounterDrift 计数偏差样本
+type CounterDrift struct {
+	ID     string `json:"id"`
+	Stored int64  `json:"stored"` // 冗余字段中的值
+	Actual int64  `json:"actual"` // 按源表重新统计的值
+}
+
+// CounterDriftReport 单项计数核对结果
+type CounterDriftReport struct {
+	Check   string         `json:"check"`   // 核对项，如 article_v3.like_count
+	Drifted int64          `json:"drifted"` // 偏差行数（已修正）
+	Samples []CounterDrift `json:"samples"` // 偏差样本
+}
+
+// counterCheck 计数核对项：冗余字段 table.column 应等于 source 按 ref_id 分组的 cnt
+type counterCheck struct {
+	table  string
+	column string
+	source string
+	buffer bool // 该字段经过Redis缓冲，核对时跳过仍有未落库增量的行
+}
+
+// counterChecks 需要核对的冗余计数
+var counterChecks = []counterCheck{
+	{
+		table:  "article_v3",
+		column: "like_count",
+		source: "SELECT article_id AS ref_id, COUNT(*) AS cnt FROM article_star GROUP BY article_id",
+		buffer: true,
+	},
+	{
+		table:  "article_v3",
+		column: "favorite_count",
+		source: "SELECT article_id AS ref_id, COUNT(*) AS cnt FROM user_favorite GROUP BY article_id",
+		buffer: true,
+	},
+	{
+		table:  "article_v3",
+		column: "comment_count",
+		source: "SELECT target_id AS ref_id, COUNT(*) AS cnt FROM comment_v3 WHERE target_type = 1 AND delete_time IS NULL GROUP BY target_id",
+		buffer: true,
+	},
+	{
+		table:  "article_category",
+		column: "article_count",
+		source: "SELECT category_id AS ref_id, COUNT(*) AS cnt FROM article_v3 WHERE category_id > 0 AND delete_time IS NULL GROUP BY category_id",
+	},
+	{
+		table:  "topic",
+		column: "article_count",
+		source: `SELECT r.topic_id AS ref_id, COUNT(*) AS cnt FROM article_topic_rel r
+			INNER JOIN article_v3 a ON a.id = r.article_id AND a.delete_time IS NULL GROUP BY r.topic_id`,
+	},
+	{
+		table:  "user",
+		column: "followed_count",
+		source: "SELECT follow_user_id AS ref_id, COUNT(*) AS cnt FROM user_follow GROUP BY follow_user_id",
+	},
+}
+
+// counterDriftSampleLimit 每项核对最多返回的偏差样本数
+const counterDriftSampleLimit = 20
+
+// CounterRepository 冗余计数仓储接口（缓冲落库与对账）
+type CounterRepository interface {
+	// ApplyArticleDeltas 将缓冲的计数增量批量写入文章表
+	ApplyArticleDeltas(articleID uint64, deltas map[string]int64) error
+	// ReconcileCounters 按源表重新统计冗余计数，修正偏差并返回核对结果
+	// skipArticleIDs 为仍有未落库增量的文章，跳过以免与缓冲重复计算
+	ReconcileCounters(skipArticleIDs []uint64) ([]CounterDriftReport, error)
+}
+
+type counterRepository struct {
+	db *gorm.DB
+}
+
+// NewCounterRepository 创建CounterRepository实例
+func NewCounterRepository(db *gorm.DB) CounterRepository {
+	return &counterRepository{db: db}
+}
+
+// articleBufferedColumns 允许经缓冲落库的文章计数列
+var articleBufferedColumns = map[string]bool{
+	"view_count":       true,
+	"real_view_count":  true,
+	"human_view_count": true,
+	"like_count":       true,
+	"comment_count":    true,
+	"share_count":      true,
+	"favorite_count":   true,
+}
+
+func (r *counterRepository) ApplyArticleDeltas(articleID uint64, deltas map[string]int64) error {
+	updates := make(map[string]interface{}, len(deltas))
+	for column, delta := range deltas {
+		if !articleBufferedColumns[column] || delta == 0 {
+			continue
+		}
+		updates[column] = gorm.Expr("GREATEST(CAST("+column+" AS SIGNED) + ?, 0)", delta)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return r.db.Table("article_v3").Where("id = ?", articleID).UpdateColumns(updates).Error
+}
+
+func (r *counterRepository) ReconcileCounters(skipArticleIDs []uint64) ([]CounterDriftReport, error) {
+	reports := make([]CounterDriftReport, 0, len(counterChecks))
+
+	for _, check := range counterChecks {
+		// 偏差条件：冗余值与源表统计值不一致
+		where := fmt.Sprintf("t.%s <> COALESCE(x.cnt, 0)", check.column)
+		args := []interface{}{}
+		if check.buffer && len(skipArticleIDs) > 0 {
+			where += " AND t.id NOT IN ?"
+			args = append(args, skipArticleIDs)
+		}
+		join := fmt.Sprintf("`%s` t LEFT JOIN (%s) x ON x.ref_id = t.id", check.table, check.source)
+
+		report := CounterDriftReport{Check: check.table + "." + check.column}
+
+		// 1. 抽样偏差行
+		var samples []CounterDrift
+		sampleSQL := fmt.Sprintf("SELECT CAST(t.id AS CHAR) AS id, t.%s AS stored, COALESCE(x.cnt, 0) AS actual FROM %s WHERE %s LIMIT %d",
+			check.column, join, where, counterDriftSampleLimit)
+		if err := r.db.Raw(sampleSQL, args...).Scan(&samples).Error; err != nil {
+			return reports, fmt.Errorf("核对%s失败: %w", report.Check, err)
+		}
+		if len(samples) == 0 {
+			report.Samples = []CounterDrift{}
+			reports = append(reports, report)
+			continue
+		}
+		report.Samples = samples
+
+		// 2. 修正
+		fixSQL := fmt.Sprintf("UPDATE %s SET t.%s = COALESCE(x.cnt, 0) WHERE %s", join, check.column, where)
+		result := r.db.Exec(fixSQL, args...)
+		if result.Error != nil {
+			return reports, fmt.Errorf("修正%s失败: %w", report.Check, result.Error)
+		}
+		report.Drifted = result.RowsAffected
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// String 核对结果摘要
+func (r CounterDriftReport) String() string {
+	if r.Drifted == 0 {
+		return fmt.Sprintf("%s: 无偏差", r.Check)
+	}
+
+	parts := make([]string, 0, len(r.Samples))
+	for _, sample := range r.Samples {
+		parts = append(parts, fmt.Sprintf("%s(%d→%d)", sample.ID, sample.Stored, sample.Actual))
+	}
+	return fmt.Sprintf("%s: 修正%d行, 样本: %s", r.Check, r.Drifted, strings.Join(parts, ", "))
+}
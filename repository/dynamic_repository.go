@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"astronomer-gin/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DynamicRepository 动态仓储接口
+type DynamicRepository interface {
+	// 基础CRUD
+	Create(dynamic *model.Dynamic) error
+	FindByID(id uint64) (*model.Dynamic, error)
+	FindByIDs(ids []uint64) ([]model.Dynamic, error)
+	SoftDelete(id uint64) error
+	CheckOwnership(id uint64, userID string) bool
+
+	// 列表查询
+	FindByUserID(userID string, page, pageSize int) ([]model.Dynamic, int64, error)
+	FindByUserIDs(userIDs []string, page, pageSize int) ([]model.Dynamic, int64, error)
+	FindByTopicID(topicID uint64, page, pageSize int) ([]model.Dynamic, int64, error)
+	FindReposts(repostType int8, repostID uint64, page, pageSize int) ([]model.Dynamic, int64, error)
+
+	// 话题关联
+	AddTopic(dynamicID, topicID uint64) error
+
+	// 统计字段更新
+	IncrementCommentCount(id uint64) error
+	DecrementCommentCount(id uint64) error
+	IncrementRepostCount(id uint64) error
+	DecrementRepostCount(id uint64) error
+
+	// 个人主页时间线（动态与文章混排）
+	FindTimeline(userID string, page, pageSize int) ([]TimelineEntry, int64, error)
+}
+
+// TimelineEntry 时间线条目（仅包含类型、ID与时间，由服务层回填详情）
+type TimelineEntry struct {
+	ItemType string    `gorm:"column:item_type"`
+	ItemID   uint64    `gorm:"column:item_id"`
+	ItemTime time.Time `gorm:"column:item_time"`
+}
+
+type dynamicRepository struct {
+	db *gorm.DB
+}
+
+// NewDynamicRepository 创建DynamicRepository实例
+func NewDynamicRepository(db *gorm.DB) DynamicRepository {
+	return &dynamicRepository{db: db}
+}
+
+// ==================== 基础CRUD实现 ====================
+
+func (r *dynamicRepository) Create(dynamic *model.Dynamic) error {
+	return r.db.Create(dynamic).Error
+}
+
+func (r *dynamicRepository) FindByID(id uint64) (*model.Dynamic, error) {
+	var dynamic model.Dynamic
+	err := r.db.Where("id = ? AND status = ?", id, model.DynamicStatusNormal).First(&dynamic).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dynamic, nil
+}
+
+func (r *dynamicRepository) FindByIDs(ids []uint64) ([]model.Dynamic, error) {
+	var dynamics []model.Dynamic
+	if len(ids) == 0 {
+		return dynamics, nil
+	}
+
+	err := r.db.Where("id IN ? AND status = ?", ids, model.DynamicStatusNormal).
+		Find(&dynamics).Error
+	return dynamics, err
+}
+
+func (r *dynamicRepository) SoftDelete(id uint64) error {
+	now := time.Now()
+	return r.db.Model(&model.Dynamic{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      model.DynamicStatusDeleted,
+			"delete_time": &now,
+		}).Error
+}
+
+func (r *dynamicRepository) CheckOwnership(id uint64, userID string) bool {
+	var count int64
+	r.db.Model(&model.Dynamic{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Count(&count)
+	return count > 0
+}
+
+// ==================== 列表查询实现 ====================
+
+func (r *dynamicRepository) FindByUserID(userID string, page, pageSize int) ([]model.Dynamic, int64, error) {
+	return r.paginate(r.db.Model(&model.Dynamic{}).
+		Where("user_id = ? AND status = ?", userID, model.DynamicStatusNormal), page, pageSize)
+}
+
+func (r *dynamicRepository) FindByUserIDs(userIDs []string, page, pageSize int) ([]model.Dynamic, int64, error) {
+	if len(userIDs) == 0 {
+		return []model.Dynamic{}, 0, nil
+	}
+	return r.paginate(r.db.Model(&model.Dynamic{}).
+		Where("user_id IN ? AND status = ?", userIDs, model.DynamicStatusNormal), page, pageSize)
+}
+
+func (r *dynamicRepository) FindByTopicID(topicID uint64, page, pageSize int) ([]model.Dynamic, int64, error) {
+	return r.paginate(r.db.Model(&model.Dynamic{}).
+		Joins("JOIN dynamic_topic_rel ON dynamic_topic_rel.dynamic_id = dynamic.id").
+		Where("dynamic_topic_rel.topic_id = ? AND dynamic.status = ?", topicID, model.DynamicStatusNormal), page, pageSize)
+}
+
+func (r *dynamicRepository) FindReposts(repostType int8, repostID uint64, page, pageSize int) ([]model.Dynamic, int64, error) {
+	return r.paginate(r.db.Model(&model.Dynamic{}).
+		Where("repost_type = ? AND repost_id = ? AND status = ?", repostType, repostID, model.DynamicStatusNormal), page, pageSize)
+}
+
+// paginate 按发布时间倒序分页
+func (r *dynamicRepository) paginate(query *gorm.DB, page, pageSize int) ([]model.Dynamic, int64, error) {
+	var dynamics []model.Dynamic
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("dynamic.create_time DESC").
+		Limit(pageSize).Offset(offset).
+		Find(&dynamics).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return dynamics, total, nil
+}
+
+// ==================== 话题关联实现 ====================
+
+func (r *dynamicRepository) AddTopic(dynamicID, topicID uint64) error {
+	rel := &model.DynamicTopicRel{
+		DynamicID: dynamicID,
+		TopicID:   topicID,
+	}
+	return r.db.Create(rel).Error
+}
+
+// ==================== 统计字段更新实现 ====================
+
+func (r *dynamicRepository) IncrementCommentCount(id uint64) error {
+	return r.db.Model(&model.Dynamic{}).Where("id = ?", id).
+		UpdateColumn("comment_count", gorm.Expr("comment_count + 1")).Error
+}
+
+func (r *dynamicRepository) DecrementCommentCount(id uint64) error {
+	return r.db.Model(&model.Dynamic{}).Where("id = ?", id).
+		UpdateColumn("comment_count", gorm.Expr("GREATEST(comment_count - 1, 0)")).Error
+}
+
+func (r *dynamicRepository) IncrementRepostCount(id uint64) error {
+	return r.db.Model(&model.Dynamic{}).Where("id = ?", id).
+		UpdateColumn("repost_count", gorm.Expr("repost_count + 1")).Error
+}
+
+func (r *dynamicRepository) DecrementRepostCount(id uint64) error {
+	return r.db.Model(&model.Dynamic{}).Where("id = ?", id).
+		UpdateColumn("repost_count", gorm.Expr("GREATEST(repost_count - 1, 0)")).Error
+}
+
+// ==================== 时间线实现 ====================
+
+// FindTimeline 查询用户主页时间线（动态 + 已发布的公开文章，按时间倒序）
+func (r *dynamicRepository) FindTimeline(userID string, page, pageSize int) ([]TimelineEntry, int64, error) {
+	var entries []TimelineEntry
+	var dynamicCount, articleCount int64
+
+	// 1. 统计总数
+	if err := r.db.Model(&model.Dynamic{}).
+		Where("user_id = ? AND status = ?", userID, model.DynamicStatusNormal).
+		Count(&dynamicCount).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.db.Model(&model.ArticleV3{}).
+		Where("user_id = ? AND status = ? AND visibility = ? AND delete_time IS NULL",
+			userID, model.ArticleV3StatusPublished, model.ArticleVisibilityPublic).
+		Count(&articleCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 2. UNION ALL 混排分页
+	offset := (page - 1) * pageSize
+	err := r.db.Raw(`
+		SELECT item_type, item_id, item_time FROM (
+			SELECT ? AS item_type, id AS item_id, create_time AS item_time
+			FROM dynamic WHERE user_id = ? AND status = ?
+			UNION ALL
+			SELECT ? AS item_type, id AS item_id, COALESCE(publish_time, create_time) AS item_time
+			FROM article_v3 WHERE user_id = ? AND status = ? AND visibility = ? AND delete_time IS NULL
+		) t
+		ORDER BY item_time DESC
+		LIMIT ? OFFSET ?`,
+		model.TimelineItemDynamic, userID, model.DynamicStatusNormal,
+		model.TimelineItemArticle, userID, model.ArticleV3StatusPublished, model.ArticleVisibilityPublic,
+		pageSize, offset,
+	).Scan(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, dynamicCount + articleCount, nil
+}
@@ -0,0 +1,401 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LegacyRowConversion 点赞/收藏记录的文章ID转换（Delete为true表示用户已对新文章点赞/收藏，删除旧记录即可）
+type LegacyRowConversion struct {
+	RowID        uint64
+	NewArticleID uint64
+	Delete       bool
+}
+
+// LegacyMigrationRepository 旧版数据迁移仓储接口
+type LegacyMigrationRepository interface {
+	// ==================== 进度与映射 ====================
+	// GetProgress 获取阶段进度（不存在时初始化，MaxID取源表当前最大ID作为快照）
+	GetProgress(stage string) (*model.LegacyMigrationProgress, error)
+	FindAllProgress() ([]model.LegacyMigrationProgress, error)
+	SaveProgress(progress *model.LegacyMigrationProgress) error
+	FindNewID(entity string, oldID uint64) (uint64, error)
+	// FindNewIDs 批量查询映射（返回 oldID -> newID）
+	FindNewIDs(entity string, oldIDs []uint64) (map[uint64]uint64, error)
+
+	// ==================== 文章 ====================
+	FindLegacyArticles(afterID, maxID uint64, limit int) ([]model.Article, error)
+	// SaveArticle 事务写入文章、内容、初始历史版本与映射
+	SaveArticle(article *model.ArticleV3, content *model.ArticleContent, oldID uint64) error
+	// SaveDraft 事务写入草稿与映射
+	SaveDraft(draft *model.ArticleDraft, oldID uint64) error
+	FindUserIcons(userIDs []string) (map[string]string, error)
+	// FindArticleBriefs 批量查询V3文章的作者与创建时间（含已删除）
+	FindArticleBriefs(ids []uint64) (map[uint64]model.ArticleV3, error)
+
+	// ==================== 评论 ====================
+	FindLegacyCommentParents(afterID, maxID uint64, limit int) ([]model.CommentParent, error)
+	FindLegacyCommentReplies(afterID, maxID uint64, limit int) ([]model.CommentSubTwo, error)
+	FindCommentByID(id uint64) (*model.CommentV3, error)
+	NextFloorNumber(articleID uint64) (int, error)
+	NextSubFloorNumber(rootID uint64) (int, error)
+	// FindLatestReplyByUser 查找楼内某用户最近的一条回复（还原二级评论的回复对象）
+	FindLatestReplyByUser(rootID uint64, userID string) (*model.CommentV3, error)
+	// SaveComment 事务写入评论与映射，并累加父评论回复数与根评论总回复数
+	SaveComment(comment *model.CommentV3, entity string, oldID uint64) error
+
+	// ==================== 点赞与收藏 ====================
+	FindStars(afterID, maxID uint64, limit int) ([]model.ArticleStar, error)
+	FindFavorites(afterID, maxID uint64, limit int) ([]model.UserFavorite, error)
+	// FindPreexistingArticles 返回ID与旧文章ID重叠、且并非迁移生成的V3文章（ID -> 创建时间）
+	FindPreexistingArticles(ids []uint64) (map[uint64]time.Time, error)
+	HasStar(userID string, articleID uint64) (bool, error)
+	HasFavorite(userID string, articleID uint64) (bool, error)
+	// ConvertStars / ConvertFavorites 批量转换并在同一事务内保存进度，保证每条记录只转换一次
+	ConvertStars(conversions []LegacyRowConversion, progress *model.LegacyMigrationProgress) error
+	ConvertFavorites(conversions []LegacyRowConversion, progress *model.LegacyMigrationProgress) error
+
+	// ==================== 计数 ====================
+	// RecountMigratedArticles 按源表重算迁移文章的点赞、收藏与评论数
+	RecountMigratedArticles() (int64, error)
+}
+
+type legacyMigrationRepository struct {
+	db *gorm.DB
+}
+
+// NewLegacyMigrationRepository 创建LegacyMigrationRepository实例
+func NewLegacyMigrationRepository(db *gorm.DB) LegacyMigrationRepository {
+	return &legacyMigrationRepository{db: db}
+}
+
+// legacyStageTables 各阶段的源表（用于MaxID快照）
+var legacyStageTables = map[string]string{
+	model.LegacyStageArticles:  "article",
+	model.LegacyStageComments:  "comment_parent",
+	model.LegacyStageReplies:   "comment_sub_two",
+	model.LegacyStageStars:     "article_star",
+	model.LegacyStageFavorites: "user_favorite",
+}
+
+// ==================== 进度与映射实现 ====================
+
+func (r *legacyMigrationRepository) GetProgress(stage string) (*model.LegacyMigrationProgress, error) {
+	var progress model.LegacyMigrationProgress
+	err := r.db.Where("stage = ?", stage).First(&progress).Error
+	if err == nil {
+		return &progress, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	progress = model.LegacyMigrationProgress{Stage: stage}
+	if table, ok := legacyStageTables[stage]; ok {
+		if err := r.db.Table(table).Select("COALESCE(MAX(id), 0)").Scan(&progress.MaxID).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&progress).Error; err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (r *legacyMigrationRepository) FindAllProgress() ([]model.LegacyMigrationProgress, error) {
+	var list []model.LegacyMigrationProgress
+	err := r.db.Find(&list).Error
+	return list, err
+}
+
+func (r *legacyMigrationRepository) SaveProgress(progress *model.LegacyMigrationProgress) error {
+	return r.db.Save(progress).Error
+}
+
+func (r *legacyMigrationRepository) FindNewID(entity string, oldID uint64) (uint64, error) {
+	var m model.LegacyIDMap
+	if err := r.db.Where("entity = ? AND old_id = ?", entity, oldID).First(&m).Error; err != nil {
+		return 0, err
+	}
+	return m.NewID, nil
+}
+
+func (r *legacyMigrationRepository) FindNewIDs(entity string, oldIDs []uint64) (map[uint64]uint64, error) {
+	result := make(map[uint64]uint64, len(oldIDs))
+	if len(oldIDs) == 0 {
+		return result, nil
+	}
+	var rows []model.LegacyIDMap
+	if err := r.db.Where("entity = ? AND old_id IN ?", entity, oldIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.OldID] = row.NewID
+	}
+	return result, nil
+}
+
+// ==================== 文章实现 ====================
+
+func (r *legacyMigrationRepository) FindLegacyArticles(afterID, maxID uint64, limit int) ([]model.Article, error) {
+	var articles []model.Article
+	err := r.db.Where("id > ? AND id <= ?", afterID, maxID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+func (r *legacyMigrationRepository) SaveArticle(article *model.ArticleV3, content *model.ArticleContent, oldID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(article).Error; err != nil {
+			return err
+		}
+		// allow_comment 带默认值，零值会被忽略，需单独写入
+		if !article.AllowComment {
+			if err := tx.Model(article).UpdateColumn("allow_comment", false).Error; err != nil {
+				return err
+			}
+		}
+		content.ArticleID = article.ID
+		if err := tx.Create(content).Error; err != nil {
+			return err
+		}
+		history := &model.ArticleHistory{
+			ArticleID:    article.ID,
+			Version:      1,
+			Title:        article.Title,
+			Content:      content.Content,
+			Summary:      article.Summary,
+			ChangeType:   model.ChangeTypeCreate,
+			ChangeReason: "旧版数据迁移",
+			OperatorID:   article.UserID,
+		}
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.LegacyIDMap{Entity: model.LegacyEntityArticle, OldID: oldID, NewID: article.ID}).Error
+	})
+}
+
+func (r *legacyMigrationRepository) SaveDraft(draft *model.ArticleDraft, oldID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(draft).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.LegacyIDMap{Entity: model.LegacyEntityDraft, OldID: oldID, NewID: draft.ID}).Error
+	})
+}
+
+func (r *legacyMigrationRepository) FindUserIcons(userIDs []string) (map[string]string, error) {
+	icons := make(map[string]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return icons, nil
+	}
+	var users []model.User
+	if err := r.db.Select("id, icon").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		icons[u.ID] = u.Icon
+	}
+	return icons, nil
+}
+
+func (r *legacyMigrationRepository) FindArticleBriefs(ids []uint64) (map[uint64]model.ArticleV3, error) {
+	result := make(map[uint64]model.ArticleV3, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var articles []model.ArticleV3
+	if err := r.db.Select("id, user_id, create_time").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range articles {
+		result[a.ID] = a
+	}
+	return result, nil
+}
+
+// ==================== 评论实现 ====================
+
+func (r *legacyMigrationRepository) FindLegacyCommentParents(afterID, maxID uint64, limit int) ([]model.CommentParent, error) {
+	var comments []model.CommentParent
+	err := r.db.Where("id > ? AND id <= ?", afterID, maxID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *legacyMigrationRepository) FindLegacyCommentReplies(afterID, maxID uint64, limit int) ([]model.CommentSubTwo, error) {
+	var comments []model.CommentSubTwo
+	err := r.db.Where("id > ? AND id <= ?", afterID, maxID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *legacyMigrationRepository) FindCommentByID(id uint64) (*model.CommentV3, error) {
+	var comment model.CommentV3
+	if err := r.db.First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *legacyMigrationRepository) NextFloorNumber(articleID uint64) (int, error) {
+	var maxFloor int
+	err := r.db.Model(&model.CommentV3{}).
+		Select("COALESCE(MAX(floor_number), 0)").
+		Where("target_type = ? AND target_id = ? AND parent_id = 0", model.CommentTargetTypeArticle, articleID).
+		Scan(&maxFloor).Error
+	return maxFloor + 1, err
+}
+
+func (r *legacyMigrationRepository) NextSubFloorNumber(rootID uint64) (int, error) {
+	var maxSubFloor int
+	err := r.db.Model(&model.CommentV3{}).
+		Select("COALESCE(MAX(sub_floor_number), 0)").
+		Where("root_id = ?", rootID).
+		Scan(&maxSubFloor).Error
+	return maxSubFloor + 1, err
+}
+
+func (r *legacyMigrationRepository) FindLatestReplyByUser(rootID uint64, userID string) (*model.CommentV3, error) {
+	var comment model.CommentV3
+	err := r.db.Where("root_id = ? AND user_id = ?", rootID, userID).
+		Order("id DESC").
+		First(&comment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *legacyMigrationRepository) SaveComment(comment *model.CommentV3, entity string, oldID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&model.LegacyIDMap{Entity: entity, OldID: oldID, NewID: comment.ID}).Error; err != nil {
+			return err
+		}
+		// 根评论的root_id指向自己
+		if comment.ParentID == 0 {
+			comment.RootID = comment.ID
+			return tx.Model(comment).UpdateColumn("root_id", comment.ID).Error
+		}
+		if err := tx.Model(&model.CommentV3{}).Where("id = ?", comment.ParentID).
+			UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.CommentV3{}).Where("id = ?", comment.RootID).
+			UpdateColumn("total_reply_count", gorm.Expr("total_reply_count + 1")).Error
+	})
+}
+
+// ==================== 点赞与收藏实现 ====================
+
+func (r *legacyMigrationRepository) FindStars(afterID, maxID uint64, limit int) ([]model.ArticleStar, error) {
+	var stars []model.ArticleStar
+	err := r.db.Where("id > ? AND id <= ?", afterID, maxID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&stars).Error
+	return stars, err
+}
+
+func (r *legacyMigrationRepository) FindFavorites(afterID, maxID uint64, limit int) ([]model.UserFavorite, error) {
+	var favorites []model.UserFavorite
+	err := r.db.Where("id > ? AND id <= ?", afterID, maxID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&favorites).Error
+	return favorites, err
+}
+
+func (r *legacyMigrationRepository) FindPreexistingArticles(ids []uint64) (map[uint64]time.Time, error) {
+	result := make(map[uint64]time.Time)
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		ID         uint64
+		CreateTime time.Time
+	}
+	err := r.db.Table("article_v3 a").
+		Select("a.id, a.create_time").
+		Where("a.id IN ?", ids).
+		Where("NOT EXISTS (SELECT 1 FROM legacy_id_map m WHERE m.entity = ? AND m.new_id = a.id)", model.LegacyEntityArticle).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.ID] = row.CreateTime
+	}
+	return result, nil
+}
+
+func (r *legacyMigrationRepository) HasStar(userID string, articleID uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.ArticleStar{}).
+		Where("user_id = ? AND article_id = ?", userID, articleID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *legacyMigrationRepository) HasFavorite(userID string, articleID uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.UserFavorite{}).
+		Where("user_id = ? AND article_id = ?", userID, articleID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *legacyMigrationRepository) ConvertStars(conversions []LegacyRowConversion, progress *model.LegacyMigrationProgress) error {
+	return r.convertRows(&model.ArticleStar{}, conversions, progress)
+}
+
+func (r *legacyMigrationRepository) ConvertFavorites(conversions []LegacyRowConversion, progress *model.LegacyMigrationProgress) error {
+	return r.convertRows(&model.UserFavorite{}, conversions, progress)
+}
+
+// convertRows 改写记录的文章ID（或删除重复记录），并在同一事务内推进检查点
+func (r *legacyMigrationRepository) convertRows(table interface{}, conversions []LegacyRowConversion, progress *model.LegacyMigrationProgress) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, c := range conversions {
+			var err error
+			if c.Delete {
+				err = tx.Where("id = ?", c.RowID).Delete(table).Error
+			} else {
+				err = tx.Model(table).Where("id = ?", c.RowID).UpdateColumn("article_id", c.NewArticleID).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Save(progress).Error
+	})
+}
+
+// ==================== 计数实现 ====================
+
+func (r *legacyMigrationRepository) RecountMigratedArticles() (int64, error) {
+	result := r.db.Exec(`
+		UPDATE article_v3 a
+		INNER JOIN legacy_id_map m ON m.entity = ? AND m.new_id = a.id
+		SET a.like_count = (SELECT COUNT(*) FROM article_star s WHERE s.article_id = a.id),
+			a.favorite_count = (SELECT COUNT(*) FROM user_favorite f WHERE f.article_id = a.id),
+			a.comment_count = (SELECT COUNT(*) FROM comment_v3 c
+				WHERE c.target_type = ? AND c.target_id = a.id AND c.delete_time IS NULL)
+	`, model.LegacyEntityArticle, model.CommentTargetTypeArticle)
+	return result.RowsAffected, result.Error
+}
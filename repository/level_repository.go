@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LevelRepository 用户经验、流水与徽章数据访问接口
+type LevelRepository interface {
+	// AddXP 写入经验流水并累加经验；同一BizKey已记录过时返回false
+	AddXP(log *model.UserXPLog) (bool, error)
+	// CountRewardedSince 某时间之后获得过经验的事件次数（用于每日上限）
+	CountRewardedSince(userID, event string, since time.Time) (int64, error)
+	// CountEvents 事件累计次数（包括超出上限未得经验的）
+	CountEvents(userID, event string) (int64, error)
+	// GetXP 用户累计经验，没有记录时为0
+	GetXP(userID string) (int64, error)
+	// CountAbove 经验高于指定值的用户数（用于排名）
+	CountAbove(xp int64) (int64, error)
+	FindTopXP(limit int) ([]model.UserXP, error)
+	FindLogs(userID string, page, pageSize int) ([]model.UserXPLog, int64, error)
+
+	FindBadges(userID string) ([]model.UserBadge, error)
+	// AwardBadge 授予徽章；已拥有时返回false
+	AwardBadge(userID, badge string) (bool, error)
+}
+
+type levelRepository struct {
+	db *gorm.DB
+}
+
+// NewLevelRepository 创建LevelRepository实例
+func NewLevelRepository(db *gorm.DB) LevelRepository {
+	return &levelRepository{db: db}
+}
+
+// AddXP 在同一事务中写入流水并累加经验
+func (r *levelRepository) AddXP(log *model.UserXPLog) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(log)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		if log.XP == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"xp": gorm.Expr("xp + ?", log.XP)}),
+		}).Create(&model.UserXP{UserID: log.UserID, XP: int64(log.XP)}).Error
+	})
+	return created, err
+}
+
+// CountRewardedSince 统计获得过经验的事件次数
+func (r *levelRepository) CountRewardedSince(userID, event string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserXPLog{}).
+		Where("user_id = ? AND event = ? AND create_time >= ? AND xp > 0", userID, event, since).
+		Count(&count).Error
+	return count, err
+}
+
+// CountEvents 统计事件累计次数
+func (r *levelRepository) CountEvents(userID, event string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserXPLog{}).Where("user_id = ? AND event = ?", userID, event).Count(&count).Error
+	return count, err
+}
+
+// GetXP 查询累计经验
+func (r *levelRepository) GetXP(userID string) (int64, error) {
+	var userXP model.UserXP
+	err := r.db.Where("user_id = ?", userID).First(&userXP).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return userXP.XP, err
+}
+
+// CountAbove 统计经验更高的用户数
+func (r *levelRepository) CountAbove(xp int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserXP{}).Where("xp > ?", xp).Count(&count).Error
+	return count, err
+}
+
+// FindTopXP 经验排行
+func (r *levelRepository) FindTopXP(limit int) ([]model.UserXP, error) {
+	var list []model.UserXP
+	err := r.db.Where("xp > 0").Order("xp DESC, update_time ASC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// FindLogs 经验流水（最近在前）
+func (r *levelRepository) FindLogs(userID string, page, pageSize int) ([]model.UserXPLog, int64, error) {
+	var logs []model.UserXPLog
+	var total int64
+	query := r.db.Model(&model.UserXPLog{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&logs).Error
+	return logs, total, err
+}
+
+// FindBadges 用户的徽章（按获得时间）
+func (r *levelRepository) FindBadges(userID string) ([]model.UserBadge, error) {
+	var badges []model.UserBadge
+	err := r.db.Where("user_id = ?", userID).Order("award_time ASC").Find(&badges).Error
+	return badges, err
+}
+
+// AwardBadge 授予徽章
+func (r *levelRepository) AwardBadge(userID, badge string) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.UserBadge{UserID: userID, Badge: badge})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// NameHistoryRepository 用户名和个性ID修改记录数据访问接口
+type NameHistoryRepository interface {
+	Create(history *model.UserNameHistory) error
+	// FindLatest 用户最近一次某类修改
+	FindLatest(userID string, kind int8) (*model.UserNameHistory, error)
+	// FindActiveRedirect 仍在跳转期内的旧个性ID（最近一条）
+	FindActiveRedirect(handle string, now time.Time) (*model.UserNameHistory, error)
+	// FindByUser 用户的全部修改记录（最近在前）
+	FindByUser(userID string, limit int) ([]model.UserNameHistory, error)
+}
+
+type nameHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewNameHistoryRepository 创建NameHistoryRepository实例
+func NewNameHistoryRepository(db *gorm.DB) NameHistoryRepository {
+	return &nameHistoryRepository{db: db}
+}
+
+// Create 写入修改记录
+func (r *nameHistoryRepository) Create(history *model.UserNameHistory) error {
+	return r.db.Create(history).Error
+}
+
+// FindLatest 最近一次修改
+func (r *nameHistoryRepository) FindLatest(userID string, kind int8) (*model.UserNameHistory, error) {
+	var history model.UserNameHistory
+	if err := r.db.Where("user_id = ? AND kind = ?", userID, kind).Order("id DESC").First(&history).Error; err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// FindActiveRedirect 查询跳转期内的旧个性ID
+func (r *nameHistoryRepository) FindActiveRedirect(handle string, now time.Time) (*model.UserNameHistory, error) {
+	var history model.UserNameHistory
+	err := r.db.Where("old_value = ? AND kind = ? AND redirect_until > ?", handle, model.NameHistoryKindHandle, now).
+		Order("id DESC").First(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// FindByUser 用户的修改记录
+func (r *nameHistoryRepository) FindByUser(userID string, limit int) ([]model.UserNameHistory, error) {
+	var histories []model.UserNameHistory
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&histories).Error
+	return histories, err
+}
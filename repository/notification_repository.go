@@ -7,6 +7,7 @@ import (
 
 type NotificationRepository interface {
 	Create(notification *model.Notification) error
+	CreateBatch(notifications []*model.Notification) error
 	FindByID(id uint64) (*model.Notification, error)
 	GetList(userID string, page, pageSize int) ([]model.Notification, int64, error)
 	GetUnreadCount(userID string) (int64, error)
@@ -29,6 +30,14 @@ func (r *notificationRepository) Create(notification *model.Notification) error
 	return r.db.Create(notification).Error
 }
 
+// CreateBatch 批量创建通知（订阅更新等扇出场景）
+func (r *notificationRepository) CreateBatch(notifications []*model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(notifications, 200).Error
+}
+
 // FindByID 根据ID查找通知
 func (r *notificationRepository) FindByID(id uint64) (*model.Notification, error) {
 	var notification model.Notification
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PrivacyRepository 用户隐私设置数据访问接口
+type PrivacyRepository interface {
+	// FindByUserID 查询隐私设置，没有记录时返回 gorm.ErrRecordNotFound
+	FindByUserID(userID string) (*model.UserPrivacy, error)
+	// Save 保存隐私设置（不存在时创建）
+	Save(privacy *model.UserPrivacy) error
+	// FindHiddenFromTrending 在给定用户中筛选关闭了热门榜单展示的用户
+	FindHiddenFromTrending(userIDs []string) ([]string, error)
+}
+
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository 创建PrivacyRepository实例
+func NewPrivacyRepository(db *gorm.DB) PrivacyRepository {
+	return &privacyRepository{db: db}
+}
+
+// FindByUserID 查询隐私设置
+func (r *privacyRepository) FindByUserID(userID string) (*model.UserPrivacy, error) {
+	var privacy model.UserPrivacy
+	if err := r.db.Where("user_id = ?", userID).First(&privacy).Error; err != nil {
+		return nil, err
+	}
+	return &privacy, nil
+}
+
+// Save 保存隐私设置
+func (r *privacyRepository) Save(privacy *model.UserPrivacy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"followers_visibility", "following_visibility", "message_permission",
+			"comment_permission", "birthday_visibility", "searchable", "show_in_trending", "update_time",
+		}),
+	}).Create(privacy).Error
+}
+
+// FindHiddenFromTrending 筛选不在热门榜单展示的用户
+func (r *privacyRepository) FindHiddenFromTrending(userIDs []string) ([]string, error) {
+	var hidden []string
+	if len(userIDs) == 0 {
+		return hidden, nil
+	}
+	err := r.db.Model(&model.UserPrivacy{}).
+		Where("user_id IN ? AND show_in_trending = ?", userIDs, false).
+		Pluck("user_id", &hidden).Error
+	return hidden, err
+}
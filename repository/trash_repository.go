@@ -0,0 +1,389 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// TrashRepository 回收站仓储接口（恢复与彻底清除）
+type TrashRepository interface {
+	// ==================== 回收站条目 ====================
+	FindByID(id uint64) (*model.TrashItem, error)
+	// FindByUser 查询用户回收站（entityType为空表示全部类型）
+	FindByUser(userID, entityType string, page, pageSize int) ([]model.TrashItem, int64, error)
+	// FindExpired 查询删除时间早于before的条目（按删除时间升序）
+	FindExpired(before time.Time, limit int) ([]model.TrashItem, error)
+
+	// ==================== 已删除实体（不过滤删除状态） ====================
+	FindArticle(id uint64) (*model.ArticleV3, error)
+	FindDraft(id uint64) (*model.ArticleDraft, error)
+	FindComment(id uint64) (*model.CommentV3, error)
+	// IsCommentTargetAlive 评论所属对象是否未被删除
+	IsCommentTargetAlive(targetType int8, targetID uint64) bool
+	// FindArticleCommentImages 文章下所有评论的图片（清除文章前收集待删除的媒体）
+	FindArticleCommentImages(articleID uint64) ([]string, error)
+
+	// ==================== 恢复（还原删除前状态并移除回收站条目） ====================
+	RestoreArticle(item *model.TrashItem) error
+	RestoreDraft(item *model.TrashItem) error
+	RestoreComment(item *model.TrashItem) error
+
+	// ==================== 彻底清除 ====================
+	// PurgeArticle 删除文章及其内容、历史、关联、统计、互动和评论
+	PurgeArticle(articleID uint64) error
+	PurgeDraft(draftID uint64) error
+	// PurgeComment 删除评论及其互动记录；仍有回复时仅清空内容保留楼层结构
+	PurgeComment(commentID uint64) error
+	// CountMediaReferences 统计仍引用该媒体URL的内容数（含回收站中可恢复的内容，为0时才可删除MinIO对象）
+	CountMediaReferences(url string) (int64, error)
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+// NewTrashRepository 创建TrashRepository实例
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+// ==================== 回收站条目实现 ====================
+
+func (r *trashRepository) FindByID(id uint64) (*model.TrashItem, error) {
+	var item model.TrashItem
+	if err := r.db.Where("id = ?", id).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *trashRepository) FindByUser(userID, entityType string, page, pageSize int) ([]model.TrashItem, int64, error) {
+	var items []model.TrashItem
+	var total int64
+
+	query := r.db.Model(&model.TrashItem{}).Where("user_id = ?", userID)
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("delete_time DESC").Limit(pageSize).Offset(offset).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+func (r *trashRepository) FindExpired(before time.Time, limit int) ([]model.TrashItem, error) {
+	var items []model.TrashItem
+	err := r.db.Where("delete_time < ?", before).
+		Order("delete_time ASC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+// ==================== 已删除实体实现 ====================
+
+func (r *trashRepository) FindArticle(id uint64) (*model.ArticleV3, error) {
+	var article model.ArticleV3
+	if err := r.db.Where("id = ?", id).First(&article).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+func (r *trashRepository) FindDraft(id uint64) (*model.ArticleDraft, error) {
+	var draft model.ArticleDraft
+	if err := r.db.Where("id = ?", id).First(&draft).Error; err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (r *trashRepository) FindComment(id uint64) (*model.CommentV3, error) {
+	var comment model.CommentV3
+	if err := r.db.Where("id = ?", id).First(&comment).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *trashRepository) IsCommentTargetAlive(targetType int8, targetID uint64) bool {
+	var count int64
+	switch targetType {
+	case model.CommentTargetTypeArticle:
+		r.db.Model(&model.ArticleV3{}).Where("id = ? AND delete_time IS NULL", targetID).Count(&count)
+	case model.CommentTargetTypeDynamic:
+		r.db.Model(&model.Dynamic{}).Where("id = ? AND delete_time IS NULL", targetID).Count(&count)
+	default:
+		return true
+	}
+	return count > 0
+}
+
+func (r *trashRepository) FindArticleCommentImages(articleID uint64) ([]string, error) {
+	var lists []model.JSONStringList
+	err := r.db.Model(&model.CommentV3{}).
+		Where("target_type = ? AND target_id = ? AND images IS NOT NULL AND images != ''",
+			model.CommentTargetTypeArticle, articleID).
+		Pluck("images", &lists).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for _, list := range lists {
+		images = append(images, list...)
+	}
+	return images, nil
+}
+
+// ==================== 恢复实现 ====================
+
+func (r *trashRepository) RestoreArticle(item *model.TrashItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.ArticleV3{}).
+			Where("id = ? AND delete_time IS NOT NULL", item.EntityID).
+			Updates(map[string]interface{}{
+				"status":      item.PrevStatus,
+				"delete_time": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Delete(&model.TrashItem{}, item.ID).Error
+	})
+}
+
+func (r *trashRepository) RestoreDraft(item *model.TrashItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.ArticleDraft{}).
+			Where("id = ? AND delete_time IS NOT NULL", item.EntityID).
+			Update("delete_time", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Delete(&model.TrashItem{}, item.ID).Error
+	})
+}
+
+func (r *trashRepository) RestoreComment(item *model.TrashItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.CommentV3{}).
+			Where("id = ? AND delete_time IS NOT NULL", item.EntityID).
+			Updates(map[string]interface{}{
+				"status":      item.PrevStatus,
+				"delete_time": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Delete(&model.TrashItem{}, item.ID).Error
+	})
+}
+
+// ==================== 彻底清除实现 ====================
+
+func (r *trashRepository) PurgeArticle(articleID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 1. 文章评论及其互动记录
+		var commentIDs []uint64
+		if err := tx.Model(&model.CommentV3{}).
+			Where("target_type = ? AND target_id = ?", model.CommentTargetTypeArticle, articleID).
+			Pluck("id", &commentIDs).Error; err != nil {
+			return err
+		}
+		if len(commentIDs) > 0 {
+			if err := purgeCommentRelations(tx, commentIDs); err != nil {
+				return err
+			}
+			if err := tx.Where("entity_type = ? AND entity_id IN ?", model.TrashEntityComment, commentIDs).
+				Delete(&model.TrashItem{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("entity IN ? AND new_id IN ?", legacyCommentEntities, commentIDs).
+				Delete(&model.LegacyIDMap{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", commentIDs).Delete(&model.CommentV3{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, m := range []interface{}{&model.CommentFloorBuilding{}, &model.CommentStats{}, &model.CommentHotList{}} {
+			if err := tx.Where("target_type = ? AND target_id = ?", model.CommentTargetTypeArticle, articleID).
+				Delete(m).Error; err != nil {
+				return err
+			}
+		}
+
+		// 2. 按 article_id 关联的数据
+		for _, m := range []interface{}{
+			&model.ArticleContent{},
+			&model.ArticleHistory{},
+			&model.ArticleColumnRel{},
+			&model.ArticleTopicRel{},
+			&model.ArticleStatsDetail{},
+			&model.ArticleStatsDaily{},
+			&model.ArticleStar{},
+			&model.UserFavorite{},
+			&model.ArticleFingerprint{},
+			&model.ArticleOriginalityClaim{},
+			&model.ArticleQualityScore{},
+		} {
+			if err := tx.Where("article_id = ?", articleID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+
+		// 3. 双向关联
+		if err := tx.Where("article_id = ? OR related_article_id = ?", articleID, articleID).
+			Delete(&model.ArticleRelation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id = ? OR source_article_id = ?", articleID, articleID).
+			Delete(&model.ArticleDuplicate{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity = ? AND new_id = ?", model.LegacyEntityArticle, articleID).
+			Delete(&model.LegacyIDMap{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("target_type = ? AND target_id = ?", model.ContributorTargetArticle, articleID).
+			Delete(&model.Contributor{}).Error; err != nil {
+			return err
+		}
+
+		// 4. 草稿：已发布的草稿随文章删除，编辑中的草稿解除关联（作为新文章草稿保留）
+		var draftIDs []uint64
+		if err := tx.Model(&model.ArticleDraft{}).Where("article_id = ? AND is_published = ?", articleID, true).
+			Pluck("id", &draftIDs).Error; err != nil {
+			return err
+		}
+		if len(draftIDs) > 0 {
+			if err := tx.Where("draft_id IN ?", draftIDs).Delete(&model.ArticleDraftSnapshot{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", draftIDs).Delete(&model.ArticleDraft{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&model.ArticleDraft{}).Where("article_id = ?", articleID).
+			Update("article_id", 0).Error; err != nil {
+			return err
+		}
+
+		// 5. 文章本身与回收站条目
+		if err := tx.Where("entity_type = ? AND entity_id = ?", model.TrashEntityArticle, articleID).
+			Delete(&model.TrashItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.ArticleV3{}, articleID).Error
+	})
+}
+
+func (r *trashRepository) PurgeDraft(draftID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("entity_type = ? AND entity_id = ?", model.TrashEntityDraft, draftID).
+			Delete(&model.TrashItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity = ? AND new_id = ?", model.LegacyEntityDraft, draftID).
+			Delete(&model.LegacyIDMap{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("draft_id = ?", draftID).Delete(&model.ArticleDraftSnapshot{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.ArticleDraft{}, draftID).Error
+	})
+}
+
+func (r *trashRepository) PurgeComment(commentID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := purgeCommentRelations(tx, []uint64{commentID}); err != nil {
+			return err
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ?", model.TrashEntityComment, commentID).
+			Delete(&model.TrashItem{}).Error; err != nil {
+			return err
+		}
+
+		// 仍有回复时保留占位行，避免回复链断裂
+		var replies int64
+		if err := tx.Model(&model.CommentV3{}).Where("parent_id = ? OR root_id = ?", commentID, commentID).
+			Where("id != ?", commentID).Count(&replies).Error; err != nil {
+			return err
+		}
+		if replies > 0 {
+			return tx.Model(&model.CommentV3{}).Where("id = ?", commentID).
+				Updates(map[string]interface{}{
+					"content":     "",
+					"images":      model.JSONStringList{},
+					"at_user_ids": model.JSONUint64List{},
+					"like_count":  0,
+				}).Error
+		}
+		if err := tx.Where("entity IN ? AND new_id = ?", legacyCommentEntities, commentID).
+			Delete(&model.LegacyIDMap{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.CommentV3{}, commentID).Error
+	})
+}
+
+// legacyCommentEntities 评论相关的旧版ID映射类型
+var legacyCommentEntities = []string{model.LegacyEntityCommentRoot, model.LegacyEntityCommentReply}
+
+// purgeCommentRelations 删除评论的点赞/举报/热评/追评记录
+func purgeCommentRelations(tx *gorm.DB, commentIDs []uint64) error {
+	for _, m := range []interface{}{
+		&model.CommentInteraction{},
+		&model.CommentReport{},
+		&model.CommentHotList{},
+		&model.CommentAuthorReply{},
+	} {
+		if err := tx.Where("comment_id IN ?", commentIDs).Delete(m).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *trashRepository) CountMediaReferences(url string) (int64, error) {
+	pattern := "%" + url + "%"
+	var total int64
+
+	queries := []*gorm.DB{
+		r.db.Model(&model.ArticleV3{}).Where("cover_image = ?", url),
+		r.db.Model(&model.ArticleContent{}).Where("content LIKE ?", pattern),
+		r.db.Model(&model.ArticleDraft{}).Where("cover_image = ? OR content LIKE ?", url, pattern),
+		r.db.Model(&model.ArticleDraftSnapshot{}).Where("cover_image = ? OR content LIKE ?", url, pattern),
+		r.db.Model(&model.CommentV3{}).Where("images LIKE ?", pattern),
+		r.db.Model(&model.Dynamic{}).Where("images LIKE ?", pattern),
+	}
+	for _, query := range queries {
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
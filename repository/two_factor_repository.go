@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"time"
+
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TwoFactorRepository 两步验证数据访问接口
+type TwoFactorRepository interface {
+	FindByUserID(userID string) (*model.UserTwoFactor, error)
+	IsEnabled(userID string) bool
+	// SavePending 保存待确认的密钥（覆盖之前未开启的密钥）
+	SavePending(userID, secret string) error
+	// Enable 开启两步验证并写入恢复码
+	Enable(userID string, step int64, codeHashes []string) error
+	// UseStep 记录通过验证的时间步，时间步不大于上次时返回false（验证码已用过）
+	UseStep(userID string, step int64) (bool, error)
+	// Delete 关闭两步验证，同时删除恢复码
+	Delete(userID string) error
+
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	// UseRecoveryCode 使用恢复码，不存在或已使用时返回false
+	UseRecoveryCode(userID, codeHash string) (bool, error)
+	CountRecoveryCodes(userID string) (int64, error)
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository 创建TwoFactorRepository实例
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// FindByUserID 查询用户的两步验证设置
+func (r *twoFactorRepository) FindByUserID(userID string) (*model.UserTwoFactor, error) {
+	var twoFactor model.UserTwoFactor
+	if err := r.db.Where("user_id = ?", userID).First(&twoFactor).Error; err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// IsEnabled 是否已开启两步验证
+func (r *twoFactorRepository) IsEnabled(userID string) bool {
+	var count int64
+	r.db.Model(&model.UserTwoFactor{}).Where("user_id = ? AND enabled = ?", userID, true).Count(&count)
+	return count > 0
+}
+
+// SavePending 保存待确认的密钥（调用方需确认尚未开启）
+func (r *twoFactorRepository) SavePending(userID, secret string) error {
+	twoFactor := &model.UserTwoFactor{UserID: userID, Secret: secret}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"secret": secret, "last_used_step": 0}),
+	}).Create(twoFactor).Error
+}
+
+// Enable 开启两步验证并写入恢复码
+func (r *twoFactorRepository) Enable(userID string, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.UserTwoFactor{}).
+			Where("user_id = ? AND enabled = ?", userID, false).
+			Updates(map[string]interface{}{"enabled": true, "enabled_at": &now, "last_used_step": step})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseStep 记录通过验证的时间步（条件更新保证同一验证码只能用一次）
+func (r *twoFactorRepository) UseStep(userID string, step int64) (bool, error) {
+	result := r.db.Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete 关闭两步验证
+func (r *twoFactorRepository) Delete(userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.UserTwoFactor{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes 重新生成恢复码（旧恢复码全部作废）
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseRecoveryCode 使用恢复码
+func (r *twoFactorRepository) UseRecoveryCode(userID, codeHash string) (bool, error) {
+	result := r.db.Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Limit(1).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountRecoveryCodes 剩余可用的恢复码数量
+func (r *twoFactorRepository) CountRecoveryCodes(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID string, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]model.UserRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, model.UserRecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return tx.Create(&codes).Error
+}
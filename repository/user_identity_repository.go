@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"astronomer-gin/model"
+
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository 第三方账号绑定数据访问接口
+type UserIdentityRepository interface {
+	FindByProviderSubject(provider, subject string) (*model.UserIdentity, error)
+	FindByUserProvider(userID, provider string) (*model.UserIdentity, error)
+	FindByUserID(userID string) ([]model.UserIdentity, error)
+	CountByUserID(userID string) (int64, error)
+	Create(identity *model.UserIdentity) error
+	// CreateWithUser 第三方首次登录时同时创建用户和绑定关系
+	CreateWithUser(user *model.User, identity *model.UserIdentity) error
+	// UpdateProfile 更新第三方用户名、邮箱和头像快照
+	UpdateProfile(id uint64, username, email, avatar string) error
+	Delete(id uint64) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository 创建UserIdentityRepository实例
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+// FindByProviderSubject 按第三方账号查询绑定
+func (r *userIdentityRepository) FindByProviderSubject(provider, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// FindByUserProvider 查询用户在某个提供方的绑定
+func (r *userIdentityRepository) FindByUserProvider(userID, provider string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	if err := r.db.Where("user_id = ? AND provider = ?", userID, provider).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// FindByUserID 用户绑定的全部第三方账号
+func (r *userIdentityRepository) FindByUserID(userID string) ([]model.UserIdentity, error) {
+	var identities []model.UserIdentity
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&identities).Error
+	return identities, err
+}
+
+// CountByUserID 用户绑定的第三方账号数量
+func (r *userIdentityRepository) CountByUserID(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserIdentity{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Create 创建绑定
+func (r *userIdentityRepository) Create(identity *model.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// CreateWithUser 在同一事务中创建用户和绑定，并发首次登录时只有一个成功
+func (r *userIdentityRepository) CreateWithUser(user *model.User, identity *model.UserIdentity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
+
+// UpdateProfile 更新第三方资料快照
+func (r *userIdentityRepository) UpdateProfile(id uint64, username, email, avatar string) error {
+	return r.db.Model(&model.UserIdentity{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"username": username,
+		"email":    email,
+		"avatar":   avatar,
+	}).Error
+}
+
+// Delete 解除绑定
+func (r *userIdentityRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.UserIdentity{}, id).Error
+}
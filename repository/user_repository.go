@@ -2,6 +2,8 @@ package repository
 
 import (
 	"astronomer-gin/model"
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -9,10 +11,17 @@ type UserRepository interface {
 	Create(user *model.User) error
 	FindByID(id string) (*model.User, error)
 	FindByPhone(phone string) (*model.User, error)
+	FindByEmail(email string) (*model.User, error)
+	FindByHandle(handle string) (*model.User, error)
 	Update(user *model.User) error
 	UpdateFields(id string, fields map[string]interface{}) error
+	ChangePhone(id, oldPhone, newPhone string) error
+	// ChangeHandle 更换个性ID并记录修改（旧ID保留到redirectUntil）
+	ChangeHandle(id, oldHandle, newHandle string, redirectUntil time.Time) error
 	Delete(id string) error
 	ExistsByPhone(phone string) bool
+	ExistsByEmail(email string) bool
+	ExistsByHandle(handle string) bool
 	SearchUsers(keyword string, page, pageSize int) ([]model.User, int64, error)
 }
 
@@ -49,6 +58,16 @@ func (r *userRepository) FindByPhone(phone string) (*model.User, error) {
 	return &user, nil
 }
 
+// FindByEmail 根据已验证邮箱查找用户
+func (r *userRepository) FindByEmail(email string) (*model.User, error) {
+	var user model.User
+	err := r.db.Where("email = ?", email).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // Update 更新用户信息
 func (r *userRepository) Update(user *model.User) error {
 	return r.db.Save(user).Error
@@ -59,6 +78,55 @@ func (r *userRepository) UpdateFields(id string, fields map[string]interface{})
 	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(fields).Error
 }
 
+// ChangePhone 更换手机号（同步更新旧评论表中冗余的手机号）
+func (r *userRepository) ChangePhone(id, oldPhone, newPhone string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ? AND phone = ?", id, oldPhone).Update("phone", newPhone)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&model.CommentParent{}).Where("user_id = ?", id).Update("phone", newPhone).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.CommentSubTwo{}).Where("user_id = ?", id).Update("phone", newPhone).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.CommentSubTwo{}).Where("to_user_id = ?", id).Update("to_phone", newPhone).Error
+	})
+}
+
+// FindByHandle 根据个性ID查找用户
+func (r *userRepository) FindByHandle(handle string) (*model.User, error) {
+	var user model.User
+	if err := r.db.Where("handle = ?", handle).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ChangeHandle 更换个性ID并写入修改记录
+func (r *userRepository) ChangeHandle(id, oldHandle, newHandle string, redirectUntil time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ? AND handle = ?", id, oldHandle).Update("handle", newHandle)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&model.UserNameHistory{
+			UserID:        id,
+			Kind:          model.NameHistoryKindHandle,
+			OldValue:      oldHandle,
+			NewValue:      newHandle,
+			RedirectUntil: &redirectUntil,
+		}).Error
+	})
+}
+
 // Delete 删除用户
 func (r *userRepository) Delete(id string) error {
 	return r.db.Where("id = ?", id).Delete(&model.User{}).Error
@@ -71,7 +139,21 @@ func (r *userRepository) ExistsByPhone(phone string) bool {
 	return count > 0
 }
 
-// SearchUsers 搜索用户（通过用户名或备注）
+// ExistsByHandle 检查个性ID是否已被使用
+func (r *userRepository) ExistsByHandle(handle string) bool {
+	var count int64
+	r.db.Model(&model.User{}).Where("handle = ?", handle).Count(&count)
+	return count > 0
+}
+
+// ExistsByEmail 检查邮箱是否已被验证绑定
+func (r *userRepository) ExistsByEmail(email string) bool {
+	var count int64
+	r.db.Model(&model.User{}).Where("email = ?", email).Count(&count)
+	return count > 0
+}
+
+// SearchUsers 搜索用户（通过用户名、个性ID或备注）
 func (r *userRepository) SearchUsers(keyword string, page, pageSize int) ([]model.User, int64, error) {
 	var users []model.User
 	var total int64
@@ -82,9 +164,13 @@ func (r *userRepository) SearchUsers(keyword string, page, pageSize int) ([]mode
 	if keyword != "" {
 		// 搜索用户名或备注包含关键词的用户
 		likeKeyword := "%" + keyword + "%"
-		query = query.Where("username LIKE ? OR note LIKE ?", likeKeyword, likeKeyword)
+		query = query.Where("username LIKE ? OR handle LIKE ? OR note LIKE ?", likeKeyword, likeKeyword, likeKeyword)
 	}
 
+	// 排除在隐私设置中关闭了搜索展示的用户
+	hidden := r.db.Model(&model.UserPrivacy{}).Select("user_id").Where("searchable = ?", false)
+	query = query.Where("id NOT IN (?)", hidden)
+
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"astronomer-gin/config"
 	"astronomer-gin/handler"
 	"astronomer-gin/handler/admin"
 	"astronomer-gin/handler/chat"
@@ -13,6 +14,8 @@ import (
 	"astronomer-gin/handler/user"
 	"astronomer-gin/middleware"
 	"astronomer-gin/pkg/database"
+	"astronomer-gin/pkg/oauth"
+	wsLib "astronomer-gin/pkg/websocket"
 	"astronomer-gin/repository"
 	"astronomer-gin/service"
 
@@ -43,7 +46,6 @@ func SetupRouter() *gin.Engine {
 
 	// 初始化Repository层
 	userRepo := repository.NewUserRepository(db)
-	blogRepo := repository.NewBlogRepository(db)
 	favoriteRepo := repository.NewFavoriteRepository(db)
 	followRepo := repository.NewFollowRepository(db)
 	notifyRepo := repository.NewNotificationRepository(db)
@@ -54,37 +56,79 @@ func SetupRouter() *gin.Engine {
 	commentV3Repo := repository.NewCommentV3Repository(db)
 	likeRepo := repository.NewLikeRepository(db)
 	columnRepo := repository.NewColumnRepository(db)
+	dynamicRepo := repository.NewDynamicRepository(db)
+	importRepo := repository.NewArticleImportRepository(db)
+	columnExportRepo := repository.NewColumnExportRepository(db)
+	articleStatsRepo := repository.NewArticleStatsRepository(db)
+	duplicateRepo := repository.NewArticleDuplicateRepository(db)
+	qualityRepo := repository.NewArticleQualityRepository(db)
+	legacyMigrationRepo := repository.NewLegacyMigrationRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	contributorRepo := repository.NewContributorRepository(db)
 
 	// 初始化Service层（使用V2版本）
-	userService := service.NewUserServiceV2(userRepo)
-	favoriteService := service.NewFavoriteServiceV2(favoriteRepo, blogRepo, notifyRepo)
-	followService := service.NewFollowServiceV2(followRepo, userRepo, notifyRepo)
+	twoFactorService := service.NewTwoFactorService(repository.NewTwoFactorRepository(db), userRepo, notifyRepo)
+	identityRepo := repository.NewUserIdentityRepository(db)
+	accountRepo := repository.NewAccountRepository(db)
+	userService := service.NewUserServiceV2(userRepo, service.NewSMSCodeService(), twoFactorService, identityRepo, accountRepo, repository.NewNameHistoryRepository(db))
+	accountService := service.NewAccountService(accountRepo, userRepo, identityRepo, notifyRepo, config.GlobalConfig.Account)
+	auditService := service.NewAuditService(repository.NewAuditRepository(db), config.GlobalConfig.Audit)
+	riskService := service.NewRiskService()
+	favoriteService := service.NewFavoriteServiceV2(favoriteRepo, articleV3Repo, notifyRepo)
+	privacyService := service.NewPrivacyService(repository.NewPrivacyRepository(db), followRepo)
+	levelService := service.NewLevelService(repository.NewLevelRepository(db), userRepo, notifyRepo, config.GlobalConfig.Level)
+	followService := service.NewFollowServiceV2(followRepo, userRepo, notifyRepo, privacyService)
 	notifyService := service.NewNotificationServiceV2(notifyRepo)
 	uploadService := service.NewUploadServiceV2()
-	searchService := service.NewSearchServiceV2(blogRepo, userRepo, followRepo)
-	trendingService := service.NewTrendingServiceV2(blogRepo, userRepo)
-	chatService := service.NewChatServiceV2(chatRepo, followRepo, userRepo)
+	searchService := service.NewSearchServiceV2(articleV3Repo, userRepo, followRepo)
+	trendingService := service.NewTrendingServiceV2(articleV3Repo, userRepo, privacyService)
+	chatService := service.NewChatServiceV2(chatRepo, followRepo, userRepo, privacyService)
 
 	// 初始化V3 Service层（企业级功能）
-	articleV3Service := service.NewArticleV3Service(articleV3Repo, userRepo, followRepo, likeRepo, favoriteRepo, db)
-	commentV3Service := service.NewCommentV3Service(commentV3Repo, articleV3Repo, userRepo, likeRepo, notifyRepo, db)
-	columnService := service.NewColumnService(columnRepo, userRepo, notifyRepo, articleV3Repo)
+	duplicateService := service.NewArticleDuplicateService(duplicateRepo, articleV3Repo)
+	qualityService := service.NewArticleQualityService(qualityRepo, articleV3Repo, nil)
+	columnChapterService := service.NewColumnChapterService(columnRepo, articleV3Repo, notifyRepo)
+	articleV3Service := service.NewArticleV3Service(articleV3Repo, userRepo, followRepo, likeRepo, favoriteRepo, duplicateService, qualityService, contributorRepo, columnChapterService, levelService, db)
+	commentV3Service := service.NewCommentV3Service(commentV3Repo, articleV3Repo, dynamicRepo, userRepo, likeRepo, notifyRepo, privacyService, levelService, db)
+	columnService := service.NewColumnService(columnRepo, userRepo, notifyRepo, articleV3Repo, contributorRepo, columnChapterService)
+	columnExportService := service.NewColumnExportService(columnExportRepo, columnRepo, articleV3Repo, userRepo)
+	engagementService := service.NewEngagementService(articleStatsRepo, articleV3Repo)
+	analyticsService := service.NewAnalyticsService(articleStatsRepo, articleV3Repo)
+	dynamicService := service.NewDynamicService(dynamicRepo, articleV3Repo, userRepo, uploadService, levelService)
+	importService := service.NewArticleImportService(importRepo, articleV3Repo, articleV3Service)
+	legacyMigrationService := service.NewLegacyMigrationService(legacyMigrationRepo)
+	trashService := service.NewTrashService(trashRepo, articleV3Repo, commentV3Repo, dynamicRepo, uploadService)
+	contributorService := service.NewContributorService(contributorRepo, articleV3Repo, userRepo, notifyRepo)
+	draftCollabService := service.NewDraftCollabService(articleV3Repo, contributorRepo, userRepo, wsLib.GetHub())
 
 	// 初始化Handler层
-	userHandler := user.NewUserHandler(userService)
+	userHandler := user.NewUserHandler(userService, riskService, privacyService, levelService, auditService)
+	twoFactorHandler := user.NewTwoFactorHandler(twoFactorService, auditService)
+	accountHandler := user.NewAccountHandler(accountService, auditService)
 	favoriteHandler := favorite.NewFavoriteHandler(favoriteService, userService)
 	followHandler := follow.NewFollowHandler(followService, userService)
 	notifyHandler := notification.NewNotificationHandler(notifyService, userService)
-	uploadHandler := upload.NewUploadHandler(uploadService)
+	uploadHandler := upload.NewUploadHandler(uploadService, levelService)
 	searchHandler := search.NewSearchHandler(searchService)
 	trendingHandler := trending.NewTrendingHandler(trendingService)
-	syncHandler := admin.NewSyncHandler(blogRepo)
+	syncHandler := admin.NewSyncHandler(articleV3Repo)
+	auditHandler := admin.NewAuditHandler(auditService)
 	chatHandler := chat.NewChatHandler(chatService, userService)
 
 	// 初始化V3 Handler层（企业级功能）
 	articleV3Handler := handler.NewArticleV3Handler(articleV3Service)
-	commentV3Handler := handler.NewCommentV3Handler(commentV3Service)
-	columnHandler := handler.NewColumnHandler(columnService)
+	commentV3Handler := handler.NewCommentV3Handler(commentV3Service, riskService, auditService)
+	columnHandler := handler.NewColumnHandler(columnService, columnExportService, columnChapterService)
+	dynamicHandler := handler.NewDynamicHandler(dynamicService)
+	importHandler := handler.NewArticleImportHandler(importService)
+	engagementHandler := handler.NewEngagementHandler(engagementService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	duplicateHandler := handler.NewArticleDuplicateHandler(duplicateService)
+	qualityHandler := handler.NewArticleQualityHandler(qualityService)
+	legacyMigrationHandler := handler.NewLegacyMigrationHandler(legacyMigrationService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	contributorHandler := handler.NewContributorHandler(contributorService, auditService)
+	draftCollabHandler := handler.NewDraftCollabHandler(draftCollabService, wsLib.GetHub())
 
 	// Swagger文档路由
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -92,6 +136,21 @@ func SetupRouter() *gin.Engine {
 	// ==================== 注册V3路由（企业级功能） ====================
 	articleV3Handler.RegisterRoutes(r)
 	commentV3Handler.RegisterRoutes(r)
+	dynamicHandler.RegisterRoutes(r)
+	importHandler.RegisterRoutes(r)
+	engagementHandler.RegisterRoutes(r)
+	analyticsHandler.RegisterRoutes(r)
+	duplicateHandler.RegisterRoutes(r)
+	qualityHandler.RegisterRoutes(r)
+	legacyMigrationHandler.RegisterRoutes(r)
+	trashHandler.RegisterRoutes(r)
+	contributorHandler.RegisterRoutes(r)
+	draftCollabHandler.RegisterRoutes(r)
+
+	// 模拟第三方登录（配置了mock类型的提供方时启用）
+	if mockServer := oauth.GetMockServer(); mockServer != nil {
+		mockServer.RegisterRoutes(r)
+	}
 
 	// ==================== V3版本的用户路由（兼容前端） ====================
 	apiV3 := r.Group("/api/v3")
@@ -101,7 +160,16 @@ func SetupRouter() *gin.Engine {
 		{
 			userV3Public.POST("/register", middleware.RegisterRateLimit(), userHandler.Register)
 			userV3Public.POST("/login", middleware.LoginRateLimit(), userHandler.Login)
+			userV3Public.POST("/sms/send", userHandler.SendLoginCode)
+			userV3Public.POST("/login/sms", middleware.LoginRateLimit(), userHandler.LoginBySMS)
+			userV3Public.POST("/login/2fa", middleware.LoginRateLimit(), userHandler.VerifyTwoFactorLogin)
 			userV3Public.GET("/captcha", userHandler.GetCaptcha)
+			userV3Public.GET("/captcha/required", userHandler.CaptchaRequired)      // 是否需要图形验证码
+			userV3Public.GET("/level/leaderboard", userHandler.GetLevelLeaderboard) // 经验排行榜
+			userV3Public.GET("/level/rules", userHandler.GetLevelRules)             // 等级规则
+			userV3Public.POST("/email/verify", userHandler.VerifyEmail)
+			userV3Public.POST("/password/forgot", middleware.PasswordRateLimit(), userHandler.ForgotPassword)
+			userV3Public.POST("/password/reset", middleware.PasswordRateLimit(), userHandler.ResetPassword)
 		}
 
 		// 用户相关路由（需要认证）- 必须在动态路由之前注册
@@ -111,13 +179,56 @@ func SetupRouter() *gin.Engine {
 			userV3Auth.GET("/current", userHandler.GetUserInfo) // 获取当前登录用户信息
 			userV3Auth.GET("/info", userHandler.GetUserInfo)
 			userV3Auth.PUT("/update", userHandler.UpdateUserInfo)
-			userV3Auth.POST("/logout", userHandler.Logout) // 用户登出
+			userV3Auth.GET("/handle/check", userHandler.CheckHandle)        // 个性ID是否可用
+			userV3Auth.PUT("/handle", userHandler.ChangeHandle)             // 修改个性ID
+			userV3Auth.GET("/privacy", userHandler.GetPrivacySettings)      // 隐私设置
+			userV3Auth.GET("/level", userHandler.GetMyLevel)                // 我的等级
+			userV3Auth.GET("/level/logs", userHandler.GetXPLogs)            // 经验记录
+			userV3Auth.PUT("/privacy", userHandler.UpdatePrivacySettings)   // 更新隐私设置
+			userV3Auth.PUT("/email", userHandler.BindEmail)                 // 绑定/更换邮箱
+			userV3Auth.POST("/email/resend", userHandler.ResendVerifyEmail) // 重新发送验证邮件
+			userV3Auth.POST("/phone/old-code", userHandler.SendChangePhoneOldCode)
+			userV3Auth.POST("/phone/verify-old", userHandler.VerifyChangePhoneOld)
+			userV3Auth.POST("/phone/new-code", userHandler.SendChangePhoneNewCode)
+			userV3Auth.PUT("/phone", userHandler.ChangePhone) // 更换手机号（需先验证原手机号）
+			userV3Auth.POST("/logout", userHandler.Logout)    // 用户登出
+			userV3Auth.GET("/security/activity", userHandler.GetSecurityActivity)
+
+			// 第三方账号绑定
+			userV3Auth.GET("/identities", userHandler.ListIdentities)
+			userV3Auth.POST("/identities/:provider", userHandler.LinkIdentity)
+			userV3Auth.DELETE("/identities/:provider", userHandler.UnlinkIdentity)
+
+			// 两步验证
+			userV3Auth.GET("/2fa", twoFactorHandler.GetStatus)
+			userV3Auth.POST("/2fa/setup", twoFactorHandler.Setup)
+			userV3Auth.POST("/2fa/enable", twoFactorHandler.Enable)
+			userV3Auth.POST("/2fa/disable", twoFactorHandler.Disable)
+			userV3Auth.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+
+			// 个人数据导出与账号注销
+			userV3Auth.POST("/account/exports", accountHandler.RequestExport)
+			userV3Auth.GET("/account/exports", accountHandler.ListExports)
+			userV3Auth.GET("/account/exports/:id/download", accountHandler.DownloadExport)
+			userV3Auth.GET("/account/deletion", accountHandler.GetDeletion)
+			userV3Auth.POST("/account/deletion", accountHandler.RequestDeletion)
+			userV3Auth.DELETE("/account/deletion", accountHandler.CancelDeletion)
+		}
+
+		// 第三方登录（回调可选认证：绑定流程需要发起绑定的用户）
+		oauthV3 := apiV3.Group("/oauth")
+		{
+			oauthV3.GET("/providers", userHandler.ListOAuthProviders)
+			oauthV3.GET("/:provider/authorize", userHandler.OAuthAuthorize)
+			oauthV3.POST("/:provider/callback", middleware.LoginRateLimit(), middleware.OptionalAuthMiddleware(), userHandler.OAuthCallback)
 		}
 
 		// 用户公开路由（动态路由，必须在静态路由之后）
 		userV3PublicDynamic := apiV3.Group("/user")
 		{
-			userV3PublicDynamic.GET("/:id", userHandler.GetUserProfile) // 获取用户资料（公开）
+			userV3PublicDynamic.GET("/by-handle/:handle", middleware.OptionalAuthMiddleware(), userHandler.GetUserByHandle) // 通过个性ID获取用户资料（旧ID跳转，生日按隐私设置）
+			userV3PublicDynamic.GET("/:id", middleware.OptionalAuthMiddleware(), userHandler.GetUserProfile)                // 获取用户资料（公开，生日按隐私设置）
+			userV3PublicDynamic.GET("/:id/level", userHandler.GetUserLevel)                                                 // 用户等级（公开）
 		}
 
 		// 上传功能
@@ -170,9 +281,12 @@ func SetupRouter() *gin.Engine {
 			columnV3Auth.POST("/:id/subscribe", columnHandler.Subscribe)                               // 订阅专栏
 			columnV3Auth.DELETE("/:id/subscribe", columnHandler.Unsubscribe)                           // 取消订阅
 			columnV3Auth.GET("/subscribed", columnHandler.GetSubscribedColumns)                        // 获取我订阅的专栏
+			columnV3Auth.GET("/reading", columnHandler.GetReadingList)                                 // 获取我在读的专栏及进度
+			columnV3Auth.GET("/:id/progress", columnHandler.GetReadingProgress)                        // 获取专栏阅读进度
 			columnV3Auth.POST("/:id/articles", columnHandler.AddArticle)                               // 添加文章到专栏
 			columnV3Auth.DELETE("/:id/articles/:articleId", columnHandler.RemoveArticle)               // 从专栏移除文章
 			columnV3Auth.PUT("/:id/articles/:articleId/position", columnHandler.UpdateArticlePosition) // 更新文章位置
+			columnV3Auth.GET("/:id/export", columnHandler.Export)                                      // 导出专栏电子书（EPUB/打印版HTML）
 		}
 
 		// 用户专栏路由（使用 :id 与其他用户路由保持一致）
@@ -181,6 +295,7 @@ func SetupRouter() *gin.Engine {
 		// ==================== 关注/好友/拉黑功能 ====================
 		// 关注相关路由（公开 - 查看粉丝/关注列���）
 		followV3Public := apiV3.Group("/user")
+		followV3Public.Use(middleware.OptionalAuthMiddleware()) // 隐私设置按查看者判断
 		{
 			followV3Public.GET("/:id/followers", followHandler.GetFollowers)
 			followV3Public.GET("/:id/following", followHandler.GetFollowing)
@@ -255,6 +370,10 @@ func SetupRouter() *gin.Engine {
 		adminV3Auth.Use(middleware.AuthMiddleware())
 		{
 			adminV3Auth.POST("/sync/articles", syncHandler.SyncArticlesToES)
+			adminV3Auth.DELETE("/users/:id/2fa", middleware.SuperAdminMiddleware(), twoFactorHandler.AdminReset)
+			adminV3Auth.GET("/users/:id/name-history", middleware.AdminMiddleware(), userHandler.GetNameHistory)
+			adminV3Auth.GET("/audit-logs", middleware.AdminMiddleware(), auditHandler.QueryLogs)
+			adminV3Auth.GET("/audit-logs/status", middleware.AdminMiddleware(), auditHandler.GetWriteStatus)
 		}
 	}
 
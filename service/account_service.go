@@ -0,0 +1,550 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"astronomer-gin/config"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	minioPkg "astronomer-gin/pkg/minio"
+	"astronomer-gin/pkg/queue"
+	"astronomer-gin/pkg/redis"
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultDeletionGraceDays 默认注销冷静期。
+	// 冷静期至少1天：token有效期24小时，申请前签发的token都会在执行前过期，
+	// 申请后登录签发新token会撤销申请，因此注销执行时不存在仍然有效的token
+	defaultDeletionGraceDays = 15
+	// defaultDataExportExpireDays 默认导出文件保留天数
+	defaultDataExportExpireDays = 7
+
+	// dataExportStaleTimeout 生成中的任务超过该时长视为中断，允许重新申请
+	dataExportStaleTimeout = 30 * time.Minute
+	// dataExportCooldown 该时长内已完成的导出直接复用，不重复生成
+	dataExportCooldown = 24 * time.Hour
+	// dataExportListLimit 导出记录列表展示的条数
+	dataExportListLimit = 10
+)
+
+// AccountService 账号注销与个人数据导出服务接口
+type AccountService interface {
+	// RequestExport 申请导出个人数据（已有进行中或近期完成的导出时直接返回）
+	RequestExport(ctx context.Context, userID string) (*model.UserDataExport, error)
+	// ListExports 最近的导出记录
+	ListExports(userID string) ([]model.UserDataExport, error)
+	// DownloadExport 下载导出的压缩包（仅本人）
+	DownloadExport(ctx context.Context, exportID uint64, userID string) ([]byte, error)
+	// RunDataExport 生成导出压缩包并通知用户（由Worker调用）
+	RunDataExport(ctx context.Context, exportID uint64) error
+	// PurgeExpiredExports 删除过期的导出文件，返回处理条数（由定时任务调用）
+	PurgeExpiredExports(now time.Time, limit int) (int, error)
+
+	// GetDeletion 冷静期中的注销申请，没有时返回nil
+	GetDeletion(userID string) (*model.AccountDeletion, error)
+	// RequestDeletion 申请注销账号（设置过密码的账号需验证密码），冷静期结束后执行
+	RequestDeletion(userID, password, reason string) (*model.AccountDeletion, error)
+	// CancelDeletion 撤销注销申请
+	CancelDeletion(userID string) error
+	// ExecuteDueDeletions 执行冷静期已结束的注销，返回成功条数（由定时任务调用）
+	ExecuteDueDeletions(now time.Time, limit int) (int, error)
+}
+
+type accountService struct {
+	accountRepo      repository.AccountRepository
+	userRepo         repository.UserRepository
+	identityRepo     repository.UserIdentityRepository
+	notificationRepo repository.NotificationRepository
+	cfg              config.AccountConfig
+	cacheHelper      *util.CacheHelper
+}
+
+// NewAccountService 创建AccountService实例
+func NewAccountService(
+	accountRepo repository.AccountRepository,
+	userRepo repository.UserRepository,
+	identityRepo repository.UserIdentityRepository,
+	notificationRepo repository.NotificationRepository,
+	cfg config.AccountConfig,
+) AccountService {
+	if cfg.DeletionGraceDays < 1 {
+		cfg.DeletionGraceDays = defaultDeletionGraceDays
+	}
+	if cfg.ExportExpireDays <= 0 {
+		cfg.ExportExpireDays = defaultDataExportExpireDays
+	}
+	return &accountService{
+		accountRepo:      accountRepo,
+		userRepo:         userRepo,
+		identityRepo:     identityRepo,
+		notificationRepo: notificationRepo,
+		cfg:              cfg,
+		cacheHelper:      util.NewCacheHelper(redis.GetClient()),
+	}
+}
+
+// ==================== 个人数据导出 ====================
+
+// RequestExport 申请导出个人数据
+func (s *accountService) RequestExport(ctx context.Context, userID string) (*model.UserDataExport, error) {
+	// 1. 进行中或近期完成的导出直接返回
+	if latest, err := s.accountRepo.FindLatestExport(userID); err == nil {
+		switch latest.Status {
+		case model.DataExportStatusPending, model.DataExportStatusRunning:
+			if time.Since(latest.UpdateTime) < dataExportStaleTimeout {
+				return latest, nil
+			}
+		case model.DataExportStatusReady:
+			if time.Since(latest.CreateTime) < dataExportCooldown {
+				return latest, nil
+			}
+		}
+	}
+
+	// 2. 创建任务并投递
+	export := &model.UserDataExport{
+		UserID: userID,
+		Status: model.DataExportStatusPending,
+	}
+	if err := s.accountRepo.CreateExport(export); err != nil {
+		return nil, fmt.Errorf("创建导出任务失败: %w", err)
+	}
+	s.dispatchExport(ctx, export.ID)
+
+	return export, nil
+}
+
+// dispatchExport 投递导出任务（队列不可用时本地执行）
+func (s *accountService) dispatchExport(ctx context.Context, exportID uint64) {
+	if queue.Client != nil {
+		task := queue.CreateTask(queue.TaskTypeAccountExport, map[string]interface{}{
+			"export_id": exportID,
+		})
+		err := queue.Client.PublishTask(ctx, task)
+		if err == nil {
+			return
+		}
+		log.Printf("⚠️ 投递个人数据导出任务失败，改为本地执行: export=%d, %v", exportID, err)
+	}
+	go func() {
+		if err := s.RunDataExport(context.Background(), exportID); err != nil {
+			log.Printf("❌ 个人数据导出失败: export=%d, %v", exportID, err)
+		}
+	}()
+}
+
+// ListExports 最近的导出记录
+func (s *accountService) ListExports(userID string) ([]model.UserDataExport, error) {
+	exports, err := s.accountRepo.FindExportsByUser(userID, dataExportListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("查询导出记录失败: %w", err)
+	}
+	return exports, nil
+}
+
+// DownloadExport 下载导出的压缩包
+// 存储桶是公开读的，压缩包不返回直链，校验本人后由服务端转发
+func (s *accountService) DownloadExport(ctx context.Context, exportID uint64, userID string) ([]byte, error) {
+	export, err := s.accountRepo.FindExportByID(exportID)
+	if err != nil || export.UserID != userID {
+		return nil, constant.ErrResourceNotFound
+	}
+
+	switch export.Status {
+	case model.DataExportStatusReady:
+		if export.ExpireTime != nil && time.Now().After(*export.ExpireTime) {
+			return nil, constant.ErrDataExportExpired
+		}
+	case model.DataExportStatusExpired:
+		return nil, constant.ErrDataExportExpired
+	default:
+		return nil, constant.ErrDataExportNotReady
+	}
+
+	data, err := minioPkg.Client.DownloadFile(ctx, export.ObjectName)
+	if err != nil {
+		return nil, fmt.Errorf("读取导出文件失败: %w", err)
+	}
+	return data, nil
+}
+
+// RunDataExport 生成个人数据压缩包
+func (s *accountService) RunDataExport(ctx context.Context, exportID uint64) error {
+	// 1. 获取任务（已完成的重复投递直接跳过）
+	export, err := s.accountRepo.FindExportByID(exportID)
+	if err != nil {
+		return fmt.Errorf("导出任务不存在: %w", err)
+	}
+	if export.Status == model.DataExportStatusReady || export.Status == model.DataExportStatusExpired {
+		return nil
+	}
+	s.accountRepo.UpdateExportFields(exportID, map[string]interface{}{"status": model.DataExportStatusRunning})
+
+	// 2. 打包
+	data, err := s.buildDataArchive(export.UserID)
+	if err != nil {
+		s.failExport(exportID, err)
+		return err
+	}
+
+	// 3. 上传到对象存储（文件名随机，不可猜测）
+	objectName := fmt.Sprintf("exports/users/%s/%s.zip", export.UserID, uuid.New().String())
+	if _, err := minioPkg.Client.UploadFile(ctx, objectName, bytes.NewReader(data), int64(len(data)), "application/zip"); err != nil {
+		s.failExport(exportID, fmt.Errorf("上传导出文件失败: %w", err))
+		return err
+	}
+
+	// 4. 更新任务并通知
+	now := time.Now()
+	expireTime := now.AddDate(0, 0, s.cfg.ExportExpireDays)
+	s.accountRepo.UpdateExportFields(exportID, map[string]interface{}{
+		"status":        model.DataExportStatusReady,
+		"object_name":   objectName,
+		"file_size":     len(data),
+		"error_message": "",
+		"generate_time": &now,
+		"expire_time":   &expireTime,
+	})
+
+	notification := &model.Notification{
+		UserID:       export.UserID,
+		Type:         model.NotificationTypeDataExport,
+		FromUsername: "系统",
+		Content:      fmt.Sprintf("你的个人数据已导出完成，请在%s前下载", expireTime.Format("2006-01-02 15:04")),
+		RelatedID:    exportID,
+		RelatedType:  "data_export",
+		CreateTime:   now,
+	}
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("⚠️ 发送数据导出通知失败: user=%s, %v", export.UserID, err)
+	}
+
+	log.Printf("✅ 个人数据导出完成: user=%s export=%d size=%d", export.UserID, exportID, len(data))
+	return nil
+}
+
+// dataExportProfile 导出的个人资料
+type dataExportProfile struct {
+	ID             string               `json:"id"`
+	Phone          string               `json:"phone"`
+	Email          string               `json:"email"`
+	Username       string               `json:"username"`
+	Icon           string               `json:"icon"`
+	Sex            int                  `json:"sex"`
+	Intro          string               `json:"intro"`
+	Note           string               `json:"note"`
+	Role           string               `json:"role"`
+	FollowingCount int64                `json:"following_count"`
+	FollowedCount  int64                `json:"followed_count"`
+	CreateTime     *time.Time           `json:"create_time"`
+	Identities     []model.UserIdentity `json:"identities"`
+}
+
+// dataExportArticle 导出的文章（含正文）
+type dataExportArticle struct {
+	model.ArticleV3
+	Content string `json:"content"`
+}
+
+// buildDataArchive 收集用户数据并打包为zip，每类数据一个JSON文件
+func (s *accountService) buildDataArchive(userID string) ([]byte, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+
+	profile := dataExportProfile{
+		ID:             user.ID,
+		Username:       user.Username,
+		Icon:           user.Icon,
+		Sex:            user.Sex,
+		Intro:          user.Intro,
+		Note:           user.Note,
+		Role:           user.Role,
+		FollowingCount: user.FollowingCount,
+		FollowedCount:  user.FollowedCount,
+		CreateTime:     user.CreateTime,
+	}
+	if user.HasPhone() {
+		profile.Phone = user.Phone
+	}
+	if user.Email != nil {
+		profile.Email = *user.Email
+	}
+	if profile.Identities, err = s.identityRepo.FindByUserID(userID); err != nil {
+		return nil, fmt.Errorf("查询第三方绑定失败: %w", err)
+	}
+
+	articles, err := s.collectArticles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 文件名 -> 数据
+	entries := []struct {
+		name  string
+		query func() (interface{}, error)
+	}{
+		{"profile.json", func() (interface{}, error) { return profile, nil }},
+		{"articles.json", func() (interface{}, error) { return articles, nil }},
+		{"drafts.json", func() (interface{}, error) { return s.accountRepo.FindDrafts(userID) }},
+		{"comments.json", func() (interface{}, error) { return s.accountRepo.FindComments(userID) }},
+		{"likes.json", func() (interface{}, error) { return s.accountRepo.FindLikes(userID) }},
+		{"favorites.json", func() (interface{}, error) { return s.accountRepo.FindFavorites(userID) }},
+		{"following.json", func() (interface{}, error) { return s.accountRepo.FindFollowing(userID) }},
+		{"followers.json", func() (interface{}, error) { return s.accountRepo.FindFollowers(userID) }},
+		{"blocks.json", func() (interface{}, error) { return s.accountRepo.FindBlocks(userID) }},
+		{"chats.json", func() (interface{}, error) { return s.accountRepo.FindChats(userID) }},
+		{"notifications.json", func() (interface{}, error) { return s.accountRepo.FindNotifications(userID) }},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		data, err := entry.query()
+		if err != nil {
+			return nil, fmt.Errorf("收集%s失败: %w", entry.name, err)
+		}
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(data); err != nil {
+			return nil, fmt.Errorf("写入%s失败: %w", entry.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// collectArticles 用户的全部文章及正文
+func (s *accountService) collectArticles(userID string) ([]dataExportArticle, error) {
+	articles, err := s.accountRepo.FindArticles(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+
+	ids := make([]uint64, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+	contents, err := s.accountRepo.FindArticleContents(ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询文章内容失败: %w", err)
+	}
+	contentByArticle := make(map[uint64]string, len(contents))
+	for _, content := range contents {
+		contentByArticle[content.ArticleID] = content.Content
+	}
+
+	result := make([]dataExportArticle, 0, len(articles))
+	for _, article := range articles {
+		result = append(result, dataExportArticle{ArticleV3: article, Content: contentByArticle[article.ID]})
+	}
+	return result, nil
+}
+
+// failExport 标记导出失败
+func (s *accountService) failExport(exportID uint64, err error) {
+	s.accountRepo.UpdateExportFields(exportID, map[string]interface{}{
+		"status":        model.DataExportStatusFailed,
+		"error_message": truncateRunes(err.Error(), 500),
+	})
+}
+
+// PurgeExpiredExports 删除过期的导出文件
+func (s *accountService) PurgeExpiredExports(now time.Time, limit int) (int, error) {
+	exports, err := s.accountRepo.FindExpiredExports(now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range exports {
+		if err := s.expireExport(&exports[i]); err != nil {
+			log.Printf("⚠️ 删除过期导出文件失败: export=%d, %v", exports[i].ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// expireExport 删除导出文件并标记过期（删除失败时保留记录，下次重试）
+func (s *accountService) expireExport(export *model.UserDataExport) error {
+	if export.ObjectName != "" {
+		if err := minioPkg.Client.DeleteFile(context.Background(), export.ObjectName); err != nil {
+			return err
+		}
+	}
+	return s.accountRepo.UpdateExportFields(export.ID, map[string]interface{}{
+		"status":      model.DataExportStatusExpired,
+		"object_name": "",
+	})
+}
+
+// ==================== 账号注销 ====================
+
+// GetDeletion 冷静期中的注销申请
+func (s *accountService) GetDeletion(userID string) (*model.AccountDeletion, error) {
+	deletion, err := s.accountRepo.FindDeletion(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询注销申请失败: %w", err)
+	}
+	if deletion.Status != model.AccountDeletionStatusPending {
+		return nil, nil
+	}
+	return deletion, nil
+}
+
+// RequestDeletion 申请注销账号
+func (s *accountService) RequestDeletion(userID, password, reason string) (*model.AccountDeletion, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+	// 第三方登录注册、未设置密码的账号凭当前登录状态即可申请
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return nil, constant.ErrPasswordIncorrect
+		}
+	}
+
+	deletion, err := s.accountRepo.FindDeletion(userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("查询注销申请失败: %w", err)
+		}
+		deletion = &model.AccountDeletion{UserID: userID}
+	} else if deletion.Status == model.AccountDeletionStatusPending {
+		return nil, constant.ErrDeletionPending
+	}
+
+	now := time.Now()
+	deletion.Status = model.AccountDeletionStatusPending
+	deletion.Reason = truncateRunes(strings.TrimSpace(reason), 500)
+	deletion.RequestTime = now
+	deletion.ScheduledTime = now.AddDate(0, 0, s.cfg.DeletionGraceDays)
+	deletion.CompleteTime = nil
+	if err := s.accountRepo.SaveDeletion(deletion); err != nil {
+		return nil, fmt.Errorf("申请注销失败: %w", err)
+	}
+
+	s.notify(userID, fmt.Sprintf("你已申请注销账号，账号将于%s注销。在此之前重新登录或撤销申请即可取消注销",
+		deletion.ScheduledTime.Format("2006-01-02 15:04")))
+	return deletion, nil
+}
+
+// CancelDeletion 撤销注销申请
+func (s *accountService) CancelDeletion(userID string) error {
+	cancelled, err := s.accountRepo.CancelDeletion(userID)
+	if err != nil {
+		return fmt.Errorf("撤销注销申请失败: %w", err)
+	}
+	if !cancelled {
+		return constant.ErrDeletionNotPending
+	}
+
+	s.notify(userID, "你已撤销账号注销申请，账号恢复正常使用")
+	return nil
+}
+
+// ExecuteDueDeletions 执行冷静期已结束的注销
+func (s *accountService) ExecuteDueDeletions(now time.Time, limit int) (int, error) {
+	deletions, err := s.accountRepo.FindDueDeletions(now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	executed := 0
+	for i := range deletions {
+		if err := s.executeDeletion(&deletions[i], now); err != nil {
+			log.Printf("❌ 注销账号失败: user=%s, %v", deletions[i].UserID, err)
+			continue
+		}
+		executed++
+	}
+	return executed, nil
+}
+
+// executeDeletion 匿名化账号并清理对象存储中的头像和导出文件
+func (s *accountService) executeDeletion(deletion *model.AccountDeletion, now time.Time) error {
+	user, err := s.userRepo.FindByID(deletion.UserID)
+	if err != nil {
+		return constant.ErrUserNotExist
+	}
+
+	placeholderPhone := model.DeletedPhonePrefix + strings.ReplaceAll(uuid.New().String(), "-", "")[:12]
+	if err := s.accountRepo.AnonymizeUser(deletion, placeholderPhone, now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("注销申请已撤销")
+		}
+		return err
+	}
+
+	// 以下清理失败只记录日志，账号已完成匿名化
+	if err := revokeUserTokens(s.userRepo, s.cacheHelper, user.ID); err != nil {
+		log.Printf("⚠️ 吊销已注销账号的登录令牌失败: user=%s, %v", user.ID, err)
+	}
+	s.cacheHelper.Delete(constant.CacheKeyUserInfo+user.Phone, constant.CacheKeyUserInfo+user.ID, constant.CacheKeyUserPrivacy+user.ID)
+
+	for _, image := range []string{user.Icon, user.Cover} {
+		if objectName := extractObjectNameFromURL(image); objectName != "" {
+			if err := minioPkg.Client.DeleteFile(context.Background(), objectName); err != nil {
+				log.Printf("⚠️ 删除已注销账号的头像或封面失败: user=%s, %v", user.ID, err)
+			}
+		}
+	}
+
+	exports, err := s.accountRepo.FindExportsByUser(user.ID, dataExportListLimit)
+	if err == nil {
+		for i := range exports {
+			if exports[i].Status != model.DataExportStatusReady {
+				continue
+			}
+			if err := s.expireExport(&exports[i]); err != nil {
+				log.Printf("⚠️ 删除已注销账号的导出文件失败: export=%d, %v", exports[i].ID, err)
+			}
+		}
+	}
+
+	log.Printf("✅ 账号已注销: user=%s", user.ID)
+	return nil
+}
+
+// notify 发送账号安全提醒
+func (s *accountService) notify(userID, content string) {
+	notification := &model.Notification{
+		UserID:       userID,
+		Type:         model.NotificationTypeSecurity,
+		FromUsername: "系统",
+		Content:      content,
+		RelatedID:    userID,
+		RelatedType:  "security",
+		CreateTime:   time.Now(),
+	}
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("⚠️  发送账号注销提醒失败: user=%s, %v", userID, err)
+	}
+}
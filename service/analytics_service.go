@@ -0,0 +1,278 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"astronomer-gin/repository"
+)
+
+// AnalyticsService 创作者数据分析服务接口（基于日终快照，数据T+1）
+type AnalyticsService interface {
+	// 时间段汇总（含与上一等长周期的对比）
+	GetSummary(userID string, start, end time.Time) (*AnalyticsSummary, error)
+	// 每日趋势（articleID为0时返回作者全部文章汇总）
+	GetTrend(userID string, articleID uint64, start, end time.Time) ([]AnalyticsPoint, error)
+	// 时间段内表现最好的文章
+	GetTopArticles(userID string, start, end time.Time, metric string, limit int) ([]repository.TopArticleRow, error)
+	// 粉丝增长
+	GetFollowerGrowth(userID string, start, end time.Time) ([]FollowerPoint, error)
+}
+
+// AnalyticsPoint 每日趋势数据点
+type AnalyticsPoint struct {
+	Date         string  `json:"date"`
+	Views        int     `json:"views"`
+	UniqueViews  int     `json:"unique_views"`
+	Likes        int     `json:"likes"`
+	Comments     int     `json:"comments"`
+	Favorites    int     `json:"favorites"`
+	Shares       int     `json:"shares"`
+	NewFollowers int     `json:"new_followers,omitempty"` // 仅作者维度
+	AvgProgress  float64 `json:"avg_progress,omitempty"`  // 仅文章维度
+	AvgStayTime  int     `json:"avg_stay_time,omitempty"` // 仅文章维度（秒）
+}
+
+// FollowerPoint 粉丝增长数据点
+type FollowerPoint struct {
+	Date          string `json:"date"`
+	NewFollowers  int    `json:"new_followers"`
+	FollowerCount int64  `json:"follower_count"`
+}
+
+// AnalyticsTotals 时间段合计
+type AnalyticsTotals struct {
+	Views        int `json:"views"`
+	UniqueViews  int `json:"unique_views"`
+	Likes        int `json:"likes"`
+	Comments     int `json:"comments"`
+	Favorites    int `json:"favorites"`
+	Shares       int `json:"shares"`
+	NewFollowers int `json:"new_followers"`
+}
+
+// AnalyticsSummary 时间段汇总
+type AnalyticsSummary struct {
+	StartDate string             `json:"start_date"`
+	EndDate   string             `json:"end_date"`
+	Current   AnalyticsTotals    `json:"current"`
+	Previous  AnalyticsTotals    `json:"previous"` // 上一等长周期
+	Change    map[string]float64 `json:"change"`   // 环比变化率（%），上期为0时不返回该项
+}
+
+const (
+	// AnalyticsMaxRangeDays 单次查询的最大天数
+	AnalyticsMaxRangeDays = 366
+	// AnalyticsDefaultRangeDays 默认查询天数
+	AnalyticsDefaultRangeDays = 30
+)
+
+type analyticsService struct {
+	statsRepo   repository.ArticleStatsRepository
+	articleRepo repository.ArticleV3Repository
+}
+
+// NewAnalyticsService 创建AnalyticsService实例
+func NewAnalyticsService(statsRepo repository.ArticleStatsRepository, articleRepo repository.ArticleV3Repository) AnalyticsService {
+	return &analyticsService{
+		statsRepo:   statsRepo,
+		articleRepo: articleRepo,
+	}
+}
+
+// GetSummary 时间段汇总
+func (s *analyticsService) GetSummary(userID string, start, end time.Time) (*AnalyticsSummary, error) {
+	if err := validateAnalyticsRange(start, end); err != nil {
+		return nil, err
+	}
+
+	days := int(end.Sub(start).Hours()/24) + 1
+	prevEnd := start.AddDate(0, 0, -1)
+	prevStart := prevEnd.AddDate(0, 0, -(days - 1))
+
+	current, err := s.sumAuthorRange(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.sumAuthorRange(userID, prevStart, prevEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	change := make(map[string]float64)
+	pairs := map[string][2]int{
+		"views":         {current.Views, previous.Views},
+		"unique_views":  {current.UniqueViews, previous.UniqueViews},
+		"likes":         {current.Likes, previous.Likes},
+		"comments":      {current.Comments, previous.Comments},
+		"favorites":     {current.Favorites, previous.Favorites},
+		"shares":        {current.Shares, previous.Shares},
+		"new_followers": {current.NewFollowers, previous.NewFollowers},
+	}
+	for key, pair := range pairs {
+		if pair[1] == 0 {
+			continue
+		}
+		change[key] = float64(int(float64(pair[0]-pair[1])/float64(pair[1])*10000)) / 100
+	}
+
+	return &AnalyticsSummary{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.Format("2006-01-02"),
+		Current:   *current,
+		Previous:  *previous,
+		Change:    change,
+	}, nil
+}
+
+// GetTrend 每日趋势
+func (s *analyticsService) GetTrend(userID string, articleID uint64, start, end time.Time) ([]AnalyticsPoint, error) {
+	if err := validateAnalyticsRange(start, end); err != nil {
+		return nil, err
+	}
+
+	// 1. 初始化每日数据点（无数据的日期补0）
+	points := make([]AnalyticsPoint, 0, int(end.Sub(start).Hours()/24)+1)
+	index := make(map[string]int)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		index[date] = len(points)
+		points = append(points, AnalyticsPoint{Date: date})
+	}
+
+	// 2. 作者维度
+	if articleID == 0 {
+		rows, err := s.statsRepo.FindAuthorDaily(userID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("查询统计数据失败: %w", err)
+		}
+		for _, row := range rows {
+			i, ok := index[row.StatDate.Format("2006-01-02")]
+			if !ok {
+				continue
+			}
+			points[i].Views = row.ViewCount
+			points[i].UniqueViews = row.UniqueViewCount
+			points[i].Likes = row.LikeCount
+			points[i].Comments = row.CommentCount
+			points[i].Favorites = row.FavoriteCount
+			points[i].Shares = row.ShareCount
+			points[i].NewFollowers = row.NewFollowers
+		}
+		return points, nil
+	}
+
+	// 3. 文章维度（仅作者本人可查看）
+	if !s.articleRepo.CheckOwnership(articleID, userID) {
+		return nil, fmt.Errorf("文章不存在或无权查看")
+	}
+	rows, err := s.statsRepo.FindArticleDaily(articleID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("查询统计数据失败: %w", err)
+	}
+	for _, row := range rows {
+		i, ok := index[row.StatDate.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		points[i].Views = row.ViewCount
+		points[i].UniqueViews = row.UniqueViewCount
+		points[i].Likes = row.LikeCount
+		points[i].Comments = row.CommentCount
+		points[i].Favorites = row.FavoriteCount
+		points[i].Shares = row.ShareCount
+		if row.ReadCount > 0 {
+			points[i].AvgProgress = float64(int(row.ProgressSum/float64(row.ReadCount)*100)) / 100
+			points[i].AvgStayTime = int(row.StayTimeSum / int64(row.ReadCount))
+		}
+	}
+	return points, nil
+}
+
+// GetTopArticles 时间段内表现最好的文章
+func (s *analyticsService) GetTopArticles(userID string, start, end time.Time, metric string, limit int) ([]repository.TopArticleRow, error) {
+	if err := validateAnalyticsRange(start, end); err != nil {
+		return nil, err
+	}
+	if metric == "" {
+		metric = "views"
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	rows, err := s.statsRepo.FindTopArticles(userID, start, end, metric, limit)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []repository.TopArticleRow{}
+	}
+	return rows, nil
+}
+
+// GetFollowerGrowth 粉丝增长
+func (s *analyticsService) GetFollowerGrowth(userID string, start, end time.Time) ([]FollowerPoint, error) {
+	if err := validateAnalyticsRange(start, end); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.statsRepo.FindAuthorDaily(userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("查询粉丝数据失败: %w", err)
+	}
+	byDate := make(map[string]int, len(rows))
+	for i, row := range rows {
+		byDate[row.StatDate.Format("2006-01-02")] = i
+	}
+
+	// 粉丝总数只在有快照的日期记录，缺失的日期沿用前一日的值
+	followerCount, err := s.statsRepo.FindLatestFollowerCount(userID, start)
+	if err != nil {
+		return nil, fmt.Errorf("查询粉丝数据失败: %w", err)
+	}
+
+	points := make([]FollowerPoint, 0, len(rows))
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		point := FollowerPoint{Date: date, FollowerCount: followerCount}
+		if i, ok := byDate[date]; ok {
+			point.NewFollowers = rows[i].NewFollowers
+			point.FollowerCount = rows[i].FollowerCount
+			followerCount = rows[i].FollowerCount
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// sumAuthorRange 汇总作者时间段数据
+func (s *analyticsService) sumAuthorRange(userID string, start, end time.Time) (*AnalyticsTotals, error) {
+	rows, err := s.statsRepo.FindAuthorDaily(userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("查询统计数据失败: %w", err)
+	}
+
+	totals := &AnalyticsTotals{}
+	for _, row := range rows {
+		totals.Views += row.ViewCount
+		totals.UniqueViews += row.UniqueViewCount
+		totals.Likes += row.LikeCount
+		totals.Comments += row.CommentCount
+		totals.Favorites += row.FavoriteCount
+		totals.Shares += row.ShareCount
+		totals.NewFollowers += row.NewFollowers
+	}
+	return totals, nil
+}
+
+// validateAnalyticsRange 校验查询区间
+func validateAnalyticsRange(start, end time.Time) error {
+	if end.Before(start) {
+		return fmt.Errorf("结束日期不能早于开始日期")
+	}
+	if end.Sub(start).Hours()/24 >= AnalyticsMaxRangeDays {
+		return fmt.Errorf("查询范围不能超过%d天", AnalyticsMaxRangeDays)
+	}
+	return nil
+}
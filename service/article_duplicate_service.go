@@ -0,0 +1,432 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/repository"
+)
+
+// ArticleDuplicateService 文章查重服务接口（SimHash近似重复检测）
+type ArticleDuplicateService interface {
+	// 计算文章指纹并检测与其他作者文章的近似重复（文章创建/内容更新后调用）
+	CheckArticle(articleID uint64) error
+
+	// ==================== 作者 ====================
+	// 查看自己文章的疑似重复记录
+	GetArticleDuplicates(articleID uint64, userID string) ([]DuplicateItem, error)
+	// 提交原创申诉
+	ClaimOriginality(articleID uint64, userID string, req *OriginalityClaimRequest) (*model.ArticleOriginalityClaim, error)
+
+	// ==================== 管理员 ====================
+	// 疑似重复聚类报告
+	GetDuplicateClusters(status int8, limit int) ([]DuplicateCluster, error)
+	// 处理疑似重复（确认/排除）
+	ResolveDuplicate(duplicateID uint64, adminID string, req *ResolveDuplicateRequest) error
+	// 原创申诉列表
+	GetOriginalityClaims(status int8, page, pageSize int) ([]model.ArticleOriginalityClaim, int64, error)
+	// 审核原创申诉
+	ReviewOriginalityClaim(claimID uint64, adminID string, req *ReviewClaimRequest) error
+}
+
+// OriginalityClaimRequest 原创申诉请求
+type OriginalityClaimRequest struct {
+	Statement string   `json:"statement" binding:"required,max=1000"`
+	Evidence  []string `json:"evidence" binding:"max=10"` // 证明材料链接（草稿截图、首发地址等）
+}
+
+// ResolveDuplicateRequest 处理疑似重复请求
+type ResolveDuplicateRequest struct {
+	Confirm bool   `json:"confirm"` // true-确认重复（下线疑似文章） false-排除
+	Note    string `json:"note" binding:"max=500"`
+}
+
+// ReviewClaimRequest 审核原创申诉请求
+type ReviewClaimRequest struct {
+	Approve bool   `json:"approve"`
+	Note    string `json:"note" binding:"max=500"`
+}
+
+// DuplicateItem 疑似重复记录（附双方文章标题）
+type DuplicateItem struct {
+	model.ArticleDuplicate
+	ArticleTitle string `json:"article_title"`
+	SourceTitle  string `json:"source_title"`
+}
+
+// DuplicateCluster 疑似重复聚类（相互近似的一组文章）
+type DuplicateCluster struct {
+	OriginalArticleID uint64                   `json:"original_article_id"` // 最早发布的文章
+	Articles          []DuplicateClusterMember `json:"articles"`
+	Pairs             []model.ArticleDuplicate `json:"pairs"`
+}
+
+// DuplicateClusterMember 聚类中的文章
+type DuplicateClusterMember struct {
+	ArticleID  uint64    `json:"article_id"`
+	UserID     string    `json:"user_id"`
+	Title      string    `json:"title"`
+	Status     int8      `json:"status"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+const (
+	// duplicateMaxDistance 判定为近似重复的最大汉明距离（64位指纹）
+	duplicateMaxDistance = 3
+	// duplicateMinTokens 特征过少的短文不参与查重，避免误判
+	duplicateMinTokens = 80
+	// duplicateCandidateLimit 单次检测的候选上限
+	duplicateCandidateLimit = 500
+	// duplicateAuditPrefix 疑似重复的审核原因前缀
+	duplicateAuditPrefix = "疑似重复"
+)
+
+type articleDuplicateService struct {
+	duplicateRepo repository.ArticleDuplicateRepository
+	articleRepo   repository.ArticleV3Repository
+}
+
+// NewArticleDuplicateService 创建ArticleDuplicateService实例
+func NewArticleDuplicateService(duplicateRepo repository.ArticleDuplicateRepository, articleRepo repository.ArticleV3Repository) ArticleDuplicateService {
+	return &articleDuplicateService{
+		duplicateRepo: duplicateRepo,
+		articleRepo:   articleRepo,
+	}
+}
+
+// CheckArticle 计算指纹并检测近似重复
+func (s *articleDuplicateService) CheckArticle(articleID uint64) error {
+	// 1. 读取文章内容
+	article, content, err := s.articleRepo.FindByIDWithContent(articleID)
+	if err != nil {
+		return fmt.Errorf("文章不存在: %w", err)
+	}
+
+	// 2. 计算指纹（短文不建索引）
+	hash, tokenCount := computeSimHash(content.Content)
+	if tokenCount < duplicateMinTokens {
+		return s.duplicateRepo.DeleteFingerprint(articleID)
+	}
+	bands := simhashBands(hash)
+	fp := &model.ArticleFingerprint{
+		ArticleID:  articleID,
+		UserID:     article.UserID,
+		SimHash:    hash,
+		Band0:      bands[0],
+		Band1:      bands[1],
+		Band2:      bands[2],
+		Band3:      bands[3],
+		TokenCount: tokenCount,
+	}
+	if err := s.duplicateRepo.SaveFingerprint(fp); err != nil {
+		return fmt.Errorf("保存文章指纹失败: %w", err)
+	}
+
+	// 3. 查找候选并计算汉明距离
+	candidates, err := s.duplicateRepo.FindCandidates(fp, duplicateCandidateLimit)
+	if err != nil {
+		return fmt.Errorf("查询相似文章失败: %w", err)
+	}
+
+	// 4. 记录疑似重复：发布较晚的一方为疑似搬运
+	var matchedSources []uint64
+	for _, candidate := range candidates {
+		distance := hammingDistance(hash, candidate.SimHash)
+		if distance > duplicateMaxDistance {
+			continue
+		}
+
+		dup := &model.ArticleDuplicate{
+			Distance:   distance,
+			Similarity: float64(64-distance) * 100 / 64,
+		}
+		if candidate.CreateTime.Before(article.CreateTime) {
+			dup.ArticleID, dup.UserID = article.ID, article.UserID
+			dup.SourceArticleID, dup.SourceUserID = candidate.ArticleID, candidate.UserID
+			matchedSources = append(matchedSources, candidate.ArticleID)
+		} else {
+			dup.ArticleID, dup.UserID = candidate.ArticleID, candidate.UserID
+			dup.SourceArticleID, dup.SourceUserID = article.ID, article.UserID
+		}
+
+		if err := s.duplicateRepo.CreateDuplicateIfAbsent(dup); err != nil {
+			return fmt.Errorf("记录疑似重复失败: %w", err)
+		}
+		s.articleRepo.UpdateFields(dup.ArticleID, map[string]interface{}{
+			"audit_status": model.AuditStatusPending,
+			"audit_reason": fmt.Sprintf("%s：与文章#%d相似度%.1f%%", duplicateAuditPrefix, dup.SourceArticleID, dup.Similarity),
+		})
+	}
+
+	// 5. 内容修改后不再相似的待处理记录自动排除
+	if err := s.duplicateRepo.DismissPendingByArticle(articleID, matchedSources, "内容修改后不再相似"); err != nil {
+		return err
+	}
+	s.clearAuditFlagIfResolved(article)
+
+	return nil
+}
+
+// ==================== 作者 ====================
+
+// GetArticleDuplicates 查看自己文章的疑似重复记录
+func (s *articleDuplicateService) GetArticleDuplicates(articleID uint64, userID string) ([]DuplicateItem, error) {
+	if !s.articleRepo.CheckOwnership(articleID, userID) {
+		return nil, constant.ErrPermissionDenied
+	}
+
+	dups, err := s.duplicateRepo.FindDuplicatesByArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := s.articleTitles(dups)
+	items := make([]DuplicateItem, 0, len(dups))
+	for _, dup := range dups {
+		items = append(items, DuplicateItem{
+			ArticleDuplicate: dup,
+			ArticleTitle:     titles[dup.ArticleID],
+			SourceTitle:      titles[dup.SourceArticleID],
+		})
+	}
+	return items, nil
+}
+
+// ClaimOriginality 提交原创申诉
+func (s *articleDuplicateService) ClaimOriginality(articleID uint64, userID string, req *OriginalityClaimRequest) (*model.ArticleOriginalityClaim, error) {
+	// 1. 权限与状态检查
+	if !s.articleRepo.CheckOwnership(articleID, userID) {
+		return nil, constant.ErrPermissionDenied
+	}
+	pending, err := s.duplicateRepo.CountPendingByArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if pending == 0 {
+		return nil, fmt.Errorf("文章没有待处理的疑似重复记录")
+	}
+	if existing, err := s.duplicateRepo.FindPendingClaimByArticle(articleID); err == nil && existing != nil {
+		return nil, fmt.Errorf("已有待审核的原创申诉")
+	}
+
+	// 2. 创建申诉
+	claim := &model.ArticleOriginalityClaim{
+		ArticleID: articleID,
+		UserID:    userID,
+		Statement: strings.TrimSpace(req.Statement),
+		Evidence:  model.JSONStringList(req.Evidence),
+		Status:    model.AuditStatusPending,
+	}
+	if err := s.duplicateRepo.CreateClaim(claim); err != nil {
+		return nil, fmt.Errorf("提交原创申诉失败: %w", err)
+	}
+	return claim, nil
+}
+
+// ==================== 管理员 ====================
+
+// GetDuplicateClusters 疑似重复聚类报告（按连通关系将成对记录合并为簇，规模大的在前）
+func (s *articleDuplicateService) GetDuplicateClusters(status int8, limit int) ([]DuplicateCluster, error) {
+	if limit < 1 || limit > 5000 {
+		limit = 1000
+	}
+	dups, err := s.duplicateRepo.FindDuplicatesByStatus(status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 并查集合并
+	parent := make(map[uint64]uint64)
+	var find func(id uint64) uint64
+	find = func(id uint64) uint64 {
+		if p, ok := parent[id]; ok && p != id {
+			parent[id] = find(p)
+			return parent[id]
+		}
+		parent[id] = id
+		return id
+	}
+	for _, dup := range dups {
+		a, b := find(dup.ArticleID), find(dup.SourceArticleID)
+		if a != b {
+			parent[a] = b
+		}
+	}
+
+	// 2. 按根分组
+	groups := make(map[uint64]*DuplicateCluster)
+	members := make(map[uint64]map[uint64]bool)
+	for _, dup := range dups {
+		root := find(dup.ArticleID)
+		if groups[root] == nil {
+			groups[root] = &DuplicateCluster{}
+			members[root] = make(map[uint64]bool)
+		}
+		groups[root].Pairs = append(groups[root].Pairs, dup)
+		members[root][dup.ArticleID] = true
+		members[root][dup.SourceArticleID] = true
+	}
+
+	// 3. 补充文章信息
+	ids := make([]uint64, 0, len(parent))
+	for id := range parent {
+		ids = append(ids, id)
+	}
+	articles := s.loadArticles(ids)
+
+	clusters := make([]DuplicateCluster, 0, len(groups))
+	for root, cluster := range groups {
+		for id := range members[root] {
+			member := DuplicateClusterMember{ArticleID: id}
+			if article, ok := articles[id]; ok {
+				member.UserID = article.UserID
+				member.Title = article.Title
+				member.Status = article.Status
+				member.CreateTime = article.CreateTime
+			}
+			cluster.Articles = append(cluster.Articles, member)
+		}
+		sort.Slice(cluster.Articles, func(i, j int) bool {
+			return cluster.Articles[i].CreateTime.Before(cluster.Articles[j].CreateTime)
+		})
+		cluster.OriginalArticleID = cluster.Articles[0].ArticleID
+		clusters = append(clusters, *cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Articles) != len(clusters[j].Articles) {
+			return len(clusters[i].Articles) > len(clusters[j].Articles)
+		}
+		return clusters[i].OriginalArticleID < clusters[j].OriginalArticleID
+	})
+
+	return clusters, nil
+}
+
+// ResolveDuplicate 处理疑似重复
+func (s *articleDuplicateService) ResolveDuplicate(duplicateID uint64, adminID string, req *ResolveDuplicateRequest) error {
+	dup, err := s.duplicateRepo.FindDuplicateByID(duplicateID)
+	if err != nil {
+		return fmt.Errorf("记录不存在")
+	}
+	if dup.Status != model.DuplicateStatusPending {
+		return fmt.Errorf("该记录已处理")
+	}
+
+	now := time.Now()
+	status := int8(model.DuplicateStatusDismissed)
+	if req.Confirm {
+		status = model.DuplicateStatusConfirmed
+	}
+	if err := s.duplicateRepo.UpdateDuplicate(duplicateID, map[string]interface{}{
+		"status":      status,
+		"handler_id":  adminID,
+		"handle_note": req.Note,
+		"handle_time": &now,
+	}); err != nil {
+		return fmt.Errorf("处理失败: %w", err)
+	}
+
+	// 确认重复：疑似文章审核驳回并下线
+	if req.Confirm {
+		return s.articleRepo.UpdateFields(dup.ArticleID, map[string]interface{}{
+			"status":        model.ArticleV3StatusAuditFailed,
+			"audit_status":  model.AuditStatusRejected,
+			"audit_reason":  fmt.Sprintf("与文章#%d重复", dup.SourceArticleID),
+			"audit_user_id": adminID,
+			"audit_time":    &now,
+		})
+	}
+
+	if article, err := s.articleRepo.FindByID(dup.ArticleID); err == nil {
+		s.clearAuditFlagIfResolved(article)
+	}
+	return nil
+}
+
+// GetOriginalityClaims 原创申诉列表
+func (s *articleDuplicateService) GetOriginalityClaims(status int8, page, pageSize int) ([]model.ArticleOriginalityClaim, int64, error) {
+	return s.duplicateRepo.FindClaims(status, page, pageSize)
+}
+
+// ReviewOriginalityClaim 审核原创申诉
+func (s *articleDuplicateService) ReviewOriginalityClaim(claimID uint64, adminID string, req *ReviewClaimRequest) error {
+	claim, err := s.duplicateRepo.FindClaimByID(claimID)
+	if err != nil {
+		return fmt.Errorf("申诉不存在")
+	}
+	if claim.Status != model.AuditStatusPending {
+		return fmt.Errorf("该申诉已审核")
+	}
+
+	now := time.Now()
+	status := int8(model.AuditStatusRejected)
+	if req.Approve {
+		status = model.AuditStatusApproved
+	}
+	if err := s.duplicateRepo.UpdateClaim(claimID, map[string]interface{}{
+		"status":      status,
+		"reviewer_id": adminID,
+		"review_note": req.Note,
+		"review_time": &now,
+	}); err != nil {
+		return fmt.Errorf("审核失败: %w", err)
+	}
+	if !req.Approve {
+		return nil
+	}
+
+	// 申诉通过：排除该文章全部待处理记录并恢复审核状态
+	if err := s.duplicateRepo.DismissPendingByArticle(claim.ArticleID, nil, "原创申诉通过"); err != nil {
+		return err
+	}
+	return s.articleRepo.UpdateFields(claim.ArticleID, map[string]interface{}{
+		"audit_status":  model.AuditStatusApproved,
+		"audit_reason":  "",
+		"audit_user_id": adminID,
+		"audit_time":    &now,
+	})
+}
+
+// ==================== 辅助方法 ====================
+
+// clearAuditFlagIfResolved 疑似重复记录全部处理完后清除查重标记
+func (s *articleDuplicateService) clearAuditFlagIfResolved(article *model.ArticleV3) {
+	if !strings.HasPrefix(article.AuditReason, duplicateAuditPrefix) {
+		return
+	}
+	if pending, err := s.duplicateRepo.CountPendingByArticle(article.ID); err != nil || pending > 0 {
+		return
+	}
+	s.articleRepo.UpdateFields(article.ID, map[string]interface{}{"audit_reason": ""})
+}
+
+// articleTitles 查询记录涉及的文章标题
+func (s *articleDuplicateService) articleTitles(dups []model.ArticleDuplicate) map[uint64]string {
+	ids := make([]uint64, 0, len(dups)*2)
+	for _, dup := range dups {
+		ids = append(ids, dup.ArticleID, dup.SourceArticleID)
+	}
+
+	titles := make(map[uint64]string)
+	for id, article := range s.loadArticles(ids) {
+		titles[id] = article.Title
+	}
+	return titles
+}
+
+// loadArticles 批量加载文章简要信息（含已下线的文章）
+func (s *articleDuplicateService) loadArticles(ids []uint64) map[uint64]*model.ArticleV3 {
+	articles := make(map[uint64]*model.ArticleV3, len(ids))
+	list, err := s.duplicateRepo.FindArticleBriefs(ids)
+	if err != nil {
+		return articles
+	}
+	for i := range list {
+		articles[list[i].ID] = &list[i]
+	}
+	return articles
+}
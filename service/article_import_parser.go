@@ -0,0 +1,486 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ==================== 导入解析（Markdown / Hexo / Hugo / WordPress WXR） ====================
+
+// importedArticle 解析后的待导入文章
+type importedArticle struct {
+	Source     string     // 来源文件路径（用于结果展示和相对路径解析）
+	Title      string     // 标题
+	Content    string     // 正文（Markdown，WXR为HTML片段，Markdown可直接兼容）
+	Summary    string     // 摘要
+	CoverImage string     // 封面
+	Tags       []string   // 标签
+	Categories []string   // 分类名称
+	Date       *time.Time // 发布时间
+	Draft      bool       // 是否为草稿
+}
+
+// frontMatter Hexo/Hugo 的 YAML front matter
+type frontMatter struct {
+	Title       string      `yaml:"title"`
+	Date        interface{} `yaml:"date"`
+	Tags        interface{} `yaml:"tags"`
+	Categories  interface{} `yaml:"categories"`
+	Draft       bool        `yaml:"draft"`     // Hugo
+	Published   *bool       `yaml:"published"` // Hexo
+	Description string      `yaml:"description"`
+	Summary     string      `yaml:"summary"`
+	Excerpt     string      `yaml:"excerpt"`
+	Cover       string      `yaml:"cover"`
+	Thumbnail   string      `yaml:"thumbnail"`
+	Image       string      `yaml:"image"`
+}
+
+var (
+	// markdownImagePattern 匹配 ![alt](path "title")
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	// htmlImagePattern 匹配 <img src="path">
+	htmlImagePattern = regexp.MustCompile(`<img[^>]+src=["']([^"']+)["']`)
+	// moreSeparator Hexo 摘要分隔符
+	moreSeparator = "<!-- more -->"
+)
+
+// dateLayouts front matter / WXR 中常见的日期格式
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ==================== Markdown 压缩包 ====================
+
+// parseMarkdownBundle 解析Markdown压缩包中的所有文章（读取的解压大小计入budget）
+func parseMarkdownBundle(zr *zip.Reader, budget *zipReadBudget) ([]importedArticle, []importFailure) {
+	var articles []importedArticle
+	var failures []importFailure
+
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || isHiddenPath(file.Name) {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(file.Name))
+		if ext != ".md" && ext != ".markdown" {
+			continue
+		}
+
+		data, err := budget.read(file)
+		if err != nil {
+			failures = append(failures, importFailure{Source: file.Name, Error: fmt.Sprintf("读取文件失败: %v", err)})
+			continue
+		}
+
+		article, err := parseMarkdownFile(file.Name, data)
+		if err != nil {
+			failures = append(failures, importFailure{Source: file.Name, Error: err.Error()})
+			continue
+		}
+		articles = append(articles, *article)
+	}
+
+	return articles, failures
+}
+
+// parseMarkdownFile 解析单个Markdown文件（含可选的YAML front matter）
+func parseMarkdownFile(name string, data []byte) (*importedArticle, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	article := &importedArticle{Source: name}
+
+	// 1. 拆分 front matter
+	var fm frontMatter
+	if strings.HasPrefix(text, "---\n") {
+		lines := strings.Split(text, "\n")
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimRight(lines[i], " \t") == "---" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("front matter 未闭合")
+		}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), &fm); err != nil {
+			return nil, fmt.Errorf("front matter 解析失败: %w", err)
+		}
+		text = strings.Join(lines[end+1:], "\n")
+	}
+	body := strings.TrimSpace(text)
+
+	// 2. 映射字段
+	article.Title = strings.TrimSpace(fm.Title)
+	if article.Title == "" {
+		article.Title = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+	article.Tags = flattenStrings(fm.Tags)
+	article.Categories = flattenStrings(fm.Categories)
+	article.Date = parseFlexibleDate(fm.Date)
+	article.Draft = fm.Draft || (fm.Published != nil && !*fm.Published)
+	article.CoverImage = firstNonEmpty(fm.Cover, fm.Thumbnail, fm.Image)
+	article.Summary = firstNonEmpty(fm.Summary, fm.Description, fm.Excerpt)
+
+	// 3. Hexo <!-- more --> 之前的内容作为摘要
+	if idx := strings.Index(body, moreSeparator); idx >= 0 {
+		if article.Summary == "" {
+			article.Summary = strings.TrimSpace(body[:idx])
+		}
+		body = strings.Replace(body, moreSeparator, "", 1)
+	}
+	article.Summary = truncateRunes(article.Summary, 500)
+	article.Content = body
+
+	return article, nil
+}
+
+// ==================== WordPress WXR ====================
+
+// wxrDocument WordPress WXR导出文件结构（字段按本地名匹配，兼容WXR 1.0~1.2）
+type wxrDocument struct {
+	Channel struct {
+		Items []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title      string        `xml:"title"`
+	PubDate    string        `xml:"pubDate"`
+	PostDate   string        `xml:"post_date"`
+	PostType   string        `xml:"post_type"`
+	Status     string        `xml:"status"`
+	PostName   string        `xml:"post_name"`
+	Encoded    []wxrEncoded  `xml:"encoded"`
+	Categories []wxrCategory `xml:"category"`
+}
+
+// wxrEncoded content:encoded 与 excerpt:encoded 同名，通过命名空间区分
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"`
+	Name   string `xml:",chardata"`
+}
+
+// parseWXR 解析WordPress WXR导出文件（仅导入文章，忽略页面和附件）
+func parseWXR(name string, data []byte) ([]importedArticle, []importFailure, error) {
+	var doc wxrDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("WXR解析失败: %w", err)
+	}
+
+	var articles []importedArticle
+	var failures []importFailure
+
+	for i, item := range doc.Channel.Items {
+		if item.PostType != "" && item.PostType != "post" {
+			continue
+		}
+		// 回收站中的文章不导入
+		if item.Status == "trash" || item.Status == "auto-draft" {
+			continue
+		}
+
+		source := fmt.Sprintf("%s#%d", name, i+1)
+		if item.PostName != "" {
+			source = fmt.Sprintf("%s#%s", name, item.PostName)
+		}
+
+		article := importedArticle{
+			Source: source,
+			Title:  strings.TrimSpace(item.Title),
+			Draft:  item.Status != "publish",
+		}
+
+		for _, enc := range item.Encoded {
+			switch {
+			case strings.Contains(enc.XMLName.Space, "excerpt"):
+				article.Summary = truncateRunes(strings.TrimSpace(enc.Value), 500)
+			case strings.Contains(enc.XMLName.Space, "content"):
+				article.Content = strings.TrimSpace(enc.Value)
+			}
+		}
+		if article.Title == "" {
+			failures = append(failures, importFailure{Source: source, Error: "缺少标题"})
+			continue
+		}
+
+		for _, category := range item.Categories {
+			name := strings.TrimSpace(category.Name)
+			if name == "" {
+				continue
+			}
+			switch category.Domain {
+			case "post_tag":
+				article.Tags = append(article.Tags, name)
+			case "category":
+				if name != "Uncategorized" && name != "未分类" {
+					article.Categories = append(article.Categories, name)
+				}
+			}
+		}
+
+		// 发布时间：优先wp:post_date（站点时区），其次pubDate
+		article.Date = parseFlexibleDate(item.PostDate)
+		if article.Date == nil {
+			article.Date = parseFlexibleDate(item.PubDate)
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles, failures, nil
+}
+
+// ==================== 本地图片解析 ====================
+
+// bundleImageResolver 在导入包内查找文章引用的本地图片
+type bundleImageResolver struct {
+	files map[string]*zip.File // 规范化路径 -> 文件
+}
+
+// newBundleImageResolver 建立压缩包图片索引
+func newBundleImageResolver(zr *zip.Reader) *bundleImageResolver {
+	resolver := &bundleImageResolver{files: make(map[string]*zip.File)}
+	if zr == nil {
+		return resolver
+	}
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || !isImageFile(file.Name) {
+			continue
+		}
+		resolver.files[path.Clean(file.Name)] = file
+	}
+	return resolver
+}
+
+// resolve 将文章中的图片引用解析为包内文件，找不到返回nil
+func (r *bundleImageResolver) resolve(source, ref string) *zip.File {
+	if len(r.files) == 0 {
+		return nil
+	}
+
+	// 1. 远程地址：仅尝试匹配WordPress上传目录（wp-content/uploads/...）
+	if isRemoteRef(ref) {
+		idx := strings.Index(ref, "wp-content/uploads/")
+		if idx < 0 {
+			return nil
+		}
+		return r.findBySuffix(strings.SplitN(ref[idx:], "?", 2)[0])
+	}
+
+	// 2. 本地路径：去掉查询串和锚点并解码
+	ref = strings.SplitN(strings.SplitN(ref, "?", 2)[0], "#", 2)[0]
+	if decoded, err := url.PathUnescape(ref); err == nil {
+		ref = decoded
+	}
+
+	dir := path.Dir(source)
+	base := strings.TrimSuffix(path.Base(source), path.Ext(source))
+	trimmed := strings.TrimPrefix(ref, "/")
+
+	candidates := []string{
+		path.Join(dir, ref),           // 相对文章所在目录
+		path.Join(dir, base, ref),     // Hexo 资源文件夹（post_asset_folder）
+		trimmed,                       // 相对压缩包根目录
+		path.Join("source", trimmed),  // Hexo source 目录
+		path.Join("static", trimmed),  // Hugo static 目录
+		path.Join(dir, "images", ref), // 常见 images 子目录
+		path.Join(path.Dir(dir), ref), // 上一级目录
+		path.Join("assets", trimmed),  // 常见 assets 目录
+		path.Join("source", "_posts", trimmed),
+	}
+	for _, candidate := range candidates {
+		if file, ok := r.files[path.Clean(candidate)]; ok {
+			return file
+		}
+	}
+
+	// 3. 兜底：压缩包可能多包了一层顶级目录
+	return r.findBySuffix(trimmed)
+}
+
+// findBySuffix 按路径后缀查找
+func (r *bundleImageResolver) findBySuffix(suffix string) *zip.File {
+	suffix = path.Clean(suffix)
+	for name, file := range r.files {
+		if name == suffix || strings.HasSuffix(name, "/"+suffix) {
+			return file
+		}
+	}
+	return nil
+}
+
+// collectImageRefs 收集正文中引用的图片地址（去重）
+func collectImageRefs(content string) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, pattern := range []*regexp.Regexp{markdownImagePattern, htmlImagePattern} {
+		for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+			ref := match[1]
+			if seen[ref] || strings.HasPrefix(ref, "data:") {
+				continue
+			}
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// ==================== 辅助函数 ====================
+
+// importFailure 单篇导入失败记录
+type importFailure struct {
+	Source string
+	Title  string
+	Error  string
+}
+
+// readZipFile 读取压缩包内文件，解压后超过limit字节时返回错误
+// 不能只信任目录中记录的 UncompressedSize64，实际读取同样限制长度
+func readZipFile(file *zip.File, limit int64) ([]byte, error) {
+	if file.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("文件解压后超过%dMB", limit/1024/1024)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("文件解压后超过%dMB", limit/1024/1024)
+	}
+	return data, nil
+}
+
+// findWXRFile 在压缩包中查找WXR导出文件
+func findWXRFile(zr *zip.Reader) (*zip.File, bool) {
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || isHiddenPath(file.Name) {
+			continue
+		}
+		if strings.ToLower(path.Ext(file.Name)) != ".xml" {
+			continue
+		}
+		head := make([]byte, 2048)
+		rc, err := file.Open()
+		if err != nil {
+			continue
+		}
+		n, _ := io.ReadFull(rc, head)
+		rc.Close()
+		if isWXR(head[:n]) {
+			return file, true
+		}
+	}
+	return nil, false
+}
+
+// isWXR 判断XML内容是否为WordPress导出文件
+func isWXR(head []byte) bool {
+	return bytes.Contains(head, []byte("wordpress.org/export/"))
+}
+
+// isHiddenPath 忽略 __MACOSX、.DS_Store 等系统文件
+func isHiddenPath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
+
+// isRemoteRef 是否为远程地址
+func isRemoteRef(ref string) bool {
+	lower := strings.ToLower(ref)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "//")
+}
+
+// flattenStrings 将字符串、列表或嵌套列表（Hexo多级分类）展开为字符串数组
+func flattenStrings(value interface{}) []string {
+	var result []string
+	switch v := value.(type) {
+	case string:
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			result = append(result, flattenStrings(item)...)
+		}
+	case nil:
+	default:
+		if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// parseFlexibleDate 解析多种格式的日期
+func parseFlexibleDate(value interface{}) *time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return &v
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" || strings.HasPrefix(v, "0000-00-00") {
+			return nil
+		}
+		for _, layout := range dateLayouts {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return &t
+			}
+		}
+	}
+	return nil
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// truncateRunes 按字符截断
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
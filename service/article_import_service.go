@@ -0,0 +1,616 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	minioPkg "astronomer-gin/pkg/minio"
+	"astronomer-gin/pkg/queue"
+	"astronomer-gin/repository"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ArticleImportService 文章导入导出服务接口
+type ArticleImportService interface {
+	// 创建导入任务（上传导入包并投递后台任务）
+	CreateImportJob(ctx context.Context, userID string, file *multipart.FileHeader, asDraft bool) (*model.ArticleImportJob, error)
+	// 获取导入任务进度
+	GetImportJob(jobID uint64, userID string) (*model.ArticleImportJob, error)
+	// 获取用户的导入任务列表
+	GetUserImportJobs(userID string, page, pageSize int) ([]model.ArticleImportJob, int64, error)
+	// 执行导入任务（由Worker调用）
+	RunImportJob(ctx context.Context, jobID uint64) error
+	// 导出用户全部文章为Markdown压缩包
+	ExportUserArticles(userID string, w io.Writer) error
+}
+
+// importResultFlushInterval 每导入N篇持久化一次逐篇结果
+const importResultFlushInterval = 10
+
+type articleImportService struct {
+	importRepo     repository.ArticleImportRepository
+	articleRepo    repository.ArticleV3Repository
+	articleService ArticleV3Service
+}
+
+// NewArticleImportService 创建ArticleImportService实例
+func NewArticleImportService(
+	importRepo repository.ArticleImportRepository,
+	articleRepo repository.ArticleV3Repository,
+	articleService ArticleV3Service,
+) ArticleImportService {
+	return &articleImportService{
+		importRepo:     importRepo,
+		articleRepo:    articleRepo,
+		articleService: articleService,
+	}
+}
+
+// ==================== 导入任务管理 ====================
+
+// CreateImportJob 创建导入任务
+func (s *articleImportService) CreateImportJob(ctx context.Context, userID string, file *multipart.FileHeader, asDraft bool) (*model.ArticleImportJob, error) {
+	// 1. 参数验证
+	if file.Size > constant.MaxImportFileSize {
+		return nil, constant.ErrFileSizeExceeded
+	}
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".zip" && ext != ".xml" {
+		return nil, fmt.Errorf("仅支持 .zip（Markdown/WXR）或 .xml（WordPress WXR）文件")
+	}
+
+	// 2. 读取文件并识别来源类型
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	sourceType, err := detectImportSource(ext, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 上传导入包到对象存储（Worker从对象存储读取）
+	objectName := minioPkg.GenerateObjectPath("imports", generateUniqueFilename(file.Filename))
+	if _, err := minioPkg.Client.UploadFile(ctx, objectName, bytes.NewReader(data), int64(len(data)), getContentType(file.Filename)); err != nil {
+		return nil, constant.ErrUploadFailed
+	}
+
+	// 4. 创建任务记录
+	job := &model.ArticleImportJob{
+		UserID:     userID,
+		SourceType: sourceType,
+		FileName:   file.Filename,
+		ObjectName: objectName,
+		AsDraft:    asDraft,
+		Status:     model.ImportStatusPending,
+		Results:    model.JSONArray{},
+	}
+	if err := s.importRepo.Create(job); err != nil {
+		return nil, fmt.Errorf("创建导入任务失败: %w", err)
+	}
+
+	// 5. 投递后台任务（队列不可用时降级为本地协程执行）
+	if queue.Client != nil {
+		task := queue.CreateTask(queue.TaskTypeImport, map[string]interface{}{
+			"job_id": job.ID,
+		})
+		err := queue.Client.PublishTask(ctx, task)
+		if err == nil {
+			return job, nil
+		}
+		log.Printf("⚠️ 投递导入任务失败，改为本地执行: job=%d, %v", job.ID, err)
+	}
+	go func(jobID uint64) {
+		if err := s.RunImportJob(context.Background(), jobID); err != nil {
+			log.Printf("❌ 导入任务执行失败: job=%d, %v", jobID, err)
+		}
+	}(job.ID)
+
+	return job, nil
+}
+
+// GetImportJob 获取导入任务进度
+func (s *articleImportService) GetImportJob(jobID uint64, userID string) (*model.ArticleImportJob, error) {
+	job, err := s.importRepo.FindByID(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("导入任务不存在")
+	}
+	if job.UserID != userID {
+		return nil, constant.ErrPermissionDenied
+	}
+	return job, nil
+}
+
+// GetUserImportJobs 获取用户的导入任务列表
+func (s *articleImportService) GetUserImportJobs(userID string, page, pageSize int) ([]model.ArticleImportJob, int64, error) {
+	return s.importRepo.FindByUserID(userID, page, pageSize)
+}
+
+// ==================== 导入执行 ====================
+
+// RunImportJob 执行导入任务
+func (s *articleImportService) RunImportJob(ctx context.Context, jobID uint64) error {
+	// 1. 获取任务（重复投递时跳过）
+	job, err := s.importRepo.FindByID(jobID)
+	if err != nil {
+		return fmt.Errorf("导入任务不存在: %w", err)
+	}
+	if job.Status != model.ImportStatusPending {
+		log.Printf("ℹ️ 导入任务已处理，跳过: job=%d status=%d", jobID, job.Status)
+		return nil
+	}
+
+	now := time.Now()
+	s.importRepo.UpdateFields(jobID, map[string]interface{}{
+		"status":     model.ImportStatusRunning,
+		"start_time": &now,
+	})
+
+	// 2. 下载并解析导入包
+	articles, failures, resolver, err := s.loadImportBundle(ctx, job)
+	if err != nil {
+		s.failJob(jobID, err)
+		return err
+	}
+
+	// 3. 初始化进度
+	results := make(model.JSONArray, 0, len(articles)+len(failures))
+	for _, failure := range failures {
+		results = append(results, importResult(failure.Source, failure.Title, "", 0, failure.Error))
+	}
+	progress := map[string]interface{}{
+		"total":         len(articles) + len(failures),
+		"processed":     len(failures),
+		"success_count": 0,
+		"fail_count":    len(failures),
+		"results":       results,
+	}
+	s.importRepo.UpdateFields(jobID, progress)
+
+	// 4. 逐篇导入
+	categories := s.loadCategoryIndex()
+	uploaded := make(map[string]string) // 包内图片路径 -> 对象存储URL
+	successCount, failCount := 0, len(failures)
+
+	for i := range articles {
+		kind, id, err := s.importOne(ctx, job, &articles[i], resolver, categories, uploaded)
+		if err != nil {
+			failCount++
+			results = append(results, importResult(articles[i].Source, articles[i].Title, "", 0, err.Error()))
+		} else {
+			successCount++
+			results = append(results, importResult(articles[i].Source, articles[i].Title, kind, id, ""))
+		}
+
+		progress = map[string]interface{}{
+			"processed":     len(failures) + i + 1,
+			"success_count": successCount,
+			"fail_count":    failCount,
+		}
+		if (i+1)%importResultFlushInterval == 0 {
+			progress["results"] = results
+		}
+		s.importRepo.UpdateFields(jobID, progress)
+	}
+
+	// 5. 完成任务并清理导入包
+	finish := time.Now()
+	s.importRepo.UpdateFields(jobID, map[string]interface{}{
+		"status":      model.ImportStatusFinished,
+		"results":     results,
+		"finish_time": &finish,
+	})
+	if err := minioPkg.Client.DeleteFile(ctx, job.ObjectName); err != nil {
+		log.Printf("⚠️ 清理导入包失败: %s, %v", job.ObjectName, err)
+	}
+
+	log.Printf("✅ 导入任务完成: job=%d 成功=%d 失败=%d", jobID, successCount, failCount)
+	return nil
+}
+
+// loadImportBundle 下载导入包并解析出待导入文章
+func (s *articleImportService) loadImportBundle(ctx context.Context, job *model.ArticleImportJob) ([]importedArticle, []importFailure, *bundleImageResolver, error) {
+	data, err := minioPkg.Client.DownloadFile(ctx, job.ObjectName)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("读取导入包失败: %w", err)
+	}
+
+	// 单独的WXR文件（无本地图片）
+	if strings.ToLower(filepath.Ext(job.FileName)) == ".xml" {
+		articles, failures, err := parseWXR(job.FileName, data)
+		return articles, failures, newBundleImageResolver(nil), err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("无效的压缩包: %w", err)
+	}
+	resolver := newBundleImageResolver(zr)
+	budget := newZipReadBudget()
+
+	if job.SourceType == model.ImportSourceWXR {
+		file, ok := findWXRFile(zr)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("压缩包中未找到WordPress导出文件")
+		}
+		xmlData, err := budget.read(file)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("读取WXR文件失败: %w", err)
+		}
+		articles, failures, err := parseWXR(file.Name, xmlData)
+		return articles, failures, resolver, err
+	}
+
+	articles, failures := parseMarkdownBundle(zr, budget)
+	return articles, failures, resolver, nil
+}
+
+// importOne 导入单篇文章，返回创建的对象类型（article/draft）与ID
+func (s *articleImportService) importOne(
+	ctx context.Context,
+	job *model.ArticleImportJob,
+	article *importedArticle,
+	resolver *bundleImageResolver,
+	categories map[string]uint64,
+	uploaded map[string]string,
+) (string, uint64, error) {
+	// 1. 上传包内引用的本地图片并改写链接
+	mapImage := func(ref string) string {
+		file := resolver.resolve(article.Source, ref)
+		if file == nil {
+			return ref
+		}
+		if url, ok := uploaded[file.Name]; ok {
+			return url
+		}
+		url, err := uploadBundleImage(ctx, file)
+		if err != nil {
+			log.Printf("⚠️ 导入图片上传失败: %s, %v", file.Name, err)
+			return ref
+		}
+		uploaded[file.Name] = url
+		return url
+	}
+	content := rewriteImageRefs(article.Content, mapImage)
+	coverImage := article.CoverImage
+	if coverImage != "" {
+		coverImage = mapImage(coverImage)
+	}
+
+	// 2. 映射分类（匹配已有分类，未匹配的并入标签）
+	var categoryID uint64
+	tags := append([]string{}, article.Tags...)
+	for _, name := range article.Categories {
+		if id, ok := categories[strings.ToLower(name)]; ok && categoryID == 0 {
+			categoryID = id
+			continue
+		}
+		tags = append(tags, name)
+	}
+	tags = dedupeStrings(tags)
+
+	// 3. 草稿
+	if job.AsDraft || article.Draft {
+		draft, err := s.articleService.SaveDraft(job.UserID, &SaveDraftRequest{
+			Title:      article.Title,
+			Summary:    article.Summary,
+			Content:    content,
+			CoverImage: coverImage,
+			CategoryID: categoryID,
+			Tags:       tags,
+		})
+		if err != nil {
+			return "", 0, err
+		}
+		return "draft", draft.ID, nil
+	}
+
+	// 4. 直接发布
+	created, err := s.articleService.CreateArticle(job.UserID, &CreateArticleRequest{
+		Title:       article.Title,
+		Summary:     article.Summary,
+		Content:     content,
+		CoverImage:  coverImage,
+		ContentType: model.ArticleContentTypeText,
+		CategoryID:  categoryID,
+		Tags:        tags,
+		Visibility:  model.ArticleVisibilityPublic,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	// 5. 保留原始发布时间
+	if article.Date != nil {
+		s.articleRepo.UpdateFields(created.ID, map[string]interface{}{
+			"publish_time": article.Date,
+			"create_time":  *article.Date,
+		})
+	}
+
+	return "article", created.ID, nil
+}
+
+// failJob 标记任务失败
+func (s *articleImportService) failJob(jobID uint64, err error) {
+	now := time.Now()
+	s.importRepo.UpdateFields(jobID, map[string]interface{}{
+		"status":        model.ImportStatusFailed,
+		"error_message": truncateRunes(err.Error(), 500),
+		"finish_time":   &now,
+	})
+}
+
+// loadCategoryIndex 加载分类名称索引（小写名称 -> ID）
+func (s *articleImportService) loadCategoryIndex() map[string]uint64 {
+	index := make(map[string]uint64)
+	categories, err := s.articleRepo.FindAllCategories()
+	if err != nil {
+		return index
+	}
+	for _, category := range categories {
+		index[strings.ToLower(category.Name)] = category.ID
+	}
+	return index
+}
+
+// ==================== 导出 ====================
+
+// exportFrontMatter 导出文件的 front matter（Hexo/Hugo 通用字段）
+type exportFrontMatter struct {
+	Title      string   `yaml:"title"`
+	Date       string   `yaml:"date"`
+	Updated    string   `yaml:"updated,omitempty"`
+	Tags       []string `yaml:"tags,omitempty"`
+	Categories []string `yaml:"categories,omitempty"`
+	Summary    string   `yaml:"summary,omitempty"`
+	Cover      string   `yaml:"cover,omitempty"`
+	Draft      bool     `yaml:"draft,omitempty"`
+}
+
+// ExportUserArticles 导出用户全部文章（posts/）与草稿（drafts/）为Markdown压缩包
+func (s *articleImportService) ExportUserArticles(userID string, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	// 1. 分类名称
+	categoryNames := make(map[uint64]string)
+	if categories, err := s.articleRepo.FindAllCategories(); err == nil {
+		for _, category := range categories {
+			categoryNames[category.ID] = category.Name
+		}
+	}
+
+	// 2. 文章
+	const batchSize = 100
+	for page := 1; ; page++ {
+		articles, _, err := s.articleRepo.FindByUserID(userID, page, batchSize, 0)
+		if err != nil {
+			return fmt.Errorf("查询文章失败: %w", err)
+		}
+
+		for _, article := range articles {
+			content, err := s.articleRepo.FindContentByArticleID(article.ID)
+			if err != nil {
+				log.Printf("⚠️ 导出文章内容缺失: article=%d, %v", article.ID, err)
+				continue
+			}
+
+			date := article.CreateTime
+			if article.PublishTime != nil {
+				date = *article.PublishTime
+			}
+			fm := exportFrontMatter{
+				Title:   article.Title,
+				Date:    date.Format("2006-01-02 15:04:05"),
+				Updated: article.UpdateTime.Format("2006-01-02 15:04:05"),
+				Tags:    article.Tags,
+				Summary: article.Summary,
+				Cover:   article.CoverImage,
+				Draft:   article.Status != model.ArticleV3StatusPublished,
+			}
+			if name, ok := categoryNames[article.CategoryID]; ok {
+				fm.Categories = []string{name}
+			}
+
+			name := fmt.Sprintf("posts/%s-%d-%s.md", date.Format("2006-01-02"), article.ID, exportFileSlug(article.Title))
+			if err := writeMarkdownEntry(zw, name, &fm, content.Content); err != nil {
+				return err
+			}
+		}
+
+		if len(articles) < batchSize {
+			break
+		}
+	}
+
+	// 3. 草稿
+	for page := 1; ; page++ {
+		drafts, _, err := s.articleRepo.FindUserDrafts(userID, page, batchSize)
+		if err != nil {
+			return fmt.Errorf("查询草稿失败: %w", err)
+		}
+
+		for _, draft := range drafts {
+			fm := exportFrontMatter{
+				Title:   draft.Title,
+				Date:    draft.CreateTime.Format("2006-01-02 15:04:05"),
+				Updated: draft.UpdateTime.Format("2006-01-02 15:04:05"),
+				Tags:    draft.Tags,
+				Summary: draft.Summary,
+				Cover:   draft.CoverImage,
+				Draft:   true,
+			}
+			if name, ok := categoryNames[draft.CategoryID]; ok {
+				fm.Categories = []string{name}
+			}
+
+			name := fmt.Sprintf("drafts/%d-%s.md", draft.ID, exportFileSlug(draft.Title))
+			if err := writeMarkdownEntry(zw, name, &fm, draft.Content); err != nil {
+				return err
+			}
+		}
+
+		if len(drafts) < batchSize {
+			break
+		}
+	}
+
+	return zw.Close()
+}
+
+// ==================== 辅助函数 ====================
+
+// detectImportSource 识别导入包类型
+func detectImportSource(ext string, data []byte) (int8, error) {
+	if ext == ".xml" {
+		if !isWXR(data[:min(len(data), 2048)]) {
+			return 0, fmt.Errorf("不是有效的WordPress导出文件")
+		}
+		return model.ImportSourceWXR, nil
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return 0, fmt.Errorf("无效的压缩包: %w", err)
+	}
+	if _, ok := findWXRFile(zr); ok {
+		return model.ImportSourceWXR, nil
+	}
+	return model.ImportSourceMarkdown, nil
+}
+
+// zipReadBudget 一次导入中Markdown/WXR文件的解压总量限制
+type zipReadBudget struct {
+	remaining int64
+}
+
+func newZipReadBudget() *zipReadBudget {
+	return &zipReadBudget{remaining: constant.MaxImportTotalSize}
+}
+
+// read 读取文件并扣减剩余额度（单个文件同时受 MaxImportEntrySize 限制）
+func (b *zipReadBudget) read(file *zip.File) ([]byte, error) {
+	if b.remaining <= 0 || file.UncompressedSize64 > uint64(b.remaining) {
+		return nil, fmt.Errorf("导入包解压后超过%dMB", constant.MaxImportTotalSize/1024/1024)
+	}
+	limit := int64(constant.MaxImportEntrySize)
+	if b.remaining < limit {
+		limit = b.remaining
+	}
+	data, err := readZipFile(file, limit)
+	if err != nil {
+		return nil, err
+	}
+	b.remaining -= int64(len(data))
+	return data, nil
+}
+
+// uploadBundleImage 将导入包中的图片上传到对象存储
+func uploadBundleImage(ctx context.Context, file *zip.File) (string, error) {
+	if file.UncompressedSize64 > constant.MaxImageSize {
+		return "", constant.ErrImageSizeExceeded
+	}
+	data, err := readZipFile(file, constant.MaxImageSize)
+	if err != nil {
+		return "", err
+	}
+	objectName := minioPkg.GenerateObjectPath("images", generateUniqueFilename(file.Name))
+	return minioPkg.Client.UploadFile(ctx, objectName, bytes.NewReader(data), int64(len(data)), getContentType(file.Name))
+}
+
+// rewriteImageRefs 改写Markdown与HTML中的图片地址
+func rewriteImageRefs(content string, mapper func(ref string) string) string {
+	for _, pattern := range []*regexp.Regexp{markdownImagePattern, htmlImagePattern} {
+		content = pattern.ReplaceAllStringFunc(content, func(match string) string {
+			sub := pattern.FindStringSubmatch(match)
+			if len(sub) < 2 || strings.HasPrefix(sub[1], "data:") {
+				return match
+			}
+			return strings.Replace(match, sub[1], mapper(sub[1]), 1)
+		})
+	}
+	return content
+}
+
+// writeMarkdownEntry 写入一篇带 front matter 的Markdown文件
+func writeMarkdownEntry(zw *zip.Writer, name string, fm *exportFrontMatter, body string) error {
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return fmt.Errorf("生成front matter失败: %w", err)
+	}
+
+	entry, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("写入压缩包失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(header)
+	buf.WriteString("---\n\n")
+	buf.WriteString(body)
+	buf.WriteString("\n")
+	_, err = entry.Write(buf.Bytes())
+	return err
+}
+
+// exportFileSlug 生成安全的文件名片段
+func exportFileSlug(title string) string {
+	replacer := strings.NewReplacer("/", "-", "\\", "-", ":", "-", "*", "", "?", "", "\"", "", "<", "", ">", "", "|", "", " ", "-")
+	slug := strings.Trim(replacer.Replace(strings.TrimSpace(title)), "-.")
+	slug = truncateRunes(slug, 60)
+	if slug == "" {
+		slug = "untitled"
+	}
+	return path.Clean(slug)
+}
+
+// importResult 构建逐篇导入结果
+func importResult(source, title, kind string, id uint64, errMsg string) map[string]interface{} {
+	result := map[string]interface{}{
+		"source": source,
+		"title":  title,
+	}
+	if errMsg != "" {
+		result["error"] = errMsg
+	} else {
+		result["type"] = kind
+		result["id"] = id
+	}
+	return result
+}
+
+// dedupeStrings 去重并保持顺序
+func dedupeStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		result = append(result, v)
+	}
+	return result
+}
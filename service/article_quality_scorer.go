@@ -0,0 +1,397 @@
+package service
+
+import (
+	"astronomer-gin/model"
+	"astronomer-gin/repository"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ==================== 可插拔评分器 ====================
+
+// QualityScorer 文章质量评分器
+// 默认使用本地启发式评分，后续接入模型时实现该接口并在构造 ArticleQualityService 时传入即可
+type QualityScorer interface {
+	// Name 评分器名称及版本（随评分结果一起存储，便于区分不同版本的分数）
+	Name() string
+	// Score 计算总分（0-100）与各维度明细
+	Score(input *QualityInput) (*QualityResult, error)
+}
+
+// QualityInput 评分输入
+type QualityInput struct {
+	Article        *model.ArticleV3
+	Content        *model.ArticleContent
+	AuthorHistory  *repository.AuthorQualityHistory
+	SensitiveWords []model.CommentSensitiveWord
+}
+
+// QualityResult 评分结果
+type QualityResult struct {
+	Score      float64
+	Components []model.QualityComponent
+}
+
+// ==================== 启发式评分器 ====================
+
+const heuristicScorerName = "heuristic-v1"
+
+// 各维度权重（合计为1）
+const (
+	qualityWeightLength      = 0.15
+	qualityWeightStructure   = 0.20
+	qualityWeightReadability = 0.15
+	qualityWeightLinks       = 0.10
+	qualityWeightSensitive   = 0.15
+	qualityWeightAuthor      = 0.10
+	qualityWeightEngagement  = 0.15
+)
+
+// qualityMinEngagementViews 早期互动维度的最小样本量（真人浏览数），不足时按中性分计
+const qualityMinEngagementViews = 30
+
+// qualityNeutralScore 数据不足时的中性分
+const qualityNeutralScore = 60.0
+
+var (
+	qualityHeadingRegex   = regexp.MustCompile(`(?m)^#{1,6}\s+\S`)
+	qualityCodeBlockRegex = regexp.MustCompile("(?s)```.*?```")
+	qualityImageRegex     = regexp.MustCompile(`!\[[^\]]*\]\([^)]+\)|<img\s`)
+	qualityLinkRegex      = regexp.MustCompile(`\]\((https?://[^)\s]+)\)|(?:^|[^(])(https?://[^\s)<>"]+)`)
+	qualityListRegex      = regexp.MustCompile(`(?m)^\s*(?:[-*+]|\d+\.)\s+\S`)
+	qualityMarkupRegex    = regexp.MustCompile("[#>*_`~|\\[\\]()!-]")
+	qualitySentenceRegex  = regexp.MustCompile(`[。！？!?；;]|\.\s`)
+)
+
+type heuristicQualityScorer struct{}
+
+// NewHeuristicQualityScorer 创建本地启发式评分器（不依赖外部服务，每个维度给出得分说明）
+func NewHeuristicQualityScorer() QualityScorer {
+	return &heuristicQualityScorer{}
+}
+
+func (h *heuristicQualityScorer) Name() string {
+	return heuristicScorerName
+}
+
+func (h *heuristicQualityScorer) Score(input *QualityInput) (*QualityResult, error) {
+	if input == nil || input.Article == nil || input.Content == nil {
+		return nil, fmt.Errorf("评分输入不完整")
+	}
+
+	content := input.Content.Content
+	// 正文（去除代码块与Markdown标记后的文字）用于长度、可读性与敏感词统计
+	prose := qualityMarkupRegex.ReplaceAllString(qualityCodeBlockRegex.ReplaceAllString(content, " "), " ")
+	proseLen := utf8.RuneCountInString(strings.Join(strings.Fields(prose), ""))
+
+	components := []model.QualityComponent{
+		h.scoreLength(proseLen),
+		h.scoreStructure(input.Content, content),
+		h.scoreReadability(prose),
+		h.scoreLinks(content, proseLen),
+		h.scoreSensitive(input.Article.Title+"\n"+prose, proseLen, input.SensitiveWords),
+		h.scoreAuthor(input.AuthorHistory),
+		h.scoreEngagement(input.Article),
+	}
+
+	total := 0.0
+	weights := 0.0
+	for _, c := range components {
+		total += c.Score * c.Weight
+		weights += c.Weight
+	}
+	score := 0.0
+	if weights > 0 {
+		score = roundScore(total / weights)
+	}
+
+	// 严重敏感词直接封顶，避免其他维度拉高总分
+	for _, c := range components {
+		if c.Key == "sensitive" && c.Score == 0 && score > 30 {
+			score = 30
+		}
+	}
+
+	return &QualityResult{Score: score, Components: components}, nil
+}
+
+// scoreLength 篇幅：800-6000字最佳，过短扣分明显，超长轻微扣分
+func (h *heuristicQualityScorer) scoreLength(proseLen int) model.QualityComponent {
+	var score float64
+	switch {
+	case proseLen < 100:
+		score = 10
+	case proseLen < 300:
+		score = 10 + float64(proseLen-100)/200*30
+	case proseLen < 800:
+		score = 40 + float64(proseLen-300)/500*60
+	case proseLen <= 6000:
+		score = 100
+	case proseLen <= 20000:
+		score = 100 - float64(proseLen-6000)/14000*30
+	default:
+		score = 70
+	}
+	return model.QualityComponent{
+		Key:    "length",
+		Name:   "篇幅",
+		Score:  roundScore(score),
+		Weight: qualityWeightLength,
+		Detail: fmt.Sprintf("正文约%d字（800-6000字最佳）", proseLen),
+	}
+}
+
+// scoreStructure 结构：小标题、代码示例、配图、列表与分段
+func (h *heuristicQualityScorer) scoreStructure(c *model.ArticleContent, content string) model.QualityComponent {
+	headings := len(c.TOC)
+	if headings == 0 {
+		headings = len(qualityHeadingRegex.FindAllString(qualityCodeBlockRegex.ReplaceAllString(content, " "), -1))
+	}
+	codeBlocks := len(qualityCodeBlockRegex.FindAllString(content, -1))
+	images := len(qualityImageRegex.FindAllString(content, -1))
+	lists := len(qualityListRegex.FindAllString(content, -1))
+	paragraphs := countParagraphs(content)
+
+	score := 0.0
+	switch {
+	case headings >= 3:
+		score += 40
+	case headings > 0:
+		score += 25
+	}
+	score += math.Min(float64(codeBlocks)*10, 20)
+	score += math.Min(float64(images)*10, 20)
+	if lists > 0 {
+		score += 10
+	}
+	switch {
+	case paragraphs >= 5:
+		score += 10
+	case paragraphs >= 3:
+		score += 5
+	}
+
+	return model.QualityComponent{
+		Key:    "structure",
+		Name:   "结构",
+		Score:  roundScore(math.Min(score, 100)),
+		Weight: qualityWeightStructure,
+		Detail: fmt.Sprintf("小标题%d个，代码块%d个，图片%d张，列表项%d个，段落%d个", headings, codeBlocks, images, lists, paragraphs),
+	}
+}
+
+// scoreReadability 可读性：平均句长与段落长度（大段不分段的文字难以阅读）
+func (h *heuristicQualityScorer) scoreReadability(prose string) model.QualityComponent {
+	sentences := 0
+	chars := 0
+	for _, s := range qualitySentenceRegex.Split(prose, -1) {
+		n := utf8.RuneCountInString(strings.Join(strings.Fields(s), ""))
+		if n == 0 {
+			continue
+		}
+		sentences++
+		chars += n
+	}
+	if sentences == 0 {
+		return model.QualityComponent{
+			Key: "readability", Name: "可读性", Score: 0, Weight: qualityWeightReadability,
+			Detail: "没有可识别的句子",
+		}
+	}
+
+	avgSentence := float64(chars) / float64(sentences)
+	score := 100.0
+	switch {
+	case avgSentence < 8:
+		score -= (8 - avgSentence) * 5
+	case avgSentence > 60:
+		score -= math.Min((avgSentence-60)*1.5, 60)
+	}
+
+	// 超过600字不分段的段落每个扣10分
+	longParagraphs := 0
+	for _, p := range strings.Split(prose, "\n\n") {
+		if utf8.RuneCountInString(strings.TrimSpace(p)) > 600 {
+			longParagraphs++
+		}
+	}
+	score -= math.Min(float64(longParagraphs)*10, 40)
+
+	return model.QualityComponent{
+		Key:    "readability",
+		Name:   "可读性",
+		Score:  roundScore(math.Max(score, 0)),
+		Weight: qualityWeightReadability,
+		Detail: fmt.Sprintf("平均句长%.1f字（8-60字为宜），超长段落%d个", avgSentence, longParagraphs),
+	}
+}
+
+// scoreLinks 外链：链接密度过高或集中指向同一域名视为推广
+func (h *heuristicQualityScorer) scoreLinks(content string, proseLen int) model.QualityComponent {
+	text := qualityCodeBlockRegex.ReplaceAllString(content, " ")
+	text = qualityImageRegex.ReplaceAllString(text, " ")
+
+	domains := make(map[string]int)
+	links := 0
+	for _, m := range qualityLinkRegex.FindAllStringSubmatch(text, -1) {
+		raw := m[1]
+		if raw == "" {
+			raw = m[2]
+		}
+		links++
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			domains[strings.ToLower(u.Host)]++
+		}
+	}
+
+	if links == 0 {
+		return model.QualityComponent{
+			Key: "links", Name: "外链", Score: 100, Weight: qualityWeightLinks,
+			Detail: "无外链",
+		}
+	}
+
+	// 每千字外链数
+	density := float64(links) / math.Max(float64(proseLen), 1) * 1000
+	topDomain := 0
+	for _, n := range domains {
+		if n > topDomain {
+			topDomain = n
+		}
+	}
+
+	score := 100.0
+	if density > 5 {
+		score -= math.Min((density-5)*10, 70)
+	}
+	if topDomain >= 5 && float64(topDomain)/float64(links) > 0.6 {
+		score -= 30
+	}
+
+	return model.QualityComponent{
+		Key:    "links",
+		Name:   "外链",
+		Score:  roundScore(math.Max(score, 0)),
+		Weight: qualityWeightLinks,
+		Detail: fmt.Sprintf("外链%d个（每千字%.1f个），同一域名最多%d个", links, density, topDomain),
+	}
+}
+
+// scoreSensitive 敏感词：按等级加权计算密度，出现严重敏感词直接记0分
+func (h *heuristicQualityScorer) scoreSensitive(text string, proseLen int, words []model.CommentSensitiveWord) model.QualityComponent {
+	text = strings.ToLower(text)
+	hits := 0
+	weighted := 0.0
+	serious := false
+	for _, w := range words {
+		if w.Word == "" {
+			continue
+		}
+		n := strings.Count(text, strings.ToLower(w.Word))
+		if n == 0 {
+			continue
+		}
+		hits += n
+		weighted += float64(n) * float64(w.Level)
+		if w.Level >= model.SensitiveWordLevelSerious {
+			serious = true
+		}
+	}
+
+	score := 100.0
+	detail := "未命中敏感词"
+	if hits > 0 {
+		density := weighted / math.Max(float64(proseLen), 1) * 1000
+		score = math.Max(100-density*20, 0)
+		detail = fmt.Sprintf("命中敏感词%d次（加权每千字%.2f）", hits, density)
+		if serious {
+			score = 0
+			detail += "，含严重敏感词"
+		}
+	}
+
+	return model.QualityComponent{
+		Key:    "sensitive",
+		Name:   "敏感内容",
+		Score:  roundScore(score),
+		Weight: qualityWeightSensitive,
+		Detail: detail,
+	}
+}
+
+// scoreAuthor 作者历史：历史平均分为基础，审核失败与搬运记录扣分
+func (h *heuristicQualityScorer) scoreAuthor(history *repository.AuthorQualityHistory) model.QualityComponent {
+	if history == nil || history.PublishedCount == 0 {
+		return model.QualityComponent{
+			Key: "author", Name: "作者历史", Score: qualityNeutralScore, Weight: qualityWeightAuthor,
+			Detail: "新作者，按中性分计",
+		}
+	}
+
+	score := qualityNeutralScore
+	if history.ScoredCount > 0 {
+		score = history.AvgQualityScore
+	}
+	// 持续创作的作者少量加分
+	score += math.Min(float64(history.PublishedCount), 20) * 0.5
+	score -= float64(history.RejectedCount) * 5
+	score -= float64(history.ConfirmedDupCount) * 15
+
+	return model.QualityComponent{
+		Key:    "author",
+		Name:   "作者历史",
+		Score:  roundScore(math.Min(math.Max(score, 0), 100)),
+		Weight: qualityWeightAuthor,
+		Detail: fmt.Sprintf("已发布%d篇，历史均分%.1f，审核失败/下线%d篇，确认搬运%d次",
+			history.PublishedCount, history.AvgQualityScore, history.RejectedCount, history.ConfirmedDupCount),
+	}
+}
+
+// scoreEngagement 早期互动：以真人浏览为分母的点赞、收藏、评论率
+func (h *heuristicQualityScorer) scoreEngagement(article *model.ArticleV3) model.QualityComponent {
+	views := article.HumanViewCount
+	if views < qualityMinEngagementViews {
+		return model.QualityComponent{
+			Key: "engagement", Name: "早期互动", Score: qualityNeutralScore, Weight: qualityWeightEngagement,
+			Detail: fmt.Sprintf("真人浏览%d次，样本不足%d次，按中性分计", views, qualityMinEngagementViews),
+		}
+	}
+
+	likeRate := float64(article.LikeCount) / float64(views)
+	favoriteRate := float64(article.FavoriteCount) / float64(views)
+	commentRate := float64(article.CommentCount) / float64(views)
+
+	// 基准：点赞率5%、收藏率2%、评论率1%各自达到即满分
+	score := math.Min(likeRate/0.05, 1)*40 +
+		math.Min(favoriteRate/0.02, 1)*40 +
+		math.Min(commentRate/0.01, 1)*20
+
+	return model.QualityComponent{
+		Key:    "engagement",
+		Name:   "早期互动",
+		Score:  roundScore(score),
+		Weight: qualityWeightEngagement,
+		Detail: fmt.Sprintf("真人浏览%d次，点赞率%.1f%%，收藏率%.1f%%，评论率%.1f%%",
+			views, likeRate*100, favoriteRate*100, commentRate*100),
+	}
+}
+
+// countParagraphs 统计非空段落数（代码块不计）
+func countParagraphs(content string) int {
+	count := 0
+	for _, p := range strings.Split(qualityCodeBlockRegex.ReplaceAllString(content, ""), "\n\n") {
+		if strings.TrimSpace(p) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// roundScore 保留两位小数（与 decimal(5,2) 一致）
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
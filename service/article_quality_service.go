@@ -0,0 +1,121 @@
+package service
+
+import (
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/repository"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ArticleQualityService 文章质量评分服务接口
+type ArticleQualityService interface {
+	// ScoreArticle 计算并保存文章质量分（发布、编辑后调用）
+	ScoreArticle(articleID uint64) (*model.ArticleQualityScore, error)
+	// GetQualityReport 作者查看自己文章的评分明细
+	GetQualityReport(articleID uint64, userID string) (*model.ArticleQualityScore, error)
+	// RescoreRecentArticles 重新评分近期发布的文章（早期互动数据会持续变化）
+	RescoreRecentArticles(since time.Time, limit int) (int, error)
+}
+
+type articleQualityService struct {
+	qualityRepo repository.ArticleQualityRepository
+	articleRepo repository.ArticleV3Repository
+	scorer      QualityScorer
+}
+
+// NewArticleQualityService 创建ArticleQualityService实例（scorer为nil时使用本地启发式评分器）
+func NewArticleQualityService(
+	qualityRepo repository.ArticleQualityRepository,
+	articleRepo repository.ArticleV3Repository,
+	scorer QualityScorer,
+) ArticleQualityService {
+	if scorer == nil {
+		scorer = NewHeuristicQualityScorer()
+	}
+	return &articleQualityService{
+		qualityRepo: qualityRepo,
+		articleRepo: articleRepo,
+		scorer:      scorer,
+	}
+}
+
+// ScoreArticle 计算并保存文章质量分
+func (s *articleQualityService) ScoreArticle(articleID uint64) (*model.ArticleQualityScore, error) {
+	// 1. 获取文章与内容
+	article, err := s.articleRepo.FindByID(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("文章不存在: %w", err)
+	}
+	content, err := s.articleRepo.FindContentByArticleID(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("文章内容不存在: %w", err)
+	}
+
+	// 2. 作者历史与敏感词库（查询失败时按缺省处理，不阻断评分）
+	history, err := s.qualityRepo.FindAuthorHistory(article.UserID, articleID)
+	if err != nil {
+		log.Printf("⚠️  查询作者历史失败: UserID=%s, Error=%v", article.UserID, err)
+	}
+	words, err := s.qualityRepo.FindEnabledSensitiveWords()
+	if err != nil {
+		log.Printf("⚠️  加载敏感词库失败: %v", err)
+	}
+
+	// 3. 评分
+	result, err := s.scorer.Score(&QualityInput{
+		Article:        article,
+		Content:        content,
+		AuthorHistory:  history,
+		SensitiveWords: words,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("质量评分失败: %w", err)
+	}
+
+	// 4. 保存明细并同步文章质量分
+	score := &model.ArticleQualityScore{
+		ArticleID:  articleID,
+		Score:      result.Score,
+		Scorer:     s.scorer.Name(),
+		Components: model.QualityComponentList(result.Components),
+		ScoreTime:  time.Now(),
+	}
+	if err := s.qualityRepo.SaveScore(score); err != nil {
+		return nil, fmt.Errorf("保存质量评分失败: %w", err)
+	}
+
+	return score, nil
+}
+
+// GetQualityReport 作者查看评分明细（尚未评分时现场计算一次）
+func (s *articleQualityService) GetQualityReport(articleID uint64, userID string) (*model.ArticleQualityScore, error) {
+	if !s.articleRepo.CheckOwnership(articleID, userID) {
+		return nil, constant.ErrPermissionDenied
+	}
+
+	score, err := s.qualityRepo.FindScore(articleID)
+	if err == nil {
+		return score, nil
+	}
+	return s.ScoreArticle(articleID)
+}
+
+// RescoreRecentArticles 重新评分近期发布的文章
+func (s *articleQualityService) RescoreRecentArticles(since time.Time, limit int) (int, error) {
+	ids, err := s.qualityRepo.FindPublishedSince(since, limit)
+	if err != nil {
+		return 0, fmt.Errorf("查询近期文章失败: %w", err)
+	}
+
+	scored := 0
+	for _, id := range ids {
+		if _, err := s.ScoreArticle(id); err != nil {
+			log.Printf("⚠️  文章重新评分失败: ArticleID=%d, Error=%v", id, err)
+			continue
+		}
+		scored++
+	}
+	return scored, nil
+}
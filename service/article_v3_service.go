@@ -4,6 +4,7 @@ import (
 	"astronomer-gin/model"
 	"astronomer-gin/pkg/constant"
 	"astronomer-gin/repository"
+	"errors"
 	"fmt"
 	"github.com/russross/blackfriday/v2"
 	"gorm.io/gorm"
@@ -21,17 +22,25 @@ type ArticleV3Service interface {
 	// 删除文章（软删除）
 	DeleteArticle(articleID uint64, userID string) error
 	// 获取文章详情（含内容）
-	GetArticleDetail(articleID uint64, viewerID string) (*ArticleDetailResponse, error)
+	GetArticleDetail(articleID uint64, visitor *ViewVisitor) (*ArticleDetailResponse, error)
 	// 获取文章列表
 	GetArticleList(req *ArticleListRequest) (*ArticleListResponse, error)
 
 	// ==================== 草稿管理 ====================
 	// 保存草稿（自动保存）
 	SaveDraft(userID string, req *SaveDraftRequest) (*model.ArticleDraft, error)
-	// 更新草稿
-	UpdateDraft(draftID uint64, userID string, req *SaveDraftRequest) error
-	// 发布草稿
+	// 更新草稿（乐观锁：req.Version与服务器版本不一致时返回*DraftConflictError）
+	UpdateDraft(draftID uint64, userID string, req *SaveDraftRequest) (*model.ArticleDraft, error)
+	// 发布草稿（关联已发布文章的草稿会更新原文章）
 	PublishDraft(draftID uint64, userID string) (*model.ArticleV3, error)
+	// 编辑已发布文章（获取或创建关联草稿，线上版本在重新发布前保持不变）
+	EditArticleDraft(articleID uint64, userID string) (*model.ArticleDraft, error)
+	// 草稿快照列表（不含正文）
+	GetDraftSnapshots(draftID uint64, userID string) ([]model.ArticleDraftSnapshot, error)
+	// 草稿快照详情
+	GetDraftSnapshot(draftID, snapshotID uint64, userID string) (*model.ArticleDraftSnapshot, error)
+	// 用快照内容覆盖草稿（同样校验版本）
+	RestoreDraftSnapshot(draftID, snapshotID uint64, userID string, version int, deviceID string) (*model.ArticleDraft, error)
 	// 获取用户草稿列表
 	GetUserDrafts(userID string, page, pageSize int) ([]model.ArticleDraft, int64, error)
 	// 获取草稿详情
@@ -88,8 +97,8 @@ type ArticleV3Service interface {
 	FavoriteArticle(articleID uint64, userID string) error
 	// 取消收藏
 	UnfavoriteArticle(articleID uint64, userID string) error
-	// 增加浏览量
-	IncrementViewCount(articleID uint64, userID string) error
+	// 增加浏览量（原始/去重/真人）
+	IncrementViewCount(articleID uint64, visitor *ViewVisitor) error
 
 	// ==================== 推荐与排序 ====================
 	// 获取精选文章
@@ -118,8 +127,8 @@ type ArticleV3Service interface {
 	RollbackToVersion(articleID uint64, userID string, version int) error
 
 	// ==================== 统计分析 ====================
-	// 获取文章详细统计
-	GetArticleStats(articleID uint64) (*model.ArticleStatsDetail, error)
+	// 获取文章详细统计（仅作者）
+	GetArticleStats(articleID uint64, userID string) (*model.ArticleStatsDetail, error)
 	// 获取用户文章统计
 	GetUserArticleStats(userID string) (*UserArticleStats, error)
 }
@@ -169,31 +178,44 @@ type SaveDraftRequest struct {
 	ColumnID   uint64   `json:"column_id"`
 	Tags       []string `json:"tags"`
 	Topics     []string `json:"topics"`
+	Version    int      `json:"version"`   // 本次修改基于的草稿版本（更新时必填，也可通过If-Match请求头传递）
+	DeviceID   string   `json:"device_id"` // 设备标识（用于提示冲突来源与快照归档）
+}
+
+// DraftConflictError 草稿版本冲突（其他设备已先保存），附带服务器当前版本供客户端合并
+type DraftConflictError struct {
+	Server *model.ArticleDraft
+}
+
+func (e *DraftConflictError) Error() string {
+	return "草稿已在其他设备更新，请合并后基于最新版本重新保存"
 }
 
 // ArticleDetailResponse 文章详情响应（扁平化结构，匹配前端期望）
 type ArticleDetailResponse struct {
 	// 文章基本信息
-	ID            uint64   `json:"id"`
-	UserID        string   `json:"user_id"`
-	Title         string   `json:"title"`
-	Summary       string   `json:"summary"`
-	CoverImage    string   `json:"cover_image"`
-	ContentType   int8     `json:"content_type"`
-	CategoryID    uint64   `json:"category_id"`
-	ColumnID      uint64   `json:"column_id"`
-	Tags          []string `json:"tags"`
-	Topics        []string `json:"topics"`
-	Visibility    int8     `json:"visibility"`
-	Status        int8     `json:"status"`
-	ViewCount     uint64   `json:"view_count"`
-	LikeCount     uint64   `json:"like_count"`
-	CommentCount  uint64   `json:"comment_count"`
-	FavoriteCount uint64   `json:"favorite_count"`
-	ShareCount    uint64   `json:"share_count"`
-	CreateTime    string   `json:"create_time"`
-	UpdateTime    string   `json:"update_time"`
-	PublishTime   string   `json:"publish_time"`
+	ID             uint64   `json:"id"`
+	UserID         string   `json:"user_id"`
+	Title          string   `json:"title"`
+	Summary        string   `json:"summary"`
+	CoverImage     string   `json:"cover_image"`
+	ContentType    int8     `json:"content_type"`
+	CategoryID     uint64   `json:"category_id"`
+	ColumnID       uint64   `json:"column_id"`
+	Tags           []string `json:"tags"`
+	Topics         []string `json:"topics"`
+	Visibility     int8     `json:"visibility"`
+	Status         int8     `json:"status"`
+	ViewCount      uint64   `json:"view_count"`       // 原始浏览量
+	RealViewCount  uint64   `json:"real_view_count"`  // 去重浏览量
+	HumanViewCount uint64   `json:"human_view_count"` // 真人浏览量（去重且排除爬虫）
+	LikeCount      uint64   `json:"like_count"`
+	CommentCount   uint64   `json:"comment_count"`
+	FavoriteCount  uint64   `json:"favorite_count"`
+	ShareCount     uint64   `json:"share_count"`
+	CreateTime     string   `json:"create_time"`
+	UpdateTime     string   `json:"update_time"`
+	PublishTime    string   `json:"publish_time"`
 
 	// 文章内容
 	Content   string `json:"content"`
@@ -205,6 +227,8 @@ type ArticleDetailResponse struct {
 	AuthorName   string `json:"author_name"`
 	AuthorAvatar string `json:"author_avatar"`
 	AuthorBio    string `json:"author_bio"`
+	// 署名（主作者在前，其后为已接受邀请的共同所有者和编辑）
+	Authors []ArticleByline `json:"authors"`
 
 	// 用户互动状态
 	IsLiked     bool `json:"is_liked"`
@@ -214,6 +238,8 @@ type ArticleDetailResponse struct {
 	// 相关数据
 	Categories      []map[string]interface{} `json:"categories"`
 	RelatedArticles []ArticleListItem        `json:"related_articles"`
+	// 专栏章节导航（文章不在专栏中时省略）
+	ColumnNav *ColumnNav `json:"column_nav,omitempty"`
 }
 
 // ArticleAuthorInfo 作者信息
@@ -225,6 +251,14 @@ type ArticleAuthorInfo struct {
 	FollowerCount int    `json:"follower_count"`
 }
 
+// ArticleByline 文章署名
+type ArticleByline struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Avatar   string `json:"avatar"`
+	Role     string `json:"role"`
+}
+
 // ArticleListRequest 文章列表请求
 type ArticleListRequest struct {
 	CategoryID uint64 `json:"category_id"`
@@ -305,12 +339,17 @@ type UserArticleStats struct {
 // ==================== Service实现 ====================
 
 type articleV3Service struct {
-	articleRepo  repository.ArticleV3Repository
-	userRepo     repository.UserRepository
-	followRepo   repository.FollowRepository
-	likeRepo     repository.LikeRepository
-	favoriteRepo repository.FavoriteRepository
-	db           *gorm.DB
+	articleRepo     repository.ArticleV3Repository
+	userRepo        repository.UserRepository
+	followRepo      repository.FollowRepository
+	likeRepo        repository.LikeRepository
+	favoriteRepo    repository.FavoriteRepository
+	duplicateSvc    ArticleDuplicateService
+	qualitySvc      ArticleQualityService
+	contributorRepo repository.ContributorRepository
+	chapterService  ColumnChapterService
+	levelService    LevelService
+	db              *gorm.DB
 }
 
 // NewArticleV3Service 创建ArticleV3Service实例
@@ -320,15 +359,25 @@ func NewArticleV3Service(
 	followRepo repository.FollowRepository,
 	likeRepo repository.LikeRepository,
 	favoriteRepo repository.FavoriteRepository,
+	duplicateSvc ArticleDuplicateService,
+	qualitySvc ArticleQualityService,
+	contributorRepo repository.ContributorRepository,
+	chapterService ColumnChapterService,
+	levelService LevelService,
 	db *gorm.DB,
 ) ArticleV3Service {
 	return &articleV3Service{
-		articleRepo:  articleRepo,
-		userRepo:     userRepo,
-		followRepo:   followRepo,
-		likeRepo:     likeRepo,
-		favoriteRepo: favoriteRepo,
-		db:           db,
+		articleRepo:     articleRepo,
+		userRepo:        userRepo,
+		followRepo:      followRepo,
+		likeRepo:        likeRepo,
+		favoriteRepo:    favoriteRepo,
+		duplicateSvc:    duplicateSvc,
+		qualitySvc:      qualitySvc,
+		contributorRepo: contributorRepo,
+		chapterService:  chapterService,
+		levelService:    levelService,
+		db:              db,
 	}
 }
 
@@ -346,7 +395,12 @@ func (s *articleV3Service) CreateArticle(userID string, req *CreateArticleReques
 		return nil, err
 	}
 
-	// 3. 构建文章对象
+	// 3. 发布到专栏需要专栏的编辑权限
+	if err := s.checkColumnTarget(req.ColumnID, userID); err != nil {
+		return nil, err
+	}
+
+	// 4. 构建文章对象
 	now := time.Now()
 	article := &model.ArticleV3{
 		UserID:      userID, // 设置作者ID
@@ -367,7 +421,7 @@ func (s *articleV3Service) CreateArticle(userID string, req *CreateArticleReques
 		PublishTime: &now,
 	}
 
-	// 4. 生成内容对象
+	// 5. 生成内容对象
 	content := &model.ArticleContent{
 		Content:     req.Content,
 		ContentHTML: string(blackfriday.Run([]byte(req.Content))),
@@ -375,39 +429,51 @@ func (s *articleV3Service) CreateArticle(userID string, req *CreateArticleReques
 		ReadTime:    s.calculateReadTime(req.Content),
 	}
 
-	// 5. 事务创建文章和内容
+	// 6. 事务创建文章和内容
 	if err := s.articleRepo.CreateWithContent(article, content); err != nil {
 		return nil, fmt.Errorf("创建文章失败: %w", err)
 	}
 
-	// 6. 创建历史版本
+	// 7. 创建历史版本
 	s.createHistoryVersion(article.ID, article.Title, req.Content, "", model.ChangeTypeCreate, article.UserID)
 
-	// 7. 更新分类计数
+	// 8. 更新分类计数
 	if req.CategoryID > 0 {
 		s.articleRepo.IncrementCategoryArticleCount(req.CategoryID)
 	}
 
-	// 8. 处理话题
+	// 9. 加入专栏并通知订阅者
+	s.publishToColumn(article)
+
+	// 10. 处理话题
 	if len(req.Topics) > 0 {
-		s.handleTopics(article.ID, req.Topics)
+		s.handleTopics(article.ID, userID, req.Topics)
 	}
 
-	// 9. 处理标签
+	// 11. 处理标签
 	if len(req.Tags) > 0 {
 		s.handleTags(req.Tags)
 	}
 
-	// 10. 初始化统计详情
+	// 12. 初始化统计详情
 	s.initializeStatsDetail(article.ID)
 
+	// 13. 异步查重
+	s.scheduleDuplicateCheck(article.ID)
+
+	// 14. 异步计算质量分
+	s.scheduleQualityScore(article.ID)
+
+	// 15. 发放经验
+	s.levelService.AwardXP(userID, model.XPEventPublishArticle, fmt.Sprintf("article:%d", article.ID))
+
 	return article, nil
 }
 
 // UpdateArticle 更新文章
 func (s *articleV3Service) UpdateArticle(articleID uint64, userID string, req *UpdateArticleRequest) error {
-	// 1. 检查权限
-	if !s.articleRepo.CheckOwnership(articleID, userID) {
+	// 1. 检查权限（作者、共同所有者或编辑）
+	if !s.hasArticleRole(articleID, userID, contributorEditRoles...) {
 		return constant.ErrPermissionDenied
 	}
 
@@ -440,6 +506,7 @@ func (s *articleV3Service) UpdateArticle(articleID uint64, userID string, req *U
 			content.WordCount = len([]rune(*req.Content))
 			content.ReadTime = s.calculateReadTime(*req.Content)
 			s.articleRepo.UpdateContent(content)
+			s.scheduleDuplicateCheck(articleID)
 		}
 	}
 
@@ -469,7 +536,7 @@ func (s *articleV3Service) UpdateArticle(articleID uint64, userID string, req *U
 
 	if req.Topics != nil {
 		updates["topics"] = model.JSONStringList(*req.Topics)
-		s.handleTopics(articleID, *req.Topics)
+		s.handleTopics(articleID, userID, *req.Topics)
 	}
 
 	if req.Visibility != nil {
@@ -500,13 +567,16 @@ func (s *articleV3Service) UpdateArticle(articleID uint64, userID string, req *U
 	}
 	s.createHistoryVersion(articleID, newTitle, newContent, "用户编辑", model.ChangeTypeEdit, userID)
 
+	// 6. 重新计算质量分
+	s.scheduleQualityScore(articleID)
+
 	return nil
 }
 
 // DeleteArticle 删除文章（软删除）
 func (s *articleV3Service) DeleteArticle(articleID uint64, userID string) error {
-	// 1. 检查权限
-	if !s.articleRepo.CheckOwnership(articleID, userID) {
+	// 1. 检查权限（作者或共同所有者）
+	if !s.hasArticleRole(articleID, userID, model.ContributorRoleOwner) {
 		return constant.ErrPermissionDenied
 	}
 
@@ -516,8 +586,11 @@ func (s *articleV3Service) DeleteArticle(articleID uint64, userID string) error
 		return fmt.Errorf("文章不存在: %w", err)
 	}
 
-	// 3. 软删除
-	if err := s.articleRepo.SoftDelete(articleID); err != nil {
+	// 3. 软删除（移入回收站）
+	if err := s.articleRepo.MoveToTrash(article); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // 并发请求已删除，计数已由该请求扣减
+		}
 		return fmt.Errorf("删除文章失败: %w", err)
 	}
 
@@ -535,7 +608,9 @@ func (s *articleV3Service) DeleteArticle(articleID uint64, userID string) error
 }
 
 // GetArticleDetail 获取文章详情
-func (s *articleV3Service) GetArticleDetail(articleID uint64, viewerID string) (*ArticleDetailResponse, error) {
+func (s *articleV3Service) GetArticleDetail(articleID uint64, visitor *ViewVisitor) (*ArticleDetailResponse, error) {
+	viewerID := visitor.UserID
+
 	// 1. 获取文章和内容
 	article, content, err := s.articleRepo.FindByIDWithContent(articleID)
 	if err != nil {
@@ -549,7 +624,7 @@ func (s *articleV3Service) GetArticleDetail(articleID uint64, viewerID string) (
 
 	// 3. 异步增加阅读量（不影响响应速度）
 	go func() {
-		if err := s.IncrementViewCount(articleID, viewerID); err != nil {
+		if err := s.IncrementViewCount(articleID, visitor); err != nil {
 			log.Printf("⚠️  增加文章阅读量失败: ArticleID=%d, Error=%v", articleID, err)
 		}
 	}()
@@ -605,7 +680,13 @@ func (s *articleV3Service) GetArticleDetail(articleID uint64, viewerID string) (
 		isFollowing = s.followRepo.IsFollowing(viewerID, article.UserID)
 	}
 
-	// 8. 格式化时间
+	// 8. 专栏章节导航（登录用户同时记录阅读进度）
+	columnNav := s.chapterService.GetColumnNav(article, viewerID)
+	if columnNav != nil && viewerID != "" {
+		go s.chapterService.RecordRead(viewerID, columnNav.ColumnID, article.ID)
+	}
+
+	// 9. 格式化时间
 	createTime := ""
 	updateTime := ""
 	publishTime := ""
@@ -619,29 +700,31 @@ func (s *articleV3Service) GetArticleDetail(articleID uint64, viewerID string) (
 		publishTime = article.PublishTime.Format("2006-01-02 15:04:05")
 	}
 
-	// 9. 返回扁平化结构
+	// 10. 返回扁平化结构
 	return &ArticleDetailResponse{
 		// 文章基本信息
-		ID:            article.ID,
-		UserID:        article.UserID,
-		Title:         article.Title,
-		Summary:       article.Summary,
-		CoverImage:    article.CoverImage,
-		ContentType:   article.ContentType,
-		CategoryID:    article.CategoryID,
-		ColumnID:      article.ColumnID,
-		Tags:          []string(article.Tags),
-		Topics:        []string(article.Topics),
-		Visibility:    article.Visibility,
-		Status:        article.Status,
-		ViewCount:     article.ViewCount,
-		LikeCount:     article.LikeCount,
-		CommentCount:  article.CommentCount,
-		FavoriteCount: article.FavoriteCount,
-		ShareCount:    article.ShareCount,
-		CreateTime:    createTime,
-		UpdateTime:    updateTime,
-		PublishTime:   publishTime,
+		ID:             article.ID,
+		UserID:         article.UserID,
+		Title:          article.Title,
+		Summary:        article.Summary,
+		CoverImage:     article.CoverImage,
+		ContentType:    article.ContentType,
+		CategoryID:     article.CategoryID,
+		ColumnID:       article.ColumnID,
+		Tags:           []string(article.Tags),
+		Topics:         []string(article.Topics),
+		Visibility:     article.Visibility,
+		Status:         article.Status,
+		ViewCount:      article.ViewCount,
+		RealViewCount:  article.RealViewCount,
+		HumanViewCount: article.HumanViewCount,
+		LikeCount:      article.LikeCount,
+		CommentCount:   article.CommentCount,
+		FavoriteCount:  article.FavoriteCount,
+		ShareCount:     article.ShareCount,
+		CreateTime:     createTime,
+		UpdateTime:     updateTime,
+		PublishTime:    publishTime,
 
 		// 文章内容
 		Content:   content.Content,
@@ -653,6 +736,7 @@ func (s *articleV3Service) GetArticleDetail(articleID uint64, viewerID string) (
 		AuthorName:   authorName,
 		AuthorAvatar: authorAvatar,
 		AuthorBio:    authorBio,
+		Authors:      s.buildBylines(article, authorName, authorAvatar),
 
 		// 用户互动状态
 		IsLiked:     isLiked,
@@ -662,6 +746,7 @@ func (s *articleV3Service) GetArticleDetail(articleID uint64, viewerID string) (
 		// 相关数据
 		Categories:      categories,
 		RelatedArticles: relatedArticles,
+		ColumnNav:       columnNav,
 	}, nil
 }
 
@@ -6,14 +6,25 @@ import (
 	"astronomer-gin/pkg/redis"
 	"astronomer-gin/repository"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"time"
+
+	"github.com/russross/blackfriday/v2"
+	"gorm.io/gorm"
 )
 
 // ==================== 草稿管理实现 ====================
 
+const (
+	// draftSnapshotLimit 每个草稿保留的快照数
+	draftSnapshotLimit = 20
+	// draftSnapshotInterval 同一设备连续自动保存时的快照间隔（换设备保存总是先归档）
+	draftSnapshotInterval = 5 * time.Minute
+)
+
 // SaveDraft 保存草稿
 func (s *articleV3Service) SaveDraft(userID string, req *SaveDraftRequest) (*model.ArticleDraft, error) {
 	draft := &model.ArticleDraft{
@@ -26,7 +37,9 @@ func (s *articleV3Service) SaveDraft(userID string, req *SaveDraftRequest) (*mod
 		ColumnID:      req.ColumnID,
 		Tags:          model.JSONStringList(req.Tags),
 		Topics:        model.JSONStringList(req.Topics),
+		Version:       1,
 		AutoSaveCount: 1,
+		LastDeviceID:  req.DeviceID,
 	}
 
 	now := time.Now()
@@ -39,51 +52,180 @@ func (s *articleV3Service) SaveDraft(userID string, req *SaveDraftRequest) (*mod
 	return draft, nil
 }
 
-// UpdateDraft 更新草稿
-func (s *articleV3Service) UpdateDraft(draftID uint64, userID string, req *SaveDraftRequest) error {
-	// 1. 检查草稿所有权
-	draft, err := s.articleRepo.FindDraftByID(draftID)
+// UpdateDraft 更新草稿（基于版本号的乐观并发控制）
+func (s *articleV3Service) UpdateDraft(draftID uint64, userID string, req *SaveDraftRequest) (*model.ArticleDraft, error) {
+	// 1. 检查草稿权限（已发布文章的草稿允许编辑协作者修改）
+	draft, err := s.findAccessibleDraft(draftID, userID, contributorEditRoles...)
 	if err != nil {
-		return fmt.Errorf("草稿不存在: %w", err)
-	}
-
-	if draft.UserID != userID {
-		return constant.ErrPermissionDenied
+		return nil, err
 	}
 
 	// 2. 更新草稿
-	draft.Title = req.Title
-	draft.Summary = req.Summary
-	draft.Content = req.Content
-	draft.CoverImage = req.CoverImage
-	draft.CategoryID = req.CategoryID
-	draft.ColumnID = req.ColumnID
-	draft.Tags = model.JSONStringList(req.Tags)
-	draft.Topics = model.JSONStringList(req.Topics)
-	draft.AutoSaveCount++
+	updated := *draft
+	updated.Title = req.Title
+	updated.Summary = req.Summary
+	updated.Content = req.Content
+	updated.CoverImage = req.CoverImage
+	updated.CategoryID = req.CategoryID
+	updated.ColumnID = req.ColumnID
+	updated.Tags = model.JSONStringList(req.Tags)
+	updated.Topics = model.JSONStringList(req.Topics)
+
+	return s.saveDraftVersion(draft, &updated, req.Version, req.DeviceID, false)
+}
+
+// saveDraftVersion 版本一致时写入草稿，并按需把被覆盖的内容归档为快照
+func (s *articleV3Service) saveDraftVersion(current, updated *model.ArticleDraft, baseVersion int, deviceID string, forceSnapshot bool) (*model.ArticleDraft, error) {
+	if baseVersion != current.Version {
+		return nil, &DraftConflictError{Server: current}
+	}
 
 	now := time.Now()
-	draft.LastEditTime = &now
+	updated.LastEditTime = &now
+	updated.LastDeviceID = deviceID
 
-	if err := s.articleRepo.UpdateDraft(draft); err != nil {
-		return fmt.Errorf("更新草稿失败: %w", err)
+	var snapshot *model.ArticleDraftSnapshot
+	if forceSnapshot || s.needDraftSnapshot(current, deviceID, now) {
+		snapshot = newDraftSnapshot(current)
 	}
 
-	return nil
+	saved, err := s.articleRepo.SaveDraftVersion(updated, baseVersion, snapshot, draftSnapshotLimit)
+	if err != nil {
+		return nil, fmt.Errorf("更新草稿失败: %w", err)
+	}
+	if !saved {
+		// 读取后到写入前被其他设备抢先保存
+		latest, err := s.articleRepo.FindDraftByID(current.ID)
+		if err != nil {
+			return nil, fmt.Errorf("草稿不存在: %w", err)
+		}
+		return nil, &DraftConflictError{Server: latest}
+	}
+
+	return updated, nil
 }
 
-// PublishDraft 发布草稿
-func (s *articleV3Service) PublishDraft(draftID uint64, userID string) (*model.ArticleV3, error) {
-	// 1. 获取草稿
-	draft, err := s.articleRepo.FindDraftByID(draftID)
+// needDraftSnapshot 换设备保存或距上次快照超过间隔时归档当前版本
+func (s *articleV3Service) needDraftSnapshot(current *model.ArticleDraft, deviceID string, now time.Time) bool {
+	if current.LastDeviceID != deviceID {
+		return true
+	}
+	latest, err := s.articleRepo.FindLatestDraftSnapshot(current.ID)
 	if err != nil {
-		return nil, fmt.Errorf("草稿不存在: %w", err)
+		return true
 	}
+	return now.Sub(latest.CreateTime) >= draftSnapshotInterval
+}
 
-	if draft.UserID != userID {
+// newDraftSnapshot 根据草稿当前内容生成快照
+func newDraftSnapshot(draft *model.ArticleDraft) *model.ArticleDraftSnapshot {
+	return &model.ArticleDraftSnapshot{
+		DraftID:    draft.ID,
+		UserID:     draft.UserID,
+		Version:    draft.Version,
+		Title:      draft.Title,
+		Summary:    draft.Summary,
+		CoverImage: draft.CoverImage,
+		Content:    draft.Content,
+		CategoryID: draft.CategoryID,
+		ColumnID:   draft.ColumnID,
+		Tags:       draft.Tags,
+		Topics:     draft.Topics,
+		DeviceID:   draft.LastDeviceID,
+	}
+}
+
+// EditArticleDraft 获取或创建已发布文章的关联草稿
+func (s *articleV3Service) EditArticleDraft(articleID uint64, userID string) (*model.ArticleDraft, error) {
+	if !s.hasArticleRole(articleID, userID, contributorEditRoles...) {
 		return nil, constant.ErrPermissionDenied
 	}
 
+	// 1. 已有编辑中的草稿则继续编辑
+	if draft, err := s.articleRepo.FindLinkedDraft(articleID); err == nil {
+		return draft, nil
+	}
+
+	// 2. 以线上版本为底稿创建草稿
+	article, content, err := s.articleRepo.FindByIDWithContent(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("文章不存在: %w", err)
+	}
+
+	now := time.Now()
+	draft := &model.ArticleDraft{
+		UserID:       userID,
+		ArticleID:    articleID,
+		Title:        article.Title,
+		Summary:      article.Summary,
+		CoverImage:   article.CoverImage,
+		Content:      content.Content,
+		CategoryID:   article.CategoryID,
+		ColumnID:     article.ColumnID,
+		Tags:         article.Tags,
+		Topics:       article.Topics,
+		Version:      1,
+		LastEditTime: &now,
+	}
+	if err := s.articleRepo.CreateDraft(draft); err != nil {
+		return nil, fmt.Errorf("创建编辑草稿失败: %w", err)
+	}
+
+	return draft, nil
+}
+
+// GetDraftSnapshots 草稿快照列表
+func (s *articleV3Service) GetDraftSnapshots(draftID uint64, userID string) ([]model.ArticleDraftSnapshot, error) {
+	if _, err := s.GetDraftDetail(draftID, userID); err != nil {
+		return nil, err
+	}
+	return s.articleRepo.FindDraftSnapshots(draftID)
+}
+
+// GetDraftSnapshot 草稿快照详情
+func (s *articleV3Service) GetDraftSnapshot(draftID, snapshotID uint64, userID string) (*model.ArticleDraftSnapshot, error) {
+	if _, err := s.GetDraftDetail(draftID, userID); err != nil {
+		return nil, err
+	}
+	snapshot, err := s.articleRepo.FindDraftSnapshot(draftID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("快照不存在: %w", err)
+	}
+	return snapshot, nil
+}
+
+// RestoreDraftSnapshot 用快照内容覆盖草稿（覆盖前的内容总会归档为新快照）
+func (s *articleV3Service) RestoreDraftSnapshot(draftID, snapshotID uint64, userID string, version int, deviceID string) (*model.ArticleDraft, error) {
+	draft, err := s.findAccessibleDraft(draftID, userID, contributorEditRoles...)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.articleRepo.FindDraftSnapshot(draftID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("快照不存在: %w", err)
+	}
+
+	updated := *draft
+	updated.Title = snapshot.Title
+	updated.Summary = snapshot.Summary
+	updated.CoverImage = snapshot.CoverImage
+	updated.Content = snapshot.Content
+	updated.CategoryID = snapshot.CategoryID
+	updated.ColumnID = snapshot.ColumnID
+	updated.Tags = snapshot.Tags
+	updated.Topics = snapshot.Topics
+
+	return s.saveDraftVersion(draft, &updated, version, deviceID, true)
+}
+
+// PublishDraft 发布草稿
+func (s *articleV3Service) PublishDraft(draftID uint64, userID string) (*model.ArticleV3, error) {
+	// 1. 获取草稿（已发布文章的草稿允许编辑协作者发布）
+	draft, err := s.findAccessibleDraft(draftID, userID, contributorEditRoles...)
+	if err != nil {
+		return nil, err
+	}
+
 	if draft.IsPublished {
 		return nil, fmt.Errorf("草稿已发布")
 	}
@@ -93,7 +235,17 @@ func (s *articleV3Service) PublishDraft(draftID uint64, userID string) (*model.A
 		return nil, err
 	}
 
-	// 3. 创建文章
+	// 关联已发布文章的草稿：更新原文章
+	if draft.ArticleID > 0 {
+		return s.republishDraft(draft, userID)
+	}
+
+	// 3. 发布到专栏需要专栏的编辑权限
+	if err := s.checkColumnTarget(draft.ColumnID, userID); err != nil {
+		return nil, err
+	}
+
+	// 4. 创建文章
 	now := time.Now()
 	article := &model.ArticleV3{
 		UserID:      userID,
@@ -110,12 +262,12 @@ func (s *articleV3Service) PublishDraft(draftID uint64, userID string) (*model.A
 		PublishTime: &now,
 	}
 
-	// 4. 使用事务发布
+	// 5. 使用事务发布
 	if err := s.articleRepo.PublishDraft(draftID, article); err != nil {
 		return nil, fmt.Errorf("发布草稿失败: %w", err)
 	}
 
-	// 5. 创建内容记录
+	// 6. 创建内容记录
 	content := &model.ArticleContent{
 		ArticleID: article.ID,
 		Content:   draft.Content,
@@ -123,26 +275,105 @@ func (s *articleV3Service) PublishDraft(draftID uint64, userID string) (*model.A
 		ReadTime:  s.calculateReadTime(draft.Content),
 	}
 	s.articleRepo.CreateContent(content)
+	s.scheduleDuplicateCheck(article.ID)
+	s.scheduleQualityScore(article.ID)
 
-	// 6. 处理分类、话题、标签
+	// 7. 处理分类、专栏、话题、标签
 	if article.CategoryID > 0 {
 		s.articleRepo.IncrementCategoryArticleCount(article.CategoryID)
 	}
+	s.publishToColumn(article)
 
 	if len(draft.Topics) > 0 {
-		s.handleTopics(article.ID, draft.Topics)
+		s.handleTopics(article.ID, userID, draft.Topics)
 	}
 
 	if len(draft.Tags) > 0 {
 		s.handleTags(draft.Tags)
 	}
 
-	// 7. 创建历史版本
+	// 8. 创建历史版本
 	s.createHistoryVersion(article.ID, article.Title, draft.Content, "从草稿发布", model.ChangeTypePublish, userID)
 
+	// 9. 发放经验
+	s.levelService.AwardXP(userID, model.XPEventPublishArticle, fmt.Sprintf("article:%d", article.ID))
+
 	return article, nil
 }
 
+// republishDraft 将关联草稿的修改发布到原文章（文章状态不变，发布前线上始终是旧版本）
+func (s *articleV3Service) republishDraft(draft *model.ArticleDraft, userID string) (*model.ArticleV3, error) {
+	// 1. 获取原文章
+	article, content, err := s.articleRepo.FindByIDWithContent(draft.ArticleID)
+	if err != nil {
+		return nil, fmt.Errorf("原文章不存在: %w", err)
+	}
+	if !s.hasArticleRole(article.ID, userID, contributorEditRoles...) {
+		return nil, constant.ErrPermissionDenied
+	}
+	// 移入其他专栏同样需要目标专栏的作者或编辑权限
+	if article.ColumnID != draft.ColumnID {
+		if err := s.checkColumnTarget(draft.ColumnID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. 应用草稿修改
+	updates := map[string]interface{}{
+		"title":       draft.Title,
+		"summary":     draft.Summary,
+		"cover_image": draft.CoverImage,
+		"category_id": draft.CategoryID,
+		"column_id":   draft.ColumnID,
+		"tags":        draft.Tags,
+		"topics":      draft.Topics,
+	}
+	content.Content = draft.Content
+	content.ContentHTML = string(blackfriday.Run([]byte(draft.Content)))
+	content.WordCount = len([]rune(draft.Content))
+	content.ReadTime = s.calculateReadTime(draft.Content)
+
+	if err := s.articleRepo.RepublishDraft(draft.ID, article.ID, updates, content); err != nil {
+		return nil, fmt.Errorf("发布草稿失败: %w", err)
+	}
+
+	// 3. 更新分类计数，更换专栏时移出原专栏并加入新专栏
+	if article.CategoryID != draft.CategoryID {
+		if article.CategoryID > 0 {
+			s.articleRepo.DecrementCategoryArticleCount(article.CategoryID)
+		}
+		if draft.CategoryID > 0 {
+			s.articleRepo.IncrementCategoryArticleCount(draft.CategoryID)
+		}
+	}
+	if article.ColumnID != draft.ColumnID {
+		if article.ColumnID > 0 {
+			if err := s.articleRepo.RemoveArticleFromColumn(article.ColumnID, article.ID); err != nil {
+				log.Printf("⚠️  文章移出专栏失败: ArticleID=%d, ColumnID=%d, Error=%v", article.ID, article.ColumnID, err)
+			} else {
+				s.articleRepo.DecrementColumnArticleCount(article.ColumnID)
+			}
+		}
+		article.ColumnID = draft.ColumnID
+		s.publishToColumn(article)
+	}
+
+	// 4. 处理话题、标签
+	if len(draft.Topics) > 0 {
+		s.handleTopics(article.ID, userID, draft.Topics)
+	}
+	if len(draft.Tags) > 0 {
+		s.handleTags(draft.Tags)
+	}
+
+	// 5. 创建历史版本并重新检测
+	s.createHistoryVersion(article.ID, draft.Title, draft.Content, "从草稿重新发布", model.ChangeTypeEdit, userID)
+	s.scheduleDuplicateCheck(article.ID)
+	s.scheduleQualityScore(article.ID)
+
+	return s.articleRepo.FindByID(article.ID)
+}
+
 // GetUserDrafts 获取用户草稿列表
 func (s *articleV3Service) GetUserDrafts(userID string, page, pageSize int) ([]model.ArticleDraft, int64, error) {
 	return s.articleRepo.FindUserDrafts(userID, page, pageSize)
@@ -150,17 +381,8 @@ func (s *articleV3Service) GetUserDrafts(userID string, page, pageSize int) ([]m
 
 // GetDraftDetail 获取草稿详情
 func (s *articleV3Service) GetDraftDetail(draftID uint64, userID string) (*model.ArticleDraft, error) {
-	draft, err := s.articleRepo.FindDraftByID(draftID)
-	if err != nil {
-		return nil, fmt.Errorf("草稿不存在: %w", err)
-	}
-
-	// 验证草稿所有权
-	if draft.UserID != userID {
-		return nil, constant.ErrPermissionDenied
-	}
-
-	return draft, nil
+	// 已发布文章的草稿允许全部协作者（含审阅者）查看
+	return s.findAccessibleDraft(draftID, userID, contributorViewRoles...)
 }
 
 // DeleteDraft 删除草稿
@@ -174,7 +396,10 @@ func (s *articleV3Service) DeleteDraft(draftID uint64, userID string) error {
 		return constant.ErrPermissionDenied
 	}
 
-	return s.articleRepo.DeleteDraft(draftID)
+	if err := s.articleRepo.MoveDraftToTrash(draft); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
 }
 
 // ==================== 分类管理实现 ====================
@@ -267,7 +492,7 @@ func (s *articleV3Service) UpdateColumn(columnID uint64, userID string, req *Upd
 		return fmt.Errorf("专栏不存在: %w", err)
 	}
 
-	if column.UserID != userID {
+	if !s.hasColumnRole(column, userID, contributorEditRoles...) {
 		return constant.ErrPermissionDenied
 	}
 
@@ -303,12 +528,12 @@ func (s *articleV3Service) AddArticleToColumn(columnID, articleID uint64, userID
 		return fmt.Errorf("专栏不存在: %w", err)
 	}
 
-	if column.UserID != userID {
+	if !s.hasColumnRole(column, userID, contributorEditRoles...) {
 		return constant.ErrPermissionDenied
 	}
 
-	// 2. 检查文章所有权
-	if !s.articleRepo.CheckOwnership(articleID, userID) {
+	// 2. 检查文章权限
+	if !s.hasArticleRole(articleID, userID, contributorEditRoles...) {
 		return constant.ErrPermissionDenied
 	}
 
@@ -323,6 +548,11 @@ func (s *articleV3Service) AddArticleToColumn(columnID, articleID uint64, userID
 	// 5. 更新文章的column_id
 	s.articleRepo.UpdateFields(articleID, map[string]interface{}{"column_id": columnID})
 
+	// 6. 已发布的文章通知专栏订阅者
+	if article, err := s.articleRepo.FindByID(articleID); err == nil && article.Status == model.ArticleV3StatusPublished {
+		s.chapterService.NotifyNewChapter(columnID, articleID)
+	}
+
 	return nil
 }
 
@@ -334,7 +564,7 @@ func (s *articleV3Service) RemoveArticleFromColumn(columnID, articleID uint64, u
 		return fmt.Errorf("专栏不存在: %w", err)
 	}
 
-	if column.UserID != userID {
+	if !s.hasColumnRole(column, userID, contributorEditRoles...) {
 		return constant.ErrPermissionDenied
 	}
 
@@ -424,6 +654,11 @@ func (s *articleV3Service) CreateTopic(name, description, userID string) (*model
 		return existing, nil
 	}
 
+	// 创建新话题需要达到等级
+	if !s.levelService.HasPrivilege(userID, model.PrivilegeCreateTopic) {
+		return nil, constant.ErrLevelTooLowForTopic
+	}
+
 	topic := &model.Topic{
 		Name:        name,
 		Description: description,
@@ -508,7 +743,15 @@ func (s *articleV3Service) LikeArticle(articleID uint64, userID string) error {
 	}
 
 	// 增加点赞数
-	return s.articleRepo.IncrementLikeCount(articleID)
+	if err := s.articleRepo.IncrementLikeCount(articleID); err != nil {
+		return err
+	}
+
+	// 作者获得经验（自己点赞不计）
+	if article, err := s.articleRepo.FindByID(articleID); err == nil && article.UserID != userID {
+		s.levelService.AwardXP(article.UserID, model.XPEventReceiveLike, fmt.Sprintf("like:article:%d:%s", articleID, userID))
+	}
+	return nil
 }
 
 // UnlikeArticle 取消点赞
@@ -554,48 +797,50 @@ func (s *articleV3Service) UnfavoriteArticle(articleID uint64, userID string) er
 }
 
 // IncrementViewCount 增加浏览量
-func (s *articleV3Service) IncrementViewCount(articleID uint64, userID string) error {
+// 原始浏览量(view_count)记录每次访问；去重浏览量(real_view_count)按自然日对访客去重；
+// 真人浏览量(human_view_count)在去重基础上排除爬虫（UA特征与访问频率）
+func (s *articleV3Service) IncrementViewCount(articleID uint64, visitor *ViewVisitor) error {
 	// 0. 检查是否是作者访问自己的文章（作者访问不计入浏览量）
-	if userID != "" {
+	if visitor.UserID != "" {
 		article, err := s.articleRepo.FindByID(articleID)
-		if err == nil && article.UserID == userID {
-			log.Printf("ℹ️  作者访问自己的文章（不计入浏览量）: ArticleID=%d, AuthorID=%s", articleID, userID)
+		if err == nil && article.UserID == visitor.UserID {
 			return nil
 		}
 	}
 
-	// 1. 总是增加总浏览量（包括未登录和登录用户的所有访问）
+	// 1. 总是增加原始浏览量
 	if err := s.articleRepo.IncrementViewCount(articleID); err != nil {
 		return err
 	}
 
-	// 2. 如果是已登录用户，使用Redis进行24小时去重后增加真实浏览量
-	if userID != "" {
-		// 构造Redis key: article:view:{articleID}:users
-		redisKey := fmt.Sprintf("article:view:%d:users", articleID)
-
-		// 使用SADD尝试将用户ID添加到集合
-		// 返回值：1表示是新成员（第一次浏览），0表示成员已存在（24小时内已浏览过）
-		added, err := redis.GetClient().SAdd(context.Background(), redisKey, userID).Result()
-		if err != nil {
-			log.Printf("⚠️  Redis SADD失败: key=%s, userID=%s, error=%v", redisKey, userID, err)
-			// Redis失败不影响主流程，仍然增加计数
-			s.articleRepo.IncrementRealViewCount(articleID)
-			return nil
-		}
-
-		// 如果是新用户浏览（24小时内第一次），增加真实浏览量
-		if added > 0 {
-			if err := s.articleRepo.IncrementRealViewCount(articleID); err != nil {
-				log.Printf("⚠️  增加真实浏览量失败: articleID=%d, error=%v", articleID, err)
-			}
+	if redis.GetClient() == nil {
+		return nil
+	}
+	ctx := context.Background()
+	today := time.Now().Format("20060102")
 
-			// 设置key的过期时间为24小时
-			redis.GetClient().Expire(context.Background(), redisKey, 24*time.Hour)
+	// 2. 当日去重（HyperLogLog，登录用户按用户ID，匿名访客按Cookie或IP+UA指纹）
+	uniqueKey := fmt.Sprintf("article:uv:%d:%s", articleID, today)
+	isNew, err := markUniqueView(ctx, uniqueKey, visitor)
+	if err != nil {
+		log.Printf("⚠️  浏览去重失败: ArticleID=%d, error=%v", articleID, err)
+		return nil
+	}
+	if !isNew {
+		return nil
+	}
+	if err := s.articleRepo.IncrementRealViewCount(articleID); err != nil {
+		log.Printf("⚠️  增加去重浏览量失败: ArticleID=%d, error=%v", articleID, err)
+	}
 
-			log.Printf("✅ 文章浏览量+1: ArticleID=%d, UserID=%s (24小时内首次)", articleID, userID)
-		} else {
-			log.Printf("ℹ️  重复浏览（已去重）: ArticleID=%d, UserID=%s", articleID, userID)
+	// 3. 排除爬虫后计入真人浏览量
+	if isBotVisitor(ctx, visitor) {
+		return nil
+	}
+	humanKey := fmt.Sprintf("article:hv:%d:%s", articleID, today)
+	if isNew, err := markUniqueView(ctx, humanKey, visitor); err == nil && isNew {
+		if err := s.articleRepo.IncrementHumanViewCount(articleID); err != nil {
+			log.Printf("⚠️  增加真人浏览量失败: ArticleID=%d, error=%v", articleID, err)
 		}
 	}
 
@@ -708,7 +953,7 @@ func (s *articleV3Service) GetArticleHistory(articleID uint64) ([]model.ArticleH
 // RollbackToVersion 回滚��指定版本
 func (s *articleV3Service) RollbackToVersion(articleID uint64, userID string, version int) error {
 	// 1. 检查权限
-	if !s.articleRepo.CheckOwnership(articleID, userID) {
+	if !s.hasArticleRole(articleID, userID, contributorEditRoles...) {
 		return constant.ErrPermissionDenied
 	}
 
@@ -723,10 +968,12 @@ func (s *articleV3Service) RollbackToVersion(articleID uint64, userID string, ve
 	if content != nil {
 		content.Content = history.Content
 		s.articleRepo.UpdateContent(content)
+		s.scheduleDuplicateCheck(articleID)
 	}
 
 	// 4. 更新文章标题
 	s.articleRepo.UpdateFields(articleID, map[string]interface{}{"title": history.Title})
+	s.scheduleQualityScore(articleID)
 
 	// 5. 创建新版本记录
 	s.createHistoryVersion(articleID, history.Title, history.Content,
@@ -738,8 +985,17 @@ func (s *articleV3Service) RollbackToVersion(articleID uint64, userID string, ve
 // ==================== 统计分析实现 ====================
 
 // GetArticleStats 获取文章详细统计
-func (s *articleV3Service) GetArticleStats(articleID uint64) (*model.ArticleStatsDetail, error) {
-	return s.articleRepo.FindStatsDetailByArticleID(articleID)
+func (s *articleV3Service) GetArticleStats(articleID uint64, userID string) (*model.ArticleStatsDetail, error) {
+	if !s.articleRepo.CheckOwnership(articleID, userID) {
+		return nil, constant.ErrPermissionDenied
+	}
+
+	stats, err := s.articleRepo.FindStatsDetailByArticleID(articleID)
+	if err != nil {
+		// 尚无统计数据
+		return &model.ArticleStatsDetail{ArticleID: articleID}, nil
+	}
+	return stats, nil
 }
 
 // GetUserArticleStats 获取用户文章统计
@@ -838,12 +1094,16 @@ func (s *articleV3Service) calculateReadTime(content string) int {
 	return readTime
 }
 
-// handleTopics 处理话题
-func (s *articleV3Service) handleTopics(articleID uint64, topicNames []string) {
+// handleTopics 处理话题（等级不足时只关联已有话题）
+func (s *articleV3Service) handleTopics(articleID uint64, userID string, topicNames []string) {
+	canCreate := s.levelService.HasPrivilege(userID, model.PrivilegeCreateTopic)
 	for _, topicName := range topicNames {
 		// 查找或创建话题
 		topic, err := s.articleRepo.FindTopicByName(topicName)
 		if err != nil {
+			if !canCreate {
+				continue
+			}
 			// 话题不存在，创建
 			topic = &model.Topic{
 				Name:   topicName,
@@ -890,10 +1150,13 @@ func (s *articleV3Service) createHistoryVersion(articleID uint64, title, content
 
 // checkArticleVisibility 检查文章可见性权限
 func (s *articleV3Service) checkArticleVisibility(article *model.ArticleV3, viewerID string) bool {
-	// 作者始终可见
+	// 作者和协作者始终可见
 	if article.UserID == viewerID {
 		return true
 	}
+	if s.contributorRepo.HasRole(model.ContributorTargetArticle, article.ID, viewerID, contributorViewRoles...) {
+		return true
+	}
 
 	switch article.Visibility {
 	case model.ArticleVisibilityPublic:
@@ -914,6 +1177,88 @@ func (s *articleV3Service) checkArticleVisibility(article *model.ArticleV3, view
 	}
 }
 
+// hasArticleRole 用户是文章作者或以指定角色之一参与文章
+func (s *articleV3Service) hasArticleRole(articleID uint64, userID string, roles ...string) bool {
+	return hasArticleRole(s.articleRepo, s.contributorRepo, articleID, userID, roles...)
+}
+
+// hasColumnRole 用户是专栏作者或以指定角色之一参与专栏
+func (s *articleV3Service) hasColumnRole(column *model.ArticleColumn, userID string, roles ...string) bool {
+	return column.UserID == userID ||
+		s.contributorRepo.HasRole(model.ContributorTargetColumn, column.ID, userID, roles...)
+}
+
+// checkColumnTarget 发布到专栏需要专栏作者或编辑权限
+func (s *articleV3Service) checkColumnTarget(columnID uint64, userID string) error {
+	if columnID == 0 {
+		return nil
+	}
+	column, err := s.articleRepo.FindColumnByID(columnID)
+	if err != nil || column.Status != 1 {
+		return fmt.Errorf("专栏不存在")
+	}
+	if !s.hasColumnRole(column, userID, contributorEditRoles...) {
+		return constant.ErrPermissionDenied
+	}
+	return nil
+}
+
+// publishToColumn 新发布的文章加入所属专栏末尾，并通知专栏订阅者
+func (s *articleV3Service) publishToColumn(article *model.ArticleV3) {
+	if article.ColumnID == 0 {
+		return
+	}
+	column, err := s.articleRepo.FindColumnByID(article.ColumnID)
+	if err != nil {
+		return
+	}
+	if err := s.articleRepo.AddArticleToColumn(column.ID, article.ID, column.ArticleCount+1); err != nil {
+		log.Printf("⚠️  文章加入专栏失败: ArticleID=%d, ColumnID=%d, Error=%v", article.ID, column.ID, err)
+		return
+	}
+	s.articleRepo.IncrementColumnArticleCount(column.ID)
+	s.chapterService.NotifyNewChapter(column.ID, article.ID)
+}
+
+// findAccessibleDraft 查询草稿并校验权限
+func (s *articleV3Service) findAccessibleDraft(draftID uint64, userID string, roles ...string) (*model.ArticleDraft, error) {
+	draft, err := s.articleRepo.FindDraftByID(draftID)
+	if err != nil {
+		return nil, fmt.Errorf("草稿不存在: %w", err)
+	}
+	if !canAccessDraft(s.articleRepo, s.contributorRepo, draft, userID, roles...) {
+		return nil, constant.ErrPermissionDenied
+	}
+	return draft, nil
+}
+
+// buildBylines 构建文章署名：主作者 + 已接受邀请的共同所有者和编辑（审阅者不署名）
+func (s *articleV3Service) buildBylines(article *model.ArticleV3, authorName, authorAvatar string) []ArticleByline {
+	bylines := []ArticleByline{{
+		UserID:   article.UserID,
+		Username: authorName,
+		Avatar:   authorAvatar,
+		Role:     model.ContributorRoleOwner,
+	}}
+
+	contributors, err := s.contributorRepo.FindAccepted(model.ContributorTargetArticle, article.ID)
+	if err != nil {
+		return bylines
+	}
+	for _, c := range contributors {
+		if c.Role == model.ContributorRoleReviewer {
+			continue
+		}
+		byline := ArticleByline{UserID: c.UserID, Role: c.Role}
+		if user, err := s.userRepo.FindByID(c.UserID); err == nil {
+			byline.Username = user.Username
+			byline.Avatar = user.Icon
+		}
+		bylines = append(bylines, byline)
+	}
+	return bylines
+}
+
 // initializeStatsDetail 初始化统计详情
 func (s *articleV3Service) initializeStatsDetail(articleID uint64) {
 	stats := &model.ArticleStatsDetail{
@@ -921,3 +1266,27 @@ func (s *articleV3Service) initializeStatsDetail(articleID uint64) {
 	}
 	s.articleRepo.CreateStatsDetail(stats)
 }
+
+// scheduleDuplicateCheck 异步计算内容指纹并查重（不影响发布速度）
+func (s *articleV3Service) scheduleDuplicateCheck(articleID uint64) {
+	if s.duplicateSvc == nil {
+		return
+	}
+	go func() {
+		if err := s.duplicateSvc.CheckArticle(articleID); err != nil {
+			log.Printf("⚠️  文章查重失败: ArticleID=%d, Error=%v", articleID, err)
+		}
+	}()
+}
+
+// scheduleQualityScore 异步重新计算文章质量分
+func (s *articleV3Service) scheduleQualityScore(articleID uint64) {
+	if s.qualitySvc == nil {
+		return
+	}
+	go func() {
+		if _, err := s.qualitySvc.ScoreArticle(articleID); err != nil {
+			log.Printf("⚠️  文章质量评分失败: ArticleID=%d, Error=%v", articleID, err)
+		}
+	}()
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"astronomer-gin/config"
+	"astronomer-gin/model"
+	"astronomer-gin/repository"
+)
+
+// 审计日志默认配置（与 config.yaml 保持一致）
+const (
+	defaultAuditRetentionDays = 180
+	defaultAuditActivityDays  = 90
+
+	maxAuditTextLength = 255 // UserAgent、说明的最大长度
+	maxAuditIDLength   = 64  // 对象ID、请求ID的最大长度
+	maxAuditIPLength   = 45
+)
+
+// AuditWriteStatus 审计日志写入失败统计（进程启动以来）
+type AuditWriteStatus struct {
+	Failures      int64      `json:"failures"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastAction    string     `json:"last_action,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+}
+
+// AuditContext 审计上下文（由处理器从请求中提取）
+type AuditContext struct {
+	ActorID   string
+	ActorRole string
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// AuditService 安全审计日志服务接口
+type AuditService interface {
+	// Record 追加一条审计日志（写入失败时精简后重试一次，仍失败则计入写入失败统计，不影响业务）
+	Record(actx *AuditContext, entry *model.AuditLog)
+	// WriteStatus 审计日志写入失败统计
+	WriteStatus() AuditWriteStatus
+
+	// Query 管理员按条件查询审计日志
+	Query(query *repository.AuditLogQuery, page, pageSize int) ([]model.AuditLog, int64, error)
+	// GetSecurityActivity 用户最近的安全活动
+	GetSecurityActivity(userID string, page, pageSize int) ([]model.AuditLog, int64, error)
+
+	// ArchiveExpired 将超过保留期的日志移入归档表（定时任务调用）
+	ArchiveExpired(now time.Time, limit int) (int, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+	cfg       config.AuditConfig
+
+	mu     sync.Mutex
+	status AuditWriteStatus
+}
+
+// NewAuditService 创建AuditService实例
+func NewAuditService(auditRepo repository.AuditRepository, cfg config.AuditConfig) AuditService {
+	if cfg.RetentionDays <= 0 {
+		cfg.RetentionDays = defaultAuditRetentionDays
+	}
+	if cfg.ActivityDays <= 0 {
+		cfg.ActivityDays = defaultAuditActivityDays
+	}
+	return &auditService{
+		auditRepo: auditRepo,
+		cfg:       cfg,
+	}
+}
+
+// Record 追加审计日志
+func (s *auditService) Record(actx *AuditContext, entry *model.AuditLog) {
+	if actx != nil {
+		entry.ActorID = actx.ActorID
+		entry.ActorRole = actx.ActorRole
+		entry.IP = truncateRunes(actx.IP, maxAuditIPLength)
+		entry.UserAgent = truncateRunes(actx.UserAgent, maxAuditTextLength)
+		entry.RequestID = truncateRunes(actx.RequestID, maxAuditIDLength)
+	}
+	if entry.Result == 0 {
+		entry.Result = model.AuditResultSuccess
+	}
+	entry.TargetID = truncateRunes(entry.TargetID, maxAuditIDLength)
+	entry.Detail = truncateRunes(entry.Detail, maxAuditTextLength)
+	entry.ID = 0
+
+	err := s.auditRepo.Create(entry)
+	if err == nil {
+		return
+	}
+
+	// 修改前/后的值可能无法写入，去掉后保留操作本身再试一次
+	entry.ID = 0
+	entry.Before = nil
+	entry.After = nil
+	entry.Detail = truncateRunes(fmt.Sprintf("%s (完整记录写入失败: %v)", entry.Detail, err), maxAuditTextLength)
+	retryErr := s.auditRepo.Create(entry)
+
+	now := time.Now()
+	s.mu.Lock()
+	s.status.Failures++
+	s.status.LastError = err.Error()
+	s.status.LastAction = entry.Action
+	s.status.LastFailureAt = &now
+	s.mu.Unlock()
+
+	log.Printf("❌ 写入审计日志失败: action=%s, actor=%s, target=%s:%s, request=%s, ip=%s, %v (精简重试: %v)",
+		entry.Action, entry.ActorID, entry.TargetType, entry.TargetID, entry.RequestID, entry.IP, err, retryErr)
+}
+
+// WriteStatus 审计日志写入失败统计
+func (s *auditService) WriteStatus() AuditWriteStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Query 查询审计日志
+func (s *auditService) Query(query *repository.AuditLogQuery, page, pageSize int) ([]model.AuditLog, int64, error) {
+	return s.auditRepo.Find(query, page, pageSize)
+}
+
+// GetSecurityActivity 用户账号最近的登录和安全设置变更
+func (s *auditService) GetSecurityActivity(userID string, page, pageSize int) ([]model.AuditLog, int64, error) {
+	since := time.Now().AddDate(0, 0, -s.cfg.ActivityDays)
+	logs, total, err := s.auditRepo.FindByTargetUser(userID, model.SecurityAuditActions, since, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 管理员对账号的操作只展示角色，不暴露管理员身份
+	for i := range logs {
+		if logs[i].ActorID != userID {
+			logs[i].ActorID = ""
+		}
+	}
+	return logs, total, nil
+}
+
+// ArchiveExpired 归档超过保留期的日志
+func (s *auditService) ArchiveExpired(now time.Time, limit int) (int, error) {
+	return s.auditRepo.ArchiveBefore(now.AddDate(0, 0, -s.cfg.RetentionDays), limit)
+}
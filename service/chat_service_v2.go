@@ -36,6 +36,7 @@ type chatServiceV2 struct {
 	chatRepo   repository.ChatRepository
 	followRepo repository.FollowRepository
 	userRepo   repository.UserRepository
+	privacy    PrivacyService
 }
 
 // NewChatServiceV2 创建私信服务V2实例
@@ -43,11 +44,13 @@ func NewChatServiceV2(
 	chatRepo repository.ChatRepository,
 	followRepo repository.FollowRepository,
 	userRepo repository.UserRepository,
+	privacy PrivacyService,
 ) ChatServiceV2 {
 	return &chatServiceV2{
 		chatRepo:   chatRepo,
 		followRepo: followRepo,
 		userRepo:   userRepo,
+		privacy:    privacy,
 	}
 }
 
@@ -77,9 +80,9 @@ func (s *chatServiceV2) SendMessage(fromUserID, toUserID string, content string,
 		return fmt.Errorf("对方已将你拉黑，无法发送私信")
 	}
 
-	// 4. 检查是否为好友（互相关注才能发私信，类似抖音）
-	if !s.followRepo.IsFriend(fromUserID, toUserID) {
-		return fmt.Errorf("只能给好友发送私信，请先互相关注")
+	// 4. 检查接收者的私信权限（默认仅好友）
+	if err := s.privacy.CheckCanMessage(toUserID, fromUserID); err != nil {
+		return err
 	}
 
 	// 5. 创建私信记录
@@ -137,8 +140,10 @@ func (s *chatServiceV2) GetChatHistory(userID, targetUserID string, page, pageSi
 		pageSize = constant.DefaultPageSize
 	}
 
-	// 检查是否为好友
-	if !s.followRepo.IsFriend(userID, targetUserID) {
+	// 检查是否为好友（非好友时，任一方允许对方发私信即可查看）
+	if !s.followRepo.IsFriend(userID, targetUserID) &&
+		s.privacy.CheckCanMessage(userID, targetUserID) != nil &&
+		s.privacy.CheckCanMessage(targetUserID, userID) != nil {
 		return nil, 0, fmt.Errorf("只能查看好友的聊天记录")
 	}
 
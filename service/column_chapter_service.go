@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/queue"
+	"astronomer-gin/repository"
+)
+
+// columnFanoutBatchSize 新章节通知每个任务处理的订阅者数量
+const columnFanoutBatchSize = 500
+
+// ColumnNavArticle 章节导航中的文章
+type ColumnNavArticle struct {
+	ID    uint64 `json:"id"`
+	Title string `json:"title"`
+}
+
+// ColumnNav 文章所在专栏的章节导航
+type ColumnNav struct {
+	ColumnID   uint64            `json:"column_id"`
+	ColumnName string            `json:"column_name"`
+	Index      int               `json:"index"` // 当前章节序号（从1开始，按专栏排序方式）
+	Total      int               `json:"total"`
+	Prev       *ColumnNavArticle `json:"prev"`
+	Next       *ColumnNavArticle `json:"next"`
+	ReadCount  int               `json:"read_count"` // 当前用户已读章节数（未登录为0）
+}
+
+// ColumnReadProgress 专栏阅读进度
+type ColumnReadProgress struct {
+	ColumnID     uint64            `json:"column_id"`
+	ColumnName   string            `json:"column_name"`
+	CoverImage   string            `json:"cover_image"`
+	Total        int               `json:"total"`
+	ReadCount    int               `json:"read_count"`
+	Percent      int               `json:"percent"`
+	LastRead     *ColumnNavArticle `json:"last_read"`     // 最近读的章节
+	ContinueWith *ColumnNavArticle `json:"continue_with"` // 继续阅读的章节，全部读完时为空
+	LastReadTime string            `json:"last_read_time"`
+}
+
+// ColumnChapterService 专栏章节服务接口（章节导航、新章节通知、阅读进度）
+type ColumnChapterService interface {
+	// NotifyNewChapter 文章发布到专栏后通知全部订阅者（投递扇出任务）
+	NotifyNewChapter(columnID, articleID uint64)
+	// RunChapterFanout 通知一批订阅者，还有剩余时投递下一批（由Worker调用）
+	RunChapterFanout(ctx context.Context, columnID, articleID, afterID uint64) error
+
+	// GetColumnNav 文章在所属专栏中的上一章/下一章，文章不在专栏中时返回nil
+	GetColumnNav(article *model.ArticleV3, viewerID string) *ColumnNav
+	// RecordRead 记录用户读过专栏中的文章
+	RecordRead(userID string, columnID, articleID uint64)
+	// GetProgress 获取用户在专栏中的阅读进度
+	GetProgress(columnID uint64, userID string) (*ColumnReadProgress, error)
+	// ListProgress 获取用户读过的专栏及进度（最近阅读在前）
+	ListProgress(userID string, page, pageSize int) ([]*ColumnReadProgress, int64, error)
+}
+
+type columnChapterService struct {
+	columnRepo       repository.ColumnRepository
+	articleRepo      repository.ArticleV3Repository
+	notificationRepo repository.NotificationRepository
+}
+
+// NewColumnChapterService 创建ColumnChapterService实例
+func NewColumnChapterService(
+	columnRepo repository.ColumnRepository,
+	articleRepo repository.ArticleV3Repository,
+	notificationRepo repository.NotificationRepository,
+) ColumnChapterService {
+	return &columnChapterService{
+		columnRepo:       columnRepo,
+		articleRepo:      articleRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// ==================== 新章节通知 ====================
+
+// NotifyNewChapter 投递新章节通知任务
+func (s *columnChapterService) NotifyNewChapter(columnID, articleID uint64) {
+	s.dispatchFanout(context.Background(), columnID, articleID, 0)
+}
+
+// dispatchFanout 投递一批通知任务（队列不可用时本地执行）
+func (s *columnChapterService) dispatchFanout(ctx context.Context, columnID, articleID, afterID uint64) {
+	if queue.Client != nil {
+		task := queue.CreateTask(queue.TaskTypeColumnChapter, map[string]interface{}{
+			"column_id":  columnID,
+			"article_id": articleID,
+			"after_id":   afterID,
+		})
+		err := queue.Client.PublishTask(ctx, task)
+		if err == nil {
+			return
+		}
+		log.Printf("⚠️  投递专栏更新通知任务失败，改为本地执行: column=%d, article=%d, %v", columnID, articleID, err)
+	}
+	go func() {
+		if err := s.RunChapterFanout(context.Background(), columnID, articleID, afterID); err != nil {
+			log.Printf("❌ 专栏更新通知失败: column=%d, article=%d, %v", columnID, articleID, err)
+		}
+	}()
+}
+
+// RunChapterFanout 按订阅ID游标通知一批订阅者
+func (s *columnChapterService) RunChapterFanout(ctx context.Context, columnID, articleID, afterID uint64) error {
+	column, err := s.columnRepo.GetByID(columnID)
+	if err != nil {
+		return err
+	}
+	article, err := s.articleRepo.FindByID(articleID)
+	if err != nil {
+		return fmt.Errorf("文章不存在: %w", err)
+	}
+	// 通知发出前文章已下线或删除，不再打扰订阅者
+	if article.Status != model.ArticleV3StatusPublished {
+		return nil
+	}
+
+	subscriptions, err := s.columnRepo.GetSubscriberBatch(columnID, afterID, columnFanoutBatchSize)
+	if err != nil {
+		return err
+	}
+
+	notifications := make([]*model.Notification, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		if sub.UserID == article.UserID {
+			continue
+		}
+		notifications = append(notifications, &model.Notification{
+			UserID:       sub.UserID,
+			Type:         model.NotificationTypeColumnUpdate,
+			FromUserID:   article.UserID,
+			FromUsername: column.Name,
+			Content:      fmt.Sprintf("专栏《%s》更新了新文章：%s", column.Name, article.Title),
+			RelatedID:    articleID,
+			RelatedType:  "article",
+		})
+	}
+	if err := s.notificationRepo.CreateBatch(notifications); err != nil {
+		return fmt.Errorf("创建专栏更新通知失败: %w", err)
+	}
+
+	// 本批已满说明可能还有订阅者，从最后一条继续
+	if len(subscriptions) == columnFanoutBatchSize {
+		s.dispatchFanout(ctx, columnID, articleID, subscriptions[len(subscriptions)-1].ID)
+	}
+
+	log.Printf("✅ 已通知专栏订阅者: column=%d, article=%d, count=%d", columnID, articleID, len(notifications))
+	return nil
+}
+
+// ==================== 章节导航 ====================
+
+// GetColumnNav 获取文章的章节导航
+func (s *columnChapterService) GetColumnNav(article *model.ArticleV3, viewerID string) *ColumnNav {
+	if article.ColumnID == 0 {
+		return nil
+	}
+	column, err := s.columnRepo.GetByID(article.ColumnID)
+	if err != nil {
+		return nil
+	}
+	ids, err := s.columnRepo.GetArticleIDs(column.ID)
+	if err != nil {
+		log.Printf("⚠️  查询专栏章节失败: column=%d, %v", column.ID, err)
+		return nil
+	}
+
+	index := indexOfID(ids, article.ID)
+	if index < 0 {
+		return nil
+	}
+
+	nav := &ColumnNav{
+		ColumnID:   column.ID,
+		ColumnName: column.Name,
+		Index:      index + 1,
+		Total:      len(ids),
+	}
+	if index > 0 {
+		nav.Prev = s.navArticle(ids[index-1])
+	}
+	if index < len(ids)-1 {
+		nav.Next = s.navArticle(ids[index+1])
+	}
+
+	// 已读数包含本次正在阅读的章节
+	if viewerID != "" {
+		read := s.readSet(viewerID, column.ID)
+		read[article.ID] = true
+		nav.ReadCount = countRead(ids, read)
+	}
+	return nav
+}
+
+// ==================== 阅读进度 ====================
+
+// RecordRead 记录阅读（失败只记录日志，不影响阅读）
+func (s *columnChapterService) RecordRead(userID string, columnID, articleID uint64) {
+	if err := s.columnRepo.RecordRead(userID, columnID, articleID); err != nil {
+		log.Printf("⚠️  记录专栏阅读失败: user=%s, column=%d, article=%d, %v", userID, columnID, articleID, err)
+	}
+}
+
+// GetProgress 获取专栏阅读进度
+func (s *columnChapterService) GetProgress(columnID uint64, userID string) (*ColumnReadProgress, error) {
+	column, err := s.columnRepo.GetByID(columnID)
+	if err != nil {
+		return nil, constant.ErrResourceNotFound
+	}
+	return s.buildProgress(column, userID)
+}
+
+// ListProgress 获取用户读过的专栏及进度
+func (s *columnChapterService) ListProgress(userID string, page, pageSize int) ([]*ColumnReadProgress, int64, error) {
+	columnIDs, total, err := s.columnRepo.GetReadColumnIDs(userID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*ColumnReadProgress, 0, len(columnIDs))
+	for _, columnID := range columnIDs {
+		// 已删除的专栏不再展示
+		column, err := s.columnRepo.GetByID(columnID)
+		if err != nil {
+			continue
+		}
+		progress, err := s.buildProgress(column, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, progress)
+	}
+	return list, total, nil
+}
+
+// buildProgress 根据专栏章节和阅读记录计算进度
+func (s *columnChapterService) buildProgress(column *model.ArticleColumn, userID string) (*ColumnReadProgress, error) {
+	ids, err := s.columnRepo.GetArticleIDs(column.ID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.columnRepo.GetReadRecords(userID, column.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &ColumnReadProgress{
+		ColumnID:   column.ID,
+		ColumnName: column.Name,
+		CoverImage: column.CoverImage,
+		Total:      len(ids),
+	}
+
+	read := make(map[uint64]bool, len(records))
+	for _, record := range records {
+		read[record.ArticleID] = true
+	}
+	progress.ReadCount = countRead(ids, read)
+	if progress.Total > 0 {
+		progress.Percent = progress.ReadCount * 100 / progress.Total
+	}
+
+	// 最近读的章节（记录按阅读时间倒序，跳过已移出专栏的文章）
+	lastIndex := -1
+	for _, record := range records {
+		if index := indexOfID(ids, record.ArticleID); index >= 0 {
+			lastIndex = index
+			progress.LastRead = s.navArticle(record.ArticleID)
+			progress.LastReadTime = record.ReadTime.Format("2006-01-02 15:04:05")
+			break
+		}
+	}
+
+	// 继续阅读：最近读的下一章；已是最后一章时回到第一篇未读
+	continueIndex := -1
+	if lastIndex+1 < len(ids) {
+		continueIndex = lastIndex + 1
+	} else {
+		for i, id := range ids {
+			if !read[id] {
+				continueIndex = i
+				break
+			}
+		}
+	}
+	if continueIndex >= 0 {
+		progress.ContinueWith = s.navArticle(ids[continueIndex])
+	}
+
+	return progress, nil
+}
+
+// readSet 用户在专栏中读过的文章
+func (s *columnChapterService) readSet(userID string, columnID uint64) map[uint64]bool {
+	read := make(map[uint64]bool)
+	records, err := s.columnRepo.GetReadRecords(userID, columnID)
+	if err != nil {
+		return read
+	}
+	for _, record := range records {
+		read[record.ArticleID] = true
+	}
+	return read
+}
+
+// navArticle 查询导航中展示的文章标题
+func (s *columnChapterService) navArticle(articleID uint64) *ColumnNavArticle {
+	nav := &ColumnNavArticle{ID: articleID}
+	if article, err := s.articleRepo.FindByID(articleID); err == nil {
+		nav.Title = article.Title
+	}
+	return nav
+}
+
+// indexOfID 查找ID所在位置，不存在返回-1
+func indexOfID(ids []uint64, id uint64) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// countRead 统计专栏现有章节中已读的数量
+func countRead(ids []uint64, read map[uint64]bool) int {
+	count := 0
+	for _, id := range ids {
+		if read[id] {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	htmlparse "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ==================== 电子书结构 ====================
+
+// ebook 导出的电子书
+type ebook struct {
+	Identifier  string
+	Title       string
+	Author      string
+	Description string
+	Language    string
+	Modified    time.Time
+	CoverURL    string      // 封面原始地址（HTML版使用）
+	Cover       *ebookImage // 封面图片（EPUB版内嵌）
+	Chapters    []ebookChapter
+	Images      []ebookImage // 章节内嵌图片（EPUB版）
+}
+
+// ebookChapter 电子书章节（一篇文章）
+type ebookChapter struct {
+	ID    string
+	Title string
+	Body  string // XHTML片段
+}
+
+// ebookImage 电子书内嵌图片
+type ebookImage struct {
+	ID        string
+	Href      string // 相对 OEBPS 的路径
+	MediaType string
+	Data      []byte
+}
+
+// chapterStripElements 导出时移除的元素（脚本、表单、外部嵌入）
+var chapterStripElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true,
+	atom.Embed: true, atom.Form: true, atom.Input: true, atom.Button: true,
+	atom.Textarea: true, atom.Select: true, atom.Link: true, atom.Meta: true,
+}
+
+// ebookStylesheet 电子书与打印版共用的样式
+const ebookStylesheet = `body { font-family: serif; line-height: 1.7; margin: 0 5%; }
+h1.chapter-title { font-size: 1.6em; margin: 1.5em 0 1em; }
+img { max-width: 100%; height: auto; }
+pre { white-space: pre-wrap; word-wrap: break-word; background: #f5f5f5; padding: 0.8em; font-size: 0.85em; }
+code { font-family: monospace; }
+blockquote { margin: 1em 0; padding-left: 1em; border-left: 3px solid #ccc; color: #555; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; }
+.cover { text-align: center; }
+.cover img { max-height: 95%; }
+.notice { color: #888; font-style: italic; }
+`
+
+// ==================== 章节内容清洗 ====================
+
+// sanitizeChapterHTML 清洗文章HTML并输出为格式良好的XHTML片段
+// mapImage 返回替换后的图片地址；返回false时图片改为外链
+func sanitizeChapterHTML(src string, mapImage func(src string) (string, bool)) (string, error) {
+	context := &htmlparse.Node{Type: htmlparse.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := htmlparse.ParseFragment(strings.NewReader(src), context)
+	if err != nil {
+		return "", fmt.Errorf("解析文章内容失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		if cleanChapterNode(node, mapImage) == nil {
+			continue
+		}
+		if err := htmlparse.Render(&buf, node); err != nil {
+			return "", fmt.Errorf("渲染文章内容失败: %w", err)
+		}
+	}
+	return buf.String(), nil
+}
+
+// cleanChapterNode 递归清洗节点，返回nil表示该节点应被移除
+func cleanChapterNode(node *htmlparse.Node, mapImage func(src string) (string, bool)) *htmlparse.Node {
+	if node.Type == htmlparse.CommentNode {
+		return nil
+	}
+	if node.Type != htmlparse.ElementNode {
+		return node
+	}
+	if chapterStripElements[node.DataAtom] {
+		return nil
+	}
+
+	// 过滤事件属性与脚本链接
+	attrs := node.Attr[:0]
+	for _, attr := range node.Attr {
+		key := strings.ToLower(attr.Key)
+		if strings.HasPrefix(key, "on") || attr.Namespace != "" {
+			continue
+		}
+		if (key == "href" || key == "src") && strings.HasPrefix(strings.ToLower(strings.TrimSpace(attr.Val)), "javascript:") {
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	node.Attr = attrs
+
+	if node.DataAtom == atom.Img {
+		return rewriteChapterImage(node, mapImage)
+	}
+
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		if replacement := cleanChapterNode(child, mapImage); replacement == nil {
+			node.RemoveChild(child)
+		} else if replacement != child {
+			node.InsertBefore(replacement, child)
+			node.RemoveChild(child)
+		}
+		child = next
+	}
+	return node
+}
+
+// rewriteChapterImage 处理图片：可内嵌的改写地址，否则替换为外链
+func rewriteChapterImage(node *htmlparse.Node, mapImage func(src string) (string, bool)) *htmlparse.Node {
+	src, alt := "", ""
+	for _, attr := range node.Attr {
+		switch attr.Key {
+		case "src":
+			src = attr.Val
+		case "alt":
+			alt = attr.Val
+		}
+	}
+	if src == "" {
+		return nil
+	}
+
+	if newSrc, ok := mapImage(src); ok {
+		node.Attr = []htmlparse.Attribute{{Key: "src", Val: newSrc}, {Key: "alt", Val: alt}}
+		return node
+	}
+
+	if alt == "" {
+		alt = "图片"
+	}
+	link := &htmlparse.Node{
+		Type:     htmlparse.ElementNode,
+		Data:     "a",
+		DataAtom: atom.A,
+		Attr:     []htmlparse.Attribute{{Key: "href", Val: src}},
+	}
+	link.AppendChild(&htmlparse.Node{Type: htmlparse.TextNode, Data: "[" + alt + "]"})
+	return link
+}
+
+// ==================== EPUB3 ====================
+
+// epubEntry EPUB压缩包中的一个文件
+type epubEntry struct {
+	name string
+	data []byte
+}
+
+// buildEPUB 生成EPUB3文件（同时附带toc.ncx兼容EPUB2阅读器）
+func buildEPUB(book *ebook) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	// 1. mimetype 必须是第一个文件且不压缩
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := mimetype.Write([]byte("application/epub+zip")); err != nil {
+		return nil, err
+	}
+
+	files := []epubEntry{
+		{"META-INF/container.xml", []byte(epubContainerXML)},
+		{"OEBPS/content.opf", []byte(epubPackageDocument(book))},
+		{"OEBPS/nav.xhtml", []byte(epubNavDocument(book))},
+		{"OEBPS/toc.ncx", []byte(epubNCX(book))},
+		{"OEBPS/style.css", []byte(ebookStylesheet)},
+	}
+
+	// 2. 封面
+	if book.Cover != nil {
+		files = append(files,
+			epubEntry{"OEBPS/cover.xhtml", []byte(epubCoverPage(book))},
+			epubEntry{"OEBPS/" + book.Cover.Href, book.Cover.Data},
+		)
+	}
+
+	// 3. 章节与图片
+	for i := range book.Chapters {
+		chapter := &book.Chapters[i]
+		files = append(files, epubEntry{"OEBPS/text/" + chapter.ID + ".xhtml", []byte(epubChapterPage(book, chapter))})
+	}
+	for _, image := range book.Images {
+		files = append(files, epubEntry{"OEBPS/" + image.Href, image.Data})
+	}
+
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(file.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+const epubContainerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// epubPackageDocument 生成 content.opf
+func epubPackageDocument(book *ebook) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&b, `<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="%s">`+"\n", book.Language)
+
+	// 元数据
+	b.WriteString(`  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">` + "\n")
+	fmt.Fprintf(&b, "    <dc:identifier id=\"book-id\">%s</dc:identifier>\n", escapeXML(book.Identifier))
+	fmt.Fprintf(&b, "    <dc:title>%s</dc:title>\n", escapeXML(book.Title))
+	fmt.Fprintf(&b, "    <dc:creator>%s</dc:creator>\n", escapeXML(book.Author))
+	fmt.Fprintf(&b, "    <dc:language>%s</dc:language>\n", book.Language)
+	if book.Description != "" {
+		fmt.Fprintf(&b, "    <dc:description>%s</dc:description>\n", escapeXML(book.Description))
+	}
+	fmt.Fprintf(&b, "    <meta property=\"dcterms:modified\">%s</meta>\n", book.Modified.UTC().Format("2006-01-02T15:04:05Z"))
+	if book.Cover != nil {
+		fmt.Fprintf(&b, "    <meta name=\"cover\" content=\"%s\"/>\n", book.Cover.ID)
+	}
+	b.WriteString("  </metadata>\n")
+
+	// 资源清单
+	b.WriteString("  <manifest>\n")
+	b.WriteString(`    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>` + "\n")
+	b.WriteString(`    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>` + "\n")
+	b.WriteString(`    <item id="css" href="style.css" media-type="text/css"/>` + "\n")
+	if book.Cover != nil {
+		fmt.Fprintf(&b, "    <item id=\"%s\" href=\"%s\" media-type=\"%s\" properties=\"cover-image\"/>\n", book.Cover.ID, book.Cover.Href, book.Cover.MediaType)
+		b.WriteString(`    <item id="cover" href="cover.xhtml" media-type="application/xhtml+xml"/>` + "\n")
+	}
+	for _, chapter := range book.Chapters {
+		fmt.Fprintf(&b, "    <item id=\"%s\" href=\"text/%s.xhtml\" media-type=\"application/xhtml+xml\"/>\n", chapter.ID, chapter.ID)
+	}
+	for _, image := range book.Images {
+		fmt.Fprintf(&b, "    <item id=\"%s\" href=\"%s\" media-type=\"%s\"/>\n", image.ID, image.Href, image.MediaType)
+	}
+	b.WriteString("  </manifest>\n")
+
+	// 阅读顺序（专栏顺序）
+	b.WriteString("  <spine toc=\"ncx\">\n")
+	if book.Cover != nil {
+		b.WriteString(`    <itemref idref="cover"/>` + "\n")
+	}
+	b.WriteString(`    <itemref idref="nav"/>` + "\n")
+	for _, chapter := range book.Chapters {
+		fmt.Fprintf(&b, "    <itemref idref=\"%s\"/>\n", chapter.ID)
+	}
+	b.WriteString("  </spine>\n")
+	b.WriteString("</package>\n")
+	return b.String()
+}
+
+// epubNavDocument 生成导航文档
+func epubNavDocument(book *ebook) string {
+	var items strings.Builder
+	for _, chapter := range book.Chapters {
+		fmt.Fprintf(&items, "      <li><a href=\"text/%s.xhtml\">%s</a></li>\n", chapter.ID, escapeXML(chapter.Title))
+	}
+
+	body := fmt.Sprintf("  <nav epub:type=\"toc\" id=\"toc\">\n    <h1>目录</h1>\n    <ol>\n%s    </ol>\n  </nav>\n", items.String())
+	return xhtmlPage(book, "目录", "style.css", body)
+}
+
+// epubNCX 生成 toc.ncx（EPUB2兼容）
+func epubNCX(book *ebook) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">` + "\n")
+	fmt.Fprintf(&b, "  <head>\n    <meta name=\"dtb:uid\" content=\"%s\"/>\n  </head>\n", escapeXML(book.Identifier))
+	fmt.Fprintf(&b, "  <docTitle><text>%s</text></docTitle>\n", escapeXML(book.Title))
+	b.WriteString("  <navMap>\n")
+	for i, chapter := range book.Chapters {
+		fmt.Fprintf(&b, "    <navPoint id=\"nav-%s\" playOrder=\"%d\">\n", chapter.ID, i+1)
+		fmt.Fprintf(&b, "      <navLabel><text>%s</text></navLabel>\n", escapeXML(chapter.Title))
+		fmt.Fprintf(&b, "      <content src=\"text/%s.xhtml\"/>\n", chapter.ID)
+		b.WriteString("    </navPoint>\n")
+	}
+	b.WriteString("  </navMap>\n</ncx>\n")
+	return b.String()
+}
+
+// epubCoverPage 生成封面页
+func epubCoverPage(book *ebook) string {
+	body := fmt.Sprintf("  <div class=\"cover\"><img src=\"%s\" alt=\"%s\"/></div>\n", book.Cover.Href, escapeXML(book.Title))
+	return xhtmlPage(book, book.Title, "style.css", body)
+}
+
+// epubChapterPage 生成章节页
+func epubChapterPage(book *ebook, chapter *ebookChapter) string {
+	body := fmt.Sprintf("  <h1 class=\"chapter-title\">%s</h1>\n%s\n", escapeXML(chapter.Title), chapter.Body)
+	return xhtmlPage(book, chapter.Title, "../style.css", body)
+}
+
+// xhtmlPage 包装XHTML页面
+func xhtmlPage(book *ebook, title, stylesheet, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="%s" lang="%s">
+<head>
+  <meta charset="UTF-8"/>
+  <title>%s</title>
+  <link rel="stylesheet" type="text/css" href="%s"/>
+</head>
+<body>
+%s</body>
+</html>
+`, book.Language, book.Language, escapeXML(title), stylesheet, body)
+}
+
+// ==================== 打印版HTML ====================
+
+// buildHTMLBook 生成适合浏览器打印为PDF的单页HTML（每篇文章分页）
+func buildHTMLBook(book *ebook) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html lang=\"%s\">\n<head>\n<meta charset=\"UTF-8\">\n<title>%s</title>\n<style>\n", book.Language, escapeXML(book.Title))
+	b.WriteString(ebookStylesheet)
+	b.WriteString(`@page { size: A4; margin: 2cm; }
+.title-page { text-align: center; padding-top: 20%; }
+.toc, .chapter { page-break-before: always; break-before: page; }
+.toc ol { line-height: 2; }
+@media print { a { color: inherit; text-decoration: none; } pre { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+`)
+
+	// 1. 扉页
+	b.WriteString("<section class=\"title-page\">\n")
+	if book.CoverURL != "" {
+		fmt.Fprintf(&b, "<img src=\"%s\" alt=\"%s\">\n", escapeXML(book.CoverURL), escapeXML(book.Title))
+	}
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p>%s</p>\n", escapeXML(book.Title), escapeXML(book.Author))
+	if book.Description != "" {
+		fmt.Fprintf(&b, "<p>%s</p>\n", escapeXML(book.Description))
+	}
+	b.WriteString("</section>\n")
+
+	// 2. 目录
+	b.WriteString("<nav class=\"toc\">\n<h2>目录</h2>\n<ol>\n")
+	for _, chapter := range book.Chapters {
+		fmt.Fprintf(&b, "<li><a href=\"#%s\">%s</a></li>\n", chapter.ID, escapeXML(chapter.Title))
+	}
+	b.WriteString("</ol>\n</nav>\n")
+
+	// 3. 正文
+	for _, chapter := range book.Chapters {
+		fmt.Fprintf(&b, "<section class=\"chapter\" id=\"%s\">\n<h1 class=\"chapter-title\">%s</h1>\n%s\n</section>\n", chapter.ID, escapeXML(chapter.Title), chapter.Body)
+	}
+
+	b.WriteString("</body>\n</html>\n")
+	return []byte(b.String())
+}
+
+// escapeXML 转义XML文本
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
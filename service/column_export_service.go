@@ -0,0 +1,394 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"astronomer-gin/model"
+	minioPkg "astronomer-gin/pkg/minio"
+	"astronomer-gin/pkg/queue"
+	"astronomer-gin/repository"
+
+	"github.com/google/uuid"
+	"github.com/russross/blackfriday/v2"
+)
+
+// ColumnExportService 专栏电子书导出服务接口
+type ColumnExportService interface {
+	// 请求导出（命中缓存直接返回下载地址，否则投递生成任务）
+	RequestExport(ctx context.Context, columnID uint64, format, userID string) (*model.ColumnExport, error)
+	// 生成电子书（由Worker调用）
+	RunColumnExport(ctx context.Context, exportID uint64) error
+}
+
+// columnExportStaleTimeout 生成中的任务超过该时长视为中断，允许重新投递
+const columnExportStaleTimeout = 15 * time.Minute
+
+type columnExportService struct {
+	exportRepo  repository.ColumnExportRepository
+	columnRepo  repository.ColumnRepository
+	articleRepo repository.ArticleV3Repository
+	userRepo    repository.UserRepository
+}
+
+// NewColumnExportService 创建ColumnExportService实例
+func NewColumnExportService(
+	exportRepo repository.ColumnExportRepository,
+	columnRepo repository.ColumnRepository,
+	articleRepo repository.ArticleV3Repository,
+	userRepo repository.UserRepository,
+) ColumnExportService {
+	return &columnExportService{
+		exportRepo:  exportRepo,
+		columnRepo:  columnRepo,
+		articleRepo: articleRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// ==================== 导出请求 ====================
+
+// RequestExport 请求导出专栏
+func (s *columnExportService) RequestExport(ctx context.Context, columnID uint64, format, userID string) (*model.ColumnExport, error) {
+	// 1. 参数验证
+	if format != model.ColumnExportFormatEPUB && format != model.ColumnExportFormatHTML {
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+
+	column, err := s.columnRepo.GetByID(columnID)
+	if err != nil {
+		return nil, fmt.Errorf("专栏不存在")
+	}
+	if column.Status != 1 && column.UserID != userID {
+		return nil, fmt.Errorf("专栏不存在")
+	}
+
+	// 2. 计算当前内容版本
+	revision, err := s.computeRevision(column)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 命中缓存或正在生成时直接返回
+	export, err := s.exportRepo.FindByColumnAndFormat(columnID, format)
+	if err == nil && export.Revision == revision {
+		switch export.Status {
+		case model.ColumnExportStatusReady:
+			return export, nil
+		case model.ColumnExportStatusPending, model.ColumnExportStatusRunning:
+			if time.Since(export.UpdateTime) < columnExportStaleTimeout {
+				return export, nil
+			}
+		}
+	}
+
+	// 4. 专栏已变更（或首次导出、上次失败），重新生成
+	if export == nil {
+		export = &model.ColumnExport{
+			ColumnID: columnID,
+			Format:   format,
+			Revision: revision,
+			Status:   model.ColumnExportStatusPending,
+		}
+		if err := s.exportRepo.Create(export); err != nil {
+			// 并发请求已创建记录
+			if existing, findErr := s.exportRepo.FindByColumnAndFormat(columnID, format); findErr == nil {
+				return existing, nil
+			}
+			return nil, fmt.Errorf("创建导出任务失败: %w", err)
+		}
+	} else {
+		if err := s.exportRepo.UpdateFields(export.ID, map[string]interface{}{
+			"revision":      revision,
+			"status":        model.ColumnExportStatusPending,
+			"file_url":      "",
+			"error_message": "",
+		}); err != nil {
+			return nil, fmt.Errorf("更新导出任务失败: %w", err)
+		}
+		export.Revision = revision
+		export.Status = model.ColumnExportStatusPending
+		export.FileURL = ""
+		export.ErrorMessage = ""
+	}
+
+	// 5. 投递生成任务（队列不可用时降级为本地协程执行）
+	s.dispatch(ctx, export.ID)
+
+	return export, nil
+}
+
+// dispatch 投递生成任务
+func (s *columnExportService) dispatch(ctx context.Context, exportID uint64) {
+	if queue.Client != nil {
+		task := queue.CreateTask(queue.TaskTypeColumnExport, map[string]interface{}{
+			"export_id": exportID,
+		})
+		err := queue.Client.PublishTask(ctx, task)
+		if err == nil {
+			return
+		}
+		log.Printf("⚠️ 投递专栏导出任务失败，改为本地执行: export=%d, %v", exportID, err)
+	}
+	go func() {
+		if err := s.RunColumnExport(context.Background(), exportID); err != nil {
+			log.Printf("❌ 专栏导出失败: export=%d, %v", exportID, err)
+		}
+	}()
+}
+
+// ==================== 电子书生成 ====================
+
+// RunColumnExport 生成专栏电子书并缓存到对象存储
+func (s *columnExportService) RunColumnExport(ctx context.Context, exportID uint64) error {
+	// 1. 获取任务（已完成的重复投递直接跳过）
+	export, err := s.exportRepo.FindByID(exportID)
+	if err != nil {
+		return fmt.Errorf("导出任务不存在: %w", err)
+	}
+	if export.Status == model.ColumnExportStatusReady {
+		return nil
+	}
+	s.exportRepo.UpdateFields(exportID, map[string]interface{}{"status": model.ColumnExportStatusRunning})
+
+	// 2. 组装电子书
+	column, err := s.columnRepo.GetByID(export.ColumnID)
+	if err != nil {
+		s.failExport(exportID, fmt.Errorf("专栏不存在"))
+		return err
+	}
+	revision, err := s.computeRevision(column)
+	if err != nil {
+		s.failExport(exportID, err)
+		return err
+	}
+
+	book, err := s.assembleBook(ctx, column, export.Format)
+	if err != nil {
+		s.failExport(exportID, err)
+		return err
+	}
+
+	// 3. 渲染
+	var data []byte
+	var ext, contentType string
+	switch export.Format {
+	case model.ColumnExportFormatEPUB:
+		data, err = buildEPUB(book)
+		ext, contentType = ".epub", "application/epub+zip"
+	default:
+		data = buildHTMLBook(book)
+		ext, contentType = ".html", "text/html; charset=utf-8"
+	}
+	if err != nil {
+		s.failExport(exportID, fmt.Errorf("生成电子书失败: %w", err))
+		return err
+	}
+
+	// 4. 上传到对象存储（文件名带版本号，旧版本上传成功后删除）
+	objectName := fmt.Sprintf("exports/columns/%d/%s%s", column.ID, revision[:16], ext)
+	fileURL, err := minioPkg.Client.UploadFile(ctx, objectName, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		s.failExport(exportID, fmt.Errorf("上传电子书失败: %w", err))
+		return err
+	}
+	if export.ObjectName != "" && export.ObjectName != objectName {
+		if err := minioPkg.Client.DeleteFile(ctx, export.ObjectName); err != nil {
+			log.Printf("⚠️ 删除旧版专栏导出文件失败: %s, %v", export.ObjectName, err)
+		}
+	}
+
+	// 5. 更新缓存记录
+	now := time.Now()
+	s.exportRepo.UpdateFields(exportID, map[string]interface{}{
+		"revision":      revision,
+		"status":        model.ColumnExportStatusReady,
+		"object_name":   objectName,
+		"file_url":      fileURL,
+		"file_size":     len(data),
+		"error_message": "",
+		"generate_time": &now,
+	})
+
+	log.Printf("✅ 专栏导出完成: column=%d format=%s chapters=%d size=%d", column.ID, export.Format, len(book.Chapters), len(data))
+	return nil
+}
+
+// assembleBook 按专栏顺序组装章节，EPUB格式内嵌站内图片
+func (s *columnExportService) assembleBook(ctx context.Context, column *model.ArticleColumn, format string) (*ebook, error) {
+	author := "未知作者"
+	if user, err := s.userRepo.FindByID(column.UserID); err == nil {
+		author = user.Username
+	}
+
+	book := &ebook{
+		Identifier:  "urn:uuid:" + uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("astronomer:column:%d", column.ID))).String(),
+		Title:       column.Name,
+		Author:      author,
+		Description: column.Description,
+		Language:    "zh-CN",
+		Modified:    time.Now(),
+		CoverURL:    column.CoverImage,
+	}
+
+	embed := format == model.ColumnExportFormatEPUB
+	images := newEbookImageCollector(ctx)
+
+	// 1. 封面
+	if embed && column.CoverImage != "" {
+		book.Cover = images.fetch(column.CoverImage, "cover")
+	}
+
+	// 2. 章节（专栏顺序）
+	const batchSize = 100
+	for page := 1; ; page++ {
+		articles, _, err := s.columnRepo.GetColumnArticles(column.ID, page, batchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, article := range articles {
+			body := s.chapterSource(article)
+
+			mapImage := func(src string) (string, bool) {
+				if !embed {
+					return src, true
+				}
+				image := images.fetch(src, "")
+				if image == nil {
+					return "", false
+				}
+				return "../" + image.Href, true
+			}
+			xhtml, err := sanitizeChapterHTML(body, mapImage)
+			if err != nil {
+				log.Printf("⚠️ 专栏导出章节内容无效: article=%d, %v", article.ID, err)
+				xhtml = `<p class="notice">本章内容无法导出，请前往站内阅读。</p>`
+			}
+
+			book.Chapters = append(book.Chapters, ebookChapter{
+				ID:    fmt.Sprintf("chapter-%03d", len(book.Chapters)+1),
+				Title: article.Title,
+				Body:  xhtml,
+			})
+		}
+
+		if len(articles) < batchSize {
+			break
+		}
+	}
+
+	if len(book.Chapters) == 0 {
+		return nil, fmt.Errorf("专栏中没有可导出的文章")
+	}
+
+	book.Images = images.images
+	return book, nil
+}
+
+// chapterSource 获取章节HTML（付费与非公开文章仅导出摘要）
+func (s *columnExportService) chapterSource(article *model.ArticleV3) string {
+	if article.IsPaid || article.Visibility != model.ArticleVisibilityPublic {
+		source := ""
+		if article.IsPaid && article.FreeContent != "" {
+			source = string(blackfriday.Run([]byte(article.FreeContent)))
+		} else if article.Summary != "" {
+			source = "<p>" + escapeXML(article.Summary) + "</p>"
+		}
+		return source + `<p class="notice">本章为付费或受限内容，请前往站内阅读全文。</p>`
+	}
+
+	content, err := s.articleRepo.FindContentByArticleID(article.ID)
+	if err != nil {
+		return `<p class="notice">本章内容缺失。</p>`
+	}
+	if content.ContentHTML != "" {
+		return content.ContentHTML
+	}
+	return string(blackfriday.Run([]byte(content.Content)))
+}
+
+// computeRevision 计算专栏内容版本（专栏信息、文章顺序、文章及内容更新时间）
+func (s *columnExportService) computeRevision(column *model.ArticleColumn) (string, error) {
+	rows, err := s.exportRepo.FindRevisionRows(column.ID)
+	if err != nil {
+		return "", fmt.Errorf("计算专栏版本失败: %w", err)
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d|%s|%s|%s|%d\n", column.ID, column.Name, column.Description, column.CoverImage, column.UpdateTime.Unix())
+	for _, row := range rows {
+		contentTime := int64(0)
+		if row.ContentUpdateTime != nil {
+			contentTime = row.ContentUpdateTime.Unix()
+		}
+		fmt.Fprintf(h, "%d|%d|%d|%d\n", row.ArticleID, row.SortOrder, row.ArticleUpdateTime.Unix(), contentTime)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// failExport 标记导出失败
+func (s *columnExportService) failExport(exportID uint64, err error) {
+	s.exportRepo.UpdateFields(exportID, map[string]interface{}{
+		"status":        model.ColumnExportStatusFailed,
+		"error_message": truncateRunes(err.Error(), 500),
+	})
+}
+
+// ==================== 图片内嵌 ====================
+
+// ebookImageCollector 从对象存储拉取站内图片并去重编号
+type ebookImageCollector struct {
+	ctx    context.Context
+	byURL  map[string]*ebookImage
+	images []ebookImage
+}
+
+func newEbookImageCollector(ctx context.Context) *ebookImageCollector {
+	return &ebookImageCollector{ctx: ctx, byURL: make(map[string]*ebookImage)}
+}
+
+// fetch 拉取图片，非站内图片或拉取失败返回nil；id为空时自动编号并计入章节图片
+func (c *ebookImageCollector) fetch(url, id string) *ebookImage {
+	if image, ok := c.byURL[url]; ok {
+		return image
+	}
+
+	objectName := extractObjectNameFromURL(url)
+	if objectName == "" || !isImageFile(objectName) {
+		return nil
+	}
+	mediaType := getContentType(objectName)
+	if !strings.HasPrefix(mediaType, "image/") || mediaType == "image/bmp" {
+		return nil // BMP不是EPUB核心媒体类型
+	}
+
+	data, err := minioPkg.Client.DownloadFile(c.ctx, objectName)
+	if err != nil {
+		log.Printf("⚠️ 专栏导出拉取图片失败: %s, %v", objectName, err)
+		return nil
+	}
+
+	ext := strings.ToLower(path.Ext(objectName))
+	if id == "" {
+		id = fmt.Sprintf("img-%03d", len(c.images)+1)
+	}
+	image := &ebookImage{
+		ID:        id,
+		Href:      "images/" + id + ext,
+		MediaType: mediaType,
+		Data:      data,
+	}
+	if id != "cover" {
+		c.images = append(c.images, *image)
+	}
+	c.byURL[url] = image
+	return image
+}
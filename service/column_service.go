@@ -57,6 +57,8 @@ type columnService struct {
 	userRepo         repository.UserRepository
 	notificationRepo repository.NotificationRepository
 	articleRepo      repository.ArticleV3Repository
+	contributorRepo  repository.ContributorRepository
+	chapterService   ColumnChapterService
 }
 
 // NewColumnService 创建专栏服务实例
@@ -65,12 +67,16 @@ func NewColumnService(
 	userRepo repository.UserRepository,
 	notificationRepo repository.NotificationRepository,
 	articleRepo repository.ArticleV3Repository,
+	contributorRepo repository.ContributorRepository,
+	chapterService ColumnChapterService,
 ) ColumnService {
 	return &columnService{
 		columnRepo:       columnRepo,
 		userRepo:         userRepo,
 		notificationRepo: notificationRepo,
 		articleRepo:      articleRepo,
+		contributorRepo:  contributorRepo,
+		chapterService:   chapterService,
 	}
 }
 
@@ -185,8 +191,8 @@ func (s *columnService) Update(userID string, columnID uint64, req *UpdateColumn
 		return err
 	}
 
-	// 权限检查：只有作者可以更新专栏
-	if column.UserID != userID {
+	// 权限检查：作者、共同所有者或编辑可以更新专栏
+	if !s.hasColumnRole(column, userID, contributorEditRoles...) {
 		return fmt.Errorf("无权限更新此专栏")
 	}
 
@@ -224,8 +230,8 @@ func (s *columnService) Delete(userID string, columnID uint64) error {
 		return err
 	}
 
-	// 权限检查：只有作者可以删除专栏
-	if column.UserID != userID {
+	// 权限检查：只有作者或共同所有者可以删除专栏
+	if !s.hasColumnRole(column, userID, model.ContributorRoleOwner) {
 		return fmt.Errorf("无权限删除此专栏")
 	}
 
@@ -267,7 +273,7 @@ func (s *columnService) Subscribe(userID string, columnID uint64) error {
 
 		notification := &model.Notification{
 			UserID:       column.UserID,
-			Type:         6, // 订阅专栏通知（需要在常量中定义）
+			Type:         model.NotificationTypeColumnSubscribe,
 			FromUserID:   userID,
 			FromUsername: user.Username,
 			Content:      fmt.Sprintf("%s 订阅了你的专栏《%s》", user.Username, column.Name),
@@ -309,8 +315,8 @@ func (s *columnService) AddArticle(userID string, columnID, articleID uint64, so
 		return err
 	}
 
-	// 权限检查：只有作者可以添加文章到专栏
-	if column.UserID != userID {
+	// 权限检查：作者、共同所有者或编辑可以添加文章到专栏
+	if !s.hasColumnRole(column, userID, contributorEditRoles...) {
 		return fmt.Errorf("无权限向此专栏添加文章")
 	}
 
@@ -320,9 +326,10 @@ func (s *columnService) AddArticle(userID string, columnID, articleID uint64, so
 		return fmt.Errorf("文章不存在")
 	}
 
-	// 检查文章作者是否为专栏作者
-	if article.UserID != userID {
-		return fmt.Errorf("只能将自己的文章添加到专栏")
+	// 检查操作者是否为文章作者或文章的编辑协作者
+	if article.UserID != userID &&
+		!s.contributorRepo.HasRole(model.ContributorTargetArticle, articleID, userID, contributorEditRoles...) {
+		return fmt.Errorf("只能将自己参与创作的文章添加到专栏")
 	}
 
 	// 添加文章到专栏
@@ -330,32 +337,10 @@ func (s *columnService) AddArticle(userID string, columnID, articleID uint64, so
 		return err
 	}
 
-	// 通知专栏订阅者（企业级功能：整合通知系统）
-	go func() {
-		subscribers, _, err := s.columnRepo.GetSubscribers(columnID, 1, 100)
-		if err != nil {
-			log.Printf("获取专栏订阅者失败", "error", err)
-			return
-		}
-
-		for _, subscriberID := range subscribers {
-			notification := &model.Notification{
-				UserID:       subscriberID,
-				Type:         7, // 专栏更新通知（需要在常量中定义）
-				FromUserID:   userID,
-				FromUsername: column.Name,
-				Content:      fmt.Sprintf("专栏《%s》更新了新文章：%s", column.Name, article.Title),
-				RelatedID:    articleID,
-				RelatedType:  "article",
-			}
-
-			if err := s.notificationRepo.Create(notification); err != nil {
-				log.Printf("创建专栏更新通知失败", "error", err, "subscriber_id", subscriberID)
-			}
-		}
-
-		log.Println("已通知专栏订阅者", "column_id", columnID, "subscriber_count", len(subscribers))
-	}()
+	// 已发布的文章通知全部专栏订阅者（分批扇出）
+	if article.Status == model.ArticleV3StatusPublished {
+		s.chapterService.NotifyNewChapter(columnID, articleID)
+	}
 
 	log.Println("添加文章到专栏成功", "column_id", columnID, "article_id", articleID, "user_id", userID)
 
@@ -370,8 +355,8 @@ func (s *columnService) RemoveArticle(userID string, columnID, articleID uint64)
 		return err
 	}
 
-	// 权限检查：只有作者可以移除文章
-	if column.UserID != userID {
+	// 权限检查：作者、共同所有者或编辑可以移除文章
+	if !s.hasColumnRole(column, userID, contributorEditRoles...) {
 		return fmt.Errorf("无权限从此专栏移除文章")
 	}
 
@@ -398,8 +383,8 @@ func (s *columnService) UpdateArticlePosition(userID string, columnID, articleID
 		return err
 	}
 
-	// 权限检查：只有作者可以调整文章顺序
-	if column.UserID != userID {
+	// 权限检查：作者、共同所有者或编辑可以调整文章顺序
+	if !s.hasColumnRole(column, userID, contributorEditRoles...) {
 		return fmt.Errorf("无权限调整此专栏的文章顺序")
 	}
 
@@ -412,3 +397,9 @@ func (s *columnService) UpdateArticlePosition(userID string, columnID, articleID
 
 	return nil
 }
+
+// hasColumnRole 用户是专栏作者或以指定角色之一参与专栏
+func (s *columnService) hasColumnRole(column *model.ArticleColumn, userID string, roles ...string) bool {
+	return column.UserID == userID ||
+		s.contributorRepo.HasRole(model.ContributorTargetColumn, column.ID, userID, roles...)
+}
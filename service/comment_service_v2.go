@@ -116,6 +116,8 @@ func (s *commentServiceV2) CreateComment(articleID int64, comment, username, pho
 				"commenter_username": username,
 				"article_id":         float64(articleID),
 				"comment_id":         float64(commentID),
+				"article_title":      article.Title,
+				"comment_content":    comment,
 			})
 			ctx := context.Background()
 			if err := queue.Client.PublishTask(ctx, task); err != nil {
@@ -4,6 +4,7 @@ import (
 	"astronomer-gin/model"
 	"astronomer-gin/pkg/constant"
 	"astronomer-gin/repository"
+	"errors"
 	"fmt"
 	"gorm.io/gorm"
 )
@@ -100,6 +101,10 @@ type CreateCommentRequest struct {
 	IPLocation  string   `json:"ip_location"`
 	DeviceType  string   `json:"device_type"`
 	UserAgent   string   `json:"user_agent"`
+
+	// 评论过于频繁时需要的图形验证码
+	CaptchaID    string `json:"captcha_id"`
+	CaptchaValue string `json:"captcha_value"`
 }
 
 // CreateReplyRequest 创建回复评论请求
@@ -116,6 +121,10 @@ type CreateReplyRequest struct {
 	IPLocation       string   `json:"ip_location"`
 	DeviceType       string   `json:"device_type"`
 	UserAgent        string   `json:"user_agent"`
+
+	// 评论过于频繁时需要的图形验证码
+	CaptchaID    string `json:"captcha_id"`
+	CaptchaValue string `json:"captcha_value"`
 }
 
 // ReportCommentRequest 举报评论请求
@@ -150,9 +159,12 @@ type UserCommentStats struct {
 type commentV3Service struct {
 	commentRepo repository.CommentV3Repository
 	articleRepo repository.ArticleV3Repository
+	dynamicRepo repository.DynamicRepository
 	userRepo    repository.UserRepository
 	likeRepo    repository.LikeRepository
 	notifyRepo  repository.NotificationRepository
+	privacy     PrivacyService
+	level       LevelService
 	db          *gorm.DB
 }
 
@@ -160,17 +172,23 @@ type commentV3Service struct {
 func NewCommentV3Service(
 	commentRepo repository.CommentV3Repository,
 	articleRepo repository.ArticleV3Repository,
+	dynamicRepo repository.DynamicRepository,
 	userRepo repository.UserRepository,
 	likeRepo repository.LikeRepository,
 	notifyRepo repository.NotificationRepository,
+	privacy PrivacyService,
+	level LevelService,
 	db *gorm.DB,
 ) CommentV3Service {
 	return &commentV3Service{
 		commentRepo: commentRepo,
 		articleRepo: articleRepo,
+		dynamicRepo: dynamicRepo,
 		userRepo:    userRepo,
 		likeRepo:    likeRepo,
 		notifyRepo:  notifyRepo,
+		privacy:     privacy,
+		level:       level,
 		db:          db,
 	}
 }
@@ -188,6 +206,9 @@ func (s *commentV3Service) CreateRootComment(req *CreateCommentRequest) (*model.
 	if err := s.checkCommentSensitiveWords(req.Content); err != nil {
 		return nil, err
 	}
+	if containsLink(req.Content) && !s.level.HasPrivilege(req.UserID, model.PrivilegePostLink) {
+		return nil, constant.ErrLevelTooLowForLink
+	}
 
 	// 3. 获取用户信息
 	user, err := s.userRepo.FindByID(req.UserID)
@@ -195,6 +216,15 @@ func (s *commentV3Service) CreateRootComment(req *CreateCommentRequest) (*model.
 		return nil, fmt.Errorf("用户不存在")
 	}
 
+	// 文章作者的评论权限设置
+	if req.TargetType == model.CommentTargetTypeArticle {
+		if article, err := s.articleRepo.FindByID(req.TargetID); err == nil {
+			if err := s.privacy.CheckCanComment(article.UserID, req.UserID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// 4. 获取楼层号
 	floorNumber, err := s.commentRepo.GetNextFloorNumber(req.TargetType, req.TargetID)
 	if err != nil {
@@ -238,6 +268,7 @@ func (s *commentV3Service) CreateRootComment(req *CreateCommentRequest) (*model.
 	if comment.RiskLevel >= model.RiskLevelHigh {
 		comment.Status = model.CommentStatusFolded
 	}
+	s.applyLevelPolicy(comment)
 
 	// 8. 创建评论
 	if err := s.commentRepo.Create(comment); err != nil {
@@ -247,10 +278,8 @@ func (s *commentV3Service) CreateRootComment(req *CreateCommentRequest) (*model.
 	// 9. 设置根评论ID（自己）
 	s.commentRepo.UpdateFields(comment.ID, map[string]interface{}{"root_id": comment.ID})
 
-	// 10. 更新文章评论数
-	if req.TargetType == model.CommentTargetTypeArticle {
-		s.articleRepo.IncrementCommentCount(req.TargetID)
-	}
+	// 10. 更新目标对象评论数
+	s.incrementTargetCommentCount(req.TargetType, req.TargetID)
 
 	// 11. 更新评论统计
 	s.commentRepo.IncrementTotalCommentCount(req.TargetType, req.TargetID)
@@ -274,6 +303,9 @@ func (s *commentV3Service) CreateReplyComment(req *CreateReplyRequest) (*model.C
 	if err := s.checkCommentSensitiveWords(req.Content); err != nil {
 		return nil, err
 	}
+	if containsLink(req.Content) && !s.level.HasPrivilege(req.UserID, model.PrivilegePostLink) {
+		return nil, constant.ErrLevelTooLowForLink
+	}
 
 	// 3. 获取父评论
 	parentComment, err := s.commentRepo.FindByID(req.ParentID)
@@ -281,6 +313,15 @@ func (s *commentV3Service) CreateReplyComment(req *CreateReplyRequest) (*model.C
 		return nil, fmt.Errorf("父评论不存在")
 	}
 
+	// 回复同样受文章作者的评论权限设置约束
+	if parentComment.TargetType == model.CommentTargetTypeArticle {
+		if article, err := s.articleRepo.FindByID(parentComment.TargetID); err == nil {
+			if err := s.privacy.CheckCanComment(article.UserID, req.UserID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// 4. 获取用户信息
 	user, err := s.userRepo.FindByID(req.UserID)
 	if err != nil {
@@ -350,6 +391,7 @@ func (s *commentV3Service) CreateReplyComment(req *CreateReplyRequest) (*model.C
 	if comment.RiskLevel >= model.RiskLevelHigh {
 		comment.Status = model.CommentStatusFolded
 	}
+	s.applyLevelPolicy(comment)
 
 	// 12. 创建评论
 	if err := s.commentRepo.Create(comment); err != nil {
@@ -362,10 +404,8 @@ func (s *commentV3Service) CreateReplyComment(req *CreateReplyRequest) (*model.C
 	// 14. 更新根评论总回复数
 	s.commentRepo.IncrementTotalReplyCount(rootID)
 
-	// 15. 更新文章评论数
-	if parentComment.TargetType == model.CommentTargetTypeArticle {
-		s.articleRepo.IncrementCommentCount(parentComment.TargetID)
-	}
+	// 15. 更新目标对象评论数
+	s.incrementTargetCommentCount(parentComment.TargetType, parentComment.TargetID)
 
 	// 16. 更新评论统计
 	s.commentRepo.IncrementTotalCommentCount(parentComment.TargetType, parentComment.TargetID)
@@ -390,8 +430,11 @@ func (s *commentV3Service) DeleteComment(commentID uint64, userID string) error
 		return fmt.Errorf("评论不存在")
 	}
 
-	// 3. 软删除
-	if err := s.commentRepo.SoftDelete(commentID); err != nil {
+	// 3. 软删除（移入回收站）
+	if err := s.commentRepo.MoveToTrash(comment); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // 并发请求已删除，计数已由该请求扣减
+		}
 		return fmt.Errorf("删除评论失败: %w", err)
 	}
 
@@ -405,10 +448,8 @@ func (s *commentV3Service) DeleteComment(commentID uint64, userID string) error
 		s.commentRepo.DecrementTotalReplyCount(comment.RootID)
 	}
 
-	// 6. 更新文章评论数
-	if comment.TargetType == model.CommentTargetTypeArticle {
-		s.articleRepo.DecrementCommentCount(comment.TargetID)
-	}
+	// 6. 更新目标对象评论数
+	s.decrementTargetCommentCount(comment.TargetType, comment.TargetID)
 
 	return nil
 }
@@ -486,6 +527,11 @@ func (s *commentV3Service) LikeComment(commentID uint64, userID string) error {
 	// 4. 重新计算热度
 	s.CalculateCommentHotScore(commentID)
 
+	// 5. 评论者获得经验（自己点赞不计）
+	if comment, err := s.commentRepo.FindByID(commentID); err == nil && comment.UserID != userID {
+		s.level.AwardXP(comment.UserID, model.XPEventReceiveLike, fmt.Sprintf("like:comment:%d:%s", commentID, userID))
+	}
+
 	return nil
 }
 
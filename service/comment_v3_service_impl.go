@@ -155,7 +155,15 @@ func (s *commentV3Service) FeatureComment(commentID uint64, authorID string) err
 	}
 
 	// 3. 精选
-	return s.commentRepo.FeatureComment(commentID)
+	if err := s.commentRepo.FeatureComment(commentID); err != nil {
+		return err
+	}
+
+	// 4. 评论者获得经验（精选自己的评论不计）
+	if comment.UserID != authorID {
+		s.level.AwardXP(comment.UserID, model.XPEventFeaturedComment, fmt.Sprintf("featured:comment:%d", commentID))
+	}
+	return nil
 }
 
 // UnfeatureComment 取消精选
@@ -399,14 +407,33 @@ func (s *commentV3Service) isTargetAuthor(targetType int8, targetID uint64, user
 		// 待实现：qaRepo.CheckOwnership(targetID, userID)
 		return false
 	case model.CommentTargetTypeDynamic:
-		// TODO: 检查是否为动态作者（需要动态模块支持）
-		// 待实现：dynamicRepo.CheckOwnership(targetID, userID)
-		return false
+		// 检查是否为动态作者
+		return s.dynamicRepo.CheckOwnership(targetID, userID)
 	default:
 		return false
 	}
 }
 
+// incrementTargetCommentCount 增加目标对象的评论数
+func (s *commentV3Service) incrementTargetCommentCount(targetType int8, targetID uint64) {
+	switch targetType {
+	case model.CommentTargetTypeArticle:
+		s.articleRepo.IncrementCommentCount(targetID)
+	case model.CommentTargetTypeDynamic:
+		s.dynamicRepo.IncrementCommentCount(targetID)
+	}
+}
+
+// decrementTargetCommentCount 减少目标对象的评论数
+func (s *commentV3Service) decrementTargetCommentCount(targetType int8, targetID uint64) {
+	switch targetType {
+	case model.CommentTargetTypeArticle:
+		s.articleRepo.DecrementCommentCount(targetID)
+	case model.CommentTargetTypeDynamic:
+		s.dynamicRepo.DecrementCommentCount(targetID)
+	}
+}
+
 // AuditResult 审核结果
 type AuditResult struct {
 	Status    int8
@@ -467,6 +494,45 @@ func (s *commentV3Service) auditCommentContent(comment *model.CommentV3) AuditRe
 	return result
 }
 
+// applyLevelPolicy 按用户等级处理评论：未解锁免先审的用户有风险的评论进入待审核并折叠，
+// 启用的"用户等级"折叠规则（rule_config.max_level）折叠等级不高于该值的用户的评论（作者本人除外）
+func (s *commentV3Service) applyLevelPolicy(comment *model.CommentV3) {
+	if comment.Status == model.CommentStatusFolded {
+		return
+	}
+
+	if comment.RiskLevel >= model.RiskLevelMedium && !s.level.HasPrivilege(comment.UserID, model.PrivilegeSkipPreModeration) {
+		comment.AuditStatus = model.CommentAuditStatusPending
+		comment.Status = model.CommentStatusFolded
+		return
+	}
+	if comment.IsAuthor {
+		return
+	}
+
+	rules, err := s.commentRepo.FindEnabledFoldRules()
+	if err != nil {
+		return
+	}
+	level := 0
+	for _, rule := range rules {
+		if rule.RuleType != model.FoldRuleTypeUserLevel {
+			continue
+		}
+		maxLevel, ok := rule.RuleConfig["max_level"].(float64)
+		if !ok {
+			continue
+		}
+		if level == 0 {
+			level = s.level.GetLevel(comment.UserID)
+		}
+		if float64(level) <= maxLevel {
+			comment.Status = model.CommentStatusFolded
+			return
+		}
+	}
+}
+
 // isRepeatedContent 检查是否为重复内容
 func (s *commentV3Service) isRepeatedContent(content string) bool {
 	// 简单检查：连续3个相同字符
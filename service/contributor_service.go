@@ -0,0 +1,331 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/repository"
+
+	"gorm.io/gorm"
+)
+
+// 协作角色分组（权限校验时使用）
+var (
+	// contributorEditRoles 可编辑和发布
+	contributorEditRoles = []string{model.ContributorRoleOwner, model.ContributorRoleEditor}
+	// contributorViewRoles 可查看草稿
+	contributorViewRoles = []string{model.ContributorRoleOwner, model.ContributorRoleEditor, model.ContributorRoleReviewer}
+)
+
+// contributorRoleNames 角色显示名称（通知文案）
+var contributorRoleNames = map[string]string{
+	model.ContributorRoleOwner:    "共同所有者",
+	model.ContributorRoleEditor:   "编辑",
+	model.ContributorRoleReviewer: "审阅者",
+}
+
+// InviteContributorRequest 邀请协作者请求
+type InviteContributorRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+}
+
+// ContributorService 文章/专栏共同创作服务接口
+type ContributorService interface {
+	// ListContributors 协作者列表（含待接受的邀请，仅所有者和协作者可查看）
+	ListContributors(targetType string, targetID uint64, userID string) ([]model.Contributor, error)
+	// Invite 邀请协作者（所有者操作，被邀请人接受后生效）
+	Invite(targetType string, targetID uint64, operatorID string, req *InviteContributorRequest) (*model.Contributor, error)
+	// UpdateRole 调整协作者角色（所有者操作），返回调整前的记录
+	UpdateRole(targetType string, targetID uint64, operatorID, userID, role string) (*model.Contributor, error)
+	// Revoke 移除协作者或撤回邀请（所有者操作；协作者也可以退出），返回被移除的记录
+	Revoke(targetType string, targetID uint64, operatorID, userID string) (*model.Contributor, error)
+
+	// ListInvitations 当前用户收到的待处理邀请
+	ListInvitations(userID string) ([]model.Contributor, error)
+	// AcceptInvitation 接受邀请
+	AcceptInvitation(invitationID uint64, userID string) error
+	// DeclineInvitation 拒绝邀请
+	DeclineInvitation(invitationID uint64, userID string) error
+}
+
+type contributorService struct {
+	contributorRepo  repository.ContributorRepository
+	articleRepo      repository.ArticleV3Repository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+}
+
+// NewContributorService 创建ContributorService实例
+func NewContributorService(
+	contributorRepo repository.ContributorRepository,
+	articleRepo repository.ArticleV3Repository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+) ContributorService {
+	return &contributorService{
+		contributorRepo:  contributorRepo,
+		articleRepo:      articleRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// ListContributors 协作者列表
+func (s *contributorService) ListContributors(targetType string, targetID uint64, userID string) ([]model.Contributor, error) {
+	primaryOwner, _, err := s.findTarget(targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if primaryOwner != userID && !s.contributorRepo.HasRole(targetType, targetID, userID, contributorViewRoles...) {
+		return nil, constant.ErrPermissionDenied
+	}
+
+	contributors, err := s.contributorRepo.FindByTarget(targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("查询协作者失败: %w", err)
+	}
+	s.fillUserInfo(contributors)
+	return contributors, nil
+}
+
+// Invite 邀请协作者
+func (s *contributorService) Invite(targetType string, targetID uint64, operatorID string, req *InviteContributorRequest) (*model.Contributor, error) {
+	if _, ok := contributorRoleNames[req.Role]; !ok {
+		return nil, fmt.Errorf("不支持的角色: %s", req.Role)
+	}
+
+	primaryOwner, title, err := s.findTarget(targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.isOwner(targetType, targetID, primaryOwner, operatorID) {
+		return nil, constant.ErrPermissionDenied
+	}
+	// 只有主作者可以授予共同所有者
+	if req.Role == model.ContributorRoleOwner && operatorID != primaryOwner {
+		return nil, constant.ErrPermissionDenied
+	}
+	if req.UserID == primaryOwner || req.UserID == operatorID {
+		return nil, fmt.Errorf("不能邀请自己或主作者")
+	}
+
+	invitee, err := s.userRepo.FindByID(req.UserID)
+	if err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+	if _, err := s.contributorRepo.Find(targetType, targetID, req.UserID); err == nil {
+		return nil, fmt.Errorf("该用户已是协作者或已被邀请")
+	}
+
+	contributor := &model.Contributor{
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserID:     req.UserID,
+		Role:       req.Role,
+		Status:     model.ContributorStatusPending,
+		InvitedBy:  operatorID,
+	}
+	if err := s.contributorRepo.Create(contributor); err != nil {
+		return nil, fmt.Errorf("邀请失败: %w", err)
+	}
+
+	s.notifyInvitation(contributor, title)
+	contributor.Username = invitee.Username
+	contributor.Avatar = invitee.Icon
+	return contributor, nil
+}
+
+// UpdateRole 调整协作者角色
+func (s *contributorService) UpdateRole(targetType string, targetID uint64, operatorID, userID, role string) (*model.Contributor, error) {
+	if _, ok := contributorRoleNames[role]; !ok {
+		return nil, fmt.Errorf("不支持的角色: %s", role)
+	}
+
+	primaryOwner, _, err := s.findTarget(targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.isOwner(targetType, targetID, primaryOwner, operatorID) {
+		return nil, constant.ErrPermissionDenied
+	}
+
+	contributor, err := s.contributorRepo.Find(targetType, targetID, userID)
+	if err != nil {
+		return nil, constant.ErrResourceNotFound
+	}
+	// 授予或调整共同所有者只能由主作者操作
+	if (role == model.ContributorRoleOwner || contributor.Role == model.ContributorRoleOwner) && operatorID != primaryOwner {
+		return nil, constant.ErrPermissionDenied
+	}
+
+	if err := s.contributorRepo.UpdateRole(contributor.ID, role); err != nil {
+		return nil, err
+	}
+	return contributor, nil
+}
+
+// Revoke 移除协作者或撤回邀请
+func (s *contributorService) Revoke(targetType string, targetID uint64, operatorID, userID string) (*model.Contributor, error) {
+	primaryOwner, _, err := s.findTarget(targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	contributor, err := s.contributorRepo.Find(targetType, targetID, userID)
+	if err != nil {
+		return nil, constant.ErrResourceNotFound
+	}
+
+	// 协作者可以主动退出；移除他人需要所有者权限，移除共同所有者只能由主作者操作
+	if operatorID != userID {
+		if !s.isOwner(targetType, targetID, primaryOwner, operatorID) {
+			return nil, constant.ErrPermissionDenied
+		}
+		if contributor.Role == model.ContributorRoleOwner && operatorID != primaryOwner {
+			return nil, constant.ErrPermissionDenied
+		}
+	}
+
+	if err := s.contributorRepo.Delete(contributor.ID); err != nil {
+		return nil, err
+	}
+	return contributor, nil
+}
+
+// ListInvitations 当前用户收到的待处理邀请
+func (s *contributorService) ListInvitations(userID string) ([]model.Contributor, error) {
+	invitations, err := s.contributorRepo.FindPendingByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询邀请失败: %w", err)
+	}
+	// 邀请列表展示邀请人
+	for i := range invitations {
+		if inviter, err := s.userRepo.FindByID(invitations[i].InvitedBy); err == nil {
+			invitations[i].Username = inviter.Username
+			invitations[i].Avatar = inviter.Icon
+		}
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation 接受邀请
+func (s *contributorService) AcceptInvitation(invitationID uint64, userID string) error {
+	invitation, err := s.findOwnInvitation(invitationID, userID)
+	if err != nil {
+		return err
+	}
+	// 对象已删除时邀请失效
+	if _, _, err := s.findTarget(invitation.TargetType, invitation.TargetID); err != nil {
+		return err
+	}
+	return s.contributorRepo.Accept(invitation.ID)
+}
+
+// DeclineInvitation 拒绝邀请
+func (s *contributorService) DeclineInvitation(invitationID uint64, userID string) error {
+	invitation, err := s.findOwnInvitation(invitationID, userID)
+	if err != nil {
+		return err
+	}
+	return s.contributorRepo.Delete(invitation.ID)
+}
+
+// findOwnInvitation 查询待处理邀请并校验被邀请人
+func (s *contributorService) findOwnInvitation(invitationID uint64, userID string) (*model.Contributor, error) {
+	invitation, err := s.contributorRepo.FindByID(invitationID)
+	if err != nil {
+		return nil, constant.ErrResourceNotFound
+	}
+	if invitation.UserID != userID {
+		return nil, constant.ErrPermissionDenied
+	}
+	if invitation.Status != model.ContributorStatusPending {
+		return nil, fmt.Errorf("邀请已处理")
+	}
+	return invitation, nil
+}
+
+// findTarget 查询协作对象，返回主作者ID和标题
+func (s *contributorService) findTarget(targetType string, targetID uint64) (string, string, error) {
+	switch targetType {
+	case model.ContributorTargetArticle:
+		article, err := s.articleRepo.FindByID(targetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", "", constant.ErrArticleNotFound
+			}
+			return "", "", fmt.Errorf("查询文章失败: %w", err)
+		}
+		return article.UserID, article.Title, nil
+	case model.ContributorTargetColumn:
+		column, err := s.articleRepo.FindColumnByID(targetID)
+		if err != nil || column.Status == 2 {
+			return "", "", constant.ErrResourceNotFound
+		}
+		return column.UserID, column.Name, nil
+	default:
+		return "", "", fmt.Errorf("不支持的类型: %s", targetType)
+	}
+}
+
+// isOwner 主作者或已接受的共同所有者
+func (s *contributorService) isOwner(targetType string, targetID uint64, primaryOwner, userID string) bool {
+	return userID == primaryOwner ||
+		s.contributorRepo.HasRole(targetType, targetID, userID, model.ContributorRoleOwner)
+}
+
+// fillUserInfo 填充协作者用户名和头像
+func (s *contributorService) fillUserInfo(contributors []model.Contributor) {
+	for i := range contributors {
+		if user, err := s.userRepo.FindByID(contributors[i].UserID); err == nil {
+			contributors[i].Username = user.Username
+			contributors[i].Avatar = user.Icon
+		}
+	}
+}
+
+// notifyInvitation 通知被邀请人
+func (s *contributorService) notifyInvitation(contributor *model.Contributor, title string) {
+	inviterName := ""
+	if inviter, err := s.userRepo.FindByID(contributor.InvitedBy); err == nil {
+		inviterName = inviter.Username
+	}
+
+	targetName := "文章"
+	if contributor.TargetType == model.ContributorTargetColumn {
+		targetName = "专栏"
+	}
+
+	notification := &model.Notification{
+		UserID:       contributor.UserID,
+		Type:         model.NotificationTypeContributorInvite,
+		FromUserID:   contributor.InvitedBy,
+		FromUsername: inviterName,
+		Content:      fmt.Sprintf("邀请你以%s身份参与%s《%s》", contributorRoleNames[contributor.Role], targetName, title),
+		RelatedID:    contributor.ID,
+		RelatedType:  "contributor",
+	}
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("⚠️  发送协作邀请通知失败: %v", err)
+	}
+}
+
+// hasArticleRole 用户是文章作者或以指定角色之一参与文章
+func hasArticleRole(articleRepo repository.ArticleV3Repository, contributorRepo repository.ContributorRepository,
+	articleID uint64, userID string, roles ...string) bool {
+	return articleRepo.CheckOwnership(articleID, userID) ||
+		contributorRepo.HasRole(model.ContributorTargetArticle, articleID, userID, roles...)
+}
+
+// canAccessDraft 草稿创建者始终可访问，已发布文章的草稿按文章作者和协作角色判断
+func canAccessDraft(articleRepo repository.ArticleV3Repository, contributorRepo repository.ContributorRepository,
+	draft *model.ArticleDraft, userID string, roles ...string) bool {
+	if draft.UserID == userID {
+		return true
+	}
+	return draft.ArticleID > 0 && hasArticleRole(articleRepo, contributorRepo, draft.ArticleID, userID, roles...)
+}
@@ -0,0 +1,571 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	wsLib "astronomer-gin/pkg/websocket"
+	"astronomer-gin/repository"
+)
+
+const (
+	// draftCollabFlushInterval 合并后的文本写回草稿的间隔
+	draftCollabFlushInterval = 10 * time.Second
+	// draftCollabHistoryLimit 会话保留的最近操作数（落后更多修订的客户端需要重新加入）
+	draftCollabHistoryLimit = 500
+	// draftCollabDeviceID 协同会话保存草稿时记录的设备ID
+	draftCollabDeviceID = "collab"
+)
+
+// draftCollabColors 成员光标颜色
+var draftCollabColors = []string{"#e6194b", "#3cb44b", "#4363d8", "#f58231", "#911eb4", "#42d4f4", "#f032e6", "#469990"}
+
+// ErrDraftCollabOutOfSync 客户端基准修订已不在服务端历史中，需要重新加入房间获取最新文本
+var ErrDraftCollabOutOfSync = errors.New("文档版本落后过多，请重新同步")
+
+// DraftParticipant 协同房间成员
+type DraftParticipant struct {
+	UserID       string    `json:"user_id"`
+	Username     string    `json:"username"`
+	Avatar       string    `json:"avatar"`
+	Color        string    `json:"color"`
+	CanEdit      bool      `json:"can_edit"` // 审阅者只读
+	Cursor       int       `json:"cursor"`
+	SelectionEnd int       `json:"selection_end"`
+	JoinTime     time.Time `json:"join_time"`
+}
+
+// DraftCollabState 加入房间时下发的文档状态
+type DraftCollabState struct {
+	DraftID      uint64             `json:"draft_id"`
+	Content      string             `json:"content"`
+	Revision     int                `json:"revision"` // 协同修订号（每个操作+1）
+	Version      int                `json:"version"`  // 草稿保存版本号
+	Participants []DraftParticipant `json:"participants"`
+}
+
+// DraftCollabOpEvent 广播给其他成员的编辑操作
+type DraftCollabOpEvent struct {
+	DraftID  uint64         `json:"draft_id"`
+	UserID   string         `json:"user_id"`
+	Revision int            `json:"revision"` // 应用该操作后的修订号
+	Ops      *TextOperation `json:"ops"`
+}
+
+// DraftCollabAckEvent 编辑操作已被接受（发给操作者）
+type DraftCollabAckEvent struct {
+	DraftID  uint64 `json:"draft_id"`
+	Revision int    `json:"revision"`
+}
+
+// DraftCollabCursorEvent 广播给其他成员的光标位置
+type DraftCollabCursorEvent struct {
+	DraftID      uint64 `json:"draft_id"`
+	UserID       string `json:"user_id"`
+	Revision     int    `json:"revision"`
+	Cursor       int    `json:"cursor"`
+	SelectionEnd int    `json:"selection_end"`
+}
+
+// DraftCollabPresenceEvent 房间成员变化
+type DraftCollabPresenceEvent struct {
+	DraftID      uint64             `json:"draft_id"`
+	Participants []DraftParticipant `json:"participants"`
+}
+
+// DraftCollabSavedEvent 合并文本已保存
+type DraftCollabSavedEvent struct {
+	DraftID  uint64 `json:"draft_id"`
+	Version  int    `json:"version"`
+	Revision int    `json:"revision"`
+}
+
+// DraftCollabService 草稿实时协同编辑服务（OT文档同步，通过WebSocket Hub的房间广播）
+type DraftCollabService interface {
+	// Join 加入草稿房间，返回当前文本、修订号和在线成员
+	Join(draftID uint64, userID string) (*DraftCollabState, error)
+	// Leave 离开草稿房间，最后一人离开时保存并关闭会话
+	Leave(draftID uint64, userID string)
+	// LeaveAll 用户断开连接时离开所有房间
+	LeaveAll(userID string)
+	// ApplyOperation 提交基于baseRevision的编辑操作，变换到最新修订后应用，向操作者确认并广播给其他成员，返回新修订号
+	ApplyOperation(draftID uint64, userID string, baseRevision int, op *TextOperation) (int, error)
+	// UpdateCursor 更新基于revision的光标/选区并广播
+	UpdateCursor(draftID uint64, userID string, revision, cursor, selectionEnd int) error
+	// GetState 查询房间状态（未开启会话时返回草稿当前内容）
+	GetState(draftID uint64, userID string) (*DraftCollabState, error)
+	// FlushAll 保存所有有未保存修改的会话
+	FlushAll()
+}
+
+// draftCollabSession 单个草稿的协同会话
+type draftCollabSession struct {
+	mu               sync.Mutex
+	draftID          uint64
+	content          string
+	revision         int
+	history          []*TextOperation // history[i] 将修订 historyStart+i 变为 historyStart+i+1
+	historyStart     int
+	savedRevision    int // 已写回草稿的修订号
+	draftVersion     int // 最近一次读取/保存时的草稿版本号
+	lastSnapshotTime time.Time
+	participants     map[string]*DraftParticipant
+	closed           bool
+}
+
+type draftCollabService struct {
+	articleRepo     repository.ArticleV3Repository
+	contributorRepo repository.ContributorRepository
+	userRepo        repository.UserRepository
+	hub             *wsLib.Hub
+
+	mu       sync.Mutex
+	sessions map[uint64]*draftCollabSession
+}
+
+// NewDraftCollabService 创建DraftCollabService实例（启动定时保存协程）
+func NewDraftCollabService(
+	articleRepo repository.ArticleV3Repository,
+	contributorRepo repository.ContributorRepository,
+	userRepo repository.UserRepository,
+	hub *wsLib.Hub,
+) DraftCollabService {
+	s := &draftCollabService{
+		articleRepo:     articleRepo,
+		contributorRepo: contributorRepo,
+		userRepo:        userRepo,
+		hub:             hub,
+		sessions:        make(map[uint64]*draftCollabSession),
+	}
+	hub.OnDisconnect(s.LeaveAll)
+	go s.run()
+	return s
+}
+
+// run 定期保存合并后的文本
+func (s *draftCollabService) run() {
+	ticker := time.NewTicker(draftCollabFlushInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.FlushAll()
+	}
+}
+
+// Join 加入草稿房间
+func (s *draftCollabService) Join(draftID uint64, userID string) (*DraftCollabState, error) {
+	draft, err := s.articleRepo.FindDraftByID(draftID)
+	if err != nil {
+		return nil, constant.ErrResourceNotFound
+	}
+	if !canAccessDraft(s.articleRepo, s.contributorRepo, draft, userID, contributorViewRoles...) {
+		return nil, constant.ErrPermissionDenied
+	}
+	if draft.IsPublished {
+		return nil, fmt.Errorf("草稿已发布")
+	}
+
+	participant := &DraftParticipant{
+		UserID:   userID,
+		Color:    draftCollabColor(userID),
+		CanEdit:  canAccessDraft(s.articleRepo, s.contributorRepo, draft, userID, contributorEditRoles...),
+		JoinTime: time.Now(),
+	}
+	if user, err := s.userRepo.FindByID(userID); err == nil {
+		participant.Username = user.Username
+		participant.Avatar = user.Icon
+	}
+
+	// 会话可能在最后一人离开时被关闭，此时重新创建
+	var session *draftCollabSession
+	for {
+		session = s.openSession(draft)
+		session.mu.Lock()
+		if !session.closed {
+			break
+		}
+		session.mu.Unlock()
+	}
+	defer session.mu.Unlock()
+
+	session.participants[userID] = participant
+	s.hub.JoinRoom(draftCollabRoom(draftID), userID)
+	s.broadcastPresence(session, userID)
+
+	return session.state(), nil
+}
+
+// Leave 离开草稿房间
+func (s *draftCollabService) Leave(draftID uint64, userID string) {
+	s.mu.Lock()
+	session, exists := s.sessions[draftID]
+	s.mu.Unlock()
+	if !exists {
+		return
+	}
+	s.leaveSession(session, userID)
+}
+
+// LeaveAll 用户断开连接时离开所有房间
+func (s *draftCollabService) LeaveAll(userID string) {
+	s.mu.Lock()
+	sessions := make([]*draftCollabSession, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.mu.Unlock()
+
+	for _, session := range sessions {
+		s.leaveSession(session, userID)
+	}
+}
+
+// leaveSession 移除成员，房间为空时保存并关闭会话
+func (s *draftCollabService) leaveSession(session *draftCollabSession, userID string) {
+	session.mu.Lock()
+	if _, exists := session.participants[userID]; !exists {
+		session.mu.Unlock()
+		return
+	}
+	delete(session.participants, userID)
+	s.hub.LeaveRoom(draftCollabRoom(session.draftID), userID)
+	s.broadcastPresence(session, "")
+	empty := len(session.participants) == 0
+	session.mu.Unlock()
+
+	if !empty {
+		return
+	}
+
+	s.flushSession(session)
+
+	// 保存期间可能有人重新加入
+	s.mu.Lock()
+	session.mu.Lock()
+	if len(session.participants) == 0 {
+		session.closed = true
+		delete(s.sessions, session.draftID)
+	}
+	session.mu.Unlock()
+	s.mu.Unlock()
+}
+
+// ApplyOperation 应用编辑操作
+func (s *draftCollabService) ApplyOperation(draftID uint64, userID string, baseRevision int, op *TextOperation) (int, error) {
+	session, err := s.findSession(draftID)
+	if err != nil {
+		return 0, err
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	participant, exists := session.participants[userID]
+	if !exists {
+		return 0, fmt.Errorf("请先加入草稿房间")
+	}
+	if !participant.CanEdit {
+		return 0, constant.ErrPermissionDenied
+	}
+	if baseRevision < session.historyStart || baseRevision > session.revision {
+		return 0, ErrDraftCollabOutOfSync
+	}
+
+	// 1. 变换到最新修订
+	for _, concurrent := range session.history[baseRevision-session.historyStart:] {
+		if op, _, err = TransformOperations(op, concurrent); err != nil {
+			return 0, fmt.Errorf("无效的编辑操作: %w", err)
+		}
+	}
+
+	// 2. 应用
+	content, err := op.Apply(session.content)
+	if err != nil {
+		return 0, fmt.Errorf("无效的编辑操作: %w", err)
+	}
+	session.content = content
+	session.revision++
+	session.history = append(session.history, op)
+	if len(session.history) > draftCollabHistoryLimit {
+		trim := len(session.history) - draftCollabHistoryLimit
+		session.history = append([]*TextOperation(nil), session.history[trim:]...)
+		session.historyStart += trim
+	}
+
+	// 3. 其他成员的光标随操作移动
+	for _, p := range session.participants {
+		p.Cursor = op.TransformIndex(p.Cursor)
+		p.SelectionEnd = op.TransformIndex(p.SelectionEnd)
+	}
+
+	// 4. 持有会话锁确认和广播，保证各成员收到的操作（含自己的确认）顺序与修订号一致
+	s.hub.SendToUser(userID, &wsLib.WSMessage{
+		Type:      wsLib.MessageTypeDraftAck,
+		Data:      DraftCollabAckEvent{DraftID: draftID, Revision: session.revision},
+		Timestamp: time.Now().Unix(),
+	})
+	s.hub.BroadcastToRoom(draftCollabRoom(draftID), &wsLib.WSMessage{
+		Type: wsLib.MessageTypeDraftOp,
+		Data: DraftCollabOpEvent{
+			DraftID:  draftID,
+			UserID:   userID,
+			Revision: session.revision,
+			Ops:      op,
+		},
+		Timestamp: time.Now().Unix(),
+	}, userID)
+
+	return session.revision, nil
+}
+
+// UpdateCursor 更新光标/选区
+func (s *draftCollabService) UpdateCursor(draftID uint64, userID string, revision, cursor, selectionEnd int) error {
+	session, err := s.findSession(draftID)
+	if err != nil {
+		return err
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	participant, exists := session.participants[userID]
+	if !exists {
+		return fmt.Errorf("请先加入草稿房间")
+	}
+	if revision < session.historyStart || revision > session.revision {
+		return ErrDraftCollabOutOfSync
+	}
+
+	// 变换到最新修订
+	for _, concurrent := range session.history[revision-session.historyStart:] {
+		cursor = concurrent.TransformIndex(cursor)
+		selectionEnd = concurrent.TransformIndex(selectionEnd)
+	}
+	length := len(utf16.Encode([]rune(session.content)))
+	participant.Cursor = clampIndex(cursor, length)
+	participant.SelectionEnd = clampIndex(selectionEnd, length)
+
+	s.hub.BroadcastToRoom(draftCollabRoom(draftID), &wsLib.WSMessage{
+		Type: wsLib.MessageTypeDraftCursor,
+		Data: DraftCollabCursorEvent{
+			DraftID:      draftID,
+			UserID:       userID,
+			Revision:     session.revision,
+			Cursor:       participant.Cursor,
+			SelectionEnd: participant.SelectionEnd,
+		},
+		Timestamp: time.Now().Unix(),
+	}, userID)
+	return nil
+}
+
+// GetState 查询房间状态
+func (s *draftCollabService) GetState(draftID uint64, userID string) (*DraftCollabState, error) {
+	draft, err := s.articleRepo.FindDraftByID(draftID)
+	if err != nil {
+		return nil, constant.ErrResourceNotFound
+	}
+	if !canAccessDraft(s.articleRepo, s.contributorRepo, draft, userID, contributorViewRoles...) {
+		return nil, constant.ErrPermissionDenied
+	}
+
+	s.mu.Lock()
+	session, exists := s.sessions[draftID]
+	s.mu.Unlock()
+	if !exists {
+		return &DraftCollabState{
+			DraftID:      draftID,
+			Content:      draft.Content,
+			Version:      draft.Version,
+			Participants: []DraftParticipant{},
+		}, nil
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.state(), nil
+}
+
+// FlushAll 保存所有有未保存修改的会话
+func (s *draftCollabService) FlushAll() {
+	s.mu.Lock()
+	sessions := make([]*draftCollabSession, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.mu.Unlock()
+
+	for _, session := range sessions {
+		s.flushSession(session)
+	}
+}
+
+// flushSession 将合并后的文本写回草稿（只覆盖正文，标题等字段以数据库为准）
+// 期间有其他设备通过接口保存时，先把对方的版本归档为快照再覆盖，保证内容可找回
+func (s *draftCollabService) flushSession(session *draftCollabSession) {
+	session.mu.Lock()
+	if session.closed || session.revision == session.savedRevision {
+		session.mu.Unlock()
+		return
+	}
+	content := session.content
+	revision := session.revision
+	knownVersion := session.draftVersion
+	lastSnapshotTime := session.lastSnapshotTime
+	session.mu.Unlock()
+
+	var (
+		latest   *model.ArticleDraft
+		snapshot *model.ArticleDraftSnapshot
+		now      time.Time
+		saved    bool
+		err      error
+	)
+	// 读取后被其他设备抢先保存时重新读取再试一次
+	for attempt := 0; attempt < 2 && !saved; attempt++ {
+		latest, err = s.articleRepo.FindDraftByID(session.draftID)
+		if err != nil || latest.IsPublished {
+			// 草稿已删除或已发布，放弃未保存的修改
+			log.Printf("⚠️  协同草稿已不可编辑，停止保存: DraftID=%d", session.draftID)
+			session.mu.Lock()
+			session.savedRevision = session.revision
+			session.mu.Unlock()
+			return
+		}
+
+		now = time.Now()
+		snapshot = nil
+		if latest.Version != knownVersion || now.Sub(lastSnapshotTime) >= draftSnapshotInterval {
+			snapshot = newDraftSnapshot(latest)
+		}
+
+		updated := *latest
+		updated.Content = content
+		updated.LastEditTime = &now
+		updated.LastDeviceID = draftCollabDeviceID
+
+		saved, err = s.articleRepo.SaveDraftVersion(&updated, latest.Version, snapshot, draftSnapshotLimit)
+		if err != nil {
+			log.Printf("⚠️  保存协同草稿失败: DraftID=%d, Error=%v", session.draftID, err)
+			return
+		}
+	}
+	if !saved {
+		return
+	}
+
+	session.mu.Lock()
+	session.savedRevision = revision
+	session.draftVersion = latest.Version + 1
+	if snapshot != nil {
+		session.lastSnapshotTime = now
+	}
+	s.hub.BroadcastToRoom(draftCollabRoom(session.draftID), &wsLib.WSMessage{
+		Type: wsLib.MessageTypeDraftSaved,
+		Data: DraftCollabSavedEvent{
+			DraftID:  session.draftID,
+			Version:  session.draftVersion,
+			Revision: revision,
+		},
+		Timestamp: now.Unix(),
+	}, "")
+	session.mu.Unlock()
+}
+
+// openSession 获取或创建草稿会话
+func (s *draftCollabService) openSession(draft *model.ArticleDraft) *draftCollabSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session, exists := s.sessions[draft.ID]; exists {
+		return session
+	}
+
+	session := &draftCollabSession{
+		draftID:      draft.ID,
+		content:      draft.Content,
+		draftVersion: draft.Version,
+		participants: make(map[string]*DraftParticipant),
+	}
+	if latest, err := s.articleRepo.FindLatestDraftSnapshot(draft.ID); err == nil {
+		session.lastSnapshotTime = latest.CreateTime
+	}
+	s.sessions[draft.ID] = session
+	return session
+}
+
+// findSession 查询已开启的草稿会话
+func (s *draftCollabService) findSession(draftID uint64) (*draftCollabSession, error) {
+	s.mu.Lock()
+	session, exists := s.sessions[draftID]
+	s.mu.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("请先加入草稿房间")
+	}
+	return session, nil
+}
+
+// broadcastPresence 广播房间成员（调用方需持有会话锁）
+func (s *draftCollabService) broadcastPresence(session *draftCollabSession, excludeUserID string) {
+	s.hub.BroadcastToRoom(draftCollabRoom(session.draftID), &wsLib.WSMessage{
+		Type: wsLib.MessageTypeDraftPresence,
+		Data: DraftCollabPresenceEvent{
+			DraftID:      session.draftID,
+			Participants: session.participantList(),
+		},
+		Timestamp: time.Now().Unix(),
+	}, excludeUserID)
+}
+
+// state 当前文档状态（调用方需持有会话锁）
+func (session *draftCollabSession) state() *DraftCollabState {
+	return &DraftCollabState{
+		DraftID:      session.draftID,
+		Content:      session.content,
+		Revision:     session.revision,
+		Version:      session.draftVersion,
+		Participants: session.participantList(),
+	}
+}
+
+// participantList 成员列表（按加入时间排序，调用方需持有会话锁）
+func (session *draftCollabSession) participantList() []DraftParticipant {
+	list := make([]DraftParticipant, 0, len(session.participants))
+	for _, p := range session.participants {
+		list = append(list, *p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].JoinTime.Before(list[j].JoinTime)
+	})
+	return list
+}
+
+// draftCollabRoom 草稿房间名
+func draftCollabRoom(draftID uint64) string {
+	return fmt.Sprintf("draft:%d", draftID)
+}
+
+// draftCollabColor 按用户ID分配固定的光标颜色
+func draftCollabColor(userID string) string {
+	h := fnv.New32a()
+	h.Write([]byte(userID))
+	return draftCollabColors[h.Sum32()%uint32(len(draftCollabColors))]
+}
+
+// clampIndex 将位置限制在 [0, length]
+func clampIndex(index, length int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
@@ -0,0 +1,469 @@
+package service
+
+import (
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/repository"
+	"context"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DynamicService 动态服务接口
+type DynamicService interface {
+	// 发布与删除
+	CreateDynamic(ctx context.Context, userID string, req *CreateDynamicRequest, images []*multipart.FileHeader) (*DynamicItem, error)
+	DeleteDynamic(dynamicID uint64, userID string) error
+
+	// 查询
+	GetDynamicDetail(dynamicID uint64) (*DynamicItem, error)
+	GetUserDynamics(userID string, page, pageSize int) ([]*DynamicItem, int64, error)
+	GetTopicDynamics(topicID uint64, page, pageSize int) ([]*DynamicItem, int64, error)
+	GetReposts(repostType int8, repostID uint64, page, pageSize int) ([]*DynamicItem, int64, error)
+
+	// 个人主页时间线（动态与文章混排）
+	GetUserTimeline(userID string, page, pageSize int) ([]*TimelineItem, int64, error)
+}
+
+// ==================== 请求/响应结构 ====================
+
+// CreateDynamicRequest 发布动态请求（multipart表单，图片通过images字段上传）
+type CreateDynamicRequest struct {
+	Content      string   `form:"content" json:"content"`
+	Topics       []string `form:"topics" json:"topics"`
+	RepostType   int8     `form:"repost_type" json:"repost_type"` // 0-原创 1-转发动态 2-转发文章
+	RepostID     uint64   `form:"repost_id" json:"repost_id"`
+	AllowComment *bool    `form:"allow_comment" json:"allow_comment"`
+	AllowRepost  *bool    `form:"allow_repost" json:"allow_repost"`
+	IP           string   `form:"-" json:"-"`
+}
+
+// DynamicItem 动态展示项
+type DynamicItem struct {
+	*model.Dynamic
+	AuthorName   string        `json:"author_name"`
+	AuthorAvatar string        `json:"author_avatar"`
+	Quoted       *DynamicQuote `json:"quoted,omitempty"` // 被转发的内容
+}
+
+// DynamicQuote 被转发内容摘要
+type DynamicQuote struct {
+	Type       int8     `json:"type"` // 1-动态 2-文章
+	ID         uint64   `json:"id"`
+	Available  bool     `json:"available"` // 原内容已删除或不可见时为false
+	UserID     string   `json:"user_id,omitempty"`
+	AuthorName string   `json:"author_name,omitempty"`
+	Content    string   `json:"content,omitempty"`
+	Images     []string `json:"images,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	CoverImage string   `json:"cover_image,omitempty"`
+}
+
+// TimelineItem 时间线条目
+type TimelineItem struct {
+	Type    string           `json:"type"` // dynamic / article
+	Time    time.Time        `json:"time"`
+	Dynamic *DynamicItem     `json:"dynamic,omitempty"`
+	Article *ArticleListItem `json:"article,omitempty"`
+}
+
+// hashtagPattern 匹配内容中的 #话题# 形式
+var hashtagPattern = regexp.MustCompile(`#([^#\s]{1,50})#`)
+
+type dynamicService struct {
+	dynamicRepo   repository.DynamicRepository
+	articleRepo   repository.ArticleV3Repository
+	userRepo      repository.UserRepository
+	uploadService UploadServiceV2
+	levelService  LevelService
+}
+
+// NewDynamicService 创建DynamicService实例
+func NewDynamicService(
+	dynamicRepo repository.DynamicRepository,
+	articleRepo repository.ArticleV3Repository,
+	userRepo repository.UserRepository,
+	uploadService UploadServiceV2,
+	levelService LevelService,
+) DynamicService {
+	return &dynamicService{
+		dynamicRepo:   dynamicRepo,
+		articleRepo:   articleRepo,
+		userRepo:      userRepo,
+		uploadService: uploadService,
+		levelService:  levelService,
+	}
+}
+
+// ==================== 发布与删除 ====================
+
+// CreateDynamic 发布动态（支持九宫格图片、#话题#、转发并评论）
+func (s *dynamicService) CreateDynamic(ctx context.Context, userID string, req *CreateDynamicRequest, images []*multipart.FileHeader) (*DynamicItem, error) {
+	// 1. 参数验证
+	content := strings.TrimSpace(req.Content)
+	if len(images) > model.DynamicMaxImages {
+		return nil, fmt.Errorf("最多上传%d张图片", model.DynamicMaxImages)
+	}
+	if utf8.RuneCountInString(content) > model.DynamicMaxContentLength {
+		return nil, fmt.Errorf("动态内容不能超过%d字", model.DynamicMaxContentLength)
+	}
+	if req.RepostType == model.DynamicRepostTypeNone && content == "" && len(images) == 0 {
+		return nil, fmt.Errorf("动态内容不能为空")
+	}
+	if content == "" {
+		content = "转发"
+	}
+	if containsLink(content) && !s.levelService.HasPrivilege(userID, model.PrivilegePostLink) {
+		return nil, constant.ErrLevelTooLowForLink
+	}
+
+	// 2. 校验转发对象（在上传图片之前，避免产生无主文件）
+	if err := s.checkRepostTarget(req.RepostType, req.RepostID); err != nil {
+		return nil, err
+	}
+	for range images {
+		if err := s.levelService.ConsumeUploadQuota(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. 上传图片
+	imageURLs := make([]string, 0, len(images))
+	for _, file := range images {
+		url, err := s.uploadService.UploadImage(ctx, file)
+		if err != nil {
+			s.cleanupImages(ctx, imageURLs)
+			return nil, fmt.Errorf("上传图片失败: %w", err)
+		}
+		imageURLs = append(imageURLs, url)
+	}
+
+	// 4. 构建动态对象
+	dynamic := &model.Dynamic{
+		UserID:       userID,
+		Content:      content,
+		Images:       model.JSONStringList(imageURLs),
+		Topics:       model.JSONStringList(collectTopics(content, req.Topics)),
+		RepostType:   req.RepostType,
+		RepostID:     req.RepostID,
+		Status:       model.DynamicStatusNormal,
+		AllowComment: true,
+		AllowRepost:  true,
+		IP:           req.IP,
+	}
+	if req.RepostType == model.DynamicRepostTypeNone {
+		dynamic.RepostID = 0
+	}
+	if req.AllowComment != nil {
+		dynamic.AllowComment = *req.AllowComment
+	}
+	if req.AllowRepost != nil {
+		dynamic.AllowRepost = *req.AllowRepost
+	}
+
+	// 5. 保存动态
+	if err := s.dynamicRepo.Create(dynamic); err != nil {
+		s.cleanupImages(ctx, imageURLs)
+		return nil, fmt.Errorf("发布动态失败: %w", err)
+	}
+
+	// 6. 关联话题
+	s.handleTopics(dynamic.ID, userID, dynamic.Topics)
+
+	// 7. 更新被转发对象的转发数
+	switch dynamic.RepostType {
+	case model.DynamicRepostTypeDynamic:
+		s.dynamicRepo.IncrementRepostCount(dynamic.RepostID)
+	case model.DynamicRepostTypeArticle:
+		s.articleRepo.IncrementShareCount(dynamic.RepostID)
+	}
+
+	// 8. 发放经验
+	s.levelService.AwardXP(userID, model.XPEventPublishDynamic, fmt.Sprintf("dynamic:%d", dynamic.ID))
+
+	return s.buildItems([]model.Dynamic{*dynamic})[0], nil
+}
+
+// DeleteDynamic 删除动态（软删除）
+func (s *dynamicService) DeleteDynamic(dynamicID uint64, userID string) error {
+	// 1. 检查权限
+	if !s.dynamicRepo.CheckOwnership(dynamicID, userID) {
+		return constant.ErrPermissionDenied
+	}
+
+	// 2. 获取动态
+	dynamic, err := s.dynamicRepo.FindByID(dynamicID)
+	if err != nil {
+		return fmt.Errorf("动态不存在")
+	}
+
+	// 3. 软删除
+	if err := s.dynamicRepo.SoftDelete(dynamicID); err != nil {
+		return fmt.Errorf("删除动态失败: %w", err)
+	}
+
+	// 4. 回退被转发动态的转发数（文章分享数为累计值，不回退）
+	if dynamic.RepostType == model.DynamicRepostTypeDynamic {
+		s.dynamicRepo.DecrementRepostCount(dynamic.RepostID)
+	}
+
+	return nil
+}
+
+// ==================== 查询 ====================
+
+// GetDynamicDetail 获取动态详情
+func (s *dynamicService) GetDynamicDetail(dynamicID uint64) (*DynamicItem, error) {
+	dynamic, err := s.dynamicRepo.FindByID(dynamicID)
+	if err != nil {
+		return nil, fmt.Errorf("动态不存在")
+	}
+	return s.buildItems([]model.Dynamic{*dynamic})[0], nil
+}
+
+// GetUserDynamics 获取用户的动态列表
+func (s *dynamicService) GetUserDynamics(userID string, page, pageSize int) ([]*DynamicItem, int64, error) {
+	dynamics, total, err := s.dynamicRepo.FindByUserID(userID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询动态列表失败: %w", err)
+	}
+	return s.buildItems(dynamics), total, nil
+}
+
+// GetTopicDynamics 获取话题下的动态
+func (s *dynamicService) GetTopicDynamics(topicID uint64, page, pageSize int) ([]*DynamicItem, int64, error) {
+	dynamics, total, err := s.dynamicRepo.FindByTopicID(topicID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询话题动态失败: %w", err)
+	}
+	return s.buildItems(dynamics), total, nil
+}
+
+// GetReposts 获取动态或文章的转发列表
+func (s *dynamicService) GetReposts(repostType int8, repostID uint64, page, pageSize int) ([]*DynamicItem, int64, error) {
+	dynamics, total, err := s.dynamicRepo.FindReposts(repostType, repostID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询转发列表失败: %w", err)
+	}
+	return s.buildItems(dynamics), total, nil
+}
+
+// GetUserTimeline 获取用户主页时间线（动态与已发布公开文章按时间混排）
+func (s *dynamicService) GetUserTimeline(userID string, page, pageSize int) ([]*TimelineItem, int64, error) {
+	// 1. 查询时间线条目
+	entries, total, err := s.dynamicRepo.FindTimeline(userID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询时间线失败: %w", err)
+	}
+
+	// 2. 按类型收集ID
+	var dynamicIDs, articleIDs []uint64
+	for _, entry := range entries {
+		if entry.ItemType == model.TimelineItemDynamic {
+			dynamicIDs = append(dynamicIDs, entry.ItemID)
+		} else {
+			articleIDs = append(articleIDs, entry.ItemID)
+		}
+	}
+
+	// 3. 批量加载详情
+	dynamics, err := s.dynamicRepo.FindByIDs(dynamicIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询动态失败: %w", err)
+	}
+	dynamicMap := make(map[uint64]*DynamicItem, len(dynamics))
+	for _, item := range s.buildItems(dynamics) {
+		dynamicMap[item.ID] = item
+	}
+
+	articles, err := s.articleRepo.FindByIDs(articleIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询文章失败: %w", err)
+	}
+	author, _ := s.userRepo.FindByID(userID)
+	articleMap := make(map[uint64]*ArticleListItem, len(articles))
+	for i := range articles {
+		item := &ArticleListItem{ArticleV3: &articles[i], AuthorName: "未知"}
+		if author != nil {
+			item.AuthorName = author.Username
+			item.AuthorAvatar = author.Icon
+		}
+		articleMap[articles[i].ID] = item
+	}
+
+	// 4. 按原顺序组装
+	items := make([]*TimelineItem, 0, len(entries))
+	for _, entry := range entries {
+		item := &TimelineItem{Type: entry.ItemType, Time: entry.ItemTime}
+		if entry.ItemType == model.TimelineItemDynamic {
+			item.Dynamic = dynamicMap[entry.ItemID]
+			if item.Dynamic == nil {
+				continue
+			}
+		} else {
+			item.Article = articleMap[entry.ItemID]
+			if item.Article == nil {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, total, nil
+}
+
+// ==================== 辅助方法 ====================
+
+// checkRepostTarget 校验转发对象是否存在且允许转发
+func (s *dynamicService) checkRepostTarget(repostType int8, repostID uint64) error {
+	switch repostType {
+	case model.DynamicRepostTypeNone:
+		return nil
+	case model.DynamicRepostTypeDynamic:
+		origin, err := s.dynamicRepo.FindByID(repostID)
+		if err != nil {
+			return fmt.Errorf("被转发的动态不存在")
+		}
+		if !origin.AllowRepost {
+			return fmt.Errorf("该动态不允许转发")
+		}
+		return nil
+	case model.DynamicRepostTypeArticle:
+		article, err := s.articleRepo.FindByID(repostID)
+		if err != nil || article.Status != model.ArticleV3StatusPublished {
+			return fmt.Errorf("被转发的文章不存在")
+		}
+		if article.Visibility != model.ArticleVisibilityPublic {
+			return fmt.Errorf("非公开文章不允许转发")
+		}
+		if !article.AllowRepost {
+			return fmt.Errorf("该文章不允许转发")
+		}
+		return nil
+	default:
+		return fmt.Errorf("无效的转发类型")
+	}
+}
+
+// handleTopics 关联话题（不存在则创建，等级不足时只关联已有话题）
+func (s *dynamicService) handleTopics(dynamicID uint64, userID string, topicNames []string) {
+	canCreate := s.levelService.HasPrivilege(userID, model.PrivilegeCreateTopic)
+	for _, topicName := range topicNames {
+		topic, err := s.articleRepo.FindTopicByName(topicName)
+		if err != nil {
+			if !canCreate {
+				continue
+			}
+			topic = &model.Topic{
+				Name:      topicName,
+				CreatorID: userID,
+				Status:    1,
+			}
+			if err := s.articleRepo.CreateTopic(topic); err != nil {
+				log.Printf("⚠️ 创建话题失败: %s, %v", topicName, err)
+				continue
+			}
+		}
+
+		if err := s.dynamicRepo.AddTopic(dynamicID, topic.ID); err != nil {
+			log.Printf("⚠️ 关联动态话题失败: dynamic=%d topic=%d, %v", dynamicID, topic.ID, err)
+		}
+	}
+}
+
+// cleanupImages 清理已上传的图片（发布失败时调用）
+func (s *dynamicService) cleanupImages(ctx context.Context, urls []string) {
+	for _, url := range urls {
+		if err := s.uploadService.DeleteFile(ctx, url); err != nil {
+			log.Printf("⚠️ 清理动态图片失败: %s, %v", url, err)
+		}
+	}
+}
+
+// buildItems 组装动态展示项（作者信息与被转发内容）
+func (s *dynamicService) buildItems(dynamics []model.Dynamic) []*DynamicItem {
+	authors := make(map[string]*model.User)
+	getAuthor := func(userID string) *model.User {
+		if author, ok := authors[userID]; ok {
+			return author
+		}
+		author, _ := s.userRepo.FindByID(userID)
+		authors[userID] = author
+		return author
+	}
+
+	items := make([]*DynamicItem, 0, len(dynamics))
+	for i := range dynamics {
+		item := &DynamicItem{Dynamic: &dynamics[i], AuthorName: "未知"}
+		if author := getAuthor(dynamics[i].UserID); author != nil {
+			item.AuthorName = author.Username
+			item.AuthorAvatar = author.Icon
+		}
+		if dynamics[i].RepostType != model.DynamicRepostTypeNone {
+			item.Quoted = s.buildQuote(dynamics[i].RepostType, dynamics[i].RepostID, getAuthor)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// buildQuote 构建被转发内容摘要
+func (s *dynamicService) buildQuote(repostType int8, repostID uint64, getAuthor func(string) *model.User) *DynamicQuote {
+	quote := &DynamicQuote{Type: repostType, ID: repostID}
+
+	switch repostType {
+	case model.DynamicRepostTypeDynamic:
+		origin, err := s.dynamicRepo.FindByID(repostID)
+		if err != nil {
+			return quote
+		}
+		quote.Available = true
+		quote.UserID = origin.UserID
+		quote.Content = origin.Content
+		quote.Images = origin.Images
+	case model.DynamicRepostTypeArticle:
+		article, err := s.articleRepo.FindByID(repostID)
+		if err != nil || article.Status != model.ArticleV3StatusPublished || article.Visibility != model.ArticleVisibilityPublic {
+			return quote
+		}
+		quote.Available = true
+		quote.UserID = article.UserID
+		quote.Title = article.Title
+		quote.Summary = article.Summary
+		quote.CoverImage = article.CoverImage
+	}
+
+	if author := getAuthor(quote.UserID); author != nil {
+		quote.AuthorName = author.Username
+	}
+	return quote
+}
+
+// collectTopics 合并显式话题与内容中的 #话题#（去重，限制数量）
+func collectTopics(content string, explicit []string) []string {
+	topics := make([]string, 0, model.DynamicMaxTopics)
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		name = strings.Trim(strings.TrimSpace(name), "#")
+		if name == "" || seen[name] || len(topics) >= model.DynamicMaxTopics {
+			return
+		}
+		seen[name] = true
+		topics = append(topics, name)
+	}
+
+	for _, name := range explicit {
+		add(name)
+	}
+	for _, match := range hashtagPattern.FindAllStringSubmatch(content, -1) {
+		add(match[1])
+	}
+	return topics
+}
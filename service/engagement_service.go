@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/geoip"
+	"astronomer-gin/pkg/queue"
+	"astronomer-gin/pkg/redis"
+	"astronomer-gin/repository"
+)
+
+// EngagementService 阅读参与度服务接口
+type EngagementService interface {
+	// 接收客户端阅读上报（校验、去重、归类后投递队列）
+	TrackEngagement(ctx context.Context, articleID uint64, req *EngagementBeaconRequest) error
+	// 写入统计（由Worker调用）
+	ApplyEngagement(event *EngagementEvent) error
+}
+
+// EngagementBeaconRequest 阅读上报请求（建议在页面隐藏/离开时通过 sendBeacon 上报一次）
+type EngagementBeaconRequest struct {
+	Progress float64 `json:"progress" binding:"min=0,max=100"` // 阅读进度（滚动深度%）
+	StayTime int     `json:"stay_time" binding:"min=0"`        // 停留时间（秒）
+	Referrer string  `json:"referrer"`                         // document.referrer
+
+	// 以下由Handler填充
+	UserID    string `json:"-"`
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+	Host      string `json:"-"`
+}
+
+// EngagementEvent 归类后的阅读事件（队列消息，不含IP等原始信息）
+type EngagementEvent struct {
+	ArticleID uint64    `json:"article_id"`
+	Progress  float64   `json:"progress"`
+	StayTime  int       `json:"stay_time"`
+	Source    string    `json:"source"`
+	Device    string    `json:"device"`
+	Region    string    `json:"region"`
+	EventTime time.Time `json:"event_time"`
+}
+
+const (
+	// engagementDedupeWindow 同一访客对同一文章的上报去重窗口
+	engagementDedupeWindow = 30 * time.Minute
+	// engagementMaxStayTime 单次停留时间上限（超出按上限计，避免挂机页面拉高均值）
+	engagementMaxStayTime = 2 * 60 * 60
+)
+
+type engagementService struct {
+	statsRepo   repository.ArticleStatsRepository
+	articleRepo repository.ArticleV3Repository
+}
+
+// NewEngagementService 创建EngagementService实例
+func NewEngagementService(statsRepo repository.ArticleStatsRepository, articleRepo repository.ArticleV3Repository) EngagementService {
+	return &engagementService{
+		statsRepo:   statsRepo,
+		articleRepo: articleRepo,
+	}
+}
+
+// TrackEngagement 接收阅读上报
+func (s *engagementService) TrackEngagement(ctx context.Context, articleID uint64, req *EngagementBeaconRequest) error {
+	// 1. 过滤爬虫
+	device := classifyDevice(req.UserAgent)
+	if device == "" {
+		return nil
+	}
+
+	// 2. 校验文章（作者本人的阅读不计入）
+	article, err := s.articleRepo.FindByID(articleID)
+	if err != nil || article.Status != model.ArticleStatusPublished {
+		return fmt.Errorf("文章不存在")
+	}
+	if req.UserID != "" && req.UserID == article.UserID {
+		return nil
+	}
+
+	// 3. 去重（登录用户按用户ID，匿名访客按IP+UA）
+	if !s.claimEngagement(ctx, articleID, req) {
+		return nil
+	}
+
+	// 4. 归类并投递队列
+	stayTime := req.StayTime
+	if stayTime > engagementMaxStayTime {
+		stayTime = engagementMaxStayTime
+	}
+	event := &EngagementEvent{
+		ArticleID: articleID,
+		Progress:  req.Progress,
+		StayTime:  stayTime,
+		Source:    classifyReferrer(req.Referrer, req.Host),
+		Device:    device,
+		Region:    geoip.Lookup(req.IP),
+		EventTime: time.Now(),
+	}
+
+	if queue.Client != nil {
+		task := queue.CreateTask(queue.TaskTypeEngagement, map[string]interface{}{
+			"article_id": event.ArticleID,
+			"progress":   event.Progress,
+			"stay_time":  event.StayTime,
+			"source":     event.Source,
+			"device":     event.Device,
+			"region":     event.Region,
+			"event_time": event.EventTime.Unix(),
+		})
+		err := queue.Client.PublishTask(ctx, task)
+		if err == nil {
+			return nil
+		}
+		log.Printf("⚠️ 投递阅读统计任务失败，改为同步写入: article=%d, %v", articleID, err)
+	}
+	return s.ApplyEngagement(event)
+}
+
+// ApplyEngagement 写入统计详情与每日汇总
+func (s *engagementService) ApplyEngagement(event *EngagementEvent) error {
+	if err := s.statsRepo.ApplyEngagement(
+		event.ArticleID, event.EventTime,
+		event.Progress, event.StayTime,
+		event.Source, event.Device, event.Region,
+	); err != nil {
+		return fmt.Errorf("写入阅读统计失败: %w", err)
+	}
+	return nil
+}
+
+// claimEngagement 占用去重窗口，返回false表示窗口内已上报过
+func (s *engagementService) claimEngagement(ctx context.Context, articleID uint64, req *EngagementBeaconRequest) bool {
+	if redis.Client == nil {
+		return true
+	}
+
+	visitor := req.UserID
+	if visitor == "" {
+		sum := sha1.Sum([]byte(req.IP + "|" + req.UserAgent))
+		visitor = hex.EncodeToString(sum[:])
+	}
+
+	key := fmt.Sprintf("engagement:%d:%s", articleID, visitor)
+	ok, err := redis.Client.SetNX(ctx, key, 1, engagementDedupeWindow).Result()
+	if err != nil {
+		return true // Redis故障时不丢数据
+	}
+	return ok
+}
+
+// ==================== 来源与设备归类 ====================
+
+// searchEngineDomains 搜索引擎域名
+var searchEngineDomains = []string{
+	"google.com", "google.com.hk", "baidu.com", "bing.com", "sogou.com", "so.com",
+	"sm.cn", "yandex.com", "yandex.ru", "duckduckgo.com", "search.yahoo.com",
+}
+
+// socialDomains 社交媒体与社区域名
+var socialDomains = []string{
+	"weibo.com", "weibo.cn", "qq.com", "zhihu.com", "douban.com", "xiaohongshu.com",
+	"bilibili.com", "juejin.cn", "v2ex.com", "twitter.com", "x.com", "t.co",
+	"facebook.com", "reddit.com", "linkedin.com",
+}
+
+// classifyReferrer 根据Referer归类流量来源
+func classifyReferrer(referrer, host string) string {
+	referrer = strings.TrimSpace(referrer)
+	if referrer == "" {
+		return model.TrafficSourceDirect
+	}
+
+	parsed, err := url.Parse(referrer)
+	if err != nil || parsed.Host == "" {
+		return model.TrafficSourceOther
+	}
+	refHost := strings.ToLower(parsed.Hostname())
+
+	if host != "" && refHost == hostWithoutPort(host) {
+		return model.TrafficSourceInternal
+	}
+	if matchDomain(refHost, searchEngineDomains) {
+		return model.TrafficSourceSearch
+	}
+	if matchDomain(refHost, socialDomains) {
+		return model.TrafficSourceSocial
+	}
+	return model.TrafficSourceOther
+}
+
+// matchDomain 判断host是否为列表中的域名或其子域名
+func matchDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyDevice 根据User-Agent归类设备，爬虫返回空字符串
+func classifyDevice(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return model.DeviceTypeOther
+	}
+
+	if isBotUserAgent(ua) {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		return model.DeviceTypeTablet
+	case strings.Contains(ua, "mobile") || strings.Contains(ua, "iphone") ||
+		strings.Contains(ua, "ipod") || strings.Contains(ua, "windows phone"):
+		return model.DeviceTypeMobile
+	case strings.Contains(ua, "windows") || strings.Contains(ua, "macintosh") ||
+		strings.Contains(ua, "x11") || strings.Contains(ua, "cros"):
+		return model.DeviceTypeDesktop
+	default:
+		return model.DeviceTypeOther
+	}
+}
+
+// hostWithoutPort 去除Host中的端口
+func hostWithoutPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
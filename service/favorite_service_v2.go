@@ -18,7 +18,7 @@ type FavoriteServiceV2 interface {
 	FavoriteArticle(userID string, articleID uint64) error
 	UnfavoriteArticle(userID string, articleID uint64) error
 	IsFavorited(userID string, articleID uint64) bool
-	GetUserFavorites(userID string, page, pageSize int) ([]model.Article, int64, error)
+	GetUserFavorites(userID string, page, pageSize int) ([]model.ArticleV3, int64, error)
 
 	// 缓存管理
 	RefreshFavoriteCache(userID string) error
@@ -27,16 +27,16 @@ type FavoriteServiceV2 interface {
 
 type favoriteServiceV2 struct {
 	favoriteRepo repository.FavoriteRepository
-	blogRepo     repository.BlogRepository
+	articleRepo  repository.ArticleV3Repository
 	notifyRepo   repository.NotificationRepository
 	cacheHelper  *util.CacheHelper
 	db           *gorm.DB
 }
 
-func NewFavoriteServiceV2(favoriteRepo repository.FavoriteRepository, blogRepo repository.BlogRepository, notifyRepo repository.NotificationRepository) FavoriteServiceV2 {
+func NewFavoriteServiceV2(favoriteRepo repository.FavoriteRepository, articleRepo repository.ArticleV3Repository, notifyRepo repository.NotificationRepository) FavoriteServiceV2 {
 	return &favoriteServiceV2{
 		favoriteRepo: favoriteRepo,
-		blogRepo:     blogRepo,
+		articleRepo:  articleRepo,
 		notifyRepo:   notifyRepo,
 		cacheHelper:  util.NewCacheHelper(redis.GetClient()),
 		db:           database.GetDB(),
@@ -46,7 +46,7 @@ func NewFavoriteServiceV2(favoriteRepo repository.FavoriteRepository, blogRepo r
 // FavoriteArticle 收藏文章（企业级实现）
 func (s *favoriteServiceV2) FavoriteArticle(userID string, articleID uint64) error {
 	// 1. 检查文章是否存在
-	article, err := s.blogRepo.FindByID(articleID)
+	article, err := s.articleRepo.FindByID(articleID)
 	if err != nil {
 		return constant.ErrArticleNotFound
 	}
@@ -70,7 +70,7 @@ func (s *favoriteServiceV2) FavoriteArticle(userID string, articleID uint64) err
 		}
 
 		// 3.2 增加文章收藏数
-		if err := tx.Model(&model.Article{}).Where("id = ?", articleID).
+		if err := tx.Model(&model.ArticleV3{}).Where("id = ?", articleID).
 			UpdateColumn("favorite_count", gorm.Expr("favorite_count + ?", 1)).Error; err != nil {
 			return err
 		}
@@ -107,7 +107,7 @@ func (s *favoriteServiceV2) UnfavoriteArticle(userID string, articleID uint64) e
 		}
 
 		// 2.2 减少文章收藏数
-		if err := tx.Model(&model.Article{}).Where("id = ?", articleID).
+		if err := tx.Model(&model.ArticleV3{}).Where("id = ?", articleID).
 			UpdateColumn("favorite_count", gorm.Expr("favorite_count - ?", 1)).Error; err != nil {
 			return err
 		}
@@ -131,7 +131,7 @@ func (s *favoriteServiceV2) IsFavorited(userID string, articleID uint64) bool {
 }
 
 // GetUserFavorites 获取用户的收藏列表（带缓存）
-func (s *favoriteServiceV2) GetUserFavorites(userID string, page, pageSize int) ([]model.Article, int64, error) {
+func (s *favoriteServiceV2) GetUserFavorites(userID string, page, pageSize int) ([]model.ArticleV3, int64, error) {
 	// 1. 参数验证
 	if page < 1 {
 		page = constant.DefaultPage
@@ -144,7 +144,7 @@ func (s *favoriteServiceV2) GetUserFavorites(userID string, page, pageSize int)
 	cacheKey := fmt.Sprintf("%s%d:page:%d:size:%d", constant.CacheKeyFavorite, userID, page, pageSize)
 
 	type CachedData struct {
-		Articles []model.Article
+		Articles []model.ArticleV3
 		Total    int64
 	}
 
@@ -161,11 +161,11 @@ func (s *favoriteServiceV2) GetUserFavorites(userID string, page, pageSize int)
 			}
 
 			if len(articleIDs) == 0 {
-				return CachedData{Articles: []model.Article{}, Total: total}, nil
+				return CachedData{Articles: []model.ArticleV3{}, Total: total}, nil
 			}
 
 			// 根据ID列表查询文章详情
-			articles, err := s.blogRepo.FindByIDs(articleIDs)
+			articles, err := s.articleRepo.FindByIDs(articleIDs)
 			if err != nil {
 				return nil, err
 			}
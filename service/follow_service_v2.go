@@ -22,8 +22,9 @@ type FollowServiceV2 interface {
 	FollowUser(userID string, followUserID string, username string) error
 	UnfollowUser(userID string, followUserID string) error
 	IsFollowing(userID string, followUserID string) bool
-	GetFollowers(userID string, page, pageSize int) ([]model.User, int64, error)
-	GetFollowing(userID string, page, pageSize int) ([]model.User, int64, error)
+	// viewerID 为查看者（未登录为空），受被查看用户的隐私设置限制
+	GetFollowers(userID, viewerID string, page, pageSize int) ([]model.User, int64, error)
+	GetFollowing(userID, viewerID string, page, pageSize int) ([]model.User, int64, error)
 
 	// 好友相关（互关即好友）
 	IsFriend(userID, targetUserID string) bool
@@ -45,15 +46,17 @@ type followServiceV2 struct {
 	followRepo  repository.FollowRepository
 	userRepo    repository.UserRepository
 	notifyRepo  repository.NotificationRepository
+	privacy     PrivacyService
 	cacheHelper *util.CacheHelper
 	db          *gorm.DB
 }
 
-func NewFollowServiceV2(followRepo repository.FollowRepository, userRepo repository.UserRepository, notifyRepo repository.NotificationRepository) FollowServiceV2 {
+func NewFollowServiceV2(followRepo repository.FollowRepository, userRepo repository.UserRepository, notifyRepo repository.NotificationRepository, privacy PrivacyService) FollowServiceV2 {
 	return &followServiceV2{
 		followRepo:  followRepo,
 		userRepo:    userRepo,
 		notifyRepo:  notifyRepo,
+		privacy:     privacy,
 		cacheHelper: util.NewCacheHelper(redis.GetClient()),
 		db:          database.GetDB(),
 	}
@@ -190,7 +193,7 @@ func (s *followServiceV2) IsFollowing(userID, followUserID string) bool {
 }
 
 // GetFollowers 获取粉丝列表（带缓存）
-func (s *followServiceV2) GetFollowers(userID string, page, pageSize int) ([]model.User, int64, error) {
+func (s *followServiceV2) GetFollowers(userID, viewerID string, page, pageSize int) ([]model.User, int64, error) {
 	// 1. 参数验证
 	if page < 1 {
 		page = constant.DefaultPage
@@ -199,6 +202,11 @@ func (s *followServiceV2) GetFollowers(userID string, page, pageSize int) ([]mod
 		pageSize = constant.DefaultPageSize
 	}
 
+	// 隐私设置（列表缓存按被查看用户共享，须在读缓存前检查）
+	if err := s.privacy.CheckFollowersVisible(userID, viewerID); err != nil {
+		return nil, 0, err
+	}
+
 	// 2. 从缓存获取
 	cacheKey := fmt.Sprintf("%sfollowers:%d:page:%d:size:%d", constant.CacheKeyFollow, userID, page, pageSize)
 
@@ -245,7 +253,7 @@ func (s *followServiceV2) GetFollowers(userID string, page, pageSize int) ([]mod
 }
 
 // GetFollowing 获取关注列表（带缓存）
-func (s *followServiceV2) GetFollowing(userID string, page, pageSize int) ([]model.User, int64, error) {
+func (s *followServiceV2) GetFollowing(userID, viewerID string, page, pageSize int) ([]model.User, int64, error) {
 	// 1. 参数验证
 	if page < 1 {
 		page = constant.DefaultPage
@@ -254,6 +262,11 @@ func (s *followServiceV2) GetFollowing(userID string, page, pageSize int) ([]mod
 		pageSize = constant.DefaultPageSize
 	}
 
+	// 隐私设置（列表缓存按被查看用户共享，须在读缓存前检查）
+	if err := s.privacy.CheckFollowingVisible(userID, viewerID); err != nil {
+		return nil, 0, err
+	}
+
 	// 2. 从缓存获取
 	cacheKey := fmt.Sprintf("%sfollowing:%d:page:%d:size:%d", constant.CacheKeyFollow, userID, page, pageSize)
 
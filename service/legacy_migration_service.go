@@ -0,0 +1,628 @@
+package service
+
+import (
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/repository"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/russross/blackfriday/v2"
+	"gorm.io/gorm"
+)
+
+// LegacyMigrationService 旧版数据迁移服务接口
+type LegacyMigrationService interface {
+	// Run 按阶段顺序执行迁移（已完成的阶段跳过，未完成的阶段从检查点继续）
+	Run(batchSize int) ([]model.LegacyMigrationProgress, error)
+	// GetProgress 查询各阶段迁移进度
+	GetProgress() ([]model.LegacyMigrationProgress, error)
+	// ResolveNewID 查询旧版ID对应的V3 ID（旧链接跳转）
+	ResolveNewID(entity string, oldID uint64) (uint64, error)
+}
+
+type legacyMigrationService struct {
+	migrationRepo repository.LegacyMigrationRepository
+}
+
+// NewLegacyMigrationService 创建LegacyMigrationService实例
+func NewLegacyMigrationService(migrationRepo repository.LegacyMigrationRepository) LegacyMigrationService {
+	return &legacyMigrationService{
+		migrationRepo: migrationRepo,
+	}
+}
+
+const (
+	defaultLegacyBatchSize = 200
+	maxLegacyBatchSize     = 2000
+)
+
+// Run 执行迁移
+func (s *legacyMigrationService) Run(batchSize int) ([]model.LegacyMigrationProgress, error) {
+	if batchSize <= 0 {
+		batchSize = defaultLegacyBatchSize
+	}
+	if batchSize > maxLegacyBatchSize {
+		batchSize = maxLegacyBatchSize
+	}
+
+	// 1. 先为所有阶段建立检查点，源表最大ID在任何写入之前快照，
+	//    之后新产生的点赞/收藏都已指向V3文章，不在迁移范围内
+	progresses := make(map[string]*model.LegacyMigrationProgress, len(model.LegacyMigrationStages))
+	for _, stage := range model.LegacyMigrationStages {
+		progress, err := s.migrationRepo.GetProgress(stage)
+		if err != nil {
+			return nil, fmt.Errorf("初始化迁移进度失败: stage=%s: %w", stage, err)
+		}
+		progresses[stage] = progress
+	}
+
+	// 2. 按顺序执行各阶段
+	for _, stage := range model.LegacyMigrationStages {
+		progress := progresses[stage]
+		if progress.Done {
+			log.Printf("⏭️  迁移阶段已完成，跳过: %s", stage)
+			continue
+		}
+
+		log.Printf("🚚 开始迁移阶段: %s (LastID=%d, MaxID=%d)", stage, progress.LastID, progress.MaxID)
+		var err error
+		switch stage {
+		case model.LegacyStageArticles:
+			err = s.migrateArticles(progress, batchSize)
+		case model.LegacyStageComments:
+			err = s.migrateComments(progress, batchSize)
+		case model.LegacyStageReplies:
+			err = s.migrateReplies(progress, batchSize)
+		case model.LegacyStageStars:
+			err = s.convertStars(progress, batchSize)
+		case model.LegacyStageFavorites:
+			err = s.convertFavorites(progress, batchSize)
+		case model.LegacyStageCounters:
+			err = s.recountArticles(progress)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("迁移阶段失败: stage=%s: %w", stage, err)
+		}
+		log.Printf("✅ 迁移阶段完成: %s (处理=%d, 跳过=%d, 冲突=%d)",
+			stage, progress.Processed, progress.Skipped, progress.Conflicts)
+	}
+
+	return s.GetProgress()
+}
+
+// GetProgress 查询迁移进度
+func (s *legacyMigrationService) GetProgress() ([]model.LegacyMigrationProgress, error) {
+	list, err := s.migrationRepo.FindAllProgress()
+	if err != nil {
+		return nil, constant.ErrDatabaseQuery
+	}
+	return list, nil
+}
+
+// ResolveNewID 查询旧版ID映射
+func (s *legacyMigrationService) ResolveNewID(entity string, oldID uint64) (uint64, error) {
+	newID, err := s.migrationRepo.FindNewID(entity, oldID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, constant.ErrResourceNotFound
+		}
+		return 0, constant.ErrDatabaseQuery
+	}
+	return newID, nil
+}
+
+// ==================== 文章与草稿 ====================
+
+// migrateArticles 迁移旧版文章（草稿进入草稿箱，已发布与已删除的进入文章表）
+func (s *legacyMigrationService) migrateArticles(progress *model.LegacyMigrationProgress, batchSize int) error {
+	for {
+		articles, err := s.migrationRepo.FindLegacyArticles(progress.LastID, progress.MaxID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			break
+		}
+
+		// 批次中断后重跑时，已写入映射的记录直接跳过
+		oldIDs := make([]uint64, 0, len(articles))
+		for _, a := range articles {
+			oldIDs = append(oldIDs, a.ID)
+		}
+		migratedArticles, err := s.migrationRepo.FindNewIDs(model.LegacyEntityArticle, oldIDs)
+		if err != nil {
+			return err
+		}
+		migratedDrafts, err := s.migrationRepo.FindNewIDs(model.LegacyEntityDraft, oldIDs)
+		if err != nil {
+			return err
+		}
+
+		for i := range articles {
+			legacy := &articles[i]
+			progress.LastID = legacy.ID
+			if _, ok := migratedArticles[legacy.ID]; ok {
+				continue
+			}
+			if _, ok := migratedDrafts[legacy.ID]; ok {
+				continue
+			}
+
+			if legacy.Status == model.ArticleStatusDraft {
+				err = s.migrationRepo.SaveDraft(buildLegacyDraft(legacy), legacy.ID)
+			} else {
+				article, content := buildLegacyArticle(legacy)
+				err = s.migrationRepo.SaveArticle(article, content, legacy.ID)
+			}
+			if err != nil {
+				return fmt.Errorf("迁移文章失败: ID=%d: %w", legacy.ID, err)
+			}
+			progress.Processed++
+		}
+
+		if err := s.migrationRepo.SaveProgress(progress); err != nil {
+			return err
+		}
+	}
+
+	progress.Done = true
+	return s.migrationRepo.SaveProgress(progress)
+}
+
+// buildLegacyArticle 将旧版文章转换为V3文章与内容
+func buildLegacyArticle(legacy *model.Article) (*model.ArticleV3, *model.ArticleContent) {
+	createTime := legacy.CreateTime
+	updateTime := legacy.UpdateTime
+	if updateTime.IsZero() {
+		updateTime = createTime
+	}
+
+	article := &model.ArticleV3{
+		UserID:      legacy.UserID,
+		Title:       truncateRunes(legacy.Title, 200),
+		Summary:     truncateRunes(legacy.Preface, 500),
+		CoverImage:  legacy.Photo,
+		ContentType: model.ArticleContentTypeText,
+		Tags:        model.JSONStringList(splitLegacyTags(legacy.Tag)),
+		Topics:      model.JSONStringList{},
+		Status:      model.ArticleV3StatusPublished,
+		Visibility:  model.ArticleVisibilityPublic,
+		// 旧版文章均已上线，视为审核通过
+		AuditStatus:  model.AuditStatusApproved,
+		AllowComment: legacy.Comment,
+		AllowRepost:  true,
+		// 旧版没有去重数据，三种浏览量均以原阅读量为准
+		ViewCount:      legacy.Visit,
+		RealViewCount:  legacy.Visit,
+		HumanViewCount: legacy.Visit,
+		LikeCount:      legacy.GoodCount,
+		CommentCount:   legacy.CommentCount,
+		FavoriteCount:  legacy.FavoriteCount,
+		PublishTime:    &createTime,
+		CreateTime:     createTime,
+		UpdateTime:     updateTime,
+		ExtInfo:        model.JSONMap{"legacy_id": legacy.ID},
+	}
+	if !legacy.Appear {
+		article.Visibility = model.ArticleVisibilityPrivate
+	}
+	if legacy.Status == model.ArticleStatusDeleted {
+		article.Status = model.ArticleV3StatusDeleted
+		article.DeleteTime = &updateTime
+	}
+
+	wordCount := len([]rune(legacy.Content))
+	readTime := int(math.Ceil(float64(wordCount) / 300.0))
+	if readTime < 1 {
+		readTime = 1
+	}
+	content := &model.ArticleContent{
+		Content:     legacy.Content,
+		ContentHTML: string(blackfriday.Run([]byte(legacy.Content))),
+		WordCount:   wordCount,
+		ReadTime:    readTime,
+	}
+	return article, content
+}
+
+// buildLegacyDraft 将旧版草稿转换为V3草稿
+func buildLegacyDraft(legacy *model.Article) *model.ArticleDraft {
+	updateTime := legacy.UpdateTime
+	if updateTime.IsZero() {
+		updateTime = legacy.CreateTime
+	}
+	return &model.ArticleDraft{
+		UserID:       legacy.UserID,
+		Title:        truncateRunes(legacy.Title, 200),
+		Summary:      truncateRunes(legacy.Preface, 500),
+		CoverImage:   legacy.Photo,
+		Content:      legacy.Content,
+		Tags:         model.JSONStringList(splitLegacyTags(legacy.Tag)),
+		Topics:       model.JSONStringList{},
+		LastEditTime: &updateTime,
+		CreateTime:   legacy.CreateTime,
+		UpdateTime:   updateTime,
+	}
+}
+
+// splitLegacyTags 拆分旧版逗号分隔的标签
+func splitLegacyTags(tag string) []string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool {
+		return r == ',' || r == '，' || r == ';' || r == '；' || r == '|'
+	})
+	return dedupeStrings(parts)
+}
+
+// ==================== 评论 ====================
+
+// migrateComments 迁移一级评论为V3根评论（按旧ID顺序分配楼层）
+func (s *legacyMigrationService) migrateComments(progress *model.LegacyMigrationProgress, batchSize int) error {
+	for {
+		comments, err := s.migrationRepo.FindLegacyCommentParents(progress.LastID, progress.MaxID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(comments) == 0 {
+			break
+		}
+
+		oldIDs := make([]uint64, 0, len(comments))
+		articleOldIDs := make([]uint64, 0, len(comments))
+		userIDs := make([]string, 0, len(comments))
+		for _, c := range comments {
+			oldIDs = append(oldIDs, uint64(c.ID))
+			articleOldIDs = append(articleOldIDs, uint64(c.ArticleID))
+			userIDs = append(userIDs, c.UserID)
+		}
+		migrated, err := s.migrationRepo.FindNewIDs(model.LegacyEntityCommentRoot, oldIDs)
+		if err != nil {
+			return err
+		}
+		articleIDs, err := s.migrationRepo.FindNewIDs(model.LegacyEntityArticle, articleOldIDs)
+		if err != nil {
+			return err
+		}
+		briefIDs := make([]uint64, 0, len(articleIDs))
+		for _, id := range articleIDs {
+			briefIDs = append(briefIDs, id)
+		}
+		briefs, err := s.migrationRepo.FindArticleBriefs(briefIDs)
+		if err != nil {
+			return err
+		}
+		icons, err := s.migrationRepo.FindUserIcons(userIDs)
+		if err != nil {
+			return err
+		}
+
+		for i := range comments {
+			legacy := &comments[i]
+			progress.LastID = uint64(legacy.ID)
+			if _, ok := migrated[uint64(legacy.ID)]; ok {
+				continue
+			}
+
+			// 文章未迁移（草稿或已丢失）的评论无处挂载
+			articleID, ok := articleIDs[uint64(legacy.ArticleID)]
+			if !ok {
+				progress.Skipped++
+				continue
+			}
+			brief := briefs[articleID]
+
+			floor, err := s.migrationRepo.NextFloorNumber(articleID)
+			if err != nil {
+				return err
+			}
+
+			comment := &model.CommentV3{
+				TargetType:  model.CommentTargetTypeArticle,
+				TargetID:    articleID,
+				UserID:      legacy.UserID,
+				Username:    legacy.Username,
+				UserAvatar:  icons[legacy.UserID],
+				ParentID:    0,
+				FloorNumber: floor,
+				ReplyChain:  model.JSONUint64List{},
+				Depth:       0,
+				Content:     legacy.Comment,
+				ContentType: model.CommentContentTypeText,
+				Status:      model.CommentStatusNormal,
+				IsAuthor:    brief.UserID != "" && brief.UserID == legacy.UserID,
+				LikeCount:   int(legacy.GoodCount),
+				IP:          legacy.CommentAddr,
+				AuditStatus: model.CommentAuditStatusApproved,
+				CreateTime:  legacyCommentTime(legacy.CommentTime, brief.CreateTime),
+			}
+			if err := s.migrationRepo.SaveComment(comment, model.LegacyEntityCommentRoot, uint64(legacy.ID)); err != nil {
+				return fmt.Errorf("迁移评论失败: ID=%d: %w", legacy.ID, err)
+			}
+			progress.Processed++
+		}
+
+		if err := s.migrationRepo.SaveProgress(progress); err != nil {
+			return err
+		}
+	}
+
+	progress.Done = true
+	return s.migrationRepo.SaveProgress(progress)
+}
+
+// migrateReplies 迁移二级评论为楼内回复，按被回复人还原回复链
+func (s *legacyMigrationService) migrateReplies(progress *model.LegacyMigrationProgress, batchSize int) error {
+	for {
+		replies, err := s.migrationRepo.FindLegacyCommentReplies(progress.LastID, progress.MaxID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(replies) == 0 {
+			break
+		}
+
+		oldIDs := make([]uint64, 0, len(replies))
+		parentOldIDs := make([]uint64, 0, len(replies))
+		userIDs := make([]string, 0, len(replies))
+		for _, r := range replies {
+			oldIDs = append(oldIDs, uint64(r.ID))
+			parentOldIDs = append(parentOldIDs, uint64(r.ParentCommentID))
+			userIDs = append(userIDs, r.UserID)
+		}
+		migrated, err := s.migrationRepo.FindNewIDs(model.LegacyEntityCommentReply, oldIDs)
+		if err != nil {
+			return err
+		}
+		rootIDs, err := s.migrationRepo.FindNewIDs(model.LegacyEntityCommentRoot, parentOldIDs)
+		if err != nil {
+			return err
+		}
+		icons, err := s.migrationRepo.FindUserIcons(userIDs)
+		if err != nil {
+			return err
+		}
+
+		for i := range replies {
+			legacy := &replies[i]
+			progress.LastID = uint64(legacy.ID)
+			if _, ok := migrated[uint64(legacy.ID)]; ok {
+				continue
+			}
+
+			rootID, ok := rootIDs[uint64(legacy.ParentCommentID)]
+			if !ok {
+				progress.Skipped++
+				continue
+			}
+			// 根评论的回复计数在批次内持续变化，每次重新读取
+			root, err := s.migrationRepo.FindCommentByID(rootID)
+			if err != nil {
+				return err
+			}
+
+			// 旧版只记录被回复人：回复楼主挂在根评论下，否则挂在楼内该用户最近的一条回复下
+			parent := root
+			if legacy.ToUserID != "" && legacy.ToUserID != root.UserID {
+				latest, err := s.migrationRepo.FindLatestReplyByUser(rootID, legacy.ToUserID)
+				if err == nil {
+					parent = latest
+				} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+			}
+
+			subFloor, err := s.migrationRepo.NextSubFloorNumber(rootID)
+			if err != nil {
+				return err
+			}
+
+			chain := make(model.JSONUint64List, 0, len(parent.ReplyChain)+1)
+			chain = append(chain, parent.ReplyChain...)
+			chain = append(chain, parent.ID)
+
+			replyToUserID := legacy.ToUserID
+			if replyToUserID == "" {
+				replyToUserID = parent.UserID
+			}
+
+			comment := &model.CommentV3{
+				TargetType:       root.TargetType,
+				TargetID:         root.TargetID,
+				UserID:           legacy.UserID,
+				Username:         legacy.Username,
+				UserAvatar:       icons[legacy.UserID],
+				ParentID:         parent.ID,
+				RootID:           rootID,
+				ReplyToUserID:    replyToUserID,
+				ReplyToCommentID: parent.ID,
+				FloorNumber:      root.FloorNumber,
+				SubFloorNumber:   subFloor,
+				ReplyChain:       chain,
+				Depth:            parent.Depth + 1,
+				Content:          legacy.Comment,
+				ContentType:      model.CommentContentTypeText,
+				Status:           model.CommentStatusNormal,
+				LikeCount:        int(legacy.GoodCount),
+				IP:               legacy.CommentAddr,
+				AuditStatus:      model.CommentAuditStatusApproved,
+				CreateTime:       legacyCommentTime(legacy.CommentTime, root.CreateTime),
+			}
+			if err := s.migrationRepo.SaveComment(comment, model.LegacyEntityCommentReply, uint64(legacy.ID)); err != nil {
+				return fmt.Errorf("迁移回复失败: ID=%d: %w", legacy.ID, err)
+			}
+			progress.Processed++
+		}
+
+		if err := s.migrationRepo.SaveProgress(progress); err != nil {
+			return err
+		}
+	}
+
+	progress.Done = true
+	return s.migrationRepo.SaveProgress(progress)
+}
+
+// legacyCommentTime 解析旧版字符串评论时间（支持时间戳），无法解析时使用兜底时间
+func legacyCommentTime(value string, fallback time.Time) time.Time {
+	if t := parseFlexibleDate(value); t != nil {
+		return *t
+	}
+	var ts int64
+	if _, err := fmt.Sscanf(strings.TrimSpace(value), "%d", &ts); err == nil && ts > 0 {
+		if ts > 1e12 {
+			return time.UnixMilli(ts)
+		}
+		return time.Unix(ts, 0)
+	}
+	if fallback.IsZero() {
+		return time.Now()
+	}
+	return fallback
+}
+
+// ==================== 点赞与收藏 ====================
+
+// convertStars 将指向旧文章ID的点赞改写为V3文章ID
+// 点赞表没有时间字段，旧ID恰好与非迁移生成的V3文章重合时无法判断归属，记为冲突并保留原样
+func (s *legacyMigrationService) convertStars(progress *model.LegacyMigrationProgress, batchSize int) error {
+	for {
+		stars, err := s.migrationRepo.FindStars(progress.LastID, progress.MaxID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(stars) == 0 {
+			break
+		}
+
+		oldIDs := make([]uint64, 0, len(stars))
+		for _, star := range stars {
+			oldIDs = append(oldIDs, uint64(star.ArticleID))
+		}
+		newIDs, err := s.migrationRepo.FindNewIDs(model.LegacyEntityArticle, oldIDs)
+		if err != nil {
+			return err
+		}
+		preexisting, err := s.migrationRepo.FindPreexistingArticles(oldIDs)
+		if err != nil {
+			return err
+		}
+
+		conversions := make([]repository.LegacyRowConversion, 0, len(stars))
+		seen := make(map[string]bool, len(stars))
+		for _, star := range stars {
+			progress.LastID = uint64(star.ID)
+			newID, ok := newIDs[uint64(star.ArticleID)]
+			if !ok {
+				progress.Skipped++
+				continue
+			}
+			if _, ok := preexisting[uint64(star.ArticleID)]; ok {
+				progress.Conflicts++
+				continue
+			}
+
+			key := fmt.Sprintf("%s:%d", star.UserID, newID)
+			duplicated := seen[key]
+			if !duplicated {
+				if duplicated, err = s.migrationRepo.HasStar(star.UserID, newID); err != nil {
+					return err
+				}
+			}
+			seen[key] = true
+			conversions = append(conversions, repository.LegacyRowConversion{
+				RowID:        uint64(star.ID),
+				NewArticleID: newID,
+				Delete:       duplicated,
+			})
+			progress.Processed++
+		}
+
+		if err := s.migrationRepo.ConvertStars(conversions, progress); err != nil {
+			return err
+		}
+	}
+
+	progress.Done = true
+	return s.migrationRepo.SaveProgress(progress)
+}
+
+// convertFavorites 将指向旧文章ID的收藏改写为V3文章ID
+// 旧ID与非迁移生成的V3文章重合时，收藏早于该V3文章创建的必然属于旧文章，其余记为冲突
+func (s *legacyMigrationService) convertFavorites(progress *model.LegacyMigrationProgress, batchSize int) error {
+	for {
+		favorites, err := s.migrationRepo.FindFavorites(progress.LastID, progress.MaxID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(favorites) == 0 {
+			break
+		}
+
+		oldIDs := make([]uint64, 0, len(favorites))
+		for _, f := range favorites {
+			oldIDs = append(oldIDs, f.ArticleID)
+		}
+		newIDs, err := s.migrationRepo.FindNewIDs(model.LegacyEntityArticle, oldIDs)
+		if err != nil {
+			return err
+		}
+		preexisting, err := s.migrationRepo.FindPreexistingArticles(oldIDs)
+		if err != nil {
+			return err
+		}
+
+		conversions := make([]repository.LegacyRowConversion, 0, len(favorites))
+		seen := make(map[string]bool, len(favorites))
+		for _, f := range favorites {
+			progress.LastID = f.ID
+			newID, ok := newIDs[f.ArticleID]
+			if !ok {
+				progress.Skipped++
+				continue
+			}
+			if createTime, ok := preexisting[f.ArticleID]; ok && !f.CreateTime.Before(createTime) {
+				progress.Conflicts++
+				continue
+			}
+
+			key := fmt.Sprintf("%s:%d", f.UserID, newID)
+			duplicated := seen[key]
+			if !duplicated {
+				if duplicated, err = s.migrationRepo.HasFavorite(f.UserID, newID); err != nil {
+					return err
+				}
+			}
+			seen[key] = true
+			conversions = append(conversions, repository.LegacyRowConversion{
+				RowID:        f.ID,
+				NewArticleID: newID,
+				Delete:       duplicated,
+			})
+			progress.Processed++
+		}
+
+		if err := s.migrationRepo.ConvertFavorites(conversions, progress); err != nil {
+			return err
+		}
+	}
+
+	progress.Done = true
+	return s.migrationRepo.SaveProgress(progress)
+}
+
+// ==================== 计数 ====================
+
+// recountArticles 按转换后的点赞、收藏与迁移后的评论重算冗余计数
+func (s *legacyMigrationService) recountArticles(progress *model.LegacyMigrationProgress) error {
+	affected, err := s.migrationRepo.RecountMigratedArticles()
+	if err != nil {
+		return err
+	}
+	progress.Processed = affected
+	progress.Done = true
+	return s.migrationRepo.SaveProgress(progress)
+}
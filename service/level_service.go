@@ -0,0 +1,428 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"time"
+
+	"astronomer-gin/config"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/redis"
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/repository"
+)
+
+// 等级默认配置（与 config.yaml 保持一致）
+var (
+	defaultLevelThresholds = []int64{0, 100, 300, 800, 2000, 5000, 12000, 30000}
+	defaultXPRules         = map[string]config.XPRule{
+		model.XPEventPublishArticle:  {XP: 20, DailyLimit: 3},
+		model.XPEventPublishDynamic:  {XP: 5, DailyLimit: 5},
+		model.XPEventReceiveLike:     {XP: 2, DailyLimit: 50},
+		model.XPEventFeaturedComment: {XP: 15},
+		model.XPEventDailyLogin:      {XP: 5, DailyLimit: 1},
+	}
+	defaultPrivilegeLevels = map[string]int{
+		model.PrivilegePostLink:          2,
+		model.PrivilegeCreateTopic:       3,
+		model.PrivilegeSkipPreModeration: 4,
+	}
+	defaultUploadQuotas = []int{20, 50, 100, 200, 500}
+)
+
+const (
+	maxLeaderboardSize = 100 // 排行榜最多返回人数
+)
+
+// linkPattern 匹配内容中的链接
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+// LevelProfile 用户等级信息
+type LevelProfile struct {
+	UserID      string      `json:"user_id"`
+	Level       int         `json:"level"`
+	XP          int64       `json:"xp"`
+	LevelXP     int64       `json:"level_xp"`     // 当前等级门槛
+	NextXP      int64       `json:"next_xp"`      // 下一级门槛（满级时为-1）
+	Rank        int64       `json:"rank"`         // 经验排名（没有经验时为0）
+	Privileges  []string    `json:"privileges"`   // 已解锁的特权
+	UploadQuota int         `json:"upload_quota"` // 每日上传文件数
+	Badges      []BadgeInfo `json:"badges"`
+}
+
+// BadgeInfo 徽章展示信息
+type BadgeInfo struct {
+	model.BadgeDefinition
+	AwardTime time.Time `json:"award_time"`
+}
+
+// LeaderboardEntry 排行榜条目
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Handle   string `json:"handle"`
+	Icon     string `json:"icon"`
+	Level    int    `json:"level"`
+	XP       int64  `json:"xp"`
+}
+
+// LevelRules 等级规则说明
+type LevelRules struct {
+	Thresholds   []int64                  `json:"thresholds"`
+	Rules        map[string]config.XPRule `json:"rules"`
+	Privileges   map[string]int           `json:"privileges"`
+	UploadQuotas []int                    `json:"upload_quotas"`
+	Badges       []model.BadgeDefinition  `json:"badges"`
+}
+
+// LevelService 用户等级、经验与徽章服务接口
+type LevelService interface {
+	// AwardXP 按规则发放经验（同一bizKey只计一次，失败只记录日志不影响主流程）
+	AwardXP(userID, event, bizKey string)
+	// RecordDailyLogin 发放每日登录经验
+	RecordDailyLogin(userID string)
+
+	// GetLevel 用户当前等级（查询失败时按1级处理）
+	GetLevel(userID string) int
+	// HasPrivilege 用户等级是否已解锁特权
+	HasPrivilege(userID, privilege string) bool
+	// ConsumeUploadQuota 占用一次每日上传额度，超出时返回 ErrUploadQuotaExceeded
+	ConsumeUploadQuota(userID string) error
+
+	GetProfile(userID string) (*LevelProfile, error)
+	GetLeaderboard(limit int) ([]LeaderboardEntry, error)
+	ListXPLogs(userID string, page, pageSize int) ([]model.UserXPLog, int64, error)
+	GetRules() *LevelRules
+}
+
+type levelService struct {
+	levelRepo        repository.LevelRepository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+	cfg              config.LevelConfig
+	cacheHelper      *util.CacheHelper
+}
+
+// NewLevelService 创建LevelService实例
+func NewLevelService(
+	levelRepo repository.LevelRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+	cfg config.LevelConfig,
+) LevelService {
+	if len(cfg.Thresholds) == 0 || cfg.Thresholds[0] != 0 {
+		cfg.Thresholds = defaultLevelThresholds
+	}
+	if len(cfg.Rules) == 0 {
+		cfg.Rules = defaultXPRules
+	}
+	if len(cfg.Privileges) == 0 {
+		cfg.Privileges = defaultPrivilegeLevels
+	}
+	if len(cfg.UploadQuotas) == 0 {
+		cfg.UploadQuotas = defaultUploadQuotas
+	}
+	return &levelService{
+		levelRepo:        levelRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		cfg:              cfg,
+		cacheHelper:      util.NewCacheHelper(redis.GetClient()),
+	}
+}
+
+// ==================== 经验发放 ====================
+
+// AwardXP 发放经验
+func (s *levelService) AwardXP(userID, event, bizKey string) {
+	rule, ok := s.cfg.Rules[event]
+	if !ok || userID == "" {
+		return
+	}
+
+	// 1. 超出每日上限的事件仍然记录（经验为0），用于徽章累计
+	xp := rule.XP
+	if rule.DailyLimit > 0 {
+		count, err := s.levelRepo.CountRewardedSince(userID, event, startOfDay(time.Now()))
+		if err != nil {
+			log.Printf("⚠️  查询经验上限失败: user=%s, event=%s, %v", userID, event, err)
+			return
+		}
+		if count >= int64(rule.DailyLimit) {
+			xp = 0
+		}
+	}
+
+	before, err := s.levelRepo.GetXP(userID)
+	if err != nil {
+		log.Printf("⚠️  查询用户经验失败: user=%s, %v", userID, err)
+		return
+	}
+
+	// 2. 写入流水
+	created, err := s.levelRepo.AddXP(&model.UserXPLog{
+		UserID: userID,
+		Event:  event,
+		XP:     xp,
+		BizKey: bizKey,
+	})
+	if err != nil {
+		log.Printf("⚠️  发放经验失败: user=%s, event=%s, %v", userID, event, err)
+		return
+	}
+	if !created {
+		return
+	}
+
+	// 3. 升级提醒与徽章
+	oldLevel, newLevel := s.levelOf(before), s.levelOf(before+int64(xp))
+	if newLevel > oldLevel {
+		s.notify(userID, fmt.Sprintf("恭喜你升级到 Lv%d", newLevel))
+	}
+	s.checkBadges(userID, event, newLevel)
+}
+
+// RecordDailyLogin 每日登录（用缓存标记避免每次请求都写库）
+func (s *levelService) RecordDailyLogin(userID string) {
+	today := time.Now().Format("2006-01-02")
+	key := constant.CacheKeyDailyLogin + userID + ":" + today
+	if s.cacheHelper.Exists(key) {
+		return
+	}
+	s.AwardXP(userID, model.XPEventDailyLogin, "login:"+today)
+	s.cacheHelper.SetString(key, "1", time.Until(startOfDay(time.Now()).AddDate(0, 0, 1)))
+}
+
+// checkBadges 检查与本次事件相关的徽章
+func (s *levelService) checkBadges(userID, event string, level int) {
+	for _, badge := range model.Badges {
+		if badge.Event != "" {
+			if badge.Event != event {
+				continue
+			}
+			count, err := s.levelRepo.CountEvents(userID, event)
+			if err != nil || count < badge.Count {
+				continue
+			}
+		} else if level < badge.Level {
+			continue
+		}
+
+		awarded, err := s.levelRepo.AwardBadge(userID, badge.Code)
+		if err != nil {
+			log.Printf("⚠️  授予徽章失败: user=%s, badge=%s, %v", userID, badge.Code, err)
+			continue
+		}
+		if awarded {
+			s.notify(userID, fmt.Sprintf("恭喜你获得徽章「%s」：%s", badge.Name, badge.Description))
+		}
+	}
+}
+
+// notify 发送成就通知
+func (s *levelService) notify(userID, content string) {
+	notification := &model.Notification{
+		UserID:       userID,
+		Type:         model.NotificationTypeAchievement,
+		FromUsername: "系统",
+		Content:      content,
+		RelatedID:    userID,
+		RelatedType:  "level",
+		CreateTime:   time.Now(),
+	}
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("⚠️  发送成就通知失败: user=%s, %v", userID, err)
+	}
+}
+
+// ==================== 等级与特权 ====================
+
+// GetLevel 当前等级
+func (s *levelService) GetLevel(userID string) int {
+	xp, err := s.levelRepo.GetXP(userID)
+	if err != nil {
+		log.Printf("⚠️  查询用户经验失败: user=%s, %v", userID, err)
+		return 1
+	}
+	return s.levelOf(xp)
+}
+
+// HasPrivilege 是否已解锁特权（未配置的特权不限制）
+func (s *levelService) HasPrivilege(userID, privilege string) bool {
+	required, ok := s.cfg.Privileges[privilege]
+	if !ok {
+		return true
+	}
+	return s.GetLevel(userID) >= required
+}
+
+// ConsumeUploadQuota 占用每日上传额度（Redis不可用时不限制）
+func (s *levelService) ConsumeUploadQuota(userID string) error {
+	key := constant.CacheKeyUploadDaily + userID + ":" + time.Now().Format("2006-01-02")
+	count, err := s.cacheHelper.Incr(key)
+	if err != nil {
+		log.Printf("⚠️  记录上传次数失败: user=%s, %v", userID, err)
+		return nil
+	}
+	if count == 1 {
+		s.cacheHelper.Expire(key, 24*time.Hour)
+	}
+	if count > int64(s.uploadQuota(s.GetLevel(userID))) {
+		return constant.ErrUploadQuotaExceeded
+	}
+	return nil
+}
+
+// levelOf 根据经验计算等级（从1开始）
+func (s *levelService) levelOf(xp int64) int {
+	level := 0
+	for _, threshold := range s.cfg.Thresholds {
+		if xp < threshold {
+			break
+		}
+		level++
+	}
+	return level
+}
+
+// uploadQuota 等级对应的每日上传额度
+func (s *levelService) uploadQuota(level int) int {
+	if level > len(s.cfg.UploadQuotas) {
+		level = len(s.cfg.UploadQuotas)
+	}
+	if level < 1 {
+		level = 1
+	}
+	return s.cfg.UploadQuotas[level-1]
+}
+
+// ==================== 查询 ====================
+
+// GetProfile 用户等级信息
+func (s *levelService) GetProfile(userID string) (*LevelProfile, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+
+	xp, err := s.levelRepo.GetXP(userID)
+	if err != nil {
+		return nil, err
+	}
+	level := s.levelOf(xp)
+
+	profile := &LevelProfile{
+		UserID:      userID,
+		Level:       level,
+		XP:          xp,
+		LevelXP:     s.cfg.Thresholds[level-1],
+		NextXP:      -1,
+		Privileges:  []string{},
+		UploadQuota: s.uploadQuota(level),
+		Badges:      []BadgeInfo{},
+	}
+	if level < len(s.cfg.Thresholds) {
+		profile.NextXP = s.cfg.Thresholds[level]
+	}
+	if xp > 0 {
+		above, err := s.levelRepo.CountAbove(xp)
+		if err != nil {
+			return nil, err
+		}
+		profile.Rank = above + 1
+	}
+
+	for privilege, required := range s.cfg.Privileges {
+		if level >= required {
+			profile.Privileges = append(profile.Privileges, privilege)
+		}
+	}
+	sort.Strings(profile.Privileges)
+
+	badges, err := s.levelRepo.FindBadges(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, badge := range badges {
+		if def := model.FindBadge(badge.Badge); def != nil {
+			profile.Badges = append(profile.Badges, BadgeInfo{BadgeDefinition: *def, AwardTime: badge.AwardTime})
+		}
+	}
+
+	return profile, nil
+}
+
+// GetLeaderboard 经验排行榜（缓存5分钟）
+func (s *levelService) GetLeaderboard(limit int) ([]LeaderboardEntry, error) {
+	if limit <= 0 || limit > maxLeaderboardSize {
+		limit = maxLeaderboardSize
+	}
+
+	var entries []LeaderboardEntry
+	err := s.cacheHelper.GetOrSet(
+		constant.CacheKeyXPLeaderboard,
+		&entries,
+		time.Duration(constant.CacheExpireShort)*time.Second,
+		func() (interface{}, error) {
+			top, err := s.levelRepo.FindTopXP(maxLeaderboardSize)
+			if err != nil {
+				return nil, err
+			}
+			list := make([]LeaderboardEntry, 0, len(top))
+			for _, item := range top {
+				user, err := s.userRepo.FindByID(item.UserID)
+				if err != nil {
+					continue
+				}
+				list = append(list, LeaderboardEntry{
+					Rank:     len(list) + 1,
+					UserID:   user.ID,
+					Username: user.Username,
+					Handle:   user.Handle,
+					Icon:     user.Icon,
+					Level:    s.levelOf(item.XP),
+					XP:       item.XP,
+				})
+			}
+			return list, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// ListXPLogs 经验流水
+func (s *levelService) ListXPLogs(userID string, page, pageSize int) ([]model.UserXPLog, int64, error) {
+	return s.levelRepo.FindLogs(userID, page, pageSize)
+}
+
+// GetRules 等级规则说明
+func (s *levelService) GetRules() *LevelRules {
+	return &LevelRules{
+		Thresholds:   s.cfg.Thresholds,
+		Rules:        s.cfg.Rules,
+		Privileges:   s.cfg.Privileges,
+		UploadQuotas: s.cfg.UploadQuotas,
+		Badges:       model.Badges,
+	}
+}
+
+// containsLink 内容是否包含链接（发布链接需要等级特权）
+func containsLink(content string) bool {
+	return linkPattern.MatchString(content)
+}
+
+// startOfDay 当天零点
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
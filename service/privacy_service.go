@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/redis"
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/repository"
+
+	"gorm.io/gorm"
+)
+
+// PrivacySettingsRequest 更新隐私设置（未传的字段保持不变）
+type PrivacySettingsRequest struct {
+	FollowersVisibility *string `json:"followers_visibility"`
+	FollowingVisibility *string `json:"following_visibility"`
+	MessagePermission   *string `json:"message_permission"`
+	CommentPermission   *string `json:"comment_permission"`
+	BirthdayVisibility  *string `json:"birthday_visibility"`
+	Searchable          *bool   `json:"searchable"`
+	ShowInTrending      *bool   `json:"show_in_trending"`
+}
+
+// PrivacyService 用户隐私设置服务接口
+type PrivacyService interface {
+	// GetSettings 获取隐私设置（未设置过时返回默认值）
+	GetSettings(userID string) (*model.UserPrivacy, error)
+	// UpdateSettings 更新隐私设置
+	UpdateSettings(userID string, req *PrivacySettingsRequest) (*model.UserPrivacy, error)
+
+	// CheckFollowersVisible 检查viewer能否查看owner的粉丝列表（未登录时viewerID为空）
+	CheckFollowersVisible(ownerID, viewerID string) error
+	// CheckFollowingVisible 检查viewer能否查看owner的关注列表
+	CheckFollowingVisible(ownerID, viewerID string) error
+	// CheckBirthdayVisible 检查viewer能否查看owner的生日（月日）
+	CheckBirthdayVisible(ownerID, viewerID string) error
+	// CheckCanMessage 检查sender能否给receiver发私信
+	CheckCanMessage(receiverID, senderID string) error
+	// CheckCanComment 检查commenter能否评论author的文章
+	CheckCanComment(authorID, commenterID string) error
+	// FilterTrendingUsers 去掉关闭了热门榜单展示的用户（保持原有顺序）
+	FilterTrendingUsers(userIDs []string) []string
+}
+
+type privacyService struct {
+	privacyRepo repository.PrivacyRepository
+	followRepo  repository.FollowRepository
+	cacheHelper *util.CacheHelper
+}
+
+// NewPrivacyService 创建PrivacyService实例
+func NewPrivacyService(privacyRepo repository.PrivacyRepository, followRepo repository.FollowRepository) PrivacyService {
+	return &privacyService{
+		privacyRepo: privacyRepo,
+		followRepo:  followRepo,
+		cacheHelper: util.NewCacheHelper(redis.GetClient()),
+	}
+}
+
+// GetSettings 获取隐私设置（带缓存）
+func (s *privacyService) GetSettings(userID string) (*model.UserPrivacy, error) {
+	var privacy model.UserPrivacy
+	err := s.cacheHelper.GetOrSet(
+		constant.CacheKeyUserPrivacy+userID,
+		&privacy,
+		time.Duration(constant.CacheExpireLong)*time.Second,
+		func() (interface{}, error) {
+			found, err := s.privacyRepo.FindByUserID(userID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.DefaultUserPrivacy(userID), nil
+			}
+			return found, err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &privacy, nil
+}
+
+// UpdateSettings 更新隐私设置
+func (s *privacyService) UpdateSettings(userID string, req *PrivacySettingsRequest) (*model.UserPrivacy, error) {
+	for _, scope := range []*string{req.FollowersVisibility, req.FollowingVisibility, req.MessagePermission, req.CommentPermission, req.BirthdayVisibility} {
+		if scope != nil && !model.IsValidPrivacyScope(*scope) {
+			return nil, constant.ErrPrivacySettingInvalid
+		}
+	}
+
+	privacy, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.FollowersVisibility != nil {
+		privacy.FollowersVisibility = *req.FollowersVisibility
+	}
+	if req.FollowingVisibility != nil {
+		privacy.FollowingVisibility = *req.FollowingVisibility
+	}
+	if req.MessagePermission != nil {
+		privacy.MessagePermission = *req.MessagePermission
+	}
+	if req.CommentPermission != nil {
+		privacy.CommentPermission = *req.CommentPermission
+	}
+	if req.BirthdayVisibility != nil {
+		privacy.BirthdayVisibility = *req.BirthdayVisibility
+	}
+	if req.Searchable != nil {
+		privacy.Searchable = *req.Searchable
+	}
+	if req.ShowInTrending != nil {
+		privacy.ShowInTrending = *req.ShowInTrending
+	}
+
+	privacy.ID = 0
+	privacy.UserID = userID
+	if err := s.privacyRepo.Save(privacy); err != nil {
+		return nil, constant.ErrUpdateFail
+	}
+	s.cacheHelper.Delete(constant.CacheKeyUserPrivacy + userID)
+
+	return privacy, nil
+}
+
+// CheckFollowersVisible 检查粉丝列表可见性
+func (s *privacyService) CheckFollowersVisible(ownerID, viewerID string) error {
+	privacy, err := s.GetSettings(ownerID)
+	if err != nil {
+		return err
+	}
+	if !s.allowed(privacy.FollowersVisibility, ownerID, viewerID) {
+		return constant.ErrFollowersHidden
+	}
+	return nil
+}
+
+// CheckFollowingVisible 检查关注列表可见性
+func (s *privacyService) CheckFollowingVisible(ownerID, viewerID string) error {
+	privacy, err := s.GetSettings(ownerID)
+	if err != nil {
+		return err
+	}
+	if !s.allowed(privacy.FollowingVisibility, ownerID, viewerID) {
+		return constant.ErrFollowingHidden
+	}
+	return nil
+}
+
+// CheckBirthdayVisible 检查生日可见性
+func (s *privacyService) CheckBirthdayVisible(ownerID, viewerID string) error {
+	privacy, err := s.GetSettings(ownerID)
+	if err != nil {
+		return err
+	}
+	if !s.allowed(privacy.BirthdayVisibility, ownerID, viewerID) {
+		return constant.ErrBirthdayHidden
+	}
+	return nil
+}
+
+// CheckCanMessage 检查私信权限
+func (s *privacyService) CheckCanMessage(receiverID, senderID string) error {
+	privacy, err := s.GetSettings(receiverID)
+	if err != nil {
+		return err
+	}
+	if !s.allowed(privacy.MessagePermission, receiverID, senderID) {
+		return constant.ErrMessageNotPermitted
+	}
+	return nil
+}
+
+// CheckCanComment 检查评论权限
+func (s *privacyService) CheckCanComment(authorID, commenterID string) error {
+	privacy, err := s.GetSettings(authorID)
+	if err != nil {
+		return err
+	}
+	if !s.allowed(privacy.CommentPermission, authorID, commenterID) {
+		return constant.ErrCommentNotPermitted
+	}
+	return nil
+}
+
+// FilterTrendingUsers 过滤热门用户榜单（查询失败时不过滤，避免榜单为空）
+func (s *privacyService) FilterTrendingUsers(userIDs []string) []string {
+	hidden, err := s.privacyRepo.FindHiddenFromTrending(userIDs)
+	if err != nil || len(hidden) == 0 {
+		return userIDs
+	}
+
+	hiddenSet := make(map[string]bool, len(hidden))
+	for _, id := range hidden {
+		hiddenSet[id] = true
+	}
+	visible := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !hiddenSet[id] {
+			visible = append(visible, id)
+		}
+	}
+	return visible
+}
+
+// allowed 判断访问者是否在隐私范围内（本人始终允许，未登录只满足"所有人"）
+func (s *privacyService) allowed(scope, ownerID, viewerID string) bool {
+	if viewerID == ownerID || scope == model.PrivacyEveryone {
+		return true
+	}
+	if viewerID == "" {
+		return false
+	}
+
+	switch scope {
+	case model.PrivacyFollowers:
+		return s.followRepo.IsFollowing(viewerID, ownerID)
+	case model.PrivacyFollowing:
+		return s.followRepo.IsFollowing(ownerID, viewerID)
+	case model.PrivacyFriends:
+		return s.followRepo.IsFriend(ownerID, viewerID)
+	default:
+		return false
+	}
+}
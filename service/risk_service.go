@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"astronomer-gin/pkg/captcha"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/redis"
+	"astronomer-gin/pkg/util"
+)
+
+// 需要风险控制的操作
+const (
+	RiskActionLogin    = "login"
+	RiskActionRegister = "register"
+	RiskActionComment  = "comment"
+)
+
+// RiskSubject 本次请求的风险主体和提交的图形验证码
+type RiskSubject struct {
+	Phone        string
+	UserID       string
+	IP           string
+	CaptchaID    string
+	CaptchaValue string
+}
+
+// RiskDelayError 登录失败过多，需要等待后再试
+type RiskDelayError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RiskDelayError) Error() string {
+	return fmt.Sprintf("尝试次数过多，请%d秒后再试", int(e.RetryAfter.Seconds()+0.5))
+}
+
+// RiskService 风险控制服务接口
+// 根据失败次数、新IP、注册/评论频率等信号决定是否需要图形验证码；登录失败不再锁定账号，而是递增等待时间，
+// 避免攻击者故意输错密码锁住他人账号
+type RiskService interface {
+	// CaptchaRequired 当前是否需要图形验证码（前端据此提前展示验证码）
+	CaptchaRequired(action string, subject *RiskSubject) bool
+	// Check 操作前检查：需要等待时返回 *RiskDelayError；需要验证码但未提交时返回 ErrCaptchaRequired，
+	// 验证码错误返回 ErrCaptchaInvalid（主动提交的验证码即使当前不需要也会校验）
+	Check(action string, subject *RiskSubject) error
+	// RecordFailure 记录失败（目前只统计登录失败）
+	RecordFailure(action string, subject *RiskSubject)
+	// RecordSuccess 记录成功：登录成功清除失败记录并记住IP，注册/评论计入频率统计
+	RecordSuccess(action string, subject *RiskSubject)
+}
+
+type riskService struct {
+	cacheHelper *util.CacheHelper
+}
+
+// NewRiskService 创建RiskService实例
+func NewRiskService() RiskService {
+	return &riskService{
+		cacheHelper: util.NewCacheHelper(redis.GetClient()),
+	}
+}
+
+// CaptchaRequired 是否需要图形验证码
+func (s *riskService) CaptchaRequired(action string, subject *RiskSubject) bool {
+	switch action {
+	case RiskActionLogin:
+		if subject.Phone != "" && s.count(loginFailKey(subject.Phone)) >= constant.RiskLoginFailPhone {
+			return true
+		}
+		if subject.IP != "" && s.count(riskKey("login:ip_fail", subject.IP)) >= constant.RiskLoginFailIP {
+			return true
+		}
+		// 登录过的账号换了新IP
+		knownKey := riskKey("login:known_ip", subject.Phone)
+		if subject.Phone != "" && subject.IP != "" && s.cacheHelper.Exists(knownKey) {
+			if _, err := s.cacheHelper.HGet(knownKey, subject.IP); err != nil {
+				return true
+			}
+		}
+	case RiskActionRegister:
+		if subject.IP != "" && s.count(riskKey("register:ip", subject.IP)) >= constant.RiskRegisterPerIP {
+			return true
+		}
+		if s.count(registerBurstKey(time.Now())) >= constant.RiskRegisterBurst {
+			return true
+		}
+	case RiskActionComment:
+		if subject.UserID != "" && s.count(riskKey("comment:user", subject.UserID)) >= constant.RiskCommentPerUser {
+			return true
+		}
+		if subject.IP != "" && s.count(riskKey("comment:ip", subject.IP)) >= constant.RiskCommentPerIP {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 操作前检查
+func (s *riskService) Check(action string, subject *RiskSubject) error {
+	if action == RiskActionLogin && subject.Phone != "" {
+		if ttl, err := s.cacheHelper.TTL(loginWaitKey(subject.Phone)); err == nil && ttl > 0 {
+			return &RiskDelayError{RetryAfter: ttl}
+		}
+	}
+
+	if subject.CaptchaID != "" || subject.CaptchaValue != "" {
+		if !captcha.VerifyCaptcha(subject.CaptchaID, subject.CaptchaValue) {
+			return constant.ErrCaptchaInvalid
+		}
+		return nil
+	}
+	if s.CaptchaRequired(action, subject) {
+		return constant.ErrCaptchaRequired
+	}
+	return nil
+}
+
+// RecordFailure 记录登录失败，达到阈值后每次失败需要等待更久
+func (s *riskService) RecordFailure(action string, subject *RiskSubject) {
+	if action != RiskActionLogin {
+		return
+	}
+	window := time.Duration(constant.RiskLoginFailWindow) * time.Second
+	if subject.IP != "" {
+		s.incr(riskKey("login:ip_fail", subject.IP), window)
+	}
+	if subject.Phone == "" {
+		return
+	}
+
+	fails := s.incr(loginFailKey(subject.Phone), window)
+	if fails >= constant.RiskLoginDelayFrom {
+		s.cacheHelper.SetString(loginWaitKey(subject.Phone), "1", loginDelay(fails))
+	}
+}
+
+// RecordSuccess 记录成功
+func (s *riskService) RecordSuccess(action string, subject *RiskSubject) {
+	switch action {
+	case RiskActionLogin:
+		if subject.Phone == "" {
+			return
+		}
+		clearLoginFailures(s.cacheHelper, subject.Phone)
+		if subject.IP != "" {
+			knownKey := riskKey("login:known_ip", subject.Phone)
+			s.cacheHelper.HSet(knownKey, subject.IP, time.Now().Unix())
+			s.cacheHelper.Expire(knownKey, time.Duration(constant.RiskKnownIPExpire)*time.Second)
+		}
+	case RiskActionRegister:
+		if subject.IP != "" {
+			s.incr(riskKey("register:ip", subject.IP), 24*time.Hour)
+		}
+		s.incr(registerBurstKey(time.Now()), time.Duration(constant.RiskRegisterBurstWindow)*time.Second)
+	case RiskActionComment:
+		if subject.UserID != "" {
+			s.incr(riskKey("comment:user", subject.UserID), time.Minute)
+		}
+		if subject.IP != "" {
+			s.incr(riskKey("comment:ip", subject.IP), time.Minute)
+		}
+	}
+}
+
+// count 读取计数，不存在时为0
+func (s *riskService) count(key string) int64 {
+	value, err := s.cacheHelper.GetString(key)
+	if err != nil {
+		return 0
+	}
+	count, _ := strconv.ParseInt(value, 10, 64)
+	return count
+}
+
+// incr 计数加一，窗口从第一次计数开始
+func (s *riskService) incr(key string, window time.Duration) int64 {
+	count, err := s.cacheHelper.Incr(key)
+	if err != nil {
+		return 0
+	}
+	if count == 1 {
+		s.cacheHelper.Expire(key, window)
+	}
+	return count
+}
+
+// loginDelay 第n次失败后的等待时间：5秒起每次翻倍，最长5分钟
+func loginDelay(fails int64) time.Duration {
+	delay := int64(constant.RiskLoginDelayBase)
+	for i := int64(constant.RiskLoginDelayFrom); i < fails && delay < constant.RiskLoginDelayMax; i++ {
+		delay *= 2
+	}
+	if delay > constant.RiskLoginDelayMax {
+		delay = constant.RiskLoginDelayMax
+	}
+	return time.Duration(delay) * time.Second
+}
+
+// clearLoginFailures 清除手机号的登录失败记录（登录成功、验证码登录、重置密码后）
+func clearLoginFailures(cacheHelper *util.CacheHelper, phone string) {
+	cacheHelper.Delete(loginFailKey(phone), loginWaitKey(phone))
+}
+
+func loginFailKey(phone string) string {
+	return "login:fail:" + phone
+}
+
+func loginWaitKey(phone string) string {
+	return "login:wait:" + phone
+}
+
+func riskKey(kind, subject string) string {
+	return "risk:" + kind + ":" + subject
+}
+
+// registerBurstKey 全站注册计数按窗口分桶
+func registerBurstKey(now time.Time) string {
+	return riskKey("register:burst", strconv.FormatInt(now.Unix()/constant.RiskRegisterBurstWindow, 10))
+}
@@ -15,7 +15,7 @@ import (
 // SearchServiceV2 企业级搜索服务接口
 type SearchServiceV2 interface {
 	// 搜索文章
-	SearchArticles(keyword string, page, pageSize int) ([]model.Article, int64, error)
+	SearchArticles(keyword string, page, pageSize int) ([]model.ArticleV3, int64, error)
 
 	// 搜索用户（带关注状态）
 	SearchUsers(keyword string, page, pageSize int, currentUserID string) ([]model.User, int64, error)
@@ -25,16 +25,16 @@ type SearchServiceV2 interface {
 }
 
 type searchServiceV2 struct {
-	blogRepo    repository.BlogRepository
+	articleRepo repository.ArticleV3Repository
 	userRepo    repository.UserRepository
 	followRepo  repository.FollowRepository
 	cacheHelper *util.CacheHelper
 }
 
 // NewSearchServiceV2 创建搜索服务V2实例
-func NewSearchServiceV2(blogRepo repository.BlogRepository, userRepo repository.UserRepository, followRepo repository.FollowRepository) SearchServiceV2 {
+func NewSearchServiceV2(articleRepo repository.ArticleV3Repository, userRepo repository.UserRepository, followRepo repository.FollowRepository) SearchServiceV2 {
 	return &searchServiceV2{
-		blogRepo:    blogRepo,
+		articleRepo: articleRepo,
 		userRepo:    userRepo,
 		followRepo:  followRepo,
 		cacheHelper: util.NewCacheHelper(redis.GetClient()),
@@ -42,7 +42,7 @@ func NewSearchServiceV2(blogRepo repository.BlogRepository, userRepo repository.
 }
 
 // SearchArticles 搜索文章（ES优先，降级到MySQL）
-func (s *searchServiceV2) SearchArticles(keyword string, page, pageSize int) ([]model.Article, int64, error) {
+func (s *searchServiceV2) SearchArticles(keyword string, page, pageSize int) ([]model.ArticleV3, int64, error) {
 	// 1. 参数验证
 	if keyword == "" {
 		return nil, 0, constant.ErrParamInvalid
@@ -66,7 +66,7 @@ func (s *searchServiceV2) SearchArticles(keyword string, page, pageSize int) ([]
 
 		// 将ES文档转换为Article模型
 		if len(esArticles) == 0 {
-			return []model.Article{}, 0, nil
+			return []model.ArticleV3{}, 0, nil
 		}
 
 		// 批量查询完整文章信息
@@ -75,14 +75,14 @@ func (s *searchServiceV2) SearchArticles(keyword string, page, pageSize int) ([]
 			articleIDs = append(articleIDs, doc.ID)
 		}
 
-		articles, err := s.blogRepo.FindByIDs(articleIDs)
+		articles, err := s.articleRepo.FindByIDs(articleIDs)
 		if err != nil {
 			return nil, 0, err
 		}
 
 		// 按ES返回的顺序排序
-		sortedArticles := make([]model.Article, 0, len(articleIDs))
-		articleMap := make(map[uint64]model.Article)
+		sortedArticles := make([]model.ArticleV3, 0, len(articleIDs))
+		articleMap := make(map[uint64]model.ArticleV3)
 		for _, article := range articles {
 			articleMap[article.ID] = article
 		}
@@ -102,13 +102,13 @@ func (s *searchServiceV2) SearchArticles(keyword string, page, pageSize int) ([]
 }
 
 // searchArticlesFromMySQL MySQL搜索（降级方案）
-func (s *searchServiceV2) searchArticlesFromMySQL(keyword string, page, pageSize int) ([]model.Article, int64, error) {
+func (s *searchServiceV2) searchArticlesFromMySQL(keyword string, page, pageSize int) ([]model.ArticleV3, int64, error) {
 	// 构建缓存键
 	cacheKey := fmt.Sprintf("search:mysql:article:%s:page:%d:size:%d", keyword, page, pageSize)
 
 	// 尝试从缓存获取
 	type CachedData struct {
-		Articles []model.Article
+		Articles []model.ArticleV3
 		Total    int64
 	}
 
@@ -119,7 +119,7 @@ func (s *searchServiceV2) searchArticlesFromMySQL(keyword string, page, pageSize
 		time.Duration(constant.CacheExpireShort)*time.Second,
 		func() (interface{}, error) {
 			// 从MySQL搜索
-			articles, total, err := s.blogRepo.SearchArticles(keyword, page, pageSize)
+			articles, total, err := s.articleRepo.SearchArticles(keyword, page, pageSize)
 			if err != nil {
 				return nil, err
 			}
@@ -191,7 +191,7 @@ func (s *searchServiceV2) SearchAll(keyword string, page, pageSize int, currentU
 
 	// 2. 并发搜索文章和用户
 	type ArticleResult struct {
-		Articles []model.Article
+		Articles []model.ArticleV3
 		Total    int64
 		Err      error
 	}
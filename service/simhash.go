@@ -0,0 +1,101 @@
+package service
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ==================== SimHash 文本指纹 ====================
+
+var (
+	// simhashCodeBlockRegex 代码块（大段相同的模板代码容易造成误判）
+	simhashCodeBlockRegex = regexp.MustCompile("(?s)```.*?```")
+	// simhashURLRegex 链接与图片地址
+	simhashURLRegex = regexp.MustCompile(`https?://\S+`)
+)
+
+// simhashTokens 提取特征：中文按相邻两字切分（bigram），英文与数字按单词切分
+func simhashTokens(content string) map[string]int {
+	text := simhashCodeBlockRegex.ReplaceAllString(content, " ")
+	text = simhashURLRegex.ReplaceAllString(text, " ")
+	text = strings.ToLower(text)
+
+	tokens := make(map[string]int)
+	var word []rune
+	var prevHan rune
+
+	flushWord := func() {
+		if len(word) > 1 {
+			tokens[string(word)]++
+		}
+		word = word[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			if prevHan != 0 {
+				tokens[string([]rune{prevHan, r})]++
+			}
+			prevHan = r
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			prevHan = 0
+			word = append(word, r)
+		default:
+			// 标点、空白与Markdown符号作为分隔
+			prevHan = 0
+			flushWord()
+		}
+	}
+	flushWord()
+
+	return tokens
+}
+
+// computeSimHash 计算64位SimHash，返回指纹与特征数量
+func computeSimHash(content string) (uint64, int) {
+	tokens := simhashTokens(content)
+
+	var weights [64]int
+	count := 0
+	for token, weight := range tokens {
+		h := fnv.New64a()
+		h.Write([]byte(token))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i] += weight
+			} else {
+				weights[i] -= weight
+			}
+		}
+		count += weight
+	}
+
+	var fingerprint uint64
+	for i := 0; i < 64; i++ {
+		if weights[i] > 0 {
+			fingerprint |= 1 << uint(i)
+		}
+	}
+	return fingerprint, count
+}
+
+// hammingDistance 两个指纹的汉明距离
+func hammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// simhashBands 将指纹拆为4个16位段（用于索引查找）
+func simhashBands(fingerprint uint64) [4]uint16 {
+	return [4]uint16{
+		uint16(fingerprint),
+		uint16(fingerprint >> 16),
+		uint16(fingerprint >> 32),
+		uint16(fingerprint >> 48),
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimhashTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]int
+	}{
+		{"中文按两字切分", "天文观测", map[string]int{"天文": 1, "文观": 1, "观测": 1}},
+		{"英文单词小写", "Go go GIN", map[string]int{"go": 2, "gin": 1}},
+		{"单字母单词忽略", "a b cd", map[string]int{"cd": 1}},
+		{"标点分隔中文", "星云，星系", map[string]int{"星云": 1, "星系": 1}},
+		{"中英混排", "望远镜Telescope", map[string]int{"望远": 1, "远镜": 1, "telescope": 1}},
+		{"忽略代码块", "前言\n```go\nfunc main() {}\n```\n结语", map[string]int{"前言": 1, "结语": 1}},
+		{"忽略链接", "参见 https://example.com/a?b=c 图片", map[string]int{"参见": 1, "图片": 1}},
+		{"空内容", "", map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := simhashTokens(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// simhashSample 测试用长文（特征数超过建索引的下限）
+const simhashSample = `猎户座大星云是夜空中最明亮的弥漫星云之一，位于猎户座腰带下方的宝剑区域，肉眼在暗夜中即可看到一团模糊的光斑。
+它距离地球大约一千三百多光年，是离我们最近的大质量恒星形成区，天文学家通过它研究恒星和行星系统的诞生过程。
+星云中心的四边形星团由几颗年轻的大质量恒星组成，它们发出的强烈紫外辐射电离了周围的气体，使星云发出红色和绿色的辉光。
+用小型望远镜观测时，建议选择没有月光的夜晚，并使用低倍率目镜寻找目标，再逐步提高倍率观察四边形星团的细节。
+拍摄星云时需要赤道仪跟踪，多张短曝光叠加可以降低噪声，同时保留星云核心和外围暗弱结构的层次。
+The Orion Nebula is one of the most studied objects in the night sky and a favourite target for amateur astronomers.`
+
+func TestComputeSimHash(t *testing.T) {
+	hash, count := computeSimHash(simhashSample)
+	if count < duplicateMinTokens {
+		t.Fatalf("样例特征数 %d 低于下限 %d", count, duplicateMinTokens)
+	}
+
+	again, _ := computeSimHash(simhashSample)
+	if again != hash {
+		t.Fatalf("同一内容的指纹不一致: %x/%x", hash, again)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		near    bool // 是否应判定为近似重复
+	}{
+		{"只改Markdown格式", strings.ReplaceAll(simhashSample, "，", "，**") + "\n\n> 转载", true},
+		{"插入代码块和链接", simhashSample + "\n```\nconst x = 1\n```\nhttps://example.com/orion.jpg", true},
+		{"修改一个词", strings.Replace(simhashSample, "赤道仪", "经纬仪", 1), true},
+		{"无关内容", `协同编辑通过操作变换保证多人同时修改同一文档时最终结果一致，服务端按版本号排序并变换并发操作，
+客户端在收到确认之前缓存本地操作，断线重连后按版本号补齐缺失的操作。
+Operational transformation keeps concurrent edits convergent across all connected clients.`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other, _ := computeSimHash(tt.content)
+			distance := hammingDistance(hash, other)
+			if near := distance <= duplicateMaxDistance; near != tt.near {
+				t.Errorf("汉明距离 %d，判定近似=%v，want %v", distance, near, tt.near)
+			}
+		})
+	}
+}
+
+func TestComputeSimHashEmpty(t *testing.T) {
+	if hash, count := computeSimHash("```\n只有代码\n```"); hash != 0 || count != 0 {
+		t.Errorf("got (%x, %d), want (0, 0)", hash, count)
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xFF, 0x0F, 4},
+		{0, ^uint64(0), 64},
+		{0x8000000000000001, 0x0000000000000001, 1},
+	}
+	for _, tt := range tests {
+		if got := hammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("hammingDistance(%x, %x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSimhashBands(t *testing.T) {
+	bands := simhashBands(0x1122334455667788)
+	if want := [4]uint16{0x7788, 0x5566, 0x3344, 0x1122}; bands != want {
+		t.Fatalf("got %x, want %x", bands, want)
+	}
+
+	// 距离不超过3时至少有一段完全相同，索引按段查找不会漏掉候选
+	base := uint64(0x0123456789ABCDEF)
+	for _, flips := range [][]uint{{0, 16, 32}, {15, 31, 63}, {1, 2, 3}, {48, 49, 50}} {
+		other := base
+		for _, bit := range flips {
+			other ^= 1 << bit
+		}
+		a, b := simhashBands(base), simhashBands(other)
+		same := false
+		for i := range a {
+			if a[i] == b[i] {
+				same = true
+			}
+		}
+		if !same {
+			t.Errorf("翻转位 %v 后没有相同的段", flips)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/queue"
+	"astronomer-gin/pkg/redis"
+	"astronomer-gin/pkg/sms"
+	"astronomer-gin/pkg/util"
+)
+
+// 短信验证码使用场景（不同场景的验证码互不通用）
+const (
+	SMSSceneLogin          = "login"            // 验证码登录/注册
+	SMSSceneChangePhoneOld = "change_phone_old" // 更换手机号：验证原手机号
+	SMSSceneChangePhoneNew = "change_phone_new" // 更换手机号：验证新手机号
+)
+
+// smsSceneTexts 短信文案中的用途说明
+var smsSceneTexts = map[string]string{
+	SMSSceneLogin:          "登录",
+	SMSSceneChangePhoneOld: "验证原手机号",
+	SMSSceneChangePhoneNew: "绑定新手机号",
+}
+
+// SMSCodeService 短信验证码服务接口
+type SMSCodeService interface {
+	// SendCode 生成并发送验证码（按手机号和IP限制发送频率）
+	SendCode(scene, phone, ip string) error
+	// VerifyCode 校验验证码，成功后验证码失效；输错次数过多时验证码作废
+	VerifyCode(scene, phone, code string) error
+}
+
+type smsCodeService struct {
+	cacheHelper *util.CacheHelper
+}
+
+// NewSMSCodeService 创建SMSCodeService实例
+func NewSMSCodeService() SMSCodeService {
+	return &smsCodeService{
+		cacheHelper: util.NewCacheHelper(redis.GetClient()),
+	}
+}
+
+// SendCode 发送验证码
+func (s *smsCodeService) SendCode(scene, phone, ip string) error {
+	sceneText, ok := smsSceneTexts[scene]
+	if !ok {
+		return fmt.Errorf("不支持的验证码场景: %s", scene)
+	}
+	if err := util.ValidatePhone(phone); err != nil {
+		return err
+	}
+
+	// 1. 发送频率限制：手机号间隔、手机号每日上限、IP每小时上限
+	cooldownKey := constant.CacheKeySMSCooldown + phone
+	if s.cacheHelper.Exists(cooldownKey) {
+		return constant.ErrTooManyRequests
+	}
+	dailyKey := constant.CacheKeySMSDaily + phone + ":" + time.Now().Format("20060102")
+	if !s.withinLimit(dailyKey, constant.SMSDailyLimitPhone, 24*time.Hour) {
+		return constant.ErrSMSSendLimit
+	}
+	if ip != "" && !s.withinLimit(constant.CacheKeySMSIP+ip, constant.SMSHourlyLimitIP, time.Hour) {
+		return constant.ErrSMSSendLimit
+	}
+	s.cacheHelper.SetString(cooldownKey, "1", time.Duration(constant.SMSSendInterval)*time.Second)
+
+	// 2. 生成验证码，重新发送时覆盖旧验证码并清零错误次数
+	code, err := randomDigits(constant.SMSCodeLength)
+	if err != nil {
+		return constant.ErrSystemError
+	}
+	expire := time.Duration(constant.SMSCodeExpire) * time.Second
+	if err := s.cacheHelper.SetString(smsCodeKey(scene, phone), code, expire); err != nil {
+		return constant.ErrSystemError
+	}
+	s.cacheHelper.Delete(smsAttemptKey(scene, phone))
+
+	// 3. 发送
+	content := fmt.Sprintf("你的%s验证码是%s，%d分钟内有效。如非本人操作请忽略。", sceneText, code, constant.SMSCodeExpire/60)
+	s.dispatch(phone, content)
+	return nil
+}
+
+// VerifyCode 校验验证码
+func (s *smsCodeService) VerifyCode(scene, phone, code string) error {
+	codeKey := smsCodeKey(scene, phone)
+	expected, err := s.cacheHelper.GetString(codeKey)
+	if err != nil {
+		return constant.ErrSMSCodeExpired
+	}
+
+	if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) != 1 {
+		attemptKey := smsAttemptKey(scene, phone)
+		attempts, _ := s.cacheHelper.Incr(attemptKey)
+		if attempts == 1 {
+			s.cacheHelper.Expire(attemptKey, time.Duration(constant.SMSCodeExpire)*time.Second)
+		}
+		// 输错次数过多，验证码作废，防止穷举
+		if attempts >= constant.SMSMaxAttempts {
+			s.cacheHelper.Delete(codeKey, attemptKey)
+			return constant.ErrSMSCodeExpired
+		}
+		return constant.ErrSMSCodeInvalid
+	}
+
+	s.cacheHelper.Delete(codeKey, smsAttemptKey(scene, phone))
+	return nil
+}
+
+// withinLimit 计数并判断是否超过上限（窗口从第一次计数开始）
+func (s *smsCodeService) withinLimit(key string, limit int64, window time.Duration) bool {
+	count, err := s.cacheHelper.Incr(key)
+	if err != nil {
+		return false
+	}
+	if count == 1 {
+		s.cacheHelper.Expire(key, window)
+	}
+	return count <= limit
+}
+
+// dispatch 投递短信任务（队列不可用时本地发送）
+func (s *smsCodeService) dispatch(phone, content string) {
+	if queue.Client != nil {
+		task := queue.CreateTask(queue.TaskTypeSMS, map[string]interface{}{
+			"phone":   phone,
+			"content": content,
+		})
+		err := queue.Client.PublishTask(context.Background(), task)
+		if err == nil {
+			return
+		}
+		log.Printf("⚠️  投递短信任务失败，改为本地发送: phone=%s, %v", util.MaskPhone(phone), err)
+	}
+	go func() {
+		if err := sms.Send(phone, content); err != nil {
+			log.Printf("❌ %v", err)
+		}
+	}()
+}
+
+func smsCodeKey(scene, phone string) string {
+	return constant.CacheKeySMSCode + scene + ":" + phone
+}
+
+func smsAttemptKey(scene, phone string) string {
+	return constant.CacheKeySMSAttempt + scene + ":" + phone
+}
+
+// randomDigits 生成n位随机数字
+func randomDigits(n int) (string, error) {
+	digits := make([]byte, n)
+	for i := range digits {
+		v, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + v.Int64())
+	}
+	return string(digits), nil
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf16"
+)
+
+// ==================== 文本操作变换（OT） ====================
+//
+// 协同编辑的文本操作，JSON格式与 ot.js 的 TextOperation 一致，前端可直接使用 ot.js 客户端：
+//   正整数 = 保留n个字符，负整数 = 删除n个字符，字符串 = 插入
+// 例如 [5, "abc", -2, 10] 表示保留5个、插入"abc"、删除2个、再保留10个。
+// 长度按UTF-16码元计算（与浏览器端 String.length 一致）。
+
+// textOpComponent 操作分量：n>0 保留，n<0 删除，s非空为插入
+type textOpComponent struct {
+	n int
+	s []uint16
+}
+
+func (c textOpComponent) isRetain() bool { return c.n > 0 }
+func (c textOpComponent) isDelete() bool { return c.n < 0 }
+func (c textOpComponent) isInsert() bool { return len(c.s) > 0 }
+
+// TextOperation 文本操作
+type TextOperation struct {
+	ops       []textOpComponent
+	baseLen   int // 操作前的文档长度
+	targetLen int // 操作后的文档长度
+}
+
+// BaseLen 操作要求的文档长度
+func (o *TextOperation) BaseLen() int {
+	return o.baseLen
+}
+
+// IsNoop 是否为空操作（只有保留）
+func (o *TextOperation) IsNoop() bool {
+	return len(o.ops) == 0 || (len(o.ops) == 1 && o.ops[0].isRetain())
+}
+
+func (o *TextOperation) retain(n int) {
+	if n <= 0 {
+		return
+	}
+	o.baseLen += n
+	o.targetLen += n
+	if last := len(o.ops) - 1; last >= 0 && o.ops[last].isRetain() {
+		o.ops[last].n += n
+		return
+	}
+	o.ops = append(o.ops, textOpComponent{n: n})
+}
+
+func (o *TextOperation) insert(s []uint16) {
+	if len(s) == 0 {
+		return
+	}
+	o.targetLen += len(s)
+	last := len(o.ops) - 1
+	switch {
+	case last >= 0 && o.ops[last].isInsert():
+		o.ops[last].s = append(o.ops[last].s, s...)
+	case last >= 0 && o.ops[last].isDelete():
+		// 插入总是排在删除之前，保证相同效果的操作有唯一表示
+		if last >= 1 && o.ops[last-1].isInsert() {
+			o.ops[last-1].s = append(o.ops[last-1].s, s...)
+		} else {
+			o.ops = append(o.ops, o.ops[last])
+			o.ops[last] = textOpComponent{s: append([]uint16(nil), s...)}
+		}
+	default:
+		o.ops = append(o.ops, textOpComponent{s: append([]uint16(nil), s...)})
+	}
+}
+
+func (o *TextOperation) delete(n int) {
+	if n <= 0 {
+		return
+	}
+	o.baseLen += n
+	if last := len(o.ops) - 1; last >= 0 && o.ops[last].isDelete() {
+		o.ops[last].n -= n
+		return
+	}
+	o.ops = append(o.ops, textOpComponent{n: -n})
+}
+
+// Apply 将操作应用到文本
+func (o *TextOperation) Apply(text string) (string, error) {
+	doc := utf16.Encode([]rune(text))
+	if len(doc) != o.baseLen {
+		return "", fmt.Errorf("操作长度与文档不匹配: 文档%d, 操作%d", len(doc), o.baseLen)
+	}
+
+	result := make([]uint16, 0, o.targetLen)
+	pos := 0
+	for _, c := range o.ops {
+		switch {
+		case c.isRetain():
+			result = append(result, doc[pos:pos+c.n]...)
+			pos += c.n
+		case c.isInsert():
+			result = append(result, c.s...)
+		default:
+			pos -= c.n
+		}
+	}
+	return string(utf16.Decode(result)), nil
+}
+
+// TransformIndex 将操作前的光标位置变换为操作后的位置
+func (o *TextOperation) TransformIndex(index int) int {
+	newIndex := index
+	for _, c := range o.ops {
+		switch {
+		case c.isRetain():
+			index -= c.n
+		case c.isInsert():
+			newIndex += len(c.s)
+		default:
+			deleted := -c.n
+			if index < deleted {
+				deleted = index
+			}
+			newIndex -= deleted
+			index += c.n
+		}
+		if index < 0 {
+			break
+		}
+	}
+	return newIndex
+}
+
+// TransformOperations 变换两个基于同一文档的并发操作，返回 a' 和 b'，
+// 满足 apply(apply(doc, a), b') == apply(apply(doc, b), a')；同一位置的插入a在前
+func TransformOperations(a, b *TextOperation) (*TextOperation, *TextOperation, error) {
+	if a.baseLen != b.baseLen {
+		return nil, nil, fmt.Errorf("并发操作的基准长度不一致: %d/%d", a.baseLen, b.baseLen)
+	}
+
+	aPrime, bPrime := &TextOperation{}, &TextOperation{}
+	ops1, ops2 := a.ops, b.ops
+	i1, i2 := 0, 0
+	var op1, op2 *textOpComponent
+	next1 := func() {
+		op1 = nil
+		if i1 < len(ops1) {
+			c := ops1[i1]
+			op1 = &c
+			i1++
+		}
+	}
+	next2 := func() {
+		op2 = nil
+		if i2 < len(ops2) {
+			c := ops2[i2]
+			op2 = &c
+			i2++
+		}
+	}
+	next1()
+	next2()
+
+	for op1 != nil || op2 != nil {
+		if op1 != nil && op1.isInsert() {
+			aPrime.insert(op1.s)
+			bPrime.retain(len(op1.s))
+			next1()
+			continue
+		}
+		if op2 != nil && op2.isInsert() {
+			aPrime.retain(len(op2.s))
+			bPrime.insert(op2.s)
+			next2()
+			continue
+		}
+		if op1 == nil || op2 == nil {
+			return nil, nil, fmt.Errorf("并发操作的长度不一致")
+		}
+
+		switch {
+		case op1.isRetain() && op2.isRetain():
+			var min int
+			switch {
+			case op1.n > op2.n:
+				min = op2.n
+				op1.n -= op2.n
+				next2()
+			case op1.n == op2.n:
+				min = op2.n
+				next1()
+				next2()
+			default:
+				min = op1.n
+				op2.n -= op1.n
+				next1()
+			}
+			aPrime.retain(min)
+			bPrime.retain(min)
+		case op1.isDelete() && op2.isDelete():
+			// 双方都删除了同一段，变换后都不再需要删除
+			switch {
+			case -op1.n > -op2.n:
+				op1.n -= op2.n
+				next2()
+			case op1.n == op2.n:
+				next1()
+				next2()
+			default:
+				op2.n -= op1.n
+				next1()
+			}
+		case op1.isDelete() && op2.isRetain():
+			var min int
+			switch {
+			case -op1.n > op2.n:
+				min = op2.n
+				op1.n += op2.n
+				next2()
+			case -op1.n == op2.n:
+				min = op2.n
+				next1()
+				next2()
+			default:
+				min = -op1.n
+				op2.n += op1.n
+				next1()
+			}
+			aPrime.delete(min)
+		default: // op1 保留，op2 删除
+			var min int
+			switch {
+			case op1.n > -op2.n:
+				min = -op2.n
+				op1.n += op2.n
+				next2()
+			case op1.n == -op2.n:
+				min = op1.n
+				next1()
+				next2()
+			default:
+				min = op1.n
+				op2.n += op1.n
+				next1()
+			}
+			bPrime.delete(min)
+		}
+	}
+	return aPrime, bPrime, nil
+}
+
+// MarshalJSON 序列化为 ot.js 格式
+func (o *TextOperation) MarshalJSON() ([]byte, error) {
+	ops := make([]interface{}, 0, len(o.ops))
+	for _, c := range o.ops {
+		if c.isInsert() {
+			ops = append(ops, string(utf16.Decode(c.s)))
+		} else {
+			ops = append(ops, c.n)
+		}
+	}
+	return json.Marshal(ops)
+}
+
+// UnmarshalJSON 从 ot.js 格式解析
+func (o *TextOperation) UnmarshalJSON(data []byte) error {
+	var raw []interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("无效的操作格式: %w", err)
+	}
+
+	*o = TextOperation{}
+	for _, item := range raw {
+		switch v := item.(type) {
+		case string:
+			o.insert(utf16.Encode([]rune(v)))
+		case float64:
+			n := int(v)
+			if float64(n) != v || n == 0 {
+				return fmt.Errorf("无效的操作分量: %v", v)
+			}
+			if n > 0 {
+				o.retain(n)
+			} else {
+				o.delete(-n)
+			}
+		default:
+			return fmt.Errorf("无效的操作分量: %v", v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"unicode/utf16"
+)
+
+func mustTextOp(t *testing.T, raw string) *TextOperation {
+	t.Helper()
+	var op TextOperation
+	if err := json.Unmarshal([]byte(raw), &op); err != nil {
+		t.Fatalf("解析操作 %s 失败: %v", raw, err)
+	}
+	return &op
+}
+
+func mustApply(t *testing.T, op *TextOperation, doc string) string {
+	t.Helper()
+	result, err := op.Apply(doc)
+	if err != nil {
+		t.Fatalf("应用操作失败: %v", err)
+	}
+	return result
+}
+
+// assertConverge 检查 apply(apply(doc, a), b') == apply(apply(doc, b), a')，并返回收敛后的文档
+func assertConverge(t *testing.T, doc string, a, b *TextOperation) string {
+	t.Helper()
+	aPrime, bPrime, err := TransformOperations(a, b)
+	if err != nil {
+		t.Fatalf("变换失败: %v", err)
+	}
+	left := mustApply(t, bPrime, mustApply(t, a, doc))
+	right := mustApply(t, aPrime, mustApply(t, b, doc))
+	if left != right {
+		t.Fatalf("未收敛: doc=%q a⋅b'=%q b⋅a'=%q", doc, left, right)
+	}
+	return left
+}
+
+func TestTransformOperationsConverge(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		a    string
+		b    string
+		want string
+	}{
+		{"不同位置插入", "hello world", `[5, ",", 6]`, `[11, "!"]`, "hello, world!"},
+		{"同一位置插入a在前", "ab", `[1, "X", 1]`, `[1, "Y", 1]`, "aXYb"},
+		{"插入与删除", "hello world", `[6, "big ", 5]`, `[5, -6]`, "hellobig "},
+		{"删除同一段", "abcdef", `[1, -3, 2]`, `[1, -3, 2]`, "aef"},
+		{"删除区间重叠", "abcdef", `[1, -3, 2]`, `[2, -3, 1]`, "af"},
+		{"删除包含另一删除", "abcdef", `[-6]`, `[2, -2, 2]`, ""},
+		{"在对方删除的区间内插入", "abcdef", `[3, "X", 3]`, `[1, -4, 1]`, "aXf"},
+		{"空操作", "abc", `[3]`, `[1, "Z", 2]`, "aZbc"},
+		{"空文档", "", `["foo"]`, `["bar"]`, "foobar"},
+		{"UTF-16代理对", "a😀b", `[4, "c"]`, `[1, -2, 1]`, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := mustTextOp(t, tt.a), mustTextOp(t, tt.b)
+			if got := assertConverge(t, tt.doc, a, b); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformOperationsBaseLenMismatch(t *testing.T) {
+	if _, _, err := TransformOperations(mustTextOp(t, `[3]`), mustTextOp(t, `[4]`)); err == nil {
+		t.Fatal("基准长度不一致时应返回错误")
+	}
+}
+
+// randomTextOp 生成基于长度为n的文档的随机操作
+func randomTextOp(r *rand.Rand, n int) *TextOperation {
+	op := &TextOperation{}
+	for remaining := n; remaining > 0; {
+		k := 1 + r.Intn(remaining)
+		switch r.Intn(3) {
+		case 0:
+			op.retain(k)
+			remaining -= k
+		case 1:
+			op.delete(k)
+			remaining -= k
+		default:
+			op.insert(utf16.Encode([]rune(randomText(r, 1+r.Intn(3)))))
+		}
+	}
+	if r.Intn(2) == 0 {
+		op.insert(utf16.Encode([]rune(randomText(r, 1+r.Intn(3)))))
+	}
+	return op
+}
+
+func randomText(r *rand.Rand, n int) string {
+	const letters = "abcxyz中文"
+	runes := []rune(letters)
+	s := make([]rune, n)
+	for i := range s {
+		s[i] = runes[r.Intn(len(runes))]
+	}
+	return string(s)
+}
+
+func TestTransformOperationsRandomConverge(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		doc := randomText(r, r.Intn(20))
+		n := len(utf16.Encode([]rune(doc)))
+		a, b := randomTextOp(r, n), randomTextOp(r, n)
+		assertConverge(t, doc, a, b)
+
+		// 一方连续提交 a、c，另一方并发提交 b：c 与 b' 再次变换后两端仍收敛
+		c := randomTextOp(r, a.targetLen)
+		aPrime, bPrime, err := TransformOperations(a, b)
+		if err != nil {
+			t.Fatalf("变换失败: %v", err)
+		}
+		cPrime, bDoublePrime, err := TransformOperations(c, bPrime)
+		if err != nil {
+			t.Fatalf("变换失败: %v", err)
+		}
+		left := mustApply(t, bDoublePrime, mustApply(t, c, mustApply(t, a, doc)))
+		right := mustApply(t, cPrime, mustApply(t, aPrime, mustApply(t, b, doc)))
+		if left != right {
+			t.Fatalf("连续操作未收敛: doc=%q left=%q right=%q", doc, left, right)
+		}
+	}
+}
+
+func TestTextOperationApplyLengthMismatch(t *testing.T) {
+	if _, err := mustTextOp(t, `[2, "x"]`).Apply("abc"); err == nil {
+		t.Fatal("文档长度不匹配时应返回错误")
+	}
+}
+
+func TestTextOperationTransformIndex(t *testing.T) {
+	tests := []struct {
+		op    string
+		index int
+		want  int
+	}{
+		{`["ab", 5]`, 0, 2},
+		{`[2, "ab", 3]`, 1, 1},
+		{`[2, "ab", 3]`, 4, 6},
+		{`[1, -3, 1]`, 3, 1},
+		{`[1, -3, 1]`, 5, 2},
+	}
+	for _, tt := range tests {
+		if got := mustTextOp(t, tt.op).TransformIndex(tt.index); got != tt.want {
+			t.Errorf("%s TransformIndex(%d) = %d, want %d", tt.op, tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestTextOperationJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`[5, "abc", -2, 10]`, `[5,"abc",-2,10]`},
+		{`[2, 3, "a", "b", -1, -1]`, `[5,"ab",-2]`}, // 相邻同类分量合并
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(mustTextOp(t, tt.in))
+		if err != nil {
+			t.Fatalf("序列化失败: %v", err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s 序列化为 %s, want %s", tt.in, data, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{`{}`, `[0]`, `[1.5]`, `[true]`} {
+		var op TextOperation
+		if err := json.Unmarshal([]byte(invalid), &op); err == nil {
+			t.Errorf("%s 应解析失败", invalid)
+		}
+	}
+}
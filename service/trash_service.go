@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/repository"
+)
+
+// TrashService 回收站服务接口（删除的文章、草稿和评论）
+type TrashService interface {
+	// 回收站列表（entityType为空表示全部类型）
+	ListTrash(userID, entityType string, page, pageSize int) ([]model.TrashItem, int64, error)
+	// 恢复（还原删除前状态并修正相关计数）
+	Restore(itemID uint64, userID string) error
+	// 彻底删除单个条目
+	Purge(itemID uint64, userID string) error
+	// 清除删除时间早于before的条目（定时任务调用），返回清除数量
+	PurgeExpired(before time.Time, limit int) (int, error)
+}
+
+type trashService struct {
+	trashRepo     repository.TrashRepository
+	articleRepo   repository.ArticleV3Repository
+	commentRepo   repository.CommentV3Repository
+	dynamicRepo   repository.DynamicRepository
+	uploadService UploadServiceV2
+}
+
+// NewTrashService 创建TrashService实例
+func NewTrashService(
+	trashRepo repository.TrashRepository,
+	articleRepo repository.ArticleV3Repository,
+	commentRepo repository.CommentV3Repository,
+	dynamicRepo repository.DynamicRepository,
+	uploadService UploadServiceV2,
+) TrashService {
+	return &trashService{
+		trashRepo:     trashRepo,
+		articleRepo:   articleRepo,
+		commentRepo:   commentRepo,
+		dynamicRepo:   dynamicRepo,
+		uploadService: uploadService,
+	}
+}
+
+// ListTrash 回收站列表
+func (s *trashService) ListTrash(userID, entityType string, page, pageSize int) ([]model.TrashItem, int64, error) {
+	switch entityType {
+	case "", model.TrashEntityArticle, model.TrashEntityDraft, model.TrashEntityComment:
+	default:
+		return nil, 0, fmt.Errorf("不支持的类型: %s", entityType)
+	}
+	return s.trashRepo.FindByUser(userID, entityType, page, pageSize)
+}
+
+// Restore 恢复回收站条目
+func (s *trashService) Restore(itemID uint64, userID string) error {
+	item, err := s.findOwnItem(itemID, userID)
+	if err != nil {
+		return err
+	}
+
+	switch item.EntityType {
+	case model.TrashEntityArticle:
+		return s.restoreArticle(item)
+	case model.TrashEntityDraft:
+		if err := s.trashRepo.RestoreDraft(item); err != nil {
+			return fmt.Errorf("恢复草稿失败: %w", err)
+		}
+		return nil
+	case model.TrashEntityComment:
+		return s.restoreComment(item)
+	default:
+		return fmt.Errorf("不支持的类型: %s", item.EntityType)
+	}
+}
+
+// restoreArticle 恢复文章并补回分类、专栏计数（删除时扣减）
+func (s *trashService) restoreArticle(item *model.TrashItem) error {
+	article, err := s.trashRepo.FindArticle(item.EntityID)
+	if err != nil {
+		return constant.ErrArticleNotFound
+	}
+
+	if err := s.trashRepo.RestoreArticle(item); err != nil {
+		return fmt.Errorf("恢复文章失败: %w", err)
+	}
+
+	if article.CategoryID > 0 {
+		s.articleRepo.IncrementCategoryArticleCount(article.CategoryID)
+	}
+	if article.ColumnID > 0 {
+		s.articleRepo.IncrementColumnArticleCount(article.ColumnID)
+	}
+	return nil
+}
+
+// restoreComment 恢复评论并补回父评论、根评论和目标对象的计数（删除时扣减）
+func (s *trashService) restoreComment(item *model.TrashItem) error {
+	comment, err := s.trashRepo.FindComment(item.EntityID)
+	if err != nil {
+		return constant.ErrCommentNotFound
+	}
+
+	// 所属内容或上级评论已删除时不能单独恢复
+	if !s.trashRepo.IsCommentTargetAlive(comment.TargetType, comment.TargetID) {
+		return fmt.Errorf("评论所属的内容已删除，请先恢复该内容")
+	}
+	if comment.ParentID > 0 {
+		parent, err := s.trashRepo.FindComment(comment.ParentID)
+		if err != nil || parent.DeleteTime != nil {
+			return fmt.Errorf("上级评论已删除，无法单独恢复")
+		}
+	}
+
+	if err := s.trashRepo.RestoreComment(item); err != nil {
+		return fmt.Errorf("恢复评论失败: %w", err)
+	}
+
+	if comment.ParentID > 0 {
+		s.commentRepo.IncrementReplyCount(comment.ParentID)
+	}
+	if comment.RootID > 0 && comment.RootID != comment.ID {
+		s.commentRepo.IncrementTotalReplyCount(comment.RootID)
+	}
+	switch comment.TargetType {
+	case model.CommentTargetTypeArticle:
+		s.articleRepo.IncrementCommentCount(comment.TargetID)
+	case model.CommentTargetTypeDynamic:
+		s.dynamicRepo.IncrementCommentCount(comment.TargetID)
+	}
+	return nil
+}
+
+// Purge 彻底删除单个条目
+func (s *trashService) Purge(itemID uint64, userID string) error {
+	item, err := s.findOwnItem(itemID, userID)
+	if err != nil {
+		return err
+	}
+	return s.purgeItem(item)
+}
+
+// PurgeExpired 清除过期条目
+func (s *trashService) PurgeExpired(before time.Time, limit int) (int, error) {
+	items, err := s.trashRepo.FindExpired(before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("查询过期条目失败: %w", err)
+	}
+
+	purged := 0
+	for i := range items {
+		if err := s.purgeItem(&items[i]); err != nil {
+			log.Printf("⚠️  清除回收站条目失败: %s#%d, %v", items[i].EntityType, items[i].EntityID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purgeItem 彻底删除实体及其关联数据，再删除不再被引用的MinIO媒体
+func (s *trashService) purgeItem(item *model.TrashItem) error {
+	var media []string
+
+	switch item.EntityType {
+	case model.TrashEntityArticle:
+		if article, err := s.trashRepo.FindArticle(item.EntityID); err == nil {
+			media = append(media, article.CoverImage)
+		}
+		if content, err := s.articleRepo.FindContentByArticleID(item.EntityID); err == nil {
+			media = append(media, collectImageRefs(content.Content)...)
+		}
+		if images, err := s.trashRepo.FindArticleCommentImages(item.EntityID); err == nil {
+			media = append(media, images...)
+		}
+		if err := s.trashRepo.PurgeArticle(item.EntityID); err != nil {
+			return fmt.Errorf("清除文章失败: %w", err)
+		}
+	case model.TrashEntityDraft:
+		if draft, err := s.trashRepo.FindDraft(item.EntityID); err == nil {
+			media = append(media, draft.CoverImage)
+			media = append(media, collectImageRefs(draft.Content)...)
+		}
+		if err := s.trashRepo.PurgeDraft(item.EntityID); err != nil {
+			return fmt.Errorf("清除草稿失败: %w", err)
+		}
+	case model.TrashEntityComment:
+		if comment, err := s.trashRepo.FindComment(item.EntityID); err == nil {
+			media = append(media, comment.Images...)
+		}
+		if err := s.trashRepo.PurgeComment(item.EntityID); err != nil {
+			return fmt.Errorf("清除评论失败: %w", err)
+		}
+	default:
+		return fmt.Errorf("不支持的类型: %s", item.EntityType)
+	}
+
+	s.cleanupMedia(media)
+	return nil
+}
+
+// cleanupMedia 删除不再被任何内容引用的媒体文件（非本站MinIO地址忽略）
+func (s *trashService) cleanupMedia(urls []string) {
+	ctx := context.Background()
+	for _, url := range dedupeStrings(urls) {
+		if url == "" || extractObjectNameFromURL(url) == "" {
+			continue
+		}
+		refs, err := s.trashRepo.CountMediaReferences(url)
+		if err != nil || refs > 0 {
+			continue
+		}
+		if err := s.uploadService.DeleteFile(ctx, url); err != nil {
+			log.Printf("⚠️  清理回收站媒体失败: %s, %v", url, err)
+		}
+	}
+}
+
+// findOwnItem 查询回收站条目并校验归属
+func (s *trashService) findOwnItem(itemID uint64, userID string) (*model.TrashItem, error) {
+	item, err := s.trashRepo.FindByID(itemID)
+	if err != nil {
+		return nil, constant.ErrResourceNotFound
+	}
+	if item.UserID != userID {
+		return nil, constant.ErrPermissionDenied
+	}
+	return item, nil
+}
@@ -17,7 +17,7 @@ import (
 // TrendingServiceV2 热门榜单服务接口
 type TrendingServiceV2 interface {
 	// 获取热门文章榜单
-	GetTrendingArticles(limit int) ([]model.Article, error)
+	GetTrendingArticles(limit int) ([]model.ArticleV3, error)
 
 	// 获取热门用户榜单
 	GetTrendingUsers(limit int) ([]model.User, error)
@@ -33,17 +33,19 @@ type TrendingServiceV2 interface {
 }
 
 type trendingServiceV2 struct {
-	blogRepo    repository.BlogRepository
+	articleRepo repository.ArticleV3Repository
 	userRepo    repository.UserRepository
+	privacy     PrivacyService
 	redisClient *redisLib.Client
 	cacheHelper *util.CacheHelper
 }
 
 // NewTrendingServiceV2 创建热门榜单服务V2实例
-func NewTrendingServiceV2(blogRepo repository.BlogRepository, userRepo repository.UserRepository) TrendingServiceV2 {
+func NewTrendingServiceV2(articleRepo repository.ArticleV3Repository, userRepo repository.UserRepository, privacy PrivacyService) TrendingServiceV2 {
 	return &trendingServiceV2{
-		blogRepo:    blogRepo,
+		articleRepo: articleRepo,
 		userRepo:    userRepo,
+		privacy:     privacy,
 		redisClient: redis.GetClient(),
 		cacheHelper: util.NewCacheHelper(redis.GetClient()),
 	}
@@ -66,7 +68,7 @@ const (
 )
 
 // GetTrendingArticles 获取热门文章榜单
-func (s *trendingServiceV2) GetTrendingArticles(limit int) ([]model.Article, error) {
+func (s *trendingServiceV2) GetTrendingArticles(limit int) ([]model.ArticleV3, error) {
 	ctx := context.Background()
 
 	// 1. 参数验证
@@ -84,7 +86,7 @@ func (s *trendingServiceV2) GetTrendingArticles(limit int) ([]model.Article, err
 		// 重新获取
 		articleIDs, err = s.redisClient.ZRevRange(ctx, TrendingArticlesKey, 0, int64(limit-1)).Result()
 		if err != nil || len(articleIDs) == 0 {
-			return []model.Article{}, nil
+			return []model.ArticleV3{}, nil
 		}
 	}
 
@@ -99,18 +101,18 @@ func (s *trendingServiceV2) GetTrendingArticles(limit int) ([]model.Article, err
 	}
 
 	if len(ids) == 0 {
-		return []model.Article{}, nil
+		return []model.ArticleV3{}, nil
 	}
 
 	// 4. 批量查询文章详情
-	articles, err := s.blogRepo.FindByIDs(ids)
+	articles, err := s.articleRepo.FindByIDs(ids)
 	if err != nil {
 		return nil, constant.ErrDatabaseQuery
 	}
 
 	// 5. 按照热度排序返回
-	sortedArticles := make([]model.Article, 0, len(ids))
-	articleMap := make(map[uint64]model.Article)
+	sortedArticles := make([]model.ArticleV3, 0, len(ids))
+	articleMap := make(map[uint64]model.ArticleV3)
 	for _, article := range articles {
 		articleMap[article.ID] = article
 	}
@@ -146,14 +148,13 @@ func (s *trendingServiceV2) GetTrendingUsers(limit int) ([]model.User, error) {
 		}
 	}
 
-	// 3. 查询用户详情
+	// 3. 去掉关闭了榜单展示的用户
+	userIDs = s.privacy.FilterTrendingUsers(userIDs)
+
+	// 4. 查询用户详情（用户ID为UUID）
 	var users []model.User
-	for _, idStr := range userIDs {
-		id, err := strconv.ParseUint(idStr, 10, 64)
-		if err != nil {
-			continue
-		}
-		user, err := s.userRepo.FindByID(strconv.Itoa(int(uint(id))))
+	for _, id := range userIDs {
+		user, err := s.userRepo.FindByID(id)
 		if err != nil {
 			continue
 		}
@@ -171,26 +172,30 @@ func (s *trendingServiceV2) UpdateArticleScore(articleID uint64) error {
 	ctx := context.Background()
 
 	// 1. 查询文章详情
-	article, err := s.blogRepo.FindByID(articleID)
+	article, err := s.articleRepo.FindByID(articleID)
 	if err != nil {
 		return err
 	}
 
 	// 2. 只计算已发布的文章
-	if article.Status != model.ArticleStatusPublished {
+	if article.Status != model.ArticleV3StatusPublished {
 		return nil
 	}
 
 	// 3. 计算热度分数
-	// score = visit * 1 + good_count * 5 + comment_count * 10 + favorite_count * 8
-	score := float64(article.Visit)*VisitWeight +
-		float64(article.GoodCount)*LikeWeight +
+	// score = view_count * 1 + like_count * 5 + comment_count * 10 + favorite_count * 8
+	score := float64(article.ViewCount)*VisitWeight +
+		float64(article.LikeCount)*LikeWeight +
 		float64(article.CommentCount)*CommentWeight +
 		float64(article.FavoriteCount)*FavoriteWeight
 
 	// 4. 时间衰减因子（文章越新，权重越高）
 	// 超过7天的文章，分数逐渐衰减
-	daysSincePublish := time.Since(article.CreateTime).Hours() / 24
+	publishTime := article.CreateTime
+	if article.PublishTime != nil {
+		publishTime = *article.PublishTime
+	}
+	daysSincePublish := time.Since(publishTime).Hours() / 24
 	if daysSincePublish > 7 {
 		decayFactor := 1.0 / (1.0 + (daysSincePublish-7)/7)
 		score *= decayFactor
@@ -252,7 +257,12 @@ func (s *trendingServiceV2) RefreshTrendingData() error {
 
 	// 2. 获取最近的文章（比如最近30天）
 	// 这里简化实现，实际应该从数据库查询最近的文章
-	articles, _, err := s.blogRepo.FindList(1, 500, "", model.ArticleStatusPublished)
+	articles, _, err := s.articleRepo.FindList(&repository.ArticleQueryParams{
+		Status:   model.ArticleV3StatusPublished,
+		SortBy:   "create_time",
+		Page:     1,
+		PageSize: 500,
+	})
 	if err != nil {
 		return err
 	}
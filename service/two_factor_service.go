@@ -0,0 +1,378 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"astronomer-gin/config"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/jwt"
+	"astronomer-gin/pkg/redis"
+	"astronomer-gin/pkg/totp"
+	"astronomer-gin/pkg/util"
+	"astronomer-gin/repository"
+)
+
+const (
+	twoFactorChallengePurpose = "2fa_challenge"  // 令牌用途：登录时的两步验证
+	twoFactorChallengeExpire  = 5 * time.Minute  // 密码验证通过后需在5分钟内完成两步验证
+	twoFactorMaxFails         = 5                // 连续输错5次后暂停验证
+	twoFactorFailWindow       = 10 * time.Minute // 输错次数的统计窗口
+	twoFactorSkew             = 1                // 允许前后各一个时间步（30秒）的时钟误差
+
+	recoveryCodeCount    = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789" // 去掉易混淆的 i/l/o/0/1
+)
+
+// TwoFactorStatus 两步验证状态
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	Required          bool       `json:"required"` // 当前角色要求开启
+	EnabledAt         *time.Time `json:"enabled_at"`
+	RecoveryCodesLeft int64      `json:"recovery_codes_left"`
+}
+
+// TwoFactorSetup 开启两步验证的密钥（用身份验证器App扫描otpauth链接生成的二维码，或手动输入密钥）
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorService 两步验证服务接口
+type TwoFactorService interface {
+	GetStatus(userID string) (*TwoFactorStatus, error)
+	// Setup 生成待确认的密钥（重复调用会换一个新密钥）
+	Setup(userID string) (*TwoFactorSetup, error)
+	// Enable 输入App生成的验证码确认开启，返回恢复码（只展示这一次）和新token
+	// 开启前签发的token全部失效（未经过两步验证的会话不能继续使用），当前会话换用新token
+	Enable(userID, code string) ([]string, string, error)
+	// Disable 关闭两步验证（需要验证码或恢复码）
+	Disable(userID, code string) error
+	// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码全部作废
+	RegenerateRecoveryCodes(userID, code string) ([]string, error)
+	// AdminReset 管理员重置用户的两步验证（用户丢失设备和恢复码时）
+	AdminReset(operatorID, userID string) error
+
+	// IsEnabled 用户是否已开启两步验证
+	IsEnabled(userID string) bool
+	// IsRequired 角色是否必须开启两步验证
+	IsRequired(role string) bool
+	// CreateChallenge 密码验证通过后签发两步验证凭证
+	CreateChallenge(userID string) (string, error)
+	// VerifyChallenge 校验凭证和验证码（或恢复码），返回用户ID
+	VerifyChallenge(challenge, code string) (string, error)
+}
+
+type twoFactorService struct {
+	twoFactorRepo    repository.TwoFactorRepository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+	cacheHelper      *util.CacheHelper
+}
+
+// NewTwoFactorService 创建TwoFactorService实例
+func NewTwoFactorService(
+	twoFactorRepo repository.TwoFactorRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+) TwoFactorService {
+	return &twoFactorService{
+		twoFactorRepo:    twoFactorRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		cacheHelper:      util.NewCacheHelper(redis.GetClient()),
+	}
+}
+
+// GetStatus 获取两步验证状态
+func (s *twoFactorService) GetStatus(userID string) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+
+	status := &TwoFactorStatus{Required: s.IsRequired(user.Role)}
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil || !twoFactor.Enabled {
+		return status, nil
+	}
+	status.Enabled = true
+	status.EnabledAt = twoFactor.EnabledAt
+	status.RecoveryCodesLeft, _ = s.twoFactorRepo.CountRecoveryCodes(userID)
+	return status, nil
+}
+
+// Setup 生成待确认的密钥
+func (s *twoFactorService) Setup(userID string) (*TwoFactorSetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+	if s.IsEnabled(userID) {
+		return nil, constant.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, constant.ErrSystemError
+	}
+	if err := s.twoFactorRepo.SavePending(userID, secret); err != nil {
+		return nil, fmt.Errorf("保存两步验证密钥失败: %w", err)
+	}
+
+	return &TwoFactorSetup{
+		Secret:     secret,
+		OtpauthURI: totp.URI(twoFactorIssuer(), user.Username, secret),
+	}, nil
+}
+
+// Enable 确认开启两步验证
+func (s *twoFactorService) Enable(userID, code string) ([]string, string, error) {
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, "", constant.ErrTwoFactorSetupMissing
+	}
+	if twoFactor.Enabled {
+		return nil, "", constant.ErrTwoFactorAlreadyEnabled
+	}
+	if s.tooManyFails(userID) {
+		return nil, "", constant.ErrTooManyRequests
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, "", constant.ErrUserNotExist
+	}
+
+	// 开启时只接受App生成的验证码，证明密钥已正确导入
+	step, ok := totp.Validate(twoFactor.Secret, normalizeTwoFactorCode(code), time.Now(), twoFactorSkew)
+	if !ok {
+		s.recordFail(userID)
+		return nil, "", constant.ErrTwoFactorCodeInvalid
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, "", constant.ErrSystemError
+	}
+	if err := s.twoFactorRepo.Enable(userID, step, hashes); err != nil {
+		return nil, "", fmt.Errorf("开启两步验证失败: %w", err)
+	}
+	s.clearFails(userID)
+
+	// 此前的会话都没有经过两步验证，全部失效；当前会话刚输入过验证码，签发新token
+	if err := revokeUserTokens(s.userRepo, s.cacheHelper, userID); err != nil {
+		return nil, "", fmt.Errorf("吊销旧token失败: %w", err)
+	}
+	token, err := jwt.Sign(user.Phone, user.ID)
+	if err != nil {
+		return nil, "", constant.ErrSystemError
+	}
+
+	s.notify(userID, "你的账号已开启两步验证，请妥善保存恢复码")
+	return codes, token, nil
+}
+
+// Disable 关闭两步验证
+func (s *twoFactorService) Disable(userID, code string) error {
+	if err := s.verify(userID, code); err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.Delete(userID); err != nil {
+		return fmt.Errorf("关闭两步验证失败: %w", err)
+	}
+
+	s.notify(userID, "你的账号已关闭两步验证。如非本人操作，请立即修改密码并重新开启两步验证")
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (s *twoFactorService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	if err := s.verify(userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, constant.ErrSystemError
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, fmt.Errorf("生成恢复码失败: %w", err)
+	}
+
+	s.notify(userID, "你的两步验证恢复码已重新生成，旧恢复码已失效。如非本人操作，请立即修改密码")
+	return codes, nil
+}
+
+// AdminReset 管理员重置两步验证
+func (s *twoFactorService) AdminReset(operatorID, userID string) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return constant.ErrUserNotExist
+	}
+	if !s.IsEnabled(userID) {
+		return constant.ErrTwoFactorNotEnabled
+	}
+	if err := s.twoFactorRepo.Delete(userID); err != nil {
+		return fmt.Errorf("重置两步验证失败: %w", err)
+	}
+	s.clearFails(userID)
+
+	log.Printf("⚠️  管理员重置了用户的两步验证: operator=%s, user=%s", operatorID, userID)
+	s.notify(userID, "管理员已重置你的两步验证，请尽快重新开启。如你未申请重置，请立即联系客服")
+	return nil
+}
+
+// IsEnabled 是否已开启两步验证
+func (s *twoFactorService) IsEnabled(userID string) bool {
+	return s.twoFactorRepo.IsEnabled(userID)
+}
+
+// IsRequired 角色是否必须开启两步验证
+func (s *twoFactorService) IsRequired(role string) bool {
+	return config.GlobalConfig != nil && config.GlobalConfig.Security.TwoFactorRequired(role)
+}
+
+// CreateChallenge 签发两步验证凭证
+func (s *twoFactorService) CreateChallenge(userID string) (string, error) {
+	token, id, err := jwt.NewSignedToken(twoFactorChallengePurpose)
+	if err != nil {
+		return "", constant.ErrSystemError
+	}
+	if err := s.cacheHelper.SetString(constant.CacheKey2FAChallenge+id, userID, twoFactorChallengeExpire); err != nil {
+		return "", constant.ErrSystemError
+	}
+	return token, nil
+}
+
+// VerifyChallenge 校验两步验证凭证和验证码
+func (s *twoFactorService) VerifyChallenge(challenge, code string) (string, error) {
+	id, ok := jwt.ParseSignedToken(twoFactorChallengePurpose, challenge)
+	if !ok {
+		return "", constant.ErrTwoFactorChallengeInvalid
+	}
+	key := constant.CacheKey2FAChallenge + id
+	userID, err := s.cacheHelper.GetString(key)
+	if err != nil {
+		return "", constant.ErrTwoFactorChallengeInvalid
+	}
+
+	if err := s.verify(userID, code); err != nil {
+		return "", err
+	}
+	// 凭证只能使用一次
+	if _, err := s.cacheHelper.GetDel(key); err != nil {
+		return "", constant.ErrTwoFactorChallengeInvalid
+	}
+	return userID, nil
+}
+
+// verify 校验验证码或恢复码（验证码不可重放，恢复码用后作废）
+func (s *twoFactorService) verify(userID, code string) error {
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil || !twoFactor.Enabled {
+		return constant.ErrTwoFactorNotEnabled
+	}
+	if s.tooManyFails(userID) {
+		return constant.ErrTooManyRequests
+	}
+
+	code = normalizeTwoFactorCode(code)
+	if len(code) == totp.Digits {
+		if step, ok := totp.Validate(twoFactor.Secret, code, time.Now(), twoFactorSkew); ok {
+			if used, err := s.twoFactorRepo.UseStep(userID, step); err == nil && used {
+				s.clearFails(userID)
+				return nil
+			}
+		}
+	} else if used, err := s.twoFactorRepo.UseRecoveryCode(userID, hashRecoveryCode(code)); err == nil && used {
+		s.clearFails(userID)
+		left, _ := s.twoFactorRepo.CountRecoveryCodes(userID)
+		s.notify(userID, fmt.Sprintf("你的账号使用了一个两步验证恢复码，剩余%d个。如非本人操作，请立即修改密码", left))
+		return nil
+	}
+
+	s.recordFail(userID)
+	return constant.ErrTwoFactorCodeInvalid
+}
+
+func (s *twoFactorService) tooManyFails(userID string) bool {
+	value, err := s.cacheHelper.GetString(constant.CacheKey2FAFail + userID)
+	if err != nil {
+		return false
+	}
+	count, _ := strconv.Atoi(value)
+	return count >= twoFactorMaxFails
+}
+
+func (s *twoFactorService) recordFail(userID string) {
+	key := constant.CacheKey2FAFail + userID
+	if count, _ := s.cacheHelper.Incr(key); count == 1 {
+		s.cacheHelper.Expire(key, twoFactorFailWindow)
+	}
+}
+
+func (s *twoFactorService) clearFails(userID string) {
+	s.cacheHelper.Delete(constant.CacheKey2FAFail + userID)
+}
+
+// notify 发送账号安全提醒
+func (s *twoFactorService) notify(userID, content string) {
+	notification := &model.Notification{
+		UserID:       userID,
+		Type:         model.NotificationTypeSecurity,
+		FromUsername: "系统",
+		Content:      content,
+		RelatedID:    userID,
+		RelatedType:  "security",
+		CreateTime:   time.Now(),
+	}
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("⚠️  发送安全提醒失败: user=%s, %v", userID, err)
+	}
+}
+
+func twoFactorIssuer() string {
+	if config.GlobalConfig != nil && config.GlobalConfig.Security.TwoFactorIssuer != "" {
+		return config.GlobalConfig.Security.TwoFactorIssuer
+	}
+	return "Astronomer"
+}
+
+// normalizeTwoFactorCode 去掉空格和连字符并转小写（恢复码展示为 xxxxx-xxxxx）
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// hashRecoveryCode 恢复码为高熵随机串，SHA-256即可防止数据库泄露后直接使用
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateRecoveryCodes 生成恢复码，返回展示给用户的明文和入库的哈希
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 10)
+		for j := range raw {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, nil, err
+			}
+			raw[j] = recoveryCodeAlphabet[n.Int64()]
+		}
+		codes = append(codes, string(raw[:5])+"-"+string(raw[5:]))
+		hashes = append(hashes, hashRecoveryCode(string(raw)))
+	}
+	return codes, hashes, nil
+}
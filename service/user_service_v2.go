@@ -1,47 +1,101 @@
 package service
 
 import (
+	"astronomer-gin/config"
 	"astronomer-gin/model"
 	"astronomer-gin/pkg/constant"
-	"astronomer-gin/pkg/email"
 	"astronomer-gin/pkg/jwt"
 	"astronomer-gin/pkg/redis"
 	"astronomer-gin/pkg/util"
 	"astronomer-gin/repository"
 	"fmt"
+	"log"
+	"strconv"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// LoginResult 登录结果
+// 开启两步验证的账号只返回 ChallengeToken，凭它和验证码调用 VerifyTwoFactorLogin 后才签发 Token
+type LoginResult struct {
+	Token                  string
+	User                   *model.User // 已脱敏
+	IsNew                  bool        // 验证码登录时自动注册的新用户
+	ChallengeToken         string
+	TwoFactorSetupRequired bool // 角色要求两步验证但尚未开启
+}
+
 // UserServiceV2 企业级用户服务接口
 type UserServiceV2 interface {
-	Register(phone, password, username string) error
-	Login(phone, password string) (string, *model.User, error)
+	Register(phone, password, username, email string) error
+	Login(phone, password string) (*LoginResult, error)
+	VerifyTwoFactorLogin(challengeToken, code string) (*LoginResult, error)
 	GetUserInfo(phone string) (*model.User, error)
 	GetUserInfoByID(userID string) (*model.User, error)
 	UpdateUserInfo(phone string, updates map[string]interface{}) error
 	ChangePassword(phone, oldPassword, newPassword string) error
 
+	// 邮箱验证与找回密码
+	GetEmailStatus(userID string) (*EmailStatus, error)
+	BindEmail(userID, email string) error
+	ResendVerifyEmail(userID string) error
+	VerifyEmail(token string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, newPassword string) (string, error)
+
+	// 短信验证码登录与更换手机号
+	SendLoginCode(phone, ip string) error
+	LoginBySMS(phone, code string) (*LoginResult, error)
+	SendChangePhoneOldCode(userID, ip string) error
+	VerifyChangePhoneOld(userID, code string) (string, error)
+	SendChangePhoneNewCode(userID, ticket, newPhone, ip string) error
+	ChangePhone(userID, ticket, newPhone, code string) (string, error)
+
+	// 第三方登录与账号绑定
+	ListOAuthProviders() []OAuthProviderInfo
+	OAuthAuthorize(providerName, userID, browserNonce string) (string, error)
+	OAuthCallback(providerName, code, state, currentUserID, browserNonce string) (*OAuthCallbackResult, error)
+	ListIdentities(userID string) ([]model.UserIdentity, error)
+	UnlinkIdentity(userID, providerName string) error
+
+	// 个性ID与改名记录
+	GetUserByHandle(handle string) (*model.User, error)
+	CheckHandleAvailable(userID, handle string) error
+	ChangeHandle(userID, handle string) (string, error)
+	GetNameHistory(userID string) ([]model.UserNameHistory, error)
+
 	// 缓存管理
 	RefreshUserCache(phone string) error
 	ClearUserCache(phone string) error
 }
 
 type userServiceV2 struct {
-	userRepo    repository.UserRepository
-	cacheHelper *util.CacheHelper
+	userRepo         repository.UserRepository
+	smsCodeService   SMSCodeService
+	twoFactorService TwoFactorService
+	identityRepo     repository.UserIdentityRepository
+	accountRepo      repository.AccountRepository
+	nameHistoryRepo  repository.NameHistoryRepository
+	cacheHelper      *util.CacheHelper
 }
 
-func NewUserServiceV2(userRepo repository.UserRepository) UserServiceV2 {
+func NewUserServiceV2(userRepo repository.UserRepository, smsCodeService SMSCodeService, twoFactorService TwoFactorService,
+	identityRepo repository.UserIdentityRepository, accountRepo repository.AccountRepository,
+	nameHistoryRepo repository.NameHistoryRepository) UserServiceV2 {
 	return &userServiceV2{
-		userRepo:    userRepo,
-		cacheHelper: util.NewCacheHelper(redis.GetClient()),
+		userRepo:         userRepo,
+		smsCodeService:   smsCodeService,
+		twoFactorService: twoFactorService,
+		identityRepo:     identityRepo,
+		accountRepo:      accountRepo,
+		nameHistoryRepo:  nameHistoryRepo,
+		cacheHelper:      util.NewCacheHelper(redis.GetClient()),
 	}
 }
 
-// Register 用户注册（企业级实现）
-func (s *userServiceV2) Register(phone, password, username string) error {
+// Register 用户注册（企业级实现，邮箱可选，验证后才生效）
+func (s *userServiceV2) Register(phone, password, username, email string) error {
 	// 1. 参数验证
 	if err := util.ValidatePhone(phone); err != nil {
 		return err
@@ -53,15 +107,25 @@ func (s *userServiceV2) Register(phone, password, username string) error {
 		return err
 	}
 
+	email = normalizeEmail(email)
+	if email != "" {
+		if err := util.ValidateEmail(email); err != nil {
+			return err
+		}
+	}
+
 	// 2. 敏感词检查
 	if util.ContainsSensitiveWord(username) {
 		return constant.ErrUsernameInvalid
 	}
 
-	// 3. 检查手机号是否已存在
+	// 3. 检查手机号、邮箱是否已存在
 	if s.userRepo.ExistsByPhone(phone) {
 		return constant.ErrPhoneRegistered
 	}
+	if email != "" && s.userRepo.ExistsByEmail(email) {
+		return constant.ErrEmailRegistered
+	}
 
 	// 4. 密码加密
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -72,75 +136,63 @@ func (s *userServiceV2) Register(phone, password, username string) error {
 	// 5. 创建用户
 	now := time.Now()
 	user := &model.User{
-		Phone:      phone,
-		Password:   string(hashedPassword),
-		Username:   username,
-		CreateTime: &now,
+		Phone:        phone,
+		Password:     string(hashedPassword),
+		Username:     username,
+		PendingEmail: email,
+		CreateTime:   &now,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return constant.ErrRegisterFailed
 	}
 
-	// 6. 异步发送欢迎邮件
-	go func() {
-		// 使用phone作为email（如果有单独的email字段，使用user.Email）
-		if err := email.SendWelcomeEmail(phone, username); err != nil {
-			// 记录错误但不影响注册流程
-			fmt.Printf("发送欢迎邮件失败: %v\n", err)
-		}
-	}()
+	// 6. 异步发送邮箱验证邮件（验证通过后再发送欢迎邮件）
+	if email != "" {
+		go func() {
+			if err := s.sendVerifyEmail(user); err != nil {
+				// 记录错误但不影响注册流程，用户可以稍后重新发送
+				fmt.Printf("发送验证邮件失败: %v\n", err)
+			}
+		}()
+	}
 
 	return nil
 }
 
 // Login 用户登录（企业级实现）
-func (s *userServiceV2) Login(phone, password string) (string, *model.User, error) {
+func (s *userServiceV2) Login(phone, password string) (*LoginResult, error) {
 	// 1. 参数验证
 	if err := util.ValidatePhone(phone); err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	// 2. 检查登录失败锁定
-	lockKey := fmt.Sprintf("login:lock:%s", phone)
-	if s.cacheHelper.Exists(lockKey) {
-		return "", nil, constant.ErrAccountLocked
-	}
-
-	// 3. 查找用户
+	// 2. 查找用户（失败次数、等待时间和验证码由 RiskService 在处理器中控制）
 	user, err := s.userRepo.FindByPhone(phone)
 	if err != nil {
-		// 记录登录失败次数
-		s.recordLoginFail(phone)
-		return "", nil, constant.ErrUserNotExist
+		return nil, constant.ErrUserNotExist
 	}
 
-	// 4. 验证密码
+	// 3. 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		// 记录登录失败次数
-		s.recordLoginFail(phone)
-		return "", nil, constant.ErrPasswordIncorrect
+		return nil, constant.ErrPasswordIncorrect
 	}
 
-	// 5. 清除登录失败记录
-	failKey := fmt.Sprintf("login:fail:%s", phone)
-	s.cacheHelper.Delete(failKey)
+	// 4. 开启两步验证时先返回验证凭证，否则直接签发token
+	return s.completeLogin(user)
+}
 
-	// 6. 生成token
-	token, err := jwt.Sign(user.Phone, user.ID)
+// VerifyTwoFactorLogin 两步验证通过后签发token
+func (s *userServiceV2) VerifyTwoFactorLogin(challengeToken, code string) (*LoginResult, error) {
+	userID, err := s.twoFactorService.VerifyChallenge(challengeToken, code)
 	if err != nil {
-		return "", nil, constant.ErrSystemError
+		return nil, err
 	}
-
-	// 7. 数据脱敏
-	userCopy := *user
-	userCopy.Password = "" // 不返回密码
-	userCopy.Phone = util.MaskPhone(user.Phone)
-
-	// 8. 更新缓存
-	s.setUserCache(user)
-
-	return token, &userCopy, nil
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+	return s.issueToken(user)
 }
 
 // GetUserInfo 获取用户信息（带缓存）
@@ -212,6 +264,9 @@ func (s *userServiceV2) UpdateUserInfo(phone string, updates map[string]interfac
 			return constant.ErrUsernameInvalid
 		}
 	}
+	if err := validateProfileUpdates(updates); err != nil {
+		return err
+	}
 
 	// 3. 禁止直接更新密码（应使用ChangePassword）
 	delete(updates, "password")
@@ -220,6 +275,9 @@ func (s *userServiceV2) UpdateUserInfo(phone string, updates map[string]interfac
 	if err := s.userRepo.UpdateFields(user.ID, updates); err != nil {
 		return constant.ErrUpdateUserFailed
 	}
+	if username, ok := updates["username"].(string); ok && username != user.Username {
+		s.recordUsernameChange(user.ID, user.Username, username)
+	}
 
 	// 5. 清除所有相关缓存
 	s.ClearUserCache(phone)                                                       // 清除基于 phone 的缓存
@@ -241,9 +299,11 @@ func (s *userServiceV2) ChangePassword(phone, oldPassword, newPassword string) e
 		return constant.ErrUserNotExist
 	}
 
-	// 3. 验证旧密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
-		return constant.ErrOldPasswordIncorrect
+	// 3. 验证旧密码（验证码或第三方登录注册的账号没有密码，首次设置不需要旧密码）
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+			return constant.ErrOldPasswordIncorrect
+		}
 	}
 
 	// 4. 加密新密码
@@ -285,20 +345,53 @@ func (s *userServiceV2) ClearUserCache(phone string) error {
 
 // ==================== 私有辅助方法 ====================
 
-// recordLoginFail 记录登录失败
-func (s *userServiceV2) recordLoginFail(phone string) {
-	failKey := fmt.Sprintf("login:fail:%s", phone)
-	count, _ := s.cacheHelper.Incr(failKey)
+// completeLogin 身份验证通过后的处理：开启两步验证时签发验证凭证，否则签发token
+func (s *userServiceV2) completeLogin(user *model.User) (*LoginResult, error) {
+	if s.twoFactorService.IsEnabled(user.ID) {
+		challenge, err := s.twoFactorService.CreateChallenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{ChallengeToken: challenge}, nil
+	}
+	return s.issueToken(user)
+}
 
-	// 第一次失败，设置过期时间（5分钟）
-	if count == 1 {
-		s.cacheHelper.Expire(failKey, time.Minute*5)
+// issueToken 签发token并返回脱敏后的用户信息
+func (s *userServiceV2) issueToken(user *model.User) (*LoginResult, error) {
+	token, err := jwt.Sign(user.Phone, user.ID)
+	if err != nil {
+		return nil, constant.ErrSystemError
+	}
+	s.cancelPendingDeletion(user.ID)
+
+	// 更新缓存
+	s.setUserCache(user)
+
+	// 数据脱敏
+	userCopy := *user
+	userCopy.Password = "" // 不返回密码
+	userCopy.Phone = util.MaskPhone(user.Phone)
+	if !user.HasPhone() {
+		userCopy.Phone = ""
 	}
 
-	// 失败5次，锁定账号5分钟
-	if count >= 5 {
-		lockKey := fmt.Sprintf("login:lock:%s", phone)
-		s.cacheHelper.SetString(lockKey, "1", time.Duration(constant.LockTimeLogin)*time.Second)
+	return &LoginResult{
+		Token:                  token,
+		User:                   &userCopy,
+		TwoFactorSetupRequired: s.twoFactorService.IsRequired(user.Role),
+	}, nil
+}
+
+// cancelPendingDeletion 冷静期内签发新token即视为放弃注销，保证注销执行时没有仍然有效的token
+func (s *userServiceV2) cancelPendingDeletion(userID string) {
+	cancelled, err := s.accountRepo.CancelDeletion(userID)
+	if err != nil {
+		log.Printf("⚠️  撤销注销申请失败: user=%s, %v", userID, err)
+		return
+	}
+	if cancelled {
+		log.Printf("♻️  用户在冷静期内重新登录，已撤销注销申请: user=%s", userID)
 	}
 }
 
@@ -307,3 +400,17 @@ func (s *userServiceV2) setUserCache(user *model.User) error {
 	cacheKey := constant.CacheKeyUserInfo + user.Phone
 	return s.cacheHelper.Set(cacheKey, user, time.Duration(constant.CacheExpireMedium)*time.Second)
 }
+
+// revokeUserTokens 使用户此前签发的token全部失效（重置密码、开启两步验证、注销账号后调用）
+// 失效时间写入数据库，Redis中保留到此前签发的token全部过期，认证中间件优先读取Redis
+func revokeUserTokens(userRepo repository.UserRepository, cacheHelper *util.CacheHelper, userID string) error {
+	now := time.Now()
+	if err := userRepo.UpdateFields(userID, map[string]interface{}{"token_valid_after": now}); err != nil {
+		return err
+	}
+	ttl := time.Duration(config.GlobalConfig.JWT.ExpireHours) * time.Hour
+	if err := cacheHelper.SetString(constant.CacheKeyTokenValid+userID, strconv.FormatInt(now.UnixMilli(), 10), ttl); err != nil {
+		log.Printf("⚠️  记录token失效时间失败: user=%s, %v", userID, err)
+	}
+	return nil
+}
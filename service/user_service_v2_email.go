@@ -0,0 +1,272 @@
+package service
+
+import (
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/email"
+	"astronomer-gin/pkg/jwt"
+	"astronomer-gin/pkg/util"
+	"encoding/json"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 一次性链接令牌
+const (
+	emailTokenVerify = "email_verify"   // 令牌用途：邮箱验证
+	emailTokenReset  = "password_reset" // 令牌用途：重置密码
+
+	emailVerifyExpire   = 24 * time.Hour   // 验证链接有效期
+	passwordResetExpire = 30 * time.Minute // 重置链接有效期
+	emailSendCooldown   = time.Minute      // 同一用户两次发信的最小间隔
+)
+
+// EmailStatus 当前用户的邮箱状态
+type EmailStatus struct {
+	Email        string `json:"email"` // 已验证邮箱（仅本人可见）
+	Verified     bool   `json:"email_verified"`
+	PendingEmail string `json:"pending_email"` // 待验证邮箱
+}
+
+// emailVerifyPayload 验证令牌对应的数据（令牌签发时的待验证邮箱，防止换绑后旧链接生效）
+type emailVerifyPayload struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+// GetEmailStatus 获取邮箱状态（直接查库，邮箱不进入用户信息缓存）
+func (s *userServiceV2) GetEmailStatus(userID string) (*EmailStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+	status := &EmailStatus{PendingEmail: user.PendingEmail}
+	if user.Email != nil {
+		status.Email = *user.Email
+		status.Verified = true
+	}
+	return status, nil
+}
+
+// BindEmail 绑定或更换邮箱（验证通过前原邮箱继续有效）
+func (s *userServiceV2) BindEmail(userID, address string) error {
+	address = normalizeEmail(address)
+	if err := util.ValidateEmail(address); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return constant.ErrUserNotExist
+	}
+	if user.Email != nil && *user.Email == address {
+		return nil
+	}
+	if s.userRepo.ExistsByEmail(address) {
+		return constant.ErrEmailRegistered
+	}
+	if s.inCooldown(emailTokenVerify, userID) {
+		return constant.ErrTooManyRequests
+	}
+
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{"pending_email": address}); err != nil {
+		return constant.ErrUpdateUserFailed
+	}
+	user.PendingEmail = address
+	return s.sendVerifyEmail(user)
+}
+
+// ResendVerifyEmail 重新发送验证邮件
+func (s *userServiceV2) ResendVerifyEmail(userID string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return constant.ErrUserNotExist
+	}
+	if user.PendingEmail == "" {
+		return constant.ErrEmailNotPending
+	}
+	if s.inCooldown(emailTokenVerify, userID) {
+		return constant.ErrTooManyRequests
+	}
+	return s.sendVerifyEmail(user)
+}
+
+// VerifyEmail 校验验证链接，待验证邮箱转为已验证
+func (s *userServiceV2) VerifyEmail(token string) error {
+	id, ok := jwt.ParseSignedToken(emailTokenVerify, token)
+	if !ok {
+		return constant.ErrEmailLinkInvalid
+	}
+	// 读取即删除，链接只能使用一次
+	raw, err := s.cacheHelper.GetDel(constant.CacheKeyEmailVerify + id)
+	if err != nil {
+		return constant.ErrEmailLinkInvalid
+	}
+	var payload emailVerifyPayload
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		return constant.ErrEmailLinkInvalid
+	}
+
+	user, err := s.userRepo.FindByID(payload.UserID)
+	if err != nil {
+		return constant.ErrUserNotExist
+	}
+	// 签发后又换绑了其他邮箱，旧链接作废
+	if user.PendingEmail != payload.Email {
+		return constant.ErrEmailLinkInvalid
+	}
+	if s.userRepo.ExistsByEmail(payload.Email) {
+		return constant.ErrEmailRegistered
+	}
+
+	firstVerify := user.Email == nil
+	updates := map[string]interface{}{
+		"email":         payload.Email,
+		"pending_email": "",
+	}
+	if err := s.userRepo.UpdateFields(user.ID, updates); err != nil {
+		return constant.ErrUpdateUserFailed
+	}
+	s.clearUserCaches(user)
+
+	// 首次验证邮箱后发送欢迎邮件
+	if firstVerify {
+		go func() {
+			if err := email.SendWelcomeEmail(payload.Email, user.Username); err != nil {
+				log.Printf("⚠️  发送欢迎邮件失败: user=%s, %v", user.ID, err)
+			}
+		}()
+	}
+	return nil
+}
+
+// ForgotPassword 发送重置密码邮件
+// 邮箱不存在或发送过于频繁时同样返回成功，避免被用来探测邮箱是否注册
+func (s *userServiceV2) ForgotPassword(address string) error {
+	address = normalizeEmail(address)
+	if err := util.ValidateEmail(address); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(address)
+	if err != nil {
+		return nil
+	}
+	if s.inCooldown(emailTokenReset, user.ID) {
+		return nil
+	}
+
+	token, id, err := jwt.NewSignedToken(emailTokenReset)
+	if err != nil {
+		return constant.ErrSystemError
+	}
+
+	// 每个用户只保留最新的重置链接
+	activeKey := constant.CacheKeyPasswordReset + "user:" + user.ID
+	if oldID, err := s.cacheHelper.GetString(activeKey); err == nil {
+		s.cacheHelper.Delete(constant.CacheKeyPasswordReset + oldID)
+	}
+	if err := s.cacheHelper.SetString(constant.CacheKeyPasswordReset+id, user.ID, passwordResetExpire); err != nil {
+		return constant.ErrSystemError
+	}
+	s.cacheHelper.SetString(activeKey, id, passwordResetExpire)
+	s.startCooldown(emailTokenReset, user.ID)
+
+	data := &email.ResetPasswordData{
+		Username:      user.Username,
+		Link:          email.SiteURL() + "/reset-password?token=" + url.QueryEscape(token),
+		ExpireMinutes: int(passwordResetExpire / time.Minute),
+	}
+	go func() {
+		if err := email.SendResetPasswordEmail(address, data); err != nil {
+			log.Printf("⚠️  发送重置密码邮件失败: user=%s, %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword 通过重置链接设置新密码，返回重置的用户ID
+func (s *userServiceV2) ResetPassword(token, newPassword string) (string, error) {
+	if err := util.ValidatePassword(newPassword); err != nil {
+		return "", err
+	}
+
+	id, ok := jwt.ParseSignedToken(emailTokenReset, token)
+	if !ok {
+		return "", constant.ErrEmailLinkInvalid
+	}
+	userID, err := s.cacheHelper.GetDel(constant.CacheKeyPasswordReset + id)
+	if err != nil {
+		return "", constant.ErrEmailLinkInvalid
+	}
+	s.cacheHelper.Delete(constant.CacheKeyPasswordReset + "user:" + userID)
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", constant.ErrUserNotExist
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return "", constant.ErrSystemError
+	}
+	if err := s.userRepo.UpdateFields(user.ID, map[string]interface{}{"password": string(hashedPassword)}); err != nil {
+		return "", constant.ErrUpdateUserFailed
+	}
+	// 重置密码用于找回被盗账号，此前登录的会话全部失效
+	if err := revokeUserTokens(s.userRepo, s.cacheHelper, user.ID); err != nil {
+		log.Printf("⚠️  重置密码后吊销token失败: user=%s, %v", user.ID, err)
+	}
+
+	// 重置后清除密码错误记录
+	clearLoginFailures(s.cacheHelper, user.Phone)
+	s.clearUserCaches(user)
+	return user.ID, nil
+}
+
+// ==================== 私有辅助方法 ====================
+
+// sendVerifyEmail 签发验证令牌并发送到待验证邮箱
+func (s *userServiceV2) sendVerifyEmail(user *model.User) error {
+	token, id, err := jwt.NewSignedToken(emailTokenVerify)
+	if err != nil {
+		return constant.ErrSystemError
+	}
+	payload := &emailVerifyPayload{UserID: user.ID, Email: user.PendingEmail}
+	if err := s.cacheHelper.Set(constant.CacheKeyEmailVerify+id, payload, emailVerifyExpire); err != nil {
+		return constant.ErrSystemError
+	}
+	s.startCooldown(emailTokenVerify, user.ID)
+
+	data := &email.VerifyEmailData{
+		Username:    user.Username,
+		Link:        email.SiteURL() + "/verify-email?token=" + url.QueryEscape(token),
+		ExpireHours: int(emailVerifyExpire / time.Hour),
+	}
+	return email.SendVerifyEmail(user.PendingEmail, data)
+}
+
+// inCooldown 是否处于发信冷却期
+func (s *userServiceV2) inCooldown(purpose, userID string) bool {
+	return s.cacheHelper.Exists(constant.CacheKeyEmailCooldown + purpose + ":" + userID)
+}
+
+// startCooldown 开始发信冷却
+func (s *userServiceV2) startCooldown(purpose, userID string) {
+	s.cacheHelper.SetString(constant.CacheKeyEmailCooldown+purpose+":"+userID, "1", emailSendCooldown)
+}
+
+// clearUserCaches 清除基于 phone 和 userID 的用户信息缓存
+func (s *userServiceV2) clearUserCaches(user *model.User) {
+	s.ClearUserCache(user.Phone)
+	s.cacheHelper.Delete(constant.CacheKeyUserInfo + user.ID)
+}
+
+// normalizeEmail 邮箱统一去空格、转小写
+func normalizeEmail(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
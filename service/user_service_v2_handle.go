@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+const (
+	handleChangeCooldown = 30 * 24 * time.Hour // 两次修改个性ID的最小间隔
+	handleRedirectPeriod = 90 * 24 * time.Hour // 旧个性ID跳转（并保留给原用户）的时长
+	nameHistoryLimit     = 200                 // 管理员查看的修改记录条数上限
+)
+
+// GetUserByHandle 根据个性ID查找用户，旧个性ID在跳转期内返回其当前用户
+// 调用方比较返回用户的 Handle 与请求的个性ID，不同说明需要跳转
+func (s *userServiceV2) GetUserByHandle(handle string) (*model.User, error) {
+	handle = util.NormalizeHandle(handle)
+	if handle == "" {
+		return nil, constant.ErrUserNotExist
+	}
+
+	user, err := s.userRepo.FindByHandle(handle)
+	if err == nil {
+		user.Password = ""
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	redirect, err := s.nameHistoryRepo.FindActiveRedirect(handle, time.Now())
+	if err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+	return s.GetUserInfoByID(redirect.UserID)
+}
+
+// CheckHandleAvailable 检查个性ID是否可用（格式、保留字、占用和跳转期保留）
+func (s *userServiceV2) CheckHandleAvailable(userID, handle string) error {
+	handle = util.NormalizeHandle(handle)
+	if err := util.ValidateHandle(handle); err != nil {
+		return err
+	}
+
+	if owner, err := s.userRepo.FindByHandle(handle); err == nil {
+		if owner.ID == userID {
+			return constant.ErrHandleUnchanged
+		}
+		return constant.ErrHandleTaken
+	}
+
+	// 他人刚改掉的个性ID在跳转期内不能被占用，原用户可以改回
+	if redirect, err := s.nameHistoryRepo.FindActiveRedirect(handle, time.Now()); err == nil && redirect.UserID != userID {
+		return constant.ErrHandleTaken
+	}
+	return nil
+}
+
+// ChangeHandle 修改个性ID，返回规范化后的新ID
+func (s *userServiceV2) ChangeHandle(userID, handle string) (string, error) {
+	handle = util.NormalizeHandle(handle)
+	if err := s.CheckHandleAvailable(userID, handle); err != nil {
+		return "", err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", constant.ErrUserNotExist
+	}
+
+	now := time.Now()
+	if last, err := s.nameHistoryRepo.FindLatest(userID, model.NameHistoryKindHandle); err == nil {
+		if now.Sub(last.ChangeTime) < handleChangeCooldown {
+			return "", constant.ErrHandleChangeTooFrequent
+		}
+	}
+
+	if err := s.userRepo.ChangeHandle(userID, user.Handle, handle, now.Add(handleRedirectPeriod)); err != nil {
+		// 并发修改时唯一索引冲突或原ID已变化
+		if s.userRepo.ExistsByHandle(handle) {
+			return "", constant.ErrHandleTaken
+		}
+		return "", constant.ErrUpdateUserFailed
+	}
+
+	s.ClearUserCache(user.Phone)
+	s.cacheHelper.Delete(fmt.Sprintf("%s%s", constant.CacheKeyUserInfo, user.ID))
+
+	return handle, nil
+}
+
+// GetNameHistory 用户名和个性ID修改记录（管理员）
+func (s *userServiceV2) GetNameHistory(userID string) ([]model.UserNameHistory, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, constant.ErrUserNotExist
+	}
+	return s.nameHistoryRepo.FindByUser(userID, nameHistoryLimit)
+}
+
+// recordUsernameChange 记录用户名修改（失败只记录日志，不影响修改）
+func (s *userServiceV2) recordUsernameChange(userID, oldName, newName string) {
+	err := s.nameHistoryRepo.Create(&model.UserNameHistory{
+		UserID:   userID,
+		Kind:     model.NameHistoryKindUsername,
+		OldValue: oldName,
+		NewValue: newName,
+	})
+	if err != nil {
+		log.Printf("⚠️ 记录用户名修改失败: user=%s, %v", userID, err)
+	}
+}
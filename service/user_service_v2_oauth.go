@@ -0,0 +1,289 @@
+package service
+
+import (
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/oauth"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// oauthStateExpire 授权状态有效期（用户在第三方页面完成授权的时间）
+const oauthStateExpire = 10 * time.Minute
+
+// oauthUsernameInvalidChars 用户名不允许的字符（与 util.ValidateUsername 一致）
+var oauthUsernameInvalidChars = regexp.MustCompile(`[^\p{Han}a-zA-Z0-9_]+`)
+
+// OAuthProviderInfo 可用的第三方登录方式
+type OAuthProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// OAuthCallbackResult 授权回调结果：登录时返回 Login，绑定时 Linked=true 并返回绑定记录
+type OAuthCallbackResult struct {
+	Linked   bool
+	Login    *LoginResult
+	Identity *model.UserIdentity
+}
+
+// oauthState 授权状态，以 state 为键保存在缓存中，只能使用一次
+type oauthState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	UserID       string `json:"user_id"`                // 绑定时为发起绑定的用户，登录时为空
+	BrowserHash  string `json:"browser_hash,omitempty"` // 登录时发起授权的浏览器Cookie中随机值的哈希
+}
+
+// ListOAuthProviders 已启用的第三方登录方式
+func (s *userServiceV2) ListOAuthProviders() []OAuthProviderInfo {
+	providers := oauth.Providers()
+	list := make([]OAuthProviderInfo, 0, len(providers))
+	for _, provider := range providers {
+		list = append(list, OAuthProviderInfo{Name: provider.Name(), DisplayName: provider.DisplayName()})
+	}
+	return list
+}
+
+// OAuthAuthorize 生成授权页地址；userID 为空时用于登录，否则用于给该用户绑定
+// 登录时 browserNonce 为写入发起授权浏览器Cookie的随机值，回调时必须由同一浏览器带回（防止登录CSRF）
+func (s *userServiceV2) OAuthAuthorize(providerName, userID, browserNonce string) (string, error) {
+	provider, ok := oauth.GetProvider(providerName)
+	if !ok {
+		return "", constant.ErrOAuthProviderNotFound
+	}
+	if userID != "" {
+		if _, err := s.identityRepo.FindByUserProvider(userID, providerName); err == nil {
+			return "", constant.ErrIdentityAlreadyLinked
+		}
+	}
+
+	verifier, challenge, err := oauth.NewPKCE()
+	if err != nil {
+		return "", constant.ErrSystemError
+	}
+	state, err := oauth.RandomString(24)
+	if err != nil {
+		return "", constant.ErrSystemError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	authURL, err := provider.AuthCodeURL(ctx, state, challenge, oauth.RedirectURL(providerName))
+	if err != nil {
+		log.Printf("⚠️  生成第三方授权地址失败: provider=%s, %v", providerName, err)
+		return "", constant.ErrOAuthFailed
+	}
+
+	st := &oauthState{Provider: providerName, CodeVerifier: verifier, UserID: userID}
+	if userID == "" {
+		if browserNonce == "" {
+			return "", constant.ErrSystemError
+		}
+		st.BrowserHash = hashBrowserNonce(browserNonce)
+	}
+	if err := s.cacheHelper.Set(constant.CacheKeyOAuthState+state, st, oauthStateExpire); err != nil {
+		return "", constant.ErrSystemError
+	}
+	return authURL, nil
+}
+
+// OAuthCallback 处理授权回调：校验 state，用授权码换取第三方账号后登录或绑定
+// 绑定必须由发起绑定的用户完成（currentUserID 为回调请求携带的登录用户），防止把他人的第三方账号绑到自己名下
+// 登录必须由发起授权的浏览器完成（browserNonce 为该浏览器Cookie中的随机值），防止攻击者诱导受害者登录攻击者的账号
+func (s *userServiceV2) OAuthCallback(providerName, code, state, currentUserID, browserNonce string) (*OAuthCallbackResult, error) {
+	provider, ok := oauth.GetProvider(providerName)
+	if !ok {
+		return nil, constant.ErrOAuthProviderNotFound
+	}
+
+	// state 只能使用一次
+	var st oauthState
+	raw, err := s.cacheHelper.GetDel(constant.CacheKeyOAuthState + state)
+	if err != nil || json.Unmarshal([]byte(raw), &st) != nil || st.Provider != providerName {
+		return nil, constant.ErrOAuthStateInvalid
+	}
+	if st.UserID != "" && st.UserID != currentUserID {
+		return nil, constant.ErrOAuthStateInvalid
+	}
+	if st.UserID == "" && (st.BrowserHash == "" ||
+		subtle.ConstantTimeCompare([]byte(st.BrowserHash), []byte(hashBrowserNonce(browserNonce))) != 1) {
+		return nil, constant.ErrOAuthStateInvalid
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	identity, err := provider.Exchange(ctx, code, st.CodeVerifier, oauth.RedirectURL(providerName))
+	if err != nil {
+		log.Printf("⚠️  第三方授权失败: provider=%s, %v", providerName, err)
+		return nil, constant.ErrOAuthFailed
+	}
+
+	if st.UserID != "" {
+		return s.linkIdentity(st.UserID, providerName, identity)
+	}
+	return s.loginByIdentity(providerName, identity)
+}
+
+// hashBrowserNonce 授权状态中只保存浏览器随机值的哈希
+func hashBrowserNonce(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// ListIdentities 用户绑定的第三方账号
+func (s *userServiceV2) ListIdentities(userID string) ([]model.UserIdentity, error) {
+	identities, err := s.identityRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询第三方账号失败: %w", err)
+	}
+	return identities, nil
+}
+
+// UnlinkIdentity 解除绑定，不能解除唯一的登录方式
+func (s *userServiceV2) UnlinkIdentity(userID, providerName string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return constant.ErrUserNotExist
+	}
+	identity, err := s.identityRepo.FindByUserProvider(userID, providerName)
+	if err != nil {
+		return constant.ErrIdentityNotLinked
+	}
+
+	// 有手机号可以验证码登录，有密码可以密码登录，否则至少保留一个第三方账号
+	if !user.HasPhone() && user.Password == "" {
+		count, err := s.identityRepo.CountByUserID(userID)
+		if err != nil {
+			return constant.ErrSystemError
+		}
+		if count <= 1 {
+			return constant.ErrIdentityLastLogin
+		}
+	}
+	return s.identityRepo.Delete(identity.ID)
+}
+
+// loginByIdentity 第三方账号登录，首次登录时自动注册
+func (s *userServiceV2) loginByIdentity(providerName string, identity *oauth.Identity) (*OAuthCallbackResult, error) {
+	existing, err := s.identityRepo.FindByProviderSubject(providerName, identity.Subject)
+	if err == nil {
+		if err := s.identityRepo.UpdateProfile(existing.ID, identity.Username, identity.Email, identity.Avatar); err != nil {
+			log.Printf("⚠️  更新第三方资料失败: identity=%d, %v", existing.ID, err)
+		}
+		user, err := s.userRepo.FindByID(existing.UserID)
+		if err != nil {
+			return nil, constant.ErrUserNotExist
+		}
+		result, err := s.completeLogin(user)
+		if err != nil {
+			return nil, err
+		}
+		return &OAuthCallbackResult{Login: result}, nil
+	}
+
+	user, err := s.createOAuthUser(identity)
+	if err != nil {
+		return nil, err
+	}
+	record := &model.UserIdentity{
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Username: identity.Username,
+		Email:    identity.Email,
+		Avatar:   identity.Avatar,
+	}
+	if err := s.identityRepo.CreateWithUser(user, record); err != nil {
+		// 同一账号并发首次登录时另一个请求已完成注册
+		if _, findErr := s.identityRepo.FindByProviderSubject(providerName, identity.Subject); findErr == nil {
+			return s.loginByIdentity(providerName, identity)
+		}
+		return nil, constant.ErrRegisterFailed
+	}
+
+	result, err := s.completeLogin(user)
+	if err != nil {
+		return nil, err
+	}
+	result.IsNew = true
+	return &OAuthCallbackResult{Login: result}, nil
+}
+
+// createOAuthUser 构造第三方登录注册的用户：没有密码，手机号为占位值
+// 第三方邮箱已验证且未被占用时直接作为已验证邮箱
+func (s *userServiceV2) createOAuthUser(identity *oauth.Identity) (*model.User, error) {
+	suffix := make([]byte, 7)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, constant.ErrSystemError
+	}
+
+	now := time.Now()
+	user := &model.User{
+		Phone:      model.UnboundPhonePrefix + hex.EncodeToString(suffix),
+		Username:   oauthUsername(identity.Username),
+		Icon:       identity.Avatar,
+		CreateTime: &now,
+	}
+	if identity.EmailVerified && identity.Email != "" {
+		email := normalizeEmail(identity.Email)
+		if !s.userRepo.ExistsByEmail(email) {
+			user.Email = &email
+		}
+	}
+	return user, nil
+}
+
+// linkIdentity 给已登录用户绑定第三方账号
+func (s *userServiceV2) linkIdentity(userID, providerName string, identity *oauth.Identity) (*OAuthCallbackResult, error) {
+	if existing, err := s.identityRepo.FindByProviderSubject(providerName, identity.Subject); err == nil {
+		if existing.UserID != userID {
+			return nil, constant.ErrIdentityLinkedToOther
+		}
+		return &OAuthCallbackResult{Linked: true, Identity: existing}, nil
+	}
+	if _, err := s.identityRepo.FindByUserProvider(userID, providerName); err == nil {
+		return nil, constant.ErrIdentityAlreadyLinked
+	}
+
+	record := &model.UserIdentity{
+		UserID:   userID,
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Username: identity.Username,
+		Email:    identity.Email,
+		Avatar:   identity.Avatar,
+	}
+	if err := s.identityRepo.Create(record); err != nil {
+		return nil, constant.ErrIdentityLinkedToOther
+	}
+	return &OAuthCallbackResult{Linked: true, Identity: record}, nil
+}
+
+// oauthUsername 由第三方用户名生成本站用户名，去掉不允许的字符，不可用时生成随机用户名
+func oauthUsername(name string) string {
+	name = oauthUsernameInvalidChars.ReplaceAllString(strings.TrimSpace(name), "_")
+	name = strings.Trim(name, "_")
+	if utf8.RuneCountInString(name) > constant.MaxUsernameLength {
+		name = string([]rune(name)[:constant.MaxUsernameLength])
+	}
+	if utf8.RuneCountInString(name) >= constant.MinUsernameLength {
+		return name
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "用户"
+	}
+	return fmt.Sprintf("用户%06d", n.Int64())
+}
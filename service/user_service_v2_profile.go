@@ -0,0 +1,79 @@
+package service
+
+import (
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"net/url"
+	"time"
+	"unicode/utf8"
+)
+
+// 个人资料限制
+const (
+	maxProfileURLLength = 255 // 网站、社交链接最大长度
+	maxCoverURLLength   = 500 // 封面地址最大长度
+	maxLocationLength   = 50  // 所在地最大字数
+)
+
+// minBirthday 允许填写的最早生日
+var minBirthday = time.Date(1900, 1, 1, 0, 0, 0, 0, time.Local)
+
+// validateProfileUpdates 校验资料更新中的封面、网站、社交链接、所在地和生日
+// 空字符串表示清空，生日为nil表示清空
+func validateProfileUpdates(updates map[string]interface{}) error {
+	if cover, ok := updates["cover"].(string); ok && cover != "" {
+		if !isHTTPURL(cover, maxCoverURLLength) {
+			return constant.ErrCoverInvalid
+		}
+	}
+
+	if website, ok := updates["website"].(string); ok && website != "" {
+		if !isHTTPURL(website, maxProfileURLLength) {
+			return constant.ErrWebsiteInvalid
+		}
+	}
+
+	if links, ok := updates["social_links"].(model.SocialLinks); ok {
+		for platform, link := range links {
+			if !isSocialPlatform(platform) || !isHTTPURL(link, maxProfileURLLength) {
+				return constant.ErrSocialLinkInvalid
+			}
+		}
+	}
+
+	if location, ok := updates["location"].(string); ok {
+		if utf8.RuneCountInString(location) > maxLocationLength {
+			return constant.ErrLocationTooLong
+		}
+	}
+
+	if birthday, ok := updates["birthday"].(*time.Time); ok && birthday != nil {
+		if birthday.Before(minBirthday) || birthday.After(time.Now()) {
+			return constant.ErrBirthdayInvalid
+		}
+	}
+
+	return nil
+}
+
+// isHTTPURL 是否为指定长度内的 http/https 地址
+func isHTTPURL(raw string, maxLength int) bool {
+	if len(raw) > maxLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isSocialPlatform 是否为支持展示的社交平台
+func isSocialPlatform(platform string) bool {
+	for _, p := range model.SocialPlatforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"astronomer-gin/config"
+	"astronomer-gin/model"
+	"astronomer-gin/pkg/constant"
+	"astronomer-gin/pkg/jwt"
+	"astronomer-gin/pkg/util"
+	"log"
+	"time"
+)
+
+const (
+	phoneChangeTicketPurpose = "phone_change"   // 令牌用途：更换手机号
+	phoneChangeTicketExpire  = 10 * time.Minute // 验证原手机号后，需在10分钟内完成更换
+)
+
+// SendLoginCode 发送登录验证码（未注册的手机号同样发送，登录时自动注册）
+func (s *userServiceV2) SendLoginCode(phone, ip string) error {
+	return s.smsCodeService.SendCode(SMSSceneLogin, phone, ip)
+}
+
+// LoginBySMS 验证码登录，手机号未注册时自动注册
+func (s *userServiceV2) LoginBySMS(phone, code string) (*LoginResult, error) {
+	if err := util.ValidatePhone(phone); err != nil {
+		return nil, err
+	}
+	if err := s.smsCodeService.VerifyCode(SMSSceneLogin, phone, code); err != nil {
+		return nil, err
+	}
+
+	created := false
+	user, err := s.userRepo.FindByPhone(phone)
+	if err != nil {
+		// 验证码注册的账号没有密码，之后可通过找回密码设置
+		now := time.Now()
+		user = &model.User{
+			Phone:      phone,
+			Username:   "用户" + phone[len(phone)-4:],
+			CreateTime: &now,
+		}
+		if err := s.userRepo.Create(user); err != nil {
+			return nil, constant.ErrRegisterFailed
+		}
+		created = true
+	}
+
+	// 能收到验证码即证明持有手机号，清除密码错误记录
+	clearLoginFailures(s.cacheHelper, phone)
+
+	// 验证码只替代密码，开启两步验证的账号仍需验证
+	result, err := s.completeLogin(user)
+	if err != nil {
+		return nil, err
+	}
+	result.IsNew = created
+	return result, nil
+}
+
+// SendChangePhoneOldCode 更换手机号第一步：向原手机号发送验证码
+func (s *userServiceV2) SendChangePhoneOldCode(userID, ip string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return constant.ErrUserNotExist
+	}
+	// 未绑定手机号（第三方登录注册）时没有原手机号需要验证
+	if !user.HasPhone() {
+		return nil
+	}
+	return s.smsCodeService.SendCode(SMSSceneChangePhoneOld, user.Phone, ip)
+}
+
+// VerifyChangePhoneOld 更换手机号第二步：验证原手机号，返回更换凭证
+func (s *userServiceV2) VerifyChangePhoneOld(userID, code string) (string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", constant.ErrUserNotExist
+	}
+	if user.HasPhone() {
+		if err := s.smsCodeService.VerifyCode(SMSSceneChangePhoneOld, user.Phone, code); err != nil {
+			return "", err
+		}
+	}
+
+	ticket, id, err := jwt.NewSignedToken(phoneChangeTicketPurpose)
+	if err != nil {
+		return "", constant.ErrSystemError
+	}
+	if err := s.cacheHelper.SetString(constant.CacheKeyPhoneChange+id, user.ID, phoneChangeTicketExpire); err != nil {
+		return "", constant.ErrSystemError
+	}
+	return ticket, nil
+}
+
+// SendChangePhoneNewCode 更换手机号第三步：凭更换凭证向新手机号发送验证码
+func (s *userServiceV2) SendChangePhoneNewCode(userID, ticket, newPhone, ip string) error {
+	if _, err := s.checkPhoneChangeTicket(userID, ticket); err != nil {
+		return err
+	}
+	if err := s.checkNewPhone(userID, newPhone); err != nil {
+		return err
+	}
+	return s.smsCodeService.SendCode(SMSSceneChangePhoneNew, newPhone, ip)
+}
+
+// ChangePhone 更换手机号第四步：验证新手机号并完成更换，返回新的登录token
+func (s *userServiceV2) ChangePhone(userID, ticket, newPhone, code string) (string, error) {
+	ticketKey, err := s.checkPhoneChangeTicket(userID, ticket)
+	if err != nil {
+		return "", err
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", constant.ErrUserNotExist
+	}
+	if err := s.checkNewPhone(userID, newPhone); err != nil {
+		return "", err
+	}
+	if err := s.smsCodeService.VerifyCode(SMSSceneChangePhoneNew, newPhone, code); err != nil {
+		return "", err
+	}
+
+	// 凭证只能使用一次
+	if _, err := s.cacheHelper.GetDel(ticketKey); err != nil {
+		return "", constant.ErrPhoneChangeExpired
+	}
+	if err := s.userRepo.ChangePhone(user.ID, user.Phone, newPhone); err != nil {
+		return "", constant.ErrUpdateUserFailed
+	}
+	s.clearUserCaches(user)
+
+	// token中携带手机号，更换后重新签发；携带旧手机号的token在过期前由认证中间件拒绝
+	s.revokePhoneTokens(user.ID, newPhone)
+	token, err := jwt.Sign(newPhone, user.ID)
+	if err != nil {
+		return "", constant.ErrSystemError
+	}
+	s.cancelPendingDeletion(user.ID)
+	return token, nil
+}
+
+// revokePhoneTokens 记录用户当前手机号，保留到此前签发的token全部过期
+func (s *userServiceV2) revokePhoneTokens(userID, phone string) {
+	ttl := time.Duration(config.GlobalConfig.JWT.ExpireHours) * time.Hour
+	if err := s.cacheHelper.SetString(constant.CacheKeyTokenPhone+userID, phone, ttl); err != nil {
+		log.Printf("⚠️  记录更换后的手机号失败: user=%s, %v", userID, err)
+	}
+}
+
+// checkPhoneChangeTicket 校验更换凭证属于当前用户，返回凭证的缓存键
+func (s *userServiceV2) checkPhoneChangeTicket(userID, ticket string) (string, error) {
+	id, ok := jwt.ParseSignedToken(phoneChangeTicketPurpose, ticket)
+	if !ok {
+		return "", constant.ErrPhoneChangeExpired
+	}
+	key := constant.CacheKeyPhoneChange + id
+	owner, err := s.cacheHelper.GetString(key)
+	if err != nil || owner != userID {
+		return "", constant.ErrPhoneChangeExpired
+	}
+	return key, nil
+}
+
+// checkNewPhone 校验新手机号格式且未被占用
+func (s *userServiceV2) checkNewPhone(userID, newPhone string) error {
+	if err := util.ValidatePhone(newPhone); err != nil {
+		return err
+	}
+	existing, err := s.userRepo.FindByPhone(newPhone)
+	if err != nil {
+		return nil
+	}
+	if existing.ID == userID {
+		return constant.ErrPhoneUnchanged
+	}
+	return constant.ErrPhoneRegistered
+}
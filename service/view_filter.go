@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"astronomer-gin/pkg/redis"
+)
+
+// ViewVisitor 浏览者信息（用于浏览量去重与爬虫过滤）
+type ViewVisitor struct {
+	UserID     string // 登录用户ID
+	VisitorID  string // 匿名访客Cookie ID
+	NewVisitor bool   // 本次请求才下发的Cookie（请求本身未携带）
+	IP         string
+	UserAgent  string
+}
+
+// fingerprint 访客指纹（IP+UA）
+func (v *ViewVisitor) fingerprint() string {
+	sum := sha1.Sum([]byte(v.IP + "|" + v.UserAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// identities 去重标识：first决定是否为新访客，其余仅登记
+// 未携带Cookie的请求按指纹判定，同时登记新下发的Cookie，避免该访客下次带Cookie访问时被重复计数
+func (v *ViewVisitor) identities() (first string, others []string) {
+	switch {
+	case v.UserID != "":
+		return "u:" + v.UserID, nil
+	case v.VisitorID != "" && !v.NewVisitor:
+		return "c:" + v.VisitorID, nil
+	case v.VisitorID != "":
+		return "f:" + v.fingerprint(), []string{"c:" + v.VisitorID}
+	default:
+		return "f:" + v.fingerprint(), nil
+	}
+}
+
+const (
+	// viewDedupeTTL 去重HyperLogLog保留时间（按自然日去重，多留一天避免跨零点边界问题）
+	viewDedupeTTL = 48 * time.Hour
+
+	// 频率阈值：同一指纹/同一IP每分钟浏览的文章数超过阈值即判定为爬虫
+	viewRateFingerprintLimit = 20
+	viewRateIPLimit          = 120
+	// viewBotBanTTL 判定为爬虫后的标记时长
+	viewBotBanTTL = time.Hour
+)
+
+// botUserAgentKeywords 已知爬虫/脚本UA关键字（小写）
+var botUserAgentKeywords = []string{
+	"bot", "spider", "crawl", "slurp", "headless", "phantomjs", "selenium", "puppeteer", "playwright",
+	"curl/", "wget/", "python-requests", "python-urllib", "aiohttp", "httpclient", "okhttp", "go-http-client",
+	"java/", "libwww-perl", "scrapy", "postman", "insomnia", "feedfetcher", "mediapartners", "lighthouse",
+	"facebookexternalhit", "preview", "monitor", "uptime",
+}
+
+// isBotUserAgent 根据UA判断是否为爬虫或脚本（空UA同样视为非浏览器）
+func isBotUserAgent(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, keyword := range botUserAgentKeywords {
+		if strings.Contains(ua, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBotVisitor 综合UA与访问频率判断是否为爬虫
+func isBotVisitor(ctx context.Context, visitor *ViewVisitor) bool {
+	if isBotUserAgent(visitor.UserAgent) {
+		return true
+	}
+	if redis.Client == nil {
+		return false
+	}
+
+	fp := visitor.fingerprint()
+	fpBanKey := "view:bot:fp:" + fp
+	ipBanKey := "view:bot:ip:" + visitor.IP
+
+	// 1. 已被标记
+	if n, err := redis.Client.Exists(ctx, fpBanKey, ipBanKey).Result(); err == nil && n > 0 {
+		return true
+	}
+
+	// 2. 频率检测（按分钟窗口计数）
+	minute := time.Now().Format("200601021504")
+	if exceedsViewRate(ctx, fmt.Sprintf("view:rate:fp:%s:%s", fp, minute), viewRateFingerprintLimit) {
+		redis.Client.Set(ctx, fpBanKey, 1, viewBotBanTTL)
+		return true
+	}
+	if visitor.IP != "" && exceedsViewRate(ctx, fmt.Sprintf("view:rate:ip:%s:%s", visitor.IP, minute), viewRateIPLimit) {
+		redis.Client.Set(ctx, ipBanKey, 1, viewBotBanTTL)
+		return true
+	}
+	return false
+}
+
+// exceedsViewRate 计数并判断是否超过阈值
+func exceedsViewRate(ctx context.Context, key string, limit int64) bool {
+	count, err := redis.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return false
+	}
+	if count == 1 {
+		redis.Client.Expire(ctx, key, 2*time.Minute)
+	}
+	return count > limit
+}
+
+// markUniqueView 将访客登记到当日HyperLogLog，返回是否为当日新访客
+func markUniqueView(ctx context.Context, key string, visitor *ViewVisitor) (bool, error) {
+	first, others := visitor.identities()
+
+	added, err := redis.Client.PFAdd(ctx, key, first).Result()
+	if err != nil {
+		return false, err
+	}
+	if len(others) > 0 {
+		members := make([]interface{}, len(others))
+		for i, other := range others {
+			members[i] = other
+		}
+		redis.Client.PFAdd(ctx, key, members...)
+	}
+	if added > 0 {
+		redis.Client.Expire(ctx, key, viewDedupeTTL)
+	}
+	return added > 0, nil
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"astronomer-gin/service"
+)
+
+// AccountExportHandler 个人数据导出任务处理器
+type AccountExportHandler struct {
+	accountService service.AccountService
+}
+
+// NewAccountExportHandler 创建个人数据导出处理器
+func NewAccountExportHandler(accountService service.AccountService) *AccountExportHandler {
+	return &AccountExportHandler{
+		accountService: accountService,
+	}
+}
+
+// Handle 实现TaskHandler接口
+func (h *AccountExportHandler) Handle(ctx context.Context, taskType string, data []byte) error {
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+
+	exportID, ok := task.Data["export_id"].(float64)
+	if !ok {
+		return fmt.Errorf("missing or invalid export_id in task data")
+	}
+
+	return h.accountService.RunDataExport(ctx, uint64(exportID))
+}
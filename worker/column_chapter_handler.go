@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"astronomer-gin/service"
+)
+
+// ColumnChapterHandler 专栏新章节通知扇出任务处理器
+type ColumnChapterHandler struct {
+	chapterService service.ColumnChapterService
+}
+
+// NewColumnChapterHandler 创建专栏新章节通知处理器
+func NewColumnChapterHandler(chapterService service.ColumnChapterService) *ColumnChapterHandler {
+	return &ColumnChapterHandler{
+		chapterService: chapterService,
+	}
+}
+
+// Handle 实现TaskHandler接口
+func (h *ColumnChapterHandler) Handle(ctx context.Context, taskType string, data []byte) error {
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+
+	columnID, ok := task.Data["column_id"].(float64)
+	if !ok {
+		return fmt.Errorf("missing or invalid column_id in task data")
+	}
+	articleID, ok := task.Data["article_id"].(float64)
+	if !ok {
+		return fmt.Errorf("missing or invalid article_id in task data")
+	}
+	afterID, _ := task.Data["after_id"].(float64)
+
+	return h.chapterService.RunChapterFanout(ctx, uint64(columnID), uint64(articleID), uint64(afterID))
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"astronomer-gin/service"
+)
+
+// ColumnExportHandler 专栏电子书导出任务处理器
+type ColumnExportHandler struct {
+	exportService service.ColumnExportService
+}
+
+// NewColumnExportHandler 创建专栏导出处理器
+func NewColumnExportHandler(exportService service.ColumnExportService) *ColumnExportHandler {
+	return &ColumnExportHandler{
+		exportService: exportService,
+	}
+}
+
+// Handle 实现TaskHandler接口
+func (h *ColumnExportHandler) Handle(ctx context.Context, taskType string, data []byte) error {
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+
+	exportID, ok := task.Data["export_id"].(float64)
+	if !ok {
+		return fmt.Errorf("missing or invalid export_id in task data")
+	}
+
+	return h.exportService.RunColumnExport(ctx, uint64(exportID))
+}
@@ -9,15 +9,36 @@ import (
 
 // CombinedHandler 组合任务处理器,可以处理多种类型的任务
 type CombinedHandler struct {
-	notificationHandler *NotificationHandler
-	statsHandler        *StatsHandler
+	notificationHandler  *NotificationHandler
+	statsHandler         *StatsHandler
+	importHandler        *ImportHandler
+	columnExportHandler  *ColumnExportHandler
+	engagementHandler    *EngagementHandler
+	columnChapterHandler *ColumnChapterHandler
+	smsHandler           *SMSHandler
+	accountExportHandler *AccountExportHandler
 }
 
 // NewCombinedHandler 创建组合处理器
-func NewCombinedHandler(notificationHandler *NotificationHandler, statsHandler *StatsHandler) *CombinedHandler {
+func NewCombinedHandler(
+	notificationHandler *NotificationHandler,
+	statsHandler *StatsHandler,
+	importHandler *ImportHandler,
+	columnExportHandler *ColumnExportHandler,
+	engagementHandler *EngagementHandler,
+	columnChapterHandler *ColumnChapterHandler,
+	smsHandler *SMSHandler,
+	accountExportHandler *AccountExportHandler,
+) *CombinedHandler {
 	return &CombinedHandler{
-		notificationHandler: notificationHandler,
-		statsHandler:        statsHandler,
+		notificationHandler:  notificationHandler,
+		statsHandler:         statsHandler,
+		importHandler:        importHandler,
+		columnExportHandler:  columnExportHandler,
+		engagementHandler:    engagementHandler,
+		columnChapterHandler: columnChapterHandler,
+		smsHandler:           smsHandler,
+		accountExportHandler: accountExportHandler,
 	}
 }
 
@@ -36,6 +57,18 @@ func (h *CombinedHandler) Handle(ctx context.Context, taskType string, data []by
 		return h.notificationHandler.Handle(ctx, taskType, data)
 	case "stats":
 		return h.statsHandler.Handle(ctx, taskType, data)
+	case "import":
+		return h.importHandler.Handle(ctx, taskType, data)
+	case "column_export":
+		return h.columnExportHandler.Handle(ctx, taskType, data)
+	case "engagement":
+		return h.engagementHandler.Handle(ctx, taskType, data)
+	case "column_chapter":
+		return h.columnChapterHandler.Handle(ctx, taskType, data)
+	case "sms":
+		return h.smsHandler.Handle(ctx, taskType, data)
+	case "account_export":
+		return h.accountExportHandler.Handle(ctx, taskType, data)
 	case "image":
 		// 图片处理任务
 		return h.handleImageTask(ctx, task)
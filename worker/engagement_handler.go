@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"astronomer-gin/service"
+)
+
+// EngagementHandler 阅读参与度统计任务处理器
+type EngagementHandler struct {
+	engagementService service.EngagementService
+}
+
+// NewEngagementHandler 创建阅读参与度处理器
+func NewEngagementHandler(engagementService service.EngagementService) *EngagementHandler {
+	return &EngagementHandler{
+		engagementService: engagementService,
+	}
+}
+
+// Handle 实现TaskHandler接口
+func (h *EngagementHandler) Handle(ctx context.Context, taskType string, data []byte) error {
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+
+	articleID, ok := task.Data["article_id"].(float64)
+	if !ok {
+		return fmt.Errorf("missing or invalid article_id in task data")
+	}
+
+	event := &service.EngagementEvent{
+		ArticleID: uint64(articleID),
+		EventTime: time.Now(),
+	}
+	event.Progress, _ = task.Data["progress"].(float64)
+	if stayTime, ok := task.Data["stay_time"].(float64); ok {
+		event.StayTime = int(stayTime)
+	}
+	event.Source, _ = task.Data["source"].(string)
+	event.Device, _ = task.Data["device"].(string)
+	event.Region, _ = task.Data["region"].(string)
+	if eventTime, ok := task.Data["event_time"].(float64); ok {
+		event.EventTime = time.Unix(int64(eventTime), 0)
+	}
+
+	return h.engagementService.ApplyEngagement(event)
+}
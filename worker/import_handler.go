@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"astronomer-gin/service"
+)
+
+// ImportHandler 文章导入任务处理器
+type ImportHandler struct {
+	importService service.ArticleImportService
+}
+
+// NewImportHandler 创建导入处理器
+func NewImportHandler(importService service.ArticleImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// Handle 实现TaskHandler接口
+func (h *ImportHandler) Handle(ctx context.Context, taskType string, data []byte) error {
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+
+	jobID, ok := task.Data["job_id"].(float64)
+	if !ok {
+		return fmt.Errorf("missing or invalid job_id in task data")
+	}
+
+	return h.importService.RunImportJob(ctx, uint64(jobID))
+}
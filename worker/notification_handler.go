@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"astronomer-gin/model"
+	"astronomer-gin/pkg/email"
 	"astronomer-gin/repository"
 )
 
@@ -85,6 +86,19 @@ func (h *NotificationHandler) handleFollowNotification(ctx context.Context, data
 	}
 
 	log.Printf("Follow notification created successfully for user %s", userID)
+
+	if recipient := h.emailRecipient(userID); recipient != nil {
+		mail := &email.FollowNotificationData{
+			Username:      recipient.Username,
+			FollowerName:  followerUsername,
+			FollowTime:    notification.CreateTime,
+			FollowerCount: int(recipient.FollowedCount),
+		}
+		if follower, err := h.userRepo.FindByID(followerID); err == nil {
+			mail.FollowerBio = follower.Intro
+		}
+		h.sendEmail(userID, func() error { return email.SendFollowNotification(*recipient.Email, mail) })
+	}
 	return nil
 }
 
@@ -121,6 +135,19 @@ func (h *NotificationHandler) handleLikeArticleNotification(ctx context.Context,
 	}
 
 	log.Printf("Like article notification created successfully for user %s", authorID)
+
+	if recipient := h.emailRecipient(authorID); recipient != nil {
+		mail := &email.LikeNotificationData{
+			Username:     recipient.Username,
+			LikerName:    likerUsername,
+			ArticleTitle: stringValue(data, "article_title"),
+			ArticleID:    articleID,
+		}
+		if likeCount, ok := data["like_count"].(float64); ok {
+			mail.LikeCount = int(likeCount)
+		}
+		h.sendEmail(authorID, func() error { return email.SendLikeNotification(*recipient.Email, mail) })
+	}
 	return nil
 }
 
@@ -158,6 +185,17 @@ func (h *NotificationHandler) handleCommentNotification(ctx context.Context, dat
 	}
 
 	log.Printf("Comment notification created successfully for user %s", authorID)
+
+	if recipient := h.emailRecipient(authorID); recipient != nil {
+		mail := &email.CommentNotificationData{
+			Username:       recipient.Username,
+			CommenterName:  commenterUsername,
+			ArticleTitle:   stringValue(data, "article_title"),
+			CommentContent: stringValue(data, "comment_content"),
+			ArticleID:      articleID,
+		}
+		h.sendEmail(authorID, func() error { return email.SendCommentNotification(*recipient.Email, mail) })
+	}
 	return nil
 }
 
@@ -196,3 +234,25 @@ func (h *NotificationHandler) handleReplyCommentNotification(ctx context.Context
 	log.Printf("Reply comment notification created successfully for user %s", targetUserID)
 	return nil
 }
+
+// emailRecipient 查询通知邮件收件人，未验证邮箱的用户不发送邮件
+func (h *NotificationHandler) emailRecipient(userID string) *model.User {
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil || user.Email == nil || *user.Email == "" {
+		return nil
+	}
+	return user
+}
+
+// sendEmail 发送通知邮件，失败只记录日志（站内通知已创建，不重试任务）
+func (h *NotificationHandler) sendEmail(userID string, send func() error) {
+	if err := send(); err != nil {
+		log.Printf("Failed to send notification email to user %s: %v", userID, err)
+	}
+}
+
+// stringValue 读取任务中的可选字符串字段
+func stringValue(data map[string]interface{}, key string) string {
+	value, _ := data[key].(string)
+	return value
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"astronomer-gin/pkg/sms"
+)
+
+// SMSHandler 短信发送任务处理器
+type SMSHandler struct{}
+
+// NewSMSHandler 创建短信处理器
+func NewSMSHandler() *SMSHandler {
+	return &SMSHandler{}
+}
+
+// Handle 实现TaskHandler接口
+func (h *SMSHandler) Handle(ctx context.Context, taskType string, data []byte) error {
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+
+	phone, ok := task.Data["phone"].(string)
+	if !ok || phone == "" {
+		return fmt.Errorf("missing or invalid phone in task data")
+	}
+	content, ok := task.Data["content"].(string)
+	if !ok || content == "" {
+		return fmt.Errorf("missing or invalid content in task data")
+	}
+
+	return sms.Send(phone, content)
+}
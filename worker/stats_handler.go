@@ -11,13 +11,13 @@ import (
 
 // StatsHandler 统计任务处理器
 type StatsHandler struct {
-	blogRepo repository.BlogRepository
+	articleRepo repository.ArticleV3Repository
 }
 
 // NewStatsHandler 创建统计处理器
-func NewStatsHandler(blogRepo repository.BlogRepository) *StatsHandler {
+func NewStatsHandler(articleRepo repository.ArticleV3Repository) *StatsHandler {
 	return &StatsHandler{
-		blogRepo: blogRepo,
+		articleRepo: articleRepo,
 	}
 }
 
@@ -52,7 +52,7 @@ func (h *StatsHandler) handleViewIncrement(ctx context.Context, data map[string]
 	log.Printf("Processing view increment for article %d", articleID)
 
 	// 更新文章浏览量
-	if err := h.blogRepo.IncrementVisit(articleID); err != nil {
+	if err := h.articleRepo.IncrementViewCount(articleID); err != nil {
 		return fmt.Errorf("failed to increment view count: %w", err)
 	}
 
@@ -69,11 +69,11 @@ func (h *StatsHandler) handleLikeUpdate(ctx context.Context, data map[string]int
 
 	// 更新点赞数
 	if increment > 0 {
-		if err := h.blogRepo.IncrementGoodCount(articleID); err != nil {
+		if err := h.articleRepo.IncrementLikeCount(articleID); err != nil {
 			return fmt.Errorf("failed to increment like count: %w", err)
 		}
 	} else {
-		if err := h.blogRepo.DecrementGoodCount(articleID); err != nil {
+		if err := h.articleRepo.DecrementLikeCount(articleID); err != nil {
 			return fmt.Errorf("failed to decrement like count: %w", err)
 		}
 	}